@@ -3,6 +3,8 @@
 require 'socket'
 require 'json'
 require 'pathname'
+require 'time'
+require 'date'
 require 'liquid'
 require 'trmnl/liquid'
 
@@ -19,6 +21,104 @@ puts "Liquid renderer listening on #{SOCKET_PATH}"
 # Thread pool to handle concurrent requests
 threads = []
 
+# English defaults used when the request carries no locale formats
+DEFAULT_LOCALE_FORMATS = {
+  'locale' => 'en',
+  'day_names' => %w[Sunday Monday Tuesday Wednesday Thursday Friday Saturday],
+  'abbr_day_names' => %w[Sun Mon Tue Wed Thu Fri Sat],
+  'month_names' => [nil] + %w[January February March April May June July August September October November December],
+  'abbr_month_names' => [nil] + %w[Jan Feb Mar Apr May Jun Jul Aug Sep Oct Nov Dec],
+  'date_format' => '%A, %B %-d',
+  'time_format' => '%-I:%M %p',
+  'separator' => '.',
+  'delimiter' => ','
+}.freeze
+
+# Locale-aware formatting filters backed by Stationmaster's locale formats.
+# Dates are shifted to the user's UTC offset (trmnl.user.utc_offset) before formatting.
+module LocaleFilters
+  # {{ "2025-06-03" | localized_date }} or {{ event.start | localized_date: "%a %-d %b" }}
+  def localized_date(input, format = nil)
+    formats = locale_formats
+    localized_strftime(input, format || formats['date_format'], formats)
+  end
+
+  # {{ event.start | localized_time }}
+  def localized_time(input, format = nil)
+    formats = locale_formats
+    localized_strftime(input, format || formats['time_format'], formats)
+  end
+
+  # {{ 1234567.891 | localized_number: 2 }} renders "1 234 567,89" for fr
+  def localized_number(input, precision = nil)
+    number = begin
+      Float(input)
+    rescue ArgumentError, TypeError
+      return input
+    end
+    formats = locale_formats
+
+    formatted = precision.nil? ? number.to_s.sub(/\.0\z/, '') : Kernel.format("%.#{Integer(precision)}f", number)
+    integer, fraction = formatted.split('.')
+    sign = integer.start_with?('-') ? '-' : ''
+    integer = integer.delete('-').reverse.scan(/\d{1,3}/).join(formats['delimiter'].reverse).reverse
+
+    [sign + integer, fraction].compact.join(formats['separator'])
+  end
+
+  private
+
+  def locale_formats
+    @context.registers[:locale_formats] || DEFAULT_LOCALE_FORMATS
+  end
+
+  def localized_strftime(input, format, formats)
+    time = to_user_time(input)
+    return input if time.nil?
+
+    # Substitute localized names before strftime so only numeric directives remain
+    pattern = format.to_s.gsub(/%%|%[-^]?[AaBb]/) do |directive|
+      next directive if directive == '%%'
+
+      name = case directive[-1]
+             when 'A' then formats['day_names'][time.wday]
+             when 'a' then formats['abbr_day_names'][time.wday]
+             when 'B' then formats['month_names'][time.month]
+             when 'b' then formats['abbr_month_names'][time.month]
+             end.to_s
+      name = name.upcase if directive.include?('^')
+      name.gsub('%', '%%')
+    end
+
+    time.strftime(pattern)
+  end
+
+  def to_user_time(input)
+    # Calendar dates have no time of day, so they are formatted as-is rather than shifted
+    return input.to_time if input.is_a?(Date)
+    return Date.iso8601(input).to_time if input.is_a?(String) && input.match?(/\A\d{4}-\d{2}-\d{2}\z/)
+
+    time = case input
+           when Time then input
+           when Numeric then Time.at(input)
+           when 'now', 'today' then render_time
+           when /\A\d+\z/ then Time.at(input.to_i)
+           when String then Time.parse(input)
+           end
+    return nil if time.nil?
+
+    time.getlocal(@context['trmnl.user.utc_offset'].to_i)
+  rescue ArgumentError
+    nil
+  end
+
+  # Honour trmnl.system.timestamp_utc so previews rendered at an overridden time agree with "now"
+  def render_time
+    timestamp = @context['trmnl.system.timestamp_utc']
+    timestamp ? Time.at(timestamp.to_i) : Time.now
+  end
+end
+
 def handle_request(client)
   begin
     # Read JSON request from client
@@ -28,13 +128,15 @@ def handle_request(client)
     request = JSON.parse(request_data)
     template_str = request['template']
     data = request['data'] || {}
+    locale_formats = request['locale_formats']
 
     # Build TRMNL Liquid environment
     environment = TRMNL::Liquid.build_environment
+    environment.register_filter(LocaleFilters)
 
     # Parse and render template
     template = Liquid::Template.parse(template_str, environment: environment)
-    rendered_html = template.render(data)
+    rendered_html = template.render(data, registers: { locale_formats: locale_formats })
 
     # Send success response
     response = {
@@ -1,11 +1,14 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/mail"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,6 +35,37 @@ func TestSMTPHandler(c *gin.Context) {
 		return
 	}
 
+	// Recipients are optional - without them only the connection is tested
+	var req struct {
+		To []string `json:"to"`
+		CC []string `json:"cc"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+			return
+		}
+	}
+
+	to, err := parseEmailAddresses(req.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cc, err := parseEmailAddresses(req.CC)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(cc) > 0 && len(to) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one recipient is required when CC is provided"})
+		return
+	}
+	if len(to)+len(cc) > maxTestEmailRecipients {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A maximum of %d recipients is allowed", maxTestEmailRecipients)})
+		return
+	}
+
 	// Test SMTP connection
 	if err := smtp.TestSMTPConnection(); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -41,12 +75,50 @@ func TestSMTPHandler(c *gin.Context) {
 		return
 	}
 
+	if len(to) == 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "SMTP connection successful",
+		})
+		return
+	}
+
+	if err := smtp.SendTestEmail(to, cc); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to send test email: " + err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "SMTP connection successful",
+		"message": "Test email sent successfully",
+		"to":      to,
+		"cc":      cc,
 	})
 }
 
+// maxTestEmailRecipients limits the combined To and CC addresses for a test email
+const maxTestEmailRecipients = 10
+
+// parseEmailAddresses validates a list of email addresses and returns their bare address form
+func parseEmailAddresses(addresses []string) ([]string, error) {
+	var parsed []string
+	for _, address := range addresses {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid email address: %s", address)
+		}
+		parsed = append(parsed, addr.Address)
+	}
+	return parsed, nil
+}
+
 // GetSystemStatusHandler returns system status information (admin only)
 func GetSystemStatusHandler(c *gin.Context) {
 	if !database.IsMultiUserMode() {
@@ -81,6 +153,22 @@ func GetSystemStatusHandler(c *gin.Context) {
 	siteURL, _ := database.GetSystemSetting("site_url")
 	enableFrequentRefreshes, _ := database.GetSystemSetting("enable_frequent_refreshes")
 	pluginProcessingTimeout, _ := database.GetSystemSetting("plugin_processing_timeout_seconds")
+	displayTimeoutFallback, _ := database.GetSystemSetting("display_timeout_fallback")
+	maintenanceModeEnabled, _ := database.GetSystemSetting("maintenance_mode_enabled")
+	maintenanceImageURL, _ := database.GetSystemSetting("maintenance_image_url")
+	maintenanceRefreshRate, _ := database.GetSystemSetting("maintenance_refresh_rate")
+	unclaimedRefreshRate, _ := database.GetSystemSetting("unclaimed_refresh_rate")
+	claimRequiresApproval, _ := database.GetSystemSetting("device_claim_requires_approval")
+	renderTimeoutSystem, _ := database.GetSystemSetting("render_timeout_system_seconds")
+	renderTimeoutPrivate, _ := database.GetSystemSetting("render_timeout_private_seconds")
+	renderTimeoutExternal, _ := database.GetSystemSetting("render_timeout_external_seconds")
+	renderTimeoutMashup, _ := database.GetSystemSetting("render_timeout_mashup_seconds")
+	unknownModelBehavior, _ := database.GetSystemSetting(database.UnknownModelBehaviorSettingKey)
+	unknownModelDefault, _ := database.GetSystemSetting(database.UnknownModelDefaultSettingKey)
+	uniqueInstanceNames, _ := database.GetSystemSetting(database.UniquePluginInstanceNamesSettingKey)
+	mashupMaxSlots, _ := database.GetSystemSetting(database.MashupMaxSlotsSettingKey)
+	mashupMaxChildRenders, _ := database.GetSystemSetting(database.MashupMaxChildRendersSettingKey)
+	webhookMaxImageSize, _ := database.GetSystemSetting("webhook_max_image_size_kb")
 
 	// Check authentication methods
 	oidcEnabled := IsOIDCEnabled()
@@ -112,6 +200,22 @@ func GetSystemStatusHandler(c *gin.Context) {
 			"site_url":                    siteURL,
 			"enable_frequent_refreshes":            enableFrequentRefreshes,
 			"plugin_processing_timeout_seconds":    pluginProcessingTimeout,
+			"display_timeout_fallback":             displayTimeoutFallback,
+			"maintenance_mode_enabled":             maintenanceModeEnabled,
+			"maintenance_image_url":                maintenanceImageURL,
+			"maintenance_refresh_rate":             maintenanceRefreshRate,
+			"unclaimed_refresh_rate":               unclaimedRefreshRate,
+			"device_claim_requires_approval":       claimRequiresApproval,
+			"render_timeout_system_seconds":        renderTimeoutSystem,
+			"render_timeout_private_seconds":       renderTimeoutPrivate,
+			"render_timeout_external_seconds":      renderTimeoutExternal,
+			"render_timeout_mashup_seconds":        renderTimeoutMashup,
+			"unknown_model_behavior":               unknownModelBehavior,
+			"unknown_model_default":                unknownModelDefault,
+			"unique_plugin_instance_names":         uniqueInstanceNames,
+			"mashup_max_slots":                     mashupMaxSlots,
+			"mashup_max_child_renders_per_hour":    mashupMaxChildRenders,
+			"webhook_max_image_size_kb":            webhookMaxImageSize,
 		},
 		"auth": gin.H{
 			"oidc_enabled":       oidcEnabled,
@@ -157,6 +261,22 @@ func UpdateSystemSettingHandler(c *gin.Context) {
 		"site_url":                     true,
 		"enable_frequent_refreshes":            true,
 		"plugin_processing_timeout_seconds":    true,
+		"display_timeout_fallback":             true,
+		"maintenance_mode_enabled":             true,
+		"maintenance_image_url":                true,
+		"maintenance_refresh_rate":             true,
+		"unclaimed_refresh_rate":               true,
+		"device_claim_requires_approval":       true,
+		"render_timeout_system_seconds":        true,
+		"render_timeout_private_seconds":       true,
+		"render_timeout_external_seconds":      true,
+		"render_timeout_mashup_seconds":        true,
+		"unknown_model_behavior":               true,
+		"unknown_model_default":                true,
+		"unique_plugin_instance_names":         true,
+		"mashup_max_slots":                     true,
+		"mashup_max_child_renders_per_hour":    true,
+		"webhook_max_image_size_kb":            true,
 	}
 
 	if !allowedSettings[req.Key] {
@@ -164,6 +284,65 @@ func UpdateSystemSettingHandler(c *gin.Context) {
 		return
 	}
 
+	switch req.Key {
+	case "maintenance_mode_enabled", "device_claim_requires_approval", database.UniquePluginInstanceNamesSettingKey:
+		if req.Value != "true" && req.Value != "false" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": req.Key + " must be true or false"})
+			return
+		}
+	case "display_timeout_fallback":
+		if req.Value != "error_image" && req.Value != "last_content" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "display_timeout_fallback must be error_image or last_content"})
+			return
+		}
+	case "plugin_processing_timeout_seconds":
+		// The display handler clamps to this range, so reject values it would silently ignore
+		if seconds, err := strconv.Atoi(req.Value); err != nil || seconds < 1 || seconds > 60 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "plugin_processing_timeout_seconds must be between 1 and 60 seconds"})
+			return
+		}
+	case "maintenance_refresh_rate":
+		if rate, err := strconv.Atoi(req.Value); err != nil || rate <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "maintenance_refresh_rate must be a positive number of seconds"})
+			return
+		}
+	case "unclaimed_refresh_rate":
+		if rate, err := strconv.Atoi(req.Value); err != nil || rate < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unclaimed_refresh_rate must be zero or a positive number of seconds"})
+			return
+		}
+	case "render_timeout_system_seconds", "render_timeout_private_seconds", "render_timeout_external_seconds", "render_timeout_mashup_seconds":
+		if seconds, err := strconv.Atoi(req.Value); err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": req.Key + " must be zero or a positive number of seconds"})
+			return
+		}
+	case database.MashupMaxSlotsSettingKey:
+		if slots, err := strconv.Atoi(req.Value); err != nil || slots < 2 || slots > 4 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mashup_max_slots must be between 2 and 4"})
+			return
+		}
+	case database.MashupMaxChildRendersSettingKey:
+		if limit, err := strconv.Atoi(req.Value); err != nil || limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mashup_max_child_renders_per_hour must be zero or a positive number"})
+			return
+		}
+	case "webhook_max_image_size_kb":
+		if size, err := strconv.Atoi(req.Value); err != nil || size <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "webhook_max_image_size_kb must be a positive number of KB"})
+			return
+		}
+	case database.UnknownModelBehaviorSettingKey:
+		if !database.IsValidUnknownModelBehavior(req.Value) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown_model_behavior must be none, reject, default or provisional"})
+			return
+		}
+	case database.UnknownModelDefaultSettingKey:
+		if _, err := database.NewDeviceService(database.DB).GetDeviceModelByName(req.Value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown_model_default must name an existing device model"})
+			return
+		}
+	}
+
 	// Update the setting
 	if err := database.SetSystemSetting(req.Key, req.Value, &user.ID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update setting"})
@@ -451,6 +630,32 @@ func UploadRestoreFileHandler(c *gin.Context) {
 		return
 	}
 
+	// Make sure the upload is a backup this build can restore before accepting it
+	metadata, err := export.ValidateBackupArchive(uploadPath)
+	if err != nil {
+		os.Remove(uploadPath)
+		if errors.Is(err, export.ErrNotBackupArchive) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      fmt.Sprintf("%s is not a valid Stationmaster backup archive: %v", filename, err),
+				"error_type": "invalid_backup_archive",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error_type": "backup_analyze_failed"})
+		return
+	}
+	if !export.IsCompatibleBackup(metadata) {
+		os.Remove(uploadPath)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Backup format version %d (Stationmaster %s) is newer than this server supports (format version %d). Upgrade Stationmaster before restoring.",
+				metadata.BackupFormatVersion(), metadata.StationmasterVersion, export.BackupFormatVersion),
+			"error_type":     "incompatible_backup_version",
+			"backup_version": metadata.StationmasterVersion,
+			"compatible":     false,
+		})
+		return
+	}
+
 	// Create restore upload record
 	restoreUpload := database.RestoreUpload{
 		AdminUserID: user.ID,
@@ -467,7 +672,17 @@ func UploadRestoreFileHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, restoreUpload)
+	c.JSON(http.StatusCreated, struct {
+		database.RestoreUpload
+		BackupVersion       string `json:"backup_version"`
+		BackupFormatVersion int    `json:"backup_format_version"`
+		Compatible          bool   `json:"compatible"`
+	}{
+		RestoreUpload:       restoreUpload,
+		BackupVersion:       metadata.StationmasterVersion,
+		BackupFormatVersion: metadata.BackupFormatVersion(),
+		Compatible:          true,
+	})
 }
 
 // GetRestoreUploadsHandler returns pending restore uploads
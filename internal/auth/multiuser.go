@@ -15,6 +15,7 @@ import (
 	"github.com/rmitchellscott/stationmaster/internal/database"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
 	"github.com/rmitchellscott/stationmaster/internal/smtp"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -26,6 +27,17 @@ type RegisterRequest struct {
 	Timezone string `json:"timezone,omitempty"`
 }
 
+// newUserTimezone picks the timezone for a user signing themselves up: the first valid candidate, else one
+// inferred from the browser's Accept-Language. An empty result leaves the UTC default.
+func newUserTimezone(c *gin.Context, candidates ...string) string {
+	for _, timezone := range candidates {
+		if timezone != "" && utils.IsValidTimezone(timezone) {
+			return timezone
+		}
+	}
+	return utils.TimezoneFromAcceptLanguage(c.GetHeader("Accept-Language"))
+}
+
 // PasswordResetRequest represents a password reset request
 type PasswordResetRequest struct {
 	Email string `json:"email" binding:"required,email"`
@@ -105,7 +117,7 @@ func PublicRegisterHandler(c *gin.Context) {
 	firstUser := userCount == 0
 
 	userService := database.NewUserService(database.DB)
-	newUser, err := userService.CreateUser(req.Username, req.Email, req.Password, firstUser, req.Timezone)
+	newUser, err := userService.CreateUser(req.Username, req.Email, req.Password, firstUser, newUserTimezone(c, req.Timezone))
 	if err != nil {
 		if strings.Contains(err.Error(), "already exists") {
 			c.JSON(http.StatusConflict, gin.H{"error": "User with this username or email already exists"})
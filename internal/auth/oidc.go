@@ -17,6 +17,7 @@ import (
 	"github.com/rmitchellscott/stationmaster/internal/config"
 	"github.com/rmitchellscott/stationmaster/internal/database"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
 	"golang.org/x/oauth2"
 )
 
@@ -283,6 +284,8 @@ func OIDCCallbackHandler(c *gin.Context) {
 		Subject           string   `json:"sub"`
 		EmailVerified     bool     `json:"email_verified"`
 		Groups            []string `json:"groups"`
+		Zoneinfo          string   `json:"zoneinfo"`
+		Locale            string   `json:"locale"`
 	}
 
 	if err := idToken.Claims(&claims); err != nil {
@@ -320,7 +323,10 @@ func OIDCCallbackHandler(c *gin.Context) {
 	}
 
 	// Handle user authentication in multi-user mode
-	if err := handleOIDCMultiUserAuth(c, username, claims.Email, claims.Name, claims.Subject, claims.Groups, rawIDToken); err != nil {
+	// Timezone for auto-created users: the zoneinfo claim, else the locale claim's region, else the browser's languages
+	timezone := newUserTimezone(c, claims.Zoneinfo, utils.TimezoneFromLocale(claims.Locale))
+
+	if err := handleOIDCMultiUserAuth(c, username, claims.Email, claims.Name, claims.Subject, claims.Groups, timezone, rawIDToken); err != nil {
 		if err.Error() == "account disabled" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "backend.auth.account_disabled"})
 		} else {
@@ -331,7 +337,7 @@ func OIDCCallbackHandler(c *gin.Context) {
 }
 
 // handleOIDCMultiUserAuth handles OIDC authentication in multi-user mode
-func handleOIDCMultiUserAuth(c *gin.Context, username, email, name, subject string, groups []string, rawIDToken string) error {
+func handleOIDCMultiUserAuth(c *gin.Context, username, email, name, subject string, groups []string, timezone, rawIDToken string) error {
 	var user *database.User
 	var err error
 
@@ -374,8 +380,8 @@ func handleOIDCMultiUserAuth(c *gin.Context, username, email, name, subject stri
 
 				// Auto-create user using the existing CreateUser method
 				userService := database.NewUserService(database.DB)
-				oidcDebug("creating new user", "username", username, "email", email, "admin", isAdmin)
-				user, err = userService.CreateUser(username, email, "", isAdmin) // Empty password for OIDC users
+				oidcDebug("creating new user", "username", username, "email", email, "admin", isAdmin, "timezone", timezone)
+				user, err = userService.CreateUser(username, email, "", isAdmin, timezone) // Empty password for OIDC users
 				if err != nil {
 					oidcDebug("failed to create user", "error", err)
 					return fmt.Errorf("failed to create user: %w", err)
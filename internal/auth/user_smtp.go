@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/smtp"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+)
+
+// smtpHostLookupTimeout bounds resolving a user's SMTP host when saving it
+const smtpHostLookupTimeout = 5 * time.Second
+
+// UpdateUserSMTPRequest represents a per-user SMTP override update.
+// A nil password keeps the stored one; an empty string clears it.
+type UpdateUserSMTPRequest struct {
+	Host     string  `json:"host" binding:"required"`
+	Port     int     `json:"port"`
+	Username string  `json:"username"`
+	Password *string `json:"password"`
+	From     string  `json:"from" binding:"required"`
+	UseTLS   *bool   `json:"use_tls"`
+}
+
+// userSMTPResponse describes a user's SMTP override without exposing the password
+func userSMTPResponse(user *database.User) gin.H {
+	return gin.H{
+		"configured":   user.SMTPHost != "",
+		"host":         user.SMTPHost,
+		"port":         user.SMTPPort,
+		"username":     user.SMTPUsername,
+		"from":         user.SMTPFrom,
+		"use_tls":      user.SMTPUseTLS,
+		"has_password": user.SMTPPasswordEncrypted != "",
+	}
+}
+
+// GetUserSMTPHandler returns the current user's SMTP override
+func GetUserSMTPHandler(c *gin.Context) {
+	user, ok := RequireUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"smtp": userSMTPResponse(user)})
+}
+
+// UpdateUserSMTPHandler sets the SMTP server used for the current user's notifications
+func UpdateUserSMTPHandler(c *gin.Context) {
+	user, ok := RequireUser(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateUserSMTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErrorMessage(err)})
+		return
+	}
+
+	host := strings.TrimSpace(req.Host)
+	if strings.ContainsAny(host, "/: ") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SMTP host must be a hostname without scheme or port"})
+		return
+	}
+	if req.Port < 0 || req.Port > 65535 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SMTP port must be between 1 and 65535"})
+		return
+	}
+	// Non-admins could otherwise use the server to probe hosts on its internal network. Connections are
+	// checked again when dialing, in case the host's DNS changes.
+	if !user.IsAdmin {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), smtpHostLookupTimeout)
+		_, err := utils.ResolvePublicIPs(ctx, host)
+		cancel()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "SMTP host must be a public hostname"})
+			return
+		}
+	}
+	from := strings.TrimSpace(req.From)
+	if _, err := mail.ParseAddress(from); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SMTP from must be a valid email address"})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"smtp_host":     host,
+		"smtp_port":     req.Port,
+		"smtp_username": strings.TrimSpace(req.Username),
+		"smtp_from":     from,
+	}
+	if req.UseTLS != nil {
+		updates["smtp_use_tls"] = *req.UseTLS
+	}
+	if req.Password != nil {
+		encrypted := ""
+		if *req.Password != "" {
+			var err error
+			encrypted, err = smtp.EncryptPassword(*req.Password)
+			if err != nil {
+				if errors.Is(err, smtp.ErrNoEncryptionKey) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "SMTP passwords cannot be stored until the server has ENCRYPTION_KEY or JWT_SECRET set"})
+					return
+				}
+				logging.Error("[SMTP] Failed to encrypt user SMTP password", "user_id", user.ID, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store SMTP password"})
+				return
+			}
+		}
+		updates["smtp_password_encrypted"] = encrypted
+	}
+
+	userService := database.NewUserService(database.DB)
+	if err := userService.UpdateUserSettings(user.ID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update SMTP settings"})
+		return
+	}
+
+	updatedUser, err := userService.GetUserByID(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load updated settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"smtp": userSMTPResponse(updatedUser)})
+}
+
+// DeleteUserSMTPHandler removes the current user's SMTP override so the global server is used again
+func DeleteUserSMTPHandler(c *gin.Context) {
+	user, ok := RequireUser(c)
+	if !ok {
+		return
+	}
+
+	updates := map[string]interface{}{
+		"smtp_host":               "",
+		"smtp_port":               0,
+		"smtp_username":           "",
+		"smtp_password_encrypted": "",
+		"smtp_from":               "",
+		"smtp_use_tls":            true,
+	}
+	if err := database.NewUserService(database.DB).UpdateUserSettings(user.ID, updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove SMTP settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "SMTP settings removed"})
+}
+
+// TestUserSMTPHandler checks that the current user's SMTP server accepts a connection
+func TestUserSMTPHandler(c *gin.Context) {
+	user, ok := RequireUser(c)
+	if !ok {
+		return
+	}
+
+	if user.SMTPHost == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No SMTP server configured"})
+		return
+	}
+
+	if err := smtp.TestUserSMTPConnection(user); err != nil {
+		logging.Warn("[SMTP] User SMTP connection test failed", "user_id", user.ID, "error", err)
+		// The dial error would tell non-admins which internal hosts and ports are open
+		message := "SMTP connection failed. Check the host, port, TLS setting and credentials"
+		if user.IsAdmin {
+			message = "SMTP connection failed: " + err.Error()
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"success": false,
+			"error":   message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SMTP connection successful",
+	})
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDeviceAlreadyClaimed       = errors.New("device already claimed")
+	ErrClaimRequestNotPending     = errors.New("claim request is no longer pending")
+	ErrClaimRequestAlreadyPending = errors.New("a claim request for this device is already pending")
+)
+
+// ClaimRequestSettingKey is the system setting that makes device claims require admin approval
+const ClaimRequestSettingKey = "device_claim_requires_approval"
+
+// IsClaimApprovalRequired reports whether device claims must be approved by an admin
+func IsClaimApprovalRequired() bool {
+	value, err := GetSystemSetting(ClaimRequestSettingKey)
+	return err == nil && value == "true"
+}
+
+// ClaimRequestService handles device claim request database operations
+type ClaimRequestService struct {
+	db *gorm.DB
+}
+
+// NewClaimRequestService creates a new claim request service
+func NewClaimRequestService(db *gorm.DB) *ClaimRequestService {
+	return &ClaimRequestService{db: db}
+}
+
+// CreateClaimRequest records a pending request from a user to claim an unclaimed device
+func (crs *ClaimRequestService) CreateClaimRequest(userID uuid.UUID, identifier, name string) (*DeviceClaimRequest, error) {
+	device, err := NewDeviceService(crs.db).GetDeviceByIdentifier(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if device.IsClaimed {
+		return nil, ErrDeviceAlreadyClaimed
+	}
+
+	var count int64
+	if err := crs.db.Model(&DeviceClaimRequest{}).
+		Where("device_id = ? AND user_id = ? AND status = ?", device.ID, userID, ClaimRequestStatusPending).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrClaimRequestAlreadyPending
+	}
+
+	request := &DeviceClaimRequest{
+		DeviceID: device.ID,
+		UserID:   userID,
+		Name:     name,
+		Status:   ClaimRequestStatusPending,
+	}
+	if err := crs.db.Create(request).Error; err != nil {
+		return nil, err
+	}
+
+	logging.Info("[CLAIM REQUEST] Created device claim request", "request_id", request.ID, "friendly_id", device.FriendlyID, "user_id", userID)
+	return request, nil
+}
+
+// GetPendingClaimRequests returns all pending claim requests with their device and requester
+func (crs *ClaimRequestService) GetPendingClaimRequests() ([]DeviceClaimRequest, error) {
+	var requests []DeviceClaimRequest
+	err := crs.db.Preload("Device").Preload("User").
+		Where("status = ?", ClaimRequestStatusPending).
+		Order("created_at ASC").
+		Find(&requests).Error
+	return requests, err
+}
+
+// GetUserClaimRequests returns a user's claim requests, newest first
+func (crs *ClaimRequestService) GetUserClaimRequests(userID uuid.UUID) ([]DeviceClaimRequest, error) {
+	var requests []DeviceClaimRequest
+	err := crs.db.Preload("Device").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&requests).Error
+	return requests, err
+}
+
+// ApproveClaimRequest links the device to the requester and denies any other pending requests for it
+func (crs *ClaimRequestService) ApproveClaimRequest(requestID, adminID uuid.UUID) (*DeviceClaimRequest, error) {
+	var request DeviceClaimRequest
+	err := crs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Device").First(&request, "id = ?", requestID).Error; err != nil {
+			return err
+		}
+		if request.Status != ClaimRequestStatusPending {
+			return ErrClaimRequestNotPending
+		}
+		if request.Device != nil && request.Device.IsClaimed {
+			return ErrDeviceAlreadyClaimed
+		}
+
+		// Guard against a concurrent claim between the read above and this update
+		result := tx.Model(&Device{}).
+			Where("id = ? AND is_claimed = ?", request.DeviceID, false).
+			Updates(map[string]interface{}{
+				"user_id":    request.UserID,
+				"name":       request.Name,
+				"is_claimed": true,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDeviceAlreadyClaimed
+		}
+
+		now := time.Now().UTC()
+		if err := tx.Model(&request).Updates(map[string]interface{}{
+			"status":      ClaimRequestStatusApproved,
+			"reviewed_by": adminID,
+			"reviewed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&DeviceClaimRequest{}).
+			Where("device_id = ? AND status = ? AND id != ?", request.DeviceID, ClaimRequestStatusPending, request.ID).
+			Updates(map[string]interface{}{
+				"status":      ClaimRequestStatusDenied,
+				"reviewed_by": adminID,
+				"reviewed_at": now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload so the response reflects the approved request and claimed device
+	if err := crs.db.Preload("Device").First(&request, "id = ?", requestID).Error; err != nil {
+		return nil, err
+	}
+
+	logging.Info("[CLAIM REQUEST] Approved device claim request", "request_id", request.ID, "device_id", request.DeviceID, "user_id", request.UserID)
+	return &request, nil
+}
+
+// DenyClaimRequest rejects a pending claim request
+func (crs *ClaimRequestService) DenyClaimRequest(requestID, adminID uuid.UUID) (*DeviceClaimRequest, error) {
+	var request DeviceClaimRequest
+	if err := crs.db.First(&request, "id = ?", requestID).Error; err != nil {
+		return nil, err
+	}
+	if request.Status != ClaimRequestStatusPending {
+		return nil, ErrClaimRequestNotPending
+	}
+
+	now := time.Now().UTC()
+	request.Status = ClaimRequestStatusDenied
+	request.ReviewedBy = &adminID
+	request.ReviewedAt = &now
+	if err := crs.db.Save(&request).Error; err != nil {
+		return nil, err
+	}
+
+	logging.Info("[CLAIM REQUEST] Denied device claim request", "request_id", request.ID, "device_id", request.DeviceID, "user_id", request.UserID)
+	return &request, nil
+}
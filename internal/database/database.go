@@ -28,8 +28,21 @@ type DatabaseConfig struct {
 	DBName   string
 	SSLMode  string
 	DataDir  string // For SQLite
+
+	// Connection pool settings (PostgreSQL only, SQLite always uses a single connection)
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// Startup connection settings, so the app can wait for a database that isn't ready yet
+	ConnectTimeout    time.Duration // Timeout for establishing a connection (PostgreSQL only)
+	ConnectRetries    int           // Retries after the first failed connection attempt
+	ConnectRetryDelay time.Duration // Delay before the first retry, doubled after each attempt
 }
 
+// maxConnectRetryDelay caps the backoff between startup connection attempts
+const maxConnectRetryDelay = 30 * time.Second
+
 // GetDatabaseConfig reads database configuration from environment variables
 func GetDatabaseConfig() *DatabaseConfig {
 	cfg := &DatabaseConfig{
@@ -41,6 +54,26 @@ func GetDatabaseConfig() *DatabaseConfig {
 		DBName:   config.Get("DB_NAME", "stationmaster"),
 		SSLMode:  config.Get("DB_SSLMODE", "disable"),
 		DataDir:  config.Get("DATA_DIR", "/data"),
+
+		MaxOpenConns:    config.GetInt("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:    config.GetInt("DB_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime: config.GetDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+
+		ConnectTimeout:    config.GetDuration("DB_CONNECT_TIMEOUT", 10*time.Second),
+		ConnectRetries:    config.GetInt("DB_CONNECT_RETRIES", 5),
+		ConnectRetryDelay: config.GetDuration("DB_CONNECT_RETRY_DELAY", 2*time.Second),
+	}
+
+	if cfg.ConnectRetries < 0 {
+		cfg.ConnectRetries = 0
+	}
+	if cfg.ConnectRetryDelay <= 0 {
+		cfg.ConnectRetryDelay = 2 * time.Second
+	}
+
+	// Idle connections beyond the open limit would be closed immediately
+	if cfg.MaxOpenConns > 0 && cfg.MaxIdleConns > cfg.MaxOpenConns {
+		cfg.MaxIdleConns = cfg.MaxOpenConns
 	}
 
 	return cfg
@@ -51,15 +84,7 @@ func Initialize() error {
 	config := GetDatabaseConfig()
 
 	var err error
-	switch config.Type {
-	case "postgres":
-		DB, err = initPostgres(config)
-	case "sqlite":
-		DB, err = initSQLite(config)
-	default:
-		return fmt.Errorf("unsupported database type: %s", config.Type)
-	}
-
+	DB, err = connectWithRetry(config)
 	if err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
@@ -84,10 +109,47 @@ func Initialize() error {
 	return nil
 }
 
+// connectWithRetry opens the configured database, retrying with exponential backoff while it is unreachable
+func connectWithRetry(config *DatabaseConfig) (*gorm.DB, error) {
+	var open func(*DatabaseConfig) (*gorm.DB, error)
+	switch config.Type {
+	case "postgres":
+		open = initPostgres
+	case "sqlite":
+		open = initSQLite
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", config.Type)
+	}
+
+	attempts := config.ConnectRetries + 1
+	delay := config.ConnectRetryDelay
+	for attempt := 1; ; attempt++ {
+		logging.Info("[STARTUP] Connecting to database", "type", config.Type, "attempt", attempt, "max_attempts", attempts)
+		db, err := open(config)
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= attempts {
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+		}
+
+		logging.Warn("[STARTUP] Database connection failed, retrying", "type", config.Type, "attempt", attempt, "retry_in", delay, "error", err)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > maxConnectRetryDelay {
+			delay = maxConnectRetryDelay
+		}
+	}
+}
+
 // initPostgres initializes PostgreSQL connection
 func initPostgres(config *DatabaseConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
 		config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode)
+	if config.ConnectTimeout > 0 {
+		// connect_timeout is in whole seconds
+		dsn += fmt.Sprintf(" connect_timeout=%d", max(int(config.ConnectTimeout.Seconds()), 1))
+	}
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: getGormLogger(),
@@ -102,9 +164,14 @@ func initPostgres(config *DatabaseConfig) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+
+	logging.Info("[STARTUP] Database connection pool configured",
+		"max_open_conns", config.MaxOpenConns,
+		"max_idle_conns", config.MaxIdleConns,
+		"conn_max_lifetime", config.ConnMaxLifetime)
 
 	return db, nil
 }
@@ -236,6 +303,91 @@ func initializeSystemSettings() error {
 			Value:       "2",
 			Description: "Timeout in seconds for plugin processing during display requests",
 		},
+		"display_timeout_fallback": {
+			Key:         "display_timeout_fallback",
+			Value:       "error_image",
+			Description: "What devices show when plugin processing times out: error_image or last_content",
+		},
+		"render_paused": {
+			Key:         "render_paused",
+			Value:       "false",
+			Description: "Pause background rendering, leaving queued render jobs pending",
+		},
+		"webhook_max_image_size_kb": {
+			Key:         "webhook_max_image_size_kb",
+			Value:       "2048",
+			Description: "Maximum image upload size in KB for image webhook plugins",
+		},
+		"maintenance_mode_enabled": {
+			Key:         "maintenance_mode_enabled",
+			Value:       "false",
+			Description: "Show the maintenance screen on all devices instead of their playlists",
+		},
+		"maintenance_image_url": {
+			Key:         "maintenance_image_url",
+			Value:       "",
+			Description: "Image served to devices during maintenance (defaults to the sleep screen)",
+		},
+		"device_claim_requires_approval": {
+			Key:         "device_claim_requires_approval",
+			Value:       "false",
+			Description: "Require admin approval before users can claim unclaimed devices",
+		},
+		"maintenance_refresh_rate": {
+			Key:         "maintenance_refresh_rate",
+			Value:       "3600",
+			Description: "Refresh rate in seconds for devices during maintenance",
+		},
+		"unclaimed_refresh_rate": {
+			Key:         "unclaimed_refresh_rate",
+			Value:       "0",
+			Description: "Refresh rate in seconds for unclaimed devices (0 uses the device refresh rate)",
+		},
+		"render_timeout_system_seconds": {
+			Key:         "render_timeout_system_seconds",
+			Value:       "0",
+			Description: "Render timeout in seconds for system plugins (0 uses RENDER_TIMEOUT)",
+		},
+		"render_timeout_private_seconds": {
+			Key:         "render_timeout_private_seconds",
+			Value:       "0",
+			Description: "Render timeout in seconds for private plugins (0 uses RENDER_TIMEOUT)",
+		},
+		"render_timeout_external_seconds": {
+			Key:         "render_timeout_external_seconds",
+			Value:       "0",
+			Description: "Render timeout in seconds for external plugins (0 uses RENDER_TIMEOUT)",
+		},
+		"render_timeout_mashup_seconds": {
+			Key:         "render_timeout_mashup_seconds",
+			Value:       "0",
+			Description: "Render timeout in seconds for mashup plugins (0 uses RENDER_TIMEOUT)",
+		},
+		UniquePluginInstanceNamesSettingKey: {
+			Key:         UniquePluginInstanceNamesSettingKey,
+			Value:       "false",
+			Description: "Require each user's plugin instance names to be unique",
+		},
+		MashupMaxSlotsSettingKey: {
+			Key:         MashupMaxSlotsSettingKey,
+			Value:       "4",
+			Description: "Maximum number of slots in a new mashup's layout",
+		},
+		MashupMaxChildRendersSettingKey: {
+			Key:         MashupMaxChildRendersSettingKey,
+			Value:       "0",
+			Description: "Maximum combined renders per hour of a mashup's children (0 for no limit)",
+		},
+		"unknown_model_behavior": {
+			Key:         "unknown_model_behavior",
+			Value:       UnknownModelNone,
+			Description: "How to handle devices reporting an unknown model: none, reject, default or provisional",
+		},
+		"unknown_model_default": {
+			Key:         "unknown_model_default",
+			Value:       "og_png",
+			Description: "Device model assigned to unknown models and used as the template for provisional models",
+		},
 	}
 
 	for _, setting := range defaultSettings {
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Image formats a device firmware can report support for
+const (
+	ImageFormatPNG = "png"
+	ImageFormatBMP = "bmp"
+)
+
+// knownImageFormats lists the image formats accepted in capability reports
+var knownImageFormats = []string{ImageFormatPNG, ImageFormatBMP}
+
+// DeviceCapabilityReport is what a device firmware reports about itself. Omitted fields
+// fall back to the defaults of the device model.
+type DeviceCapabilityReport struct {
+	PartialRefresh *bool    `json:"partial_refresh,omitempty"`
+	Color          *bool    `json:"color,omitempty"`
+	MaxBitDepth    *int     `json:"max_bit_depth,omitempty"`
+	ImageFormats   []string `json:"image_formats,omitempty"`
+}
+
+// Validate checks that the report only contains supported values and normalizes image formats
+func (r *DeviceCapabilityReport) Validate() error {
+	if r.MaxBitDepth != nil && (*r.MaxBitDepth < 1 || *r.MaxBitDepth > 8) {
+		return fmt.Errorf("max_bit_depth must be between 1 and 8")
+	}
+	for i, format := range r.ImageFormats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if !slices.Contains(knownImageFormats, format) {
+			return fmt.Errorf("unsupported image format %q", format)
+		}
+		r.ImageFormats[i] = format
+	}
+	return nil
+}
+
+// DeviceCapabilities are the resolved capabilities used for rendering decisions
+type DeviceCapabilities struct {
+	PartialRefresh bool     `json:"partial_refresh"`
+	Color          bool     `json:"color"`
+	MaxBitDepth    int      `json:"max_bit_depth"`
+	ImageFormats   []string `json:"image_formats"`
+	Reported       bool     `json:"reported"` // False when every value comes from the device model
+}
+
+// SupportsImageFormat reports whether the device can display the given image format
+func (c DeviceCapabilities) SupportsImageFormat(format string) bool {
+	return slices.Contains(c.ImageFormats, format)
+}
+
+// ModelCapabilities returns the capabilities implied by a device model, used when the firmware
+// does not report its own. Devices without a model are treated as 1-bit PNG displays.
+func ModelCapabilities(model *DeviceModel) DeviceCapabilities {
+	caps := DeviceCapabilities{
+		MaxBitDepth:  1,
+		ImageFormats: []string{ImageFormatPNG},
+	}
+	if model == nil {
+		return caps
+	}
+
+	if model.BitDepth > 0 {
+		caps.MaxBitDepth = model.BitDepth
+	}
+	caps.Color = model.ColorDepth >= colorDepthColor
+	if model.MimeType == "image/bmp" {
+		caps.ImageFormats = []string{ImageFormatBMP}
+	}
+	return caps
+}
+
+// GetCapabilityReport parses the capabilities last reported by the device firmware, if any
+func (d *Device) GetCapabilityReport() (*DeviceCapabilityReport, error) {
+	if d.ReportedCapabilities == "" {
+		return nil, nil
+	}
+	var report DeviceCapabilityReport
+	if err := json.Unmarshal([]byte(d.ReportedCapabilities), &report); err != nil {
+		return nil, fmt.Errorf("invalid reported capabilities: %w", err)
+	}
+	return &report, nil
+}
+
+// EffectiveCapabilities merges the capabilities reported by the firmware over the model defaults
+func (d *Device) EffectiveCapabilities() DeviceCapabilities {
+	caps := ModelCapabilities(d.DeviceModel)
+
+	report, err := d.GetCapabilityReport()
+	if err != nil || report == nil {
+		return caps
+	}
+
+	caps.Reported = true
+	if report.PartialRefresh != nil {
+		caps.PartialRefresh = *report.PartialRefresh
+	}
+	if report.Color != nil {
+		caps.Color = *report.Color
+	}
+	if report.MaxBitDepth != nil {
+		caps.MaxBitDepth = *report.MaxBitDepth
+	}
+	if len(report.ImageFormats) > 0 {
+		caps.ImageFormats = report.ImageFormats
+	}
+	return caps
+}
@@ -0,0 +1,76 @@
+package database
+
+import "fmt"
+
+// colorDepthColor is the DeviceModel.ColorDepth of color displays
+const colorDepthColor = 24
+
+// DeviceRequirements are the minimum device capabilities a plugin definition needs to render usefully
+type DeviceRequirements struct {
+	MinScreenWidth  int  `json:"min_screen_width,omitempty"`
+	MinScreenHeight int  `json:"min_screen_height,omitempty"`
+	MinBitDepth     int  `json:"min_bit_depth,omitempty"`
+	RequiresColor   bool `json:"requires_color,omitempty"`
+}
+
+// IsEmpty reports whether no requirement is set
+func (r DeviceRequirements) IsEmpty() bool {
+	return r == DeviceRequirements{}
+}
+
+// Validate checks that the requirements are well formed
+func (r DeviceRequirements) Validate() error {
+	if r.MinScreenWidth < 0 || r.MinScreenHeight < 0 {
+		return fmt.Errorf("minimum screen dimensions cannot be negative")
+	}
+	if r.MinBitDepth < 0 || r.MinBitDepth > 8 {
+		return fmt.Errorf("minimum bit depth must be between 0 and 8")
+	}
+	return nil
+}
+
+// UnmetBy returns a description of each requirement the device does not meet. Screen size comes
+// from the device model; color and bit depth use the firmware-reported capabilities when available.
+// Devices without a known model or capability report are not checked.
+func (r DeviceRequirements) UnmetBy(device *Device) []string {
+	if device == nil || (device.DeviceModel == nil && device.ReportedCapabilities == "") {
+		return nil
+	}
+
+	var unmet []string
+	if model := device.DeviceModel; model != nil {
+		if r.MinScreenWidth > 0 && model.ScreenWidth < r.MinScreenWidth {
+			unmet = append(unmet, fmt.Sprintf("screen width of at least %dpx (device has %dpx)", r.MinScreenWidth, model.ScreenWidth))
+		}
+		if r.MinScreenHeight > 0 && model.ScreenHeight < r.MinScreenHeight {
+			unmet = append(unmet, fmt.Sprintf("screen height of at least %dpx (device has %dpx)", r.MinScreenHeight, model.ScreenHeight))
+		}
+	}
+
+	caps := device.EffectiveCapabilities()
+	if r.MinBitDepth > 0 && caps.MaxBitDepth < r.MinBitDepth {
+		unmet = append(unmet, fmt.Sprintf("bit depth of at least %d (device has %d)", r.MinBitDepth, caps.MaxBitDepth))
+	}
+	if r.RequiresColor && !caps.Color {
+		unmet = append(unmet, "color display")
+	}
+	return unmet
+}
+
+// DeviceRequirements returns the minimum device capabilities declared by the plugin definition
+func (pd *PluginDefinition) DeviceRequirements() DeviceRequirements {
+	return DeviceRequirements{
+		MinScreenWidth:  pd.MinScreenWidth,
+		MinScreenHeight: pd.MinScreenHeight,
+		MinBitDepth:     pd.MinBitDepth,
+		RequiresColor:   pd.RequiresColor,
+	}
+}
+
+// SetDeviceRequirements stores the minimum device capabilities on the plugin definition
+func (pd *PluginDefinition) SetDeviceRequirements(r DeviceRequirements) {
+	pd.MinScreenWidth = r.MinScreenWidth
+	pd.MinScreenHeight = r.MinScreenHeight
+	pd.MinBitDepth = r.MinBitDepth
+	pd.RequiresColor = r.RequiresColor
+}
@@ -53,20 +53,33 @@ func (ds *DeviceService) CreateUnclaimedDevice(macAddress string, modelName stri
 		device.ReportedModelName = &mappedModelName
 		
 		// Look up the device model by name
-		var deviceModel DeviceModel
-		// Get the latest active version of this model
-		if err := ds.db.Where("model_name = ? AND is_active = ? AND deleted_at IS NULL", mappedModelName, true).
-			Order("created_at DESC").
-			First(&deviceModel).Error; err == nil {
+		if deviceModel, err := findActiveDeviceModel(ds.db, mappedModelName); err == nil {
 			// Model exists, set the device_model_id
 			device.DeviceModelID = &deviceModel.ID
 		} else {
 			logging.Warn("[CREATE DEVICE] Model not found in device_models table", "model", mappedModelName)
+			deviceModel, err := resolveUnknownModel(ds.db, mappedModelName)
+			if err != nil {
+				return nil, err
+			}
+			if deviceModel != nil {
+				device.DeviceModelID = &deviceModel.ID
+			}
 		}
 	}
 
-	if err := ds.db.Create(device).Error; err != nil {
-		return nil, err
+	for attempt := 1; ; attempt++ {
+		err := ds.db.Create(device).Error
+		if err == nil {
+			break
+		}
+		// Another registration may have taken the same friendly ID between generating and inserting it
+		if taken, _ := ds.friendlyIDTaken(device.FriendlyID); !taken || attempt >= 3 {
+			return nil, err
+		}
+		if device.FriendlyID, err = ds.generateFriendlyID(); err != nil {
+			return nil, err
+		}
 	}
 
 	return device, nil
@@ -96,22 +109,7 @@ func (ds *DeviceService) ClaimDevice(userID uuid.UUID, friendlyID, name string)
 
 // ClaimDeviceByIdentifier claims an unclaimed device for a user using either friendly ID or MAC address
 func (ds *DeviceService) ClaimDeviceByIdentifier(userID uuid.UUID, identifier, name string) (*Device, error) {
-	var device *Device
-	var err error
-	
-	// Detect if the identifier is a MAC address or friendly ID
-	if ds.isMAC(identifier) {
-		// Normalize MAC address to colon format (AA:BB:CC:DD:EE:FF) to match database storage
-		normalizedMAC := ds.normalizeMAC(identifier)
-		logging.Debug("[CLAIM DEVICE] Looking up MAC address", "identifier", identifier, "normalized_mac", normalizedMAC)
-		device, err = ds.GetDeviceByMacAddress(normalizedMAC)
-	} else {
-		// Treat as friendly ID (convert to uppercase for consistency)
-		upperID := strings.ToUpper(identifier)
-		logging.Debug("[CLAIM DEVICE] Looking up friendly ID", "identifier", identifier, "upper_id", upperID)
-		device, err = ds.GetDeviceByFriendlyID(upperID)
-	}
-	
+	device, err := ds.GetDeviceByIdentifier(identifier)
 	if err != nil {
 		logging.Error("[CLAIM DEVICE] Device lookup failed", "error", err)
 		return nil, err
@@ -134,6 +132,27 @@ func (ds *DeviceService) ClaimDeviceByIdentifier(userID uuid.UUID, identifier, n
 	return device, nil
 }
 
+// GetDeviceByIdentifier looks up a device by either friendly ID or MAC address
+func (ds *DeviceService) GetDeviceByIdentifier(identifier string) (*Device, error) {
+	var device *Device
+	var err error
+	
+	// Detect if the identifier is a MAC address or friendly ID
+	if ds.isMAC(identifier) {
+		// Normalize MAC address to colon format (AA:BB:CC:DD:EE:FF) to match database storage
+		normalizedMAC := ds.normalizeMAC(identifier)
+		logging.Debug("[CLAIM DEVICE] Looking up MAC address", "identifier", identifier, "normalized_mac", normalizedMAC)
+		device, err = ds.GetDeviceByMacAddress(normalizedMAC)
+	} else {
+		// Treat as friendly ID (convert to uppercase for consistency)
+		upperID := strings.ToUpper(identifier)
+		logging.Debug("[CLAIM DEVICE] Looking up friendly ID", "identifier", identifier, "upper_id", upperID)
+		device, err = ds.GetDeviceByFriendlyID(upperID)
+	}
+	
+	return device, err
+}
+
 // ImportDevice creates a new device with provided credentials and immediately claims it to a user
 func (ds *DeviceService) ImportDevice(userID uuid.UUID, macAddress, apiKey, friendlyID, name, modelName string) (*Device, error) {
 	normalizedMAC := ds.normalizeMAC(macAddress)
@@ -308,6 +327,15 @@ func (ds *DeviceService) ValidateDeviceModelByID(modelID uint) (*DeviceModel, er
 	return &deviceModel, nil
 }
 
+// UpdateDeviceModelGrayscaleLevels sets the gray levels a device model quantizes to; 0 uses every level of its bit depth
+func (ds *DeviceService) UpdateDeviceModelGrayscaleLevels(deviceModel *DeviceModel, levels int) error {
+	if err := ds.db.Model(deviceModel).Update("grayscale_levels", levels).Error; err != nil {
+		return err
+	}
+	deviceModel.GrayscaleLevels = levels
+	return nil
+}
+
 // GetDeviceModelByName finds a device model by name and returns it
 func (ds *DeviceService) GetDeviceModelByName(modelName string) (*DeviceModel, error) {
 	if modelName == "" {
@@ -361,15 +389,80 @@ func mapDeviceModelName(deviceModel string) string {
 	return deviceModel
 }
 
-// UpdateLastPlaylistItemID updates the last shown playlist item UUID for stable rotation
+// UpdateLastPlaylistItemID updates the last shown playlist item UUID for stable rotation.
+// The display count is incremented when the same item is served again and reset otherwise.
 func (ds *DeviceService) UpdateLastPlaylistItemID(deviceID uuid.UUID, playlistItemID uuid.UUID) error {
-	result := ds.db.Model(&Device{}).Where("id = ?", deviceID).Update("last_playlist_item_id", playlistItemID)
+	result := ds.db.Model(&Device{}).Where("id = ?", deviceID).Updates(map[string]interface{}{
+		"last_playlist_item_display_count": gorm.Expr("CASE WHEN last_playlist_item_id = ? THEN last_playlist_item_display_count + 1 ELSE 1 END", playlistItemID),
+		"last_playlist_item_id":            playlistItemID,
+		"hold_current_item":                false,
+	})
 	if result.Error != nil {
 		return result.Error
 	}
 	return nil
 }
 
+// SetCurrentPlaylistItem pins the given playlist item as current so the device's next check-in serves it
+func (ds *DeviceService) SetCurrentPlaylistItem(deviceID uuid.UUID, playlistItemID uuid.UUID) error {
+	return ds.db.Model(&Device{}).Where("id = ?", deviceID).Updates(map[string]interface{}{
+		"last_playlist_item_id":            playlistItemID,
+		"last_playlist_item_display_count": 0,
+		"hold_current_item":                true,
+	}).Error
+}
+
+const (
+	// maxDeviceResolutionVariants is how many reported resolutions are kept per device. Every variant is
+	// rendered for each of the device's instances, so older ones are dropped.
+	maxDeviceResolutionVariants = 3
+	// MaxDeviceResolutionDimension is the largest width or height accepted as a resolution variant
+	MaxDeviceResolutionDimension = 4096
+)
+
+// RecordDeviceResolution stores or refreshes a resolution variant reported by a device, keeping only the
+// most recently reported maxDeviceResolutionVariants
+func (ds *DeviceService) RecordDeviceResolution(deviceID uuid.UUID, width, height, bitDepth int) error {
+	now := time.Now().UTC()
+
+	result := ds.db.Model(&DeviceResolution{}).
+		Where("device_id = ? AND width = ? AND height = ? AND bit_depth = ?", deviceID, width, height, bitDepth).
+		Update("last_seen_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	if err := ds.db.Create(&DeviceResolution{
+		DeviceID:   deviceID,
+		Width:      width,
+		Height:     height,
+		BitDepth:   bitDepth,
+		LastSeenAt: now,
+	}).Error; err != nil {
+		return err
+	}
+
+	var keepIDs []uuid.UUID
+	if err := ds.db.Model(&DeviceResolution{}).Where("device_id = ?", deviceID).
+		Order("last_seen_at DESC").Limit(maxDeviceResolutionVariants).Pluck("id", &keepIDs).Error; err != nil {
+		return err
+	}
+	return ds.db.Where("device_id = ? AND id NOT IN ?", deviceID, keepIDs).Delete(&DeviceResolution{}).Error
+}
+
+// GetRecentDeviceResolutions returns the resolution variants a device has reported since the given time
+func (ds *DeviceService) GetRecentDeviceResolutions(deviceID uuid.UUID, since time.Time) ([]DeviceResolution, error) {
+	var resolutions []DeviceResolution
+	err := ds.db.Where("device_id = ? AND last_seen_at >= ?", deviceID, since).
+		Order("last_seen_at DESC").
+		Limit(maxDeviceResolutionVariants).
+		Find(&resolutions).Error
+	return resolutions, err
+}
+
 // UpdateDeviceStatus updates device status information from TRMNL requests
 func (ds *DeviceService) UpdateDeviceStatus(macAddress string, firmwareVersion string, batteryVoltage float64, batteryPercent int, rssi int, modelName string) error {
 	now := time.Now().UTC()
@@ -399,16 +492,19 @@ func (ds *DeviceService) UpdateDeviceStatus(macAddress string, firmwareVersion s
 				if !device.ManualModelOverride {
 					// Only update device_model_id if it's not manually overridden
 					// Look up the device model by name to get its ID
-					var deviceModel DeviceModel
-					// Get the latest active version of this model
-					if err := tx.Where("model_name = ? AND is_active = ? AND deleted_at IS NULL", mappedModelName, true).
-						Order("created_at DESC").
-						First(&deviceModel).Error; err == nil {
+					if deviceModel, err := findActiveDeviceModel(tx, mappedModelName); err == nil {
 						// Model exists, update the device_model_id
 						updateFields["device_model_id"] = deviceModel.ID
 						selectFields = append(selectFields, "device_model_id")
 					} else {
 						logging.Warn("[DEVICE UPDATE] Model not found in device_models table", "model", mappedModelName)
+						// Only fill in a missing model; a device keeps its current model if it starts reporting an unknown one
+						if device.DeviceModelID == nil {
+							if deviceModel, err := resolveUnknownModel(tx, mappedModelName); err == nil && deviceModel != nil {
+								updateFields["device_model_id"] = deviceModel.ID
+								selectFields = append(selectFields, "device_model_id")
+							}
+						}
 					}
 				} else {
 					logging.Debug("[DEVICE UPDATE] Device has manual model override, not updating model")
@@ -443,6 +539,9 @@ func (ds *DeviceService) GetUnclaimedDevices() ([]Device, error) {
 // DeleteDevice deletes a device and all associated data
 func (ds *DeviceService) DeleteDevice(deviceID uuid.UUID) error {
 	return ds.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("device_id = ?", deviceID).Delete(&DeviceResolution{}).Error; err != nil {
+			return fmt.Errorf("failed to delete device resolutions: %w", err)
+		}
 		// Delete device will cascade to playlists, playlist items, and schedules
 		return tx.Delete(&Device{}, "id = ?", deviceID).Error
 	})
@@ -493,10 +592,324 @@ func (ds *DeviceService) UnlinkDevice(deviceID uuid.UUID) error {
 
 func (ds *DeviceService) AdminDeleteDevice(deviceID uuid.UUID) error {
 	return ds.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("device_id = ?", deviceID).Delete(&DeviceResolution{}).Error; err != nil {
+			return fmt.Errorf("failed to delete device resolutions: %w", err)
+		}
 		return tx.Delete(&Device{}, "id = ?", deviceID).Error
 	})
 }
 
+// DeviceReassignResult summarizes what moved when a device changed owner
+type DeviceReassignResult struct {
+	PreviousUserID       *uuid.UUID `json:"previous_user_id"`
+	NewUserID            uuid.UUID  `json:"new_user_id"`
+	PlaylistsTransferred int        `json:"playlists_transferred"`
+	PlaylistsRemoved     int        `json:"playlists_removed"`
+	InstancesTransferred int        `json:"instances_transferred"`
+	InstancesCopied      int        `json:"instances_copied"`
+	DefinitionsCopied    int        `json:"definitions_copied"`
+}
+
+// ReassignDevice moves a device to another user. When transferContent is set, the device's
+// playlists move with it; plugin instances used only by this device are transferred and
+// instances the previous owner still uses elsewhere are copied for the new owner. Private and mashup
+// definitions owned by the previous owner are copied so the new owner can edit them.
+// Otherwise the playlists are removed, as when a device is unlinked.
+func (ds *DeviceService) ReassignDevice(deviceID, newUserID uuid.UUID, transferContent bool) (*DeviceReassignResult, error) {
+	result := &DeviceReassignResult{NewUserID: newUserID}
+
+	err := ds.db.Transaction(func(tx *gorm.DB) error {
+		var device Device
+		if err := tx.First(&device, "id = ?", deviceID).Error; err != nil {
+			return fmt.Errorf("device not found: %w", err)
+		}
+
+		var newUser User
+		if err := tx.First(&newUser, "id = ?", newUserID).Error; err != nil {
+			return fmt.Errorf("target user not found: %w", err)
+		}
+		result.PreviousUserID = device.UserID
+
+		var playlists []Playlist
+		if err := tx.Where("device_id = ?", deviceID).Find(&playlists).Error; err != nil {
+			return fmt.Errorf("failed to load playlists: %w", err)
+		}
+
+		if !transferContent || device.UserID == nil {
+			if err := tx.Where("device_id = ?", deviceID).Delete(&Playlist{}).Error; err != nil {
+				return fmt.Errorf("failed to delete playlists: %w", err)
+			}
+			result.PlaylistsRemoved = len(playlists)
+		} else {
+			if err := ds.transferDevicePlaylists(tx, deviceID, *device.UserID, newUserID, playlists, result); err != nil {
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{
+			"user_id":    newUserID,
+			"is_claimed": true,
+		}
+		if err := tx.Model(&Device{}).Where("id = ?", deviceID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to reassign device: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// instanceTransfer tracks the definitions and plugin instances a device reassignment has already handed
+// to the new owner, so ones shared by several playlist items or mashups are moved or copied once
+type instanceTransfer struct {
+	tx          *gorm.DB
+	oldUserID   uuid.UUID
+	newUserID   uuid.UUID
+	definitions map[string]string       // Previous definition ID -> definition ID the new owner uses
+	instances   map[uuid.UUID]uuid.UUID // Previous instance ID -> instance ID the new owner uses
+	result      *DeviceReassignResult
+}
+
+// transferDevicePlaylists moves a device's playlists and the plugin instances they reference to a new owner
+func (ds *DeviceService) transferDevicePlaylists(tx *gorm.DB, deviceID, oldUserID, newUserID uuid.UUID, playlists []Playlist, result *DeviceReassignResult) error {
+	if len(playlists) == 0 {
+		return nil
+	}
+
+	playlistIDs := make([]uuid.UUID, 0, len(playlists))
+	for _, playlist := range playlists {
+		playlistIDs = append(playlistIDs, playlist.ID)
+	}
+
+	var instanceIDs []uuid.UUID
+	if err := tx.Model(&PlaylistItem{}).
+		Where("playlist_id IN ?", playlistIDs).
+		Distinct("plugin_instance_id").
+		Pluck("plugin_instance_id", &instanceIDs).Error; err != nil {
+		return fmt.Errorf("failed to load playlist plugin instances: %w", err)
+	}
+
+	transfer := &instanceTransfer{
+		tx:          tx,
+		oldUserID:   oldUserID,
+		newUserID:   newUserID,
+		definitions: make(map[string]string),
+		instances:   make(map[uuid.UUID]uuid.UUID),
+		result:      result,
+	}
+
+	for _, instanceID := range instanceIDs {
+		// Instances still shown on another of the previous owner's devices or in one of their mashups stay with them
+		var otherUses int64
+		if err := tx.Model(&PlaylistItem{}).
+			Where("plugin_instance_id = ? AND playlist_id NOT IN ?", instanceID, playlistIDs).
+			Count(&otherUses).Error; err != nil {
+			return fmt.Errorf("failed to check plugin instance usage: %w", err)
+		}
+		var mashupUses int64
+		if err := tx.Model(&MashupChild{}).Where("child_instance_id = ?", instanceID).Count(&mashupUses).Error; err != nil {
+			return fmt.Errorf("failed to check plugin instance usage: %w", err)
+		}
+
+		targetID, err := transfer.instance(instanceID, otherUses+mashupUses > 0)
+		if err != nil {
+			return err
+		}
+		if targetID == instanceID {
+			continue
+		}
+		if err := tx.Model(&PlaylistItem{}).
+			Where("plugin_instance_id = ? AND playlist_id IN ?", instanceID, playlistIDs).
+			Update("plugin_instance_id", targetID).Error; err != nil {
+			return fmt.Errorf("failed to repoint playlist items: %w", err)
+		}
+	}
+
+	if err := tx.Model(&Playlist{}).Where("device_id = ?", deviceID).Update("user_id", newUserID).Error; err != nil {
+		return fmt.Errorf("failed to transfer playlists: %w", err)
+	}
+	result.PlaylistsTransferred = len(playlists)
+
+	return nil
+}
+
+// instance hands a plugin instance of the previous owner to the new owner and returns the ID the new
+// owner's references should use. When keepOriginal is set the instance is copied, along with its polling
+// and webhook data; otherwise it is moved. Instances owned by anyone else are left alone.
+func (t *instanceTransfer) instance(instanceID uuid.UUID, keepOriginal bool) (uuid.UUID, error) {
+	if targetID, ok := t.instances[instanceID]; ok {
+		return targetID, nil
+	}
+
+	var instance PluginInstance
+	if err := t.tx.First(&instance, "id = ?", instanceID).Error; err != nil {
+		return uuid.Nil, fmt.Errorf("failed to load plugin instance %s: %w", instanceID, err)
+	}
+	if instance.UserID != t.oldUserID {
+		t.instances[instanceID] = instanceID
+		return instanceID, nil
+	}
+
+	definitionID, err := t.definition(instance.PluginDefinitionID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	targetID := instanceID
+	if !keepOriginal {
+		updates := map[string]interface{}{
+			"user_id":              t.newUserID,
+			"plugin_definition_id": definitionID,
+		}
+		if err := t.tx.Model(&PluginInstance{}).Where("id = ?", instanceID).Updates(updates).Error; err != nil {
+			return uuid.Nil, fmt.Errorf("failed to transfer plugin instance: %w", err)
+		}
+		t.result.InstancesTransferred++
+	} else {
+		copied := PluginInstance{
+			UserID:                 t.newUserID,
+			PluginDefinitionID:     definitionID,
+			Name:                   instance.Name,
+			Settings:               instance.Settings,
+			RefreshInterval:        instance.RefreshInterval,
+			IsActive:               instance.IsActive,
+			LastSchemaVersion:      instance.LastSchemaVersion,
+			NeedsConfigUpdate:      instance.NeedsConfigUpdate,
+			WebhookCoalesceSeconds: instance.WebhookCoalesceSeconds,
+			SettingsProfiles:       instance.SettingsProfiles,
+			ActiveProfile:          instance.ActiveProfile,
+		}
+		if err := t.tx.Create(&copied).Error; err != nil {
+			return uuid.Nil, fmt.Errorf("failed to copy plugin instance: %w", err)
+		}
+		if err := t.copyInstanceData(instanceID, copied.ID); err != nil {
+			return uuid.Nil, err
+		}
+		targetID = copied.ID
+		t.result.InstancesCopied++
+	}
+	t.instances[instanceID] = targetID
+
+	if err := t.mashupChildren(instanceID, targetID); err != nil {
+		return uuid.Nil, err
+	}
+	return targetID, nil
+}
+
+// definition returns the definition ID the new owner's instances should use. Definitions owned by the
+// previous owner are copied for the new owner; system, external and other users' definitions are shared.
+func (t *instanceTransfer) definition(definitionID string) (string, error) {
+	if targetID, ok := t.definitions[definitionID]; ok {
+		return targetID, nil
+	}
+
+	var definition PluginDefinition
+	if err := t.tx.First(&definition, "id = ?", definitionID).Error; err != nil {
+		return "", fmt.Errorf("failed to load plugin definition %s: %w", definitionID, err)
+	}
+	if definition.OwnerID == nil || *definition.OwnerID != t.oldUserID {
+		t.definitions[definitionID] = definitionID
+		return definitionID, nil
+	}
+
+	copied := definition
+	copied.ID = ""
+	copied.Identifier = uuid.New().String()
+	copied.OwnerID = &t.newUserID
+	copied.IsPublished = false
+	copied.PublishedAt = nil
+	copied.CreatedAt = time.Time{}
+	copied.UpdatedAt = time.Time{}
+	copied.Owner = nil
+	copied.Instances = nil
+	if err := t.tx.Create(&copied).Error; err != nil {
+		return "", fmt.Errorf("failed to copy plugin definition: %w", err)
+	}
+
+	t.definitions[definitionID] = copied.ID
+	t.result.DefinitionsCopied++
+	return copied.ID, nil
+}
+
+// mashupChildren hands the children of a mashup instance to the new owner. When the mashup was copied,
+// its copy gets child rows of its own; when it was moved, its rows are repointed at the new owner's children.
+func (t *instanceTransfer) mashupChildren(mashupID, targetID uuid.UUID) error {
+	var children []MashupChild
+	if err := t.tx.Where("mashup_instance_id = ?", mashupID).Find(&children).Error; err != nil {
+		return fmt.Errorf("failed to load mashup children: %w", err)
+	}
+
+	for _, child := range children {
+		// A child the previous owner still uses elsewhere, including in the original mashup, is copied
+		var playlistUses, mashupUses int64
+		if err := t.tx.Model(&PlaylistItem{}).Where("plugin_instance_id = ?", child.ChildInstanceID).Count(&playlistUses).Error; err != nil {
+			return fmt.Errorf("failed to check mashup child usage: %w", err)
+		}
+		if err := t.tx.Model(&MashupChild{}).
+			Where("child_instance_id = ? AND mashup_instance_id <> ?", child.ChildInstanceID, mashupID).
+			Count(&mashupUses).Error; err != nil {
+			return fmt.Errorf("failed to check mashup child usage: %w", err)
+		}
+		keepOriginal := targetID != mashupID || playlistUses+mashupUses > 0
+
+		childID, err := t.instance(child.ChildInstanceID, keepOriginal)
+		if err != nil {
+			return err
+		}
+
+		if targetID == mashupID {
+			if childID != child.ChildInstanceID {
+				if err := t.tx.Model(&MashupChild{}).Where("id = ?", child.ID).Update("child_instance_id", childID).Error; err != nil {
+					return fmt.Errorf("failed to repoint mashup child: %w", err)
+				}
+			}
+			continue
+		}
+
+		copiedChild := MashupChild{
+			MashupInstanceID: targetID,
+			ChildInstanceID:  childID,
+			SlotPosition:     child.SlotPosition,
+		}
+		if err := t.tx.Create(&copiedChild).Error; err != nil {
+			return fmt.Errorf("failed to copy mashup child: %w", err)
+		}
+	}
+	return nil
+}
+
+// copyInstanceData copies the stored polling and webhook data of a plugin instance to its copy
+func (t *instanceTransfer) copyInstanceData(fromID, toID uuid.UUID) error {
+	var pollingData []PrivatePluginPollingData
+	if err := t.tx.Where("plugin_instance_id = ?", fromID.String()).Find(&pollingData).Error; err != nil {
+		return fmt.Errorf("failed to load polling data: %w", err)
+	}
+	for _, data := range pollingData {
+		data.ID = toID.String() + "_polling_data"
+		data.PluginInstanceID = toID.String()
+		if err := t.tx.Create(&data).Error; err != nil {
+			return fmt.Errorf("failed to copy polling data: %w", err)
+		}
+	}
+
+	var webhookData []PrivatePluginWebhookData
+	if err := t.tx.Where("plugin_instance_id = ?", fromID.String()).Find(&webhookData).Error; err != nil {
+		return fmt.Errorf("failed to load webhook data: %w", err)
+	}
+	for _, data := range webhookData {
+		data.ID = toID.String() + "_webhook_data"
+		data.PluginInstanceID = toID.String()
+		if err := t.tx.Create(&data).Error; err != nil {
+			return fmt.Errorf("failed to copy webhook data: %w", err)
+		}
+	}
+	return nil
+}
+
 // GetAllDevices returns all devices in the system (admin only)
 func (ds *DeviceService) GetAllDevices() ([]Device, error) {
 	var devices []Device
@@ -540,36 +953,6 @@ func generateAPIKey() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// generateFriendlyID generates a unique 6-character friendly ID for a device
-func (ds *DeviceService) generateFriendlyID() (string, error) {
-	for attempts := 0; attempts < 100; attempts++ {
-		// Generate 3 random bytes for a 6-character hex string
-		bytes := make([]byte, 3)
-		if _, err := rand.Read(bytes); err != nil {
-			return "", err
-		}
-
-		friendlyID := hex.EncodeToString(bytes)
-		friendlyID = friendlyID[:6]              // Ensure exactly 6 characters
-		friendlyID = strings.ToUpper(friendlyID) // Convert to uppercase like "917F0B"
-
-		// Check if this ID already exists
-		var existingDevice Device
-		err := ds.db.Where("friendly_id = ?", friendlyID).First(&existingDevice).Error
-		if err != nil {
-			if err.Error() == "record not found" {
-				// ID is unique, we can use it
-				return friendlyID, nil
-			}
-			// Some other database error
-			return "", err
-		}
-		// ID already exists, try again
-	}
-
-	return "", fmt.Errorf("failed to generate unique friendly ID after 100 attempts")
-}
-
 // CreateDeviceLog stores a new log entry for a device
 func (ds *DeviceService) CreateDeviceLog(deviceID uuid.UUID, logData string, level string) (*DeviceLog, error) {
 	if level == "" {
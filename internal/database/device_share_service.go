@@ -0,0 +1,73 @@
+package database
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeviceShareService handles public share links for device screens
+type DeviceShareService struct {
+	db *gorm.DB
+}
+
+// NewDeviceShareService creates a new device share service
+func NewDeviceShareService(db *gorm.DB) *DeviceShareService {
+	return &DeviceShareService{db: db}
+}
+
+// CreateShareLink generates a new public token for a device's current screen
+func (s *DeviceShareService) CreateShareLink(deviceID, userID uuid.UUID, name string) (*DeviceShareLink, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	link := &DeviceShareLink{
+		DeviceID: deviceID,
+		UserID:   userID,
+		Token:    fmt.Sprintf("%x", tokenBytes),
+		Name:     name,
+	}
+	if err := s.db.Create(link).Error; err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	return link, nil
+}
+
+// GetShareLinksForDevice returns all share links for a device, newest first
+func (s *DeviceShareService) GetShareLinksForDevice(deviceID uuid.UUID) ([]DeviceShareLink, error) {
+	var links []DeviceShareLink
+	err := s.db.Where("device_id = ?", deviceID).Order("created_at DESC").Find(&links).Error
+	return links, err
+}
+
+// GetShareLinkByToken looks up a share link by its public token
+func (s *DeviceShareService) GetShareLinkByToken(token string) (*DeviceShareLink, error) {
+	var link DeviceShareLink
+	if err := s.db.Where("token = ?", token).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// RevokeShareLink deletes a share link so its token stops working
+func (s *DeviceShareService) RevokeShareLink(deviceID, linkID uuid.UUID) error {
+	result := s.db.Where("id = ? AND device_id = ?", linkID, deviceID).Delete(&DeviceShareLink{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TouchShareLink records that a share link was just used
+func (s *DeviceShareService) TouchShareLink(linkID uuid.UUID) error {
+	return s.db.Model(&DeviceShareLink{}).Where("id = ?", linkID).Update("last_accessed_at", time.Now().UTC()).Error
+}
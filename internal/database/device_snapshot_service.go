@@ -0,0 +1,49 @@
+package database
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeviceSnapshotService handles saved device screen snapshots
+type DeviceSnapshotService struct {
+	db *gorm.DB
+}
+
+// NewDeviceSnapshotService creates a new device snapshot service
+func NewDeviceSnapshotService(db *gorm.DB) *DeviceSnapshotService {
+	return &DeviceSnapshotService{db: db}
+}
+
+// CreateSnapshot records a snapshot whose image has already been saved
+func (s *DeviceSnapshotService) CreateSnapshot(snapshot *DeviceSnapshot) error {
+	return s.db.Create(snapshot).Error
+}
+
+// GetSnapshotsForDevice returns a device's snapshots, newest first
+func (s *DeviceSnapshotService) GetSnapshotsForDevice(deviceID uuid.UUID) ([]DeviceSnapshot, error) {
+	var snapshots []DeviceSnapshot
+	err := s.db.Where("device_id = ?", deviceID).Order("created_at DESC").Find(&snapshots).Error
+	return snapshots, err
+}
+
+// GetSnapshot returns one of a device's snapshots
+func (s *DeviceSnapshotService) GetSnapshot(deviceID, snapshotID uuid.UUID) (*DeviceSnapshot, error) {
+	var snapshot DeviceSnapshot
+	if err := s.db.Where("id = ? AND device_id = ?", snapshotID, deviceID).First(&snapshot).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// DeleteSnapshot removes a snapshot record and returns it so the caller can remove its image
+func (s *DeviceSnapshotService) DeleteSnapshot(deviceID, snapshotID uuid.UUID) (*DeviceSnapshot, error) {
+	snapshot, err := s.GetSnapshot(deviceID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Delete(snapshot).Error; err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -39,6 +40,15 @@ func (s *FirmwareService) GetFirmwareVersionByVersion(version string) (*Firmware
 	return &fwVersion, nil
 }
 
+func (s *FirmwareService) GetFirmwareVersionForFamily(version, family string) (*FirmwareVersion, error) {
+	var fwVersion FirmwareVersion
+	err := s.db.Where("version = ? AND model_family = ?", version, family).First(&fwVersion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &fwVersion, nil
+}
+
 func (s *FirmwareService) GetLatestFirmwareVersion() (*FirmwareVersion, error) {
 	var version FirmwareVersion
 	err := s.db.Where("is_latest = ? AND model_family = ?", true, "trmnl").First(&version).Error
@@ -57,6 +67,34 @@ func (s *FirmwareService) GetLatestFirmwareVersionForFamily(family string) (*Fir
 	return &version, nil
 }
 
+// GetLatestFirmwareVersionForChannel returns the most recently released version of a family that a
+// device on the given channel may receive. An empty channel is treated as stable.
+func (s *FirmwareService) GetLatestFirmwareVersionForChannel(family, channel string) (*FirmwareVersion, error) {
+	var version FirmwareVersion
+	err := s.db.Where("model_family = ? AND channel IN ?", family, firmwareChannelsFor(channel)).
+		Order("released_at DESC").
+		First(&version).Error
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// SetFirmwareVersionChannel moves a firmware version to another release channel
+func (s *FirmwareService) SetFirmwareVersionChannel(id uuid.UUID, channel string) error {
+	if !IsValidFirmwareChannel(channel) {
+		return fmt.Errorf("invalid firmware channel: %s", channel)
+	}
+	result := s.db.Model(&FirmwareVersion{}).Where("id = ?", id).Update("channel", channel)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (s *FirmwareService) GetFirmwareFamilies() ([]string, error) {
 	var families []string
 	err := s.db.Model(&FirmwareVersion{}).Distinct("model_family").Pluck("model_family", &families).Error
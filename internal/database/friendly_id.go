@@ -0,0 +1,146 @@
+package database
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultFriendlyIDLength   = 6
+	defaultFriendlyIDAlphabet = "0123456789ABCDEF"
+	minFriendlyIDLength       = 4
+	// maxFriendlyIDTotalLength matches the size of the friendly_id column
+	maxFriendlyIDTotalLength = 32
+	friendlyIDAttempts       = 100
+)
+
+// friendlyIDPrefixPattern limits prefixes to characters that survive the upper-casing done on lookup
+var friendlyIDPrefixPattern = regexp.MustCompile(`^[A-Z0-9-]*$`)
+
+// FriendlyIDFormat describes how new device friendly IDs are generated
+type FriendlyIDFormat struct {
+	Prefix   string
+	Length   int
+	Alphabet string
+}
+
+// LoadFriendlyIDFormat reads FRIENDLY_ID_PREFIX, FRIENDLY_ID_LENGTH and FRIENDLY_ID_ALPHABET
+func LoadFriendlyIDFormat() (FriendlyIDFormat, error) {
+	format := FriendlyIDFormat{
+		Prefix:   strings.ToUpper(strings.TrimSpace(config.Get("FRIENDLY_ID_PREFIX", ""))),
+		Length:   config.GetInt("FRIENDLY_ID_LENGTH", defaultFriendlyIDLength),
+		Alphabet: strings.ToUpper(config.Get("FRIENDLY_ID_ALPHABET", defaultFriendlyIDAlphabet)),
+	}
+	return format, format.Validate()
+}
+
+// Validate checks that the format can produce IDs that fit the column and can be looked up again
+func (f FriendlyIDFormat) Validate() error {
+	if !friendlyIDPrefixPattern.MatchString(f.Prefix) {
+		return fmt.Errorf("FRIENDLY_ID_PREFIX may only contain letters, digits and dashes")
+	}
+	if f.Length < minFriendlyIDLength {
+		return fmt.Errorf("FRIENDLY_ID_LENGTH must be at least %d", minFriendlyIDLength)
+	}
+	if len(f.Prefix)+f.Length > maxFriendlyIDTotalLength {
+		return fmt.Errorf("FRIENDLY_ID_PREFIX and FRIENDLY_ID_LENGTH together must not exceed %d characters", maxFriendlyIDTotalLength)
+	}
+
+	seen := make(map[rune]bool)
+	for _, r := range f.Alphabet {
+		if !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') {
+			return fmt.Errorf("FRIENDLY_ID_ALPHABET may only contain letters and digits")
+		}
+		if seen[r] {
+			return fmt.Errorf("FRIENDLY_ID_ALPHABET contains %q more than once", r)
+		}
+		seen[r] = true
+	}
+	if len(seen) < 2 {
+		return fmt.Errorf("FRIENDLY_ID_ALPHABET must contain at least 2 characters")
+	}
+	return nil
+}
+
+var (
+	friendlyIDFormatOnce sync.Once
+	friendlyIDFormat     FriendlyIDFormat
+)
+
+// currentFriendlyIDFormat returns the configured format, falling back to the default 6-character hex
+// IDs when the configuration is invalid so devices can still register
+func currentFriendlyIDFormat() FriendlyIDFormat {
+	friendlyIDFormatOnce.Do(func() {
+		format, err := LoadFriendlyIDFormat()
+		if err != nil {
+			logging.Error("[FRIENDLY ID] Invalid friendly ID format, using the default", "error", err)
+			format = FriendlyIDFormat{Length: defaultFriendlyIDLength, Alphabet: defaultFriendlyIDAlphabet}
+		}
+		friendlyIDFormat = format
+	})
+	return friendlyIDFormat
+}
+
+// Generate returns a random ID in this format
+func (f FriendlyIDFormat) Generate() (string, error) {
+	alphabet := []rune(f.Alphabet)
+	max := big.NewInt(int64(len(alphabet)))
+
+	var b strings.Builder
+	b.WriteString(f.Prefix)
+	for i := 0; i < f.Length; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteRune(alphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// generateFriendlyID generates a unique friendly ID for a device in the configured format. IDs that
+// would be mistaken for a MAC address when claiming are skipped.
+func (ds *DeviceService) generateFriendlyID() (string, error) {
+	format := currentFriendlyIDFormat()
+	for attempts := 0; attempts < friendlyIDAttempts; attempts++ {
+		friendlyID, err := format.Generate()
+		if err != nil {
+			return "", err
+		}
+		if ds.isMAC(friendlyID) {
+			continue
+		}
+
+		taken, err := ds.friendlyIDTaken(friendlyID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return friendlyID, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to generate unique friendly ID after %d attempts", friendlyIDAttempts)
+}
+
+// friendlyIDTaken reports whether a device already uses the friendly ID
+func (ds *DeviceService) friendlyIDTaken(friendlyID string) (bool, error) {
+	var existingDevice Device
+	err := ds.db.Select("id").Where("friendly_id = ?", friendlyID).First(&existingDevice).Error
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return false, err
+}
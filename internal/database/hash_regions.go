@@ -0,0 +1,60 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// maxHashIgnoreRegions limits how many regions a plugin definition can exclude from change detection
+const maxHashIgnoreRegions = 20
+
+// HashIgnoreRegion is a rectangle of the rendered image, in device pixels, whose pixels don't count
+// when deciding whether content changed (a live clock, for example)
+type HashIgnoreRegion struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ValidateHashIgnoreRegions checks that the regions are well formed
+func ValidateHashIgnoreRegions(regions []HashIgnoreRegion) error {
+	if len(regions) > maxHashIgnoreRegions {
+		return fmt.Errorf("at most %d hash ignore regions are allowed", maxHashIgnoreRegions)
+	}
+	for i, region := range regions {
+		if region.X < 0 || region.Y < 0 {
+			return fmt.Errorf("region %d: x and y cannot be negative", i+1)
+		}
+		if region.Width <= 0 || region.Height <= 0 {
+			return fmt.Errorf("region %d: width and height must be positive", i+1)
+		}
+	}
+	return nil
+}
+
+// HashIgnoreRegions returns the regions the plugin definition excludes from content hashing
+func (pd *PluginDefinition) HashIgnoreRegions() []HashIgnoreRegion {
+	if len(pd.HashIgnoreRegionsJSON) == 0 {
+		return nil
+	}
+	var regions []HashIgnoreRegion
+	if err := json.Unmarshal(pd.HashIgnoreRegionsJSON, &regions); err != nil {
+		return nil
+	}
+	return regions
+}
+
+// SetHashIgnoreRegions stores the regions excluded from content hashing; an empty list clears them
+func (pd *PluginDefinition) SetHashIgnoreRegions(regions []HashIgnoreRegion) error {
+	if len(regions) == 0 {
+		pd.HashIgnoreRegionsJSON = nil
+		return nil
+	}
+	data, err := json.Marshal(regions)
+	if err != nil {
+		return err
+	}
+	pd.HashIgnoreRegionsJSON = data
+	return nil
+}
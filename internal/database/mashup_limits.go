@@ -0,0 +1,71 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// MashupMaxSlotsSettingKey is the system setting limiting how many slots a mashup layout may have
+	MashupMaxSlotsSettingKey = "mashup_max_slots"
+	// MashupMaxChildRendersSettingKey is the system setting limiting a mashup's combined child renders per hour
+	MashupMaxChildRendersSettingKey = "mashup_max_child_renders_per_hour"
+
+	defaultMashupMaxSlots = 4
+	// mashupFrequentRefreshSeconds is the refresh interval below which a mashup is flagged as rendering often
+	mashupFrequentRefreshSeconds = 300
+)
+
+// MashupMaxSlots returns the largest slot count allowed for new mashups
+func MashupMaxSlots() int {
+	value, err := GetSystemSetting(MashupMaxSlotsSettingKey)
+	if err != nil {
+		return defaultMashupMaxSlots
+	}
+	slots, err := strconv.Atoi(value)
+	if err != nil || slots < 1 {
+		return defaultMashupMaxSlots
+	}
+	return slots
+}
+
+// MashupMaxChildRendersPerHour returns the limit on a mashup's combined child renders per hour, 0 for no limit
+func MashupMaxChildRendersPerHour() int {
+	value, err := GetSystemSetting(MashupMaxChildRendersSettingKey)
+	if err != nil {
+		return 0
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// MashupRenderLoad returns how many renders per hour a set of children cause, from their refresh intervals
+func MashupRenderLoad(children []PluginInstance) float64 {
+	var load float64
+	for _, child := range children {
+		if child.RefreshInterval > 0 {
+			load += 3600 / float64(child.RefreshInterval)
+		}
+	}
+	return load
+}
+
+// CheckMashupRenderLoad validates the children for a mashup against the configured render load limit. The
+// returned warnings flag children that make the mashup re-render often even when the limit isn't exceeded.
+func CheckMashupRenderLoad(children []PluginInstance) (warnings []string, err error) {
+	load := MashupRenderLoad(children)
+	if limit := MashupMaxChildRendersPerHour(); limit > 0 && load > float64(limit) {
+		return nil, fmt.Errorf("children would cause %.0f renders per hour, more than the limit of %d. Use children with longer refresh intervals", load, limit)
+	}
+
+	warnings = []string{}
+	for _, child := range children {
+		if child.RefreshInterval > 0 && child.RefreshInterval < mashupFrequentRefreshSeconds {
+			warnings = append(warnings, fmt.Sprintf("%q refreshes every %d seconds, so the mashup will re-render that often", child.Name, child.RefreshInterval))
+		}
+	}
+	return warnings, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -19,16 +20,26 @@ func NewMashupService(db *gorm.DB) *MashupService {
 	return &MashupService{db: db}
 }
 
-// MashupSlotInfo defines metadata for a mashup slot
+// MaxMashupSlotGutter is the largest spacing in pixels allowed between mashup slots
+const MaxMashupSlotGutter = 64
+
+// MashupSlotInfo defines metadata for a mashup slot.
+// Geometry is expressed as fractions of the full screen so the frontend and
+// the compositing renderer can lay slots out identically at any resolution.
 type MashupSlotInfo struct {
-	Position     string `json:"position"`      // Slot identifier like "left", "right", "q1", etc.
-	ViewClass    string `json:"view_class"`    // CSS class like "view--half_vertical"
-	DisplayName  string `json:"display_name"`  // User-friendly name like "Left Panel"
-	RequiredSize string `json:"required_size"` // Size requirement: "half", "quarter", "full"
+	Position     string  `json:"position"`      // Slot identifier like "left", "right", "q1", etc.
+	ViewClass    string  `json:"view_class"`    // CSS class like "view--half_vertical"
+	DisplayName  string  `json:"display_name"`  // User-friendly name like "Left Panel"
+	RequiredSize string  `json:"required_size"` // Size requirement: "half", "quarter", "full"
+	Order        int     `json:"order"`         // Zero-based reading order (top-left to bottom-right)
+	X            float64 `json:"x"`             // Left edge as a fraction of screen width
+	Y            float64 `json:"y"`             // Top edge as a fraction of screen height
+	Width        float64 `json:"width"`         // Width as a fraction of screen width
+	Height       float64 `json:"height"`        // Height as a fraction of screen height
 }
 
 // CreateMashupDefinition creates a new mashup plugin definition
-func (s *MashupService) CreateMashupDefinition(userID uuid.UUID, name string, layout string, backdrop bool) (*PluginDefinition, error) {
+func (s *MashupService) CreateMashupDefinition(userID uuid.UUID, name string, layout string, backdrop bool, slotGutter int) (*PluginDefinition, error) {
 	// Generate slot metadata based on layout
 	slots, err := s.generateSlotMetadata(layout)
 	if err != nil {
@@ -54,6 +65,7 @@ func (s *MashupService) CreateMashupDefinition(userID uuid.UUID, name string, la
 		IsMashup:           true,
 		MashupLayout:       &layout,
 		MashupSlots:        slotsJSON,
+		SlotGutter:         slotGutter,
 		EnableBackdrop:     &backdrop,
 		IsActive:           true,
 	}
@@ -65,8 +77,9 @@ func (s *MashupService) CreateMashupDefinition(userID uuid.UUID, name string, la
 	return definition, nil
 }
 
-// UpdateMashupDefinition updates an existing mashup definition's name, description, and backdrop setting
-func (s *MashupService) UpdateMashupDefinition(definitionID string, userID uuid.UUID, name string, description string, backdrop bool) error {
+// UpdateMashupDefinition updates an existing mashup definition's name, description, and backdrop setting.
+// The slot gutter is only changed when slotGutter is set.
+func (s *MashupService) UpdateMashupDefinition(definitionID string, userID uuid.UUID, name string, description string, backdrop bool, slotGutter *int) error {
 	var definition PluginDefinition
 	if err := s.db.Where("id = ? AND owner_id = ? AND plugin_type = ?", definitionID, userID, "mashup").First(&definition).Error; err != nil {
 		return fmt.Errorf("mashup definition not found: %w", err)
@@ -77,6 +90,9 @@ func (s *MashupService) UpdateMashupDefinition(definitionID string, userID uuid.
 		"description":     description,
 		"enable_backdrop": backdrop,
 	}
+	if slotGutter != nil {
+		updates["slot_gutter"] = *slotGutter
+	}
 
 	if err := s.db.Model(&definition).Updates(updates).Error; err != nil {
 		return fmt.Errorf("failed to update mashup definition: %w", err)
@@ -179,60 +195,103 @@ func (s *MashupService) ValidateMashupChild(instanceID uuid.UUID) error {
 	return nil
 }
 
-// generateSlotMetadata generates slot configuration based on layout type
+// generateSlotMetadata generates slot configuration based on layout type.
+// Slots are returned in the order their views appear in the mashup markup. Order gives each slot's
+// reading order: sorted by its top edge, then by its left edge.
 func (s *MashupService) generateSlotMetadata(layout string) ([]MashupSlotInfo, error) {
+	var slots []MashupSlotInfo
+
 	switch layout {
 	case "1Lx1R": // 1 Left, 1 Right
-		return []MashupSlotInfo{
-			{Position: "left", ViewClass: "view--half_vertical", DisplayName: "Left Panel", RequiredSize: "half"},
-			{Position: "right", ViewClass: "view--half_vertical", DisplayName: "Right Panel", RequiredSize: "half"},
-		}, nil
-		
+		slots = []MashupSlotInfo{
+			{Position: "left", ViewClass: "view--half_vertical", DisplayName: "Left Panel", RequiredSize: "half", X: 0, Y: 0, Width: 0.5, Height: 1},
+			{Position: "right", ViewClass: "view--half_vertical", DisplayName: "Right Panel", RequiredSize: "half", X: 0.5, Y: 0, Width: 0.5, Height: 1},
+		}
+
 	case "1Tx1B": // 1 Top, 1 Bottom
-		return []MashupSlotInfo{
-			{Position: "top", ViewClass: "view--half_horizontal", DisplayName: "Top Panel", RequiredSize: "half"},
-			{Position: "bottom", ViewClass: "view--half_horizontal", DisplayName: "Bottom Panel", RequiredSize: "half"},
-		}, nil
-		
+		slots = []MashupSlotInfo{
+			{Position: "top", ViewClass: "view--half_horizontal", DisplayName: "Top Panel", RequiredSize: "half", X: 0, Y: 0, Width: 1, Height: 0.5},
+			{Position: "bottom", ViewClass: "view--half_horizontal", DisplayName: "Bottom Panel", RequiredSize: "half", X: 0, Y: 0.5, Width: 1, Height: 0.5},
+		}
+
 	case "1Lx2R": // 1 Left, 2 Right
-		return []MashupSlotInfo{
-			{Position: "left", ViewClass: "view--half_vertical", DisplayName: "Left Panel", RequiredSize: "half"},
-			{Position: "right-top", ViewClass: "view--quadrant", DisplayName: "Right Top", RequiredSize: "quarter"},
-			{Position: "right-bottom", ViewClass: "view--quadrant", DisplayName: "Right Bottom", RequiredSize: "quarter"},
-		}, nil
-		
+		slots = []MashupSlotInfo{
+			{Position: "left", ViewClass: "view--half_vertical", DisplayName: "Left Panel", RequiredSize: "half", X: 0, Y: 0, Width: 0.5, Height: 1},
+			{Position: "right-top", ViewClass: "view--quadrant", DisplayName: "Right Top", RequiredSize: "quarter", X: 0.5, Y: 0, Width: 0.5, Height: 0.5},
+			{Position: "right-bottom", ViewClass: "view--quadrant", DisplayName: "Right Bottom", RequiredSize: "quarter", X: 0.5, Y: 0.5, Width: 0.5, Height: 0.5},
+		}
+
 	case "2Lx1R": // 2 Left, 1 Right
-		return []MashupSlotInfo{
-			{Position: "left-top", ViewClass: "view--quadrant", DisplayName: "Left Top", RequiredSize: "quarter"},
-			{Position: "left-bottom", ViewClass: "view--quadrant", DisplayName: "Left Bottom", RequiredSize: "quarter"},
-			{Position: "right", ViewClass: "view--half_vertical", DisplayName: "Right Panel", RequiredSize: "half"},
-		}, nil
-		
+		slots = []MashupSlotInfo{
+			{Position: "left-top", ViewClass: "view--quadrant", DisplayName: "Left Top", RequiredSize: "quarter", X: 0, Y: 0, Width: 0.5, Height: 0.5},
+			{Position: "left-bottom", ViewClass: "view--quadrant", DisplayName: "Left Bottom", RequiredSize: "quarter", X: 0, Y: 0.5, Width: 0.5, Height: 0.5},
+			{Position: "right", ViewClass: "view--half_vertical", DisplayName: "Right Panel", RequiredSize: "half", X: 0.5, Y: 0, Width: 0.5, Height: 1},
+		}
+
 	case "2Tx1B": // 2 Top, 1 Bottom
-		return []MashupSlotInfo{
-			{Position: "top-left", ViewClass: "view--quadrant", DisplayName: "Top Left", RequiredSize: "quarter"},
-			{Position: "top-right", ViewClass: "view--quadrant", DisplayName: "Top Right", RequiredSize: "quarter"},
-			{Position: "bottom", ViewClass: "view--half_horizontal", DisplayName: "Bottom Panel", RequiredSize: "half"},
-		}, nil
-		
+		slots = []MashupSlotInfo{
+			{Position: "top-left", ViewClass: "view--quadrant", DisplayName: "Top Left", RequiredSize: "quarter", X: 0, Y: 0, Width: 0.5, Height: 0.5},
+			{Position: "top-right", ViewClass: "view--quadrant", DisplayName: "Top Right", RequiredSize: "quarter", X: 0.5, Y: 0, Width: 0.5, Height: 0.5},
+			{Position: "bottom", ViewClass: "view--half_horizontal", DisplayName: "Bottom Panel", RequiredSize: "half", X: 0, Y: 0.5, Width: 1, Height: 0.5},
+		}
+
 	case "1Tx2B": // 1 Top, 2 Bottom
-		return []MashupSlotInfo{
-			{Position: "top", ViewClass: "view--half_horizontal", DisplayName: "Top Panel", RequiredSize: "half"},
-			{Position: "bottom-left", ViewClass: "view--quadrant", DisplayName: "Bottom Left", RequiredSize: "quarter"},
-			{Position: "bottom-right", ViewClass: "view--quadrant", DisplayName: "Bottom Right", RequiredSize: "quarter"},
-		}, nil
-		
+		slots = []MashupSlotInfo{
+			{Position: "top", ViewClass: "view--half_horizontal", DisplayName: "Top Panel", RequiredSize: "half", X: 0, Y: 0, Width: 1, Height: 0.5},
+			{Position: "bottom-left", ViewClass: "view--quadrant", DisplayName: "Bottom Left", RequiredSize: "quarter", X: 0, Y: 0.5, Width: 0.5, Height: 0.5},
+			{Position: "bottom-right", ViewClass: "view--quadrant", DisplayName: "Bottom Right", RequiredSize: "quarter", X: 0.5, Y: 0.5, Width: 0.5, Height: 0.5},
+		}
+
 	case "2x2": // 2x2 Grid (4 quadrants)
-		return []MashupSlotInfo{
-			{Position: "q1", ViewClass: "view--quadrant", DisplayName: "Top Left", RequiredSize: "quarter"},
-			{Position: "q2", ViewClass: "view--quadrant", DisplayName: "Top Right", RequiredSize: "quarter"},
-			{Position: "q3", ViewClass: "view--quadrant", DisplayName: "Bottom Left", RequiredSize: "quarter"},
-			{Position: "q4", ViewClass: "view--quadrant", DisplayName: "Bottom Right", RequiredSize: "quarter"},
-		}, nil
-		
+		slots = []MashupSlotInfo{
+			{Position: "q1", ViewClass: "view--quadrant", DisplayName: "Top Left", RequiredSize: "quarter", X: 0, Y: 0, Width: 0.5, Height: 0.5},
+			{Position: "q2", ViewClass: "view--quadrant", DisplayName: "Top Right", RequiredSize: "quarter", X: 0.5, Y: 0, Width: 0.5, Height: 0.5},
+			{Position: "q3", ViewClass: "view--quadrant", DisplayName: "Bottom Left", RequiredSize: "quarter", X: 0, Y: 0.5, Width: 0.5, Height: 0.5},
+			{Position: "q4", ViewClass: "view--quadrant", DisplayName: "Bottom Right", RequiredSize: "quarter", X: 0.5, Y: 0.5, Width: 0.5, Height: 0.5},
+		}
+
 	default:
 		return nil, fmt.Errorf("unsupported layout: %s", layout)
 	}
+
+	// The slice stays in markup order, which the mashup layout CSS depends on
+	reading := make([]int, len(slots))
+	for i := range reading {
+		reading[i] = i
+	}
+	sort.SliceStable(reading, func(i, j int) bool {
+		a, b := slots[reading[i]], slots[reading[j]]
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	for order, index := range reading {
+		slots[index].Order = order
+	}
+
+	return slots, nil
+}
+
+// SlotPixelSize returns the size in pixels a slot takes up on a screen when the mashup grid has a gap of
+// gutter pixels, which takes half the gutter from each edge that borders another slot
+func SlotPixelSize(slot MashupSlotInfo, screenWidth, screenHeight, gutter int) (int, int) {
+	width := slot.Width * float64(screenWidth)
+	height := slot.Height * float64(screenHeight)
+	half := float64(gutter) / 2
+	if slot.X > 0 {
+		width -= half
+	}
+	if slot.X+slot.Width < 1 {
+		width -= half
+	}
+	if slot.Y > 0 {
+		height -= half
+	}
+	if slot.Y+slot.Height < 1 {
+		height -= half
+	}
+	return int(math.Round(width)), int(math.Round(height))
 }
 
 // GetSlotMetadata returns slot metadata for a layout (public method)
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"math"
+	"testing"
+)
+
+// slotGeometry lists a layout's slots in markup order, which the mashup renderer writes its views in
+type slotGeometry struct {
+	position            string
+	order               int
+	x, y, width, height float64
+}
+
+func TestGetSlotMetadata_Geometry(t *testing.T) {
+	expected := map[string][]slotGeometry{
+		"1Lx1R": {
+			{"left", 0, 0, 0, 0.5, 1},
+			{"right", 1, 0.5, 0, 0.5, 1},
+		},
+		"1Tx1B": {
+			{"top", 0, 0, 0, 1, 0.5},
+			{"bottom", 1, 0, 0.5, 1, 0.5},
+		},
+		"1Lx2R": {
+			{"left", 0, 0, 0, 0.5, 1},
+			{"right-top", 1, 0.5, 0, 0.5, 0.5},
+			{"right-bottom", 2, 0.5, 0.5, 0.5, 0.5},
+		},
+		"2Lx1R": {
+			{"left-top", 0, 0, 0, 0.5, 0.5},
+			{"left-bottom", 2, 0, 0.5, 0.5, 0.5},
+			{"right", 1, 0.5, 0, 0.5, 1},
+		},
+		"2Tx1B": {
+			{"top-left", 0, 0, 0, 0.5, 0.5},
+			{"top-right", 1, 0.5, 0, 0.5, 0.5},
+			{"bottom", 2, 0, 0.5, 1, 0.5},
+		},
+		"1Tx2B": {
+			{"top", 0, 0, 0, 1, 0.5},
+			{"bottom-left", 1, 0, 0.5, 0.5, 0.5},
+			{"bottom-right", 2, 0.5, 0.5, 0.5, 0.5},
+		},
+		"2x2": {
+			{"q1", 0, 0, 0, 0.5, 0.5},
+			{"q2", 1, 0.5, 0, 0.5, 0.5},
+			{"q3", 2, 0, 0.5, 0.5, 0.5},
+			{"q4", 3, 0.5, 0.5, 0.5, 0.5},
+		},
+	}
+
+	service := &MashupService{}
+
+	for _, layout := range service.GetAvailableLayouts() {
+		layoutID := layout["id"].(string)
+		want, ok := expected[layoutID]
+		if !ok {
+			t.Errorf("layout %s has no expected geometry", layoutID)
+			continue
+		}
+
+		slots, err := service.GetSlotMetadata(layoutID)
+		if err != nil {
+			t.Fatalf("GetSlotMetadata(%s) error = %v", layoutID, err)
+		}
+		if len(slots) != len(want) {
+			t.Fatalf("GetSlotMetadata(%s) returned %d slots, want %d", layoutID, len(slots), len(want))
+		}
+		if layout["slots"].(int) != len(slots) {
+			t.Errorf("layout %s advertises %d slots but metadata has %d", layoutID, layout["slots"], len(slots))
+		}
+
+		area := 0.0
+		for i, slot := range slots {
+			w := want[i]
+			if slot.Position != w.position {
+				t.Errorf("%s slot %d position = %s, want %s", layoutID, i, slot.Position, w.position)
+			}
+			if slot.Order != w.order {
+				t.Errorf("%s slot %s order = %d, want %d", layoutID, slot.Position, slot.Order, w.order)
+			}
+			if slot.X != w.x || slot.Y != w.y || slot.Width != w.width || slot.Height != w.height {
+				t.Errorf("%s slot %s geometry = (%v, %v, %v, %v), want (%v, %v, %v, %v)",
+					layoutID, slot.Position, slot.X, slot.Y, slot.Width, slot.Height, w.x, w.y, w.width, w.height)
+			}
+			area += slot.Width * slot.Height
+		}
+
+		if math.Abs(area-1) > 1e-9 {
+			t.Errorf("%s slots cover %.2f of the screen, want 1.00", layoutID, area)
+		}
+
+		for i := range slots {
+			for j := i + 1; j < len(slots); j++ {
+				a, b := slots[i], slots[j]
+				if a.X < b.X+b.Width && b.X < a.X+a.Width && a.Y < b.Y+b.Height && b.Y < a.Y+a.Height {
+					t.Errorf("%s slots %s and %s overlap", layoutID, a.Position, b.Position)
+				}
+			}
+		}
+	}
+}
+
+func TestGetSlotMetadata_Deterministic(t *testing.T) {
+	service := &MashupService{}
+
+	for _, layout := range service.GetAvailableLayouts() {
+		layoutID := layout["id"].(string)
+		first, _ := service.GetSlotMetadata(layoutID)
+		for run := 0; run < 5; run++ {
+			again, _ := service.GetSlotMetadata(layoutID)
+			for i := range first {
+				if first[i] != again[i] {
+					t.Fatalf("GetSlotMetadata(%s) is not deterministic at slot %d", layoutID, i)
+				}
+			}
+		}
+	}
+}
+
+func TestGetSlotMetadata_UnsupportedLayout(t *testing.T) {
+	service := &MashupService{}
+
+	if _, err := service.GetSlotMetadata("3x3"); err == nil {
+		t.Error("GetSlotMetadata(3x3) expected error for unsupported layout")
+	}
+}
+
+func TestSlotPixelSize_Gutter(t *testing.T) {
+	const screenWidth, screenHeight = 800, 480
+
+	// Slot sizes on an 800x480 screen with a 10px gutter: half-screen sides lose 5px to the gutter
+	expected := map[string][][2]int{
+		"1Lx1R": {{395, 480}, {395, 480}},
+		"1Tx1B": {{800, 235}, {800, 235}},
+		"1Lx2R": {{395, 480}, {395, 235}, {395, 235}},
+		"2Lx1R": {{395, 235}, {395, 235}, {395, 480}},
+		"2Tx1B": {{395, 235}, {395, 235}, {800, 235}},
+		"1Tx2B": {{800, 235}, {395, 235}, {395, 235}},
+		"2x2":   {{395, 235}, {395, 235}, {395, 235}, {395, 235}},
+	}
+
+	service := &MashupService{}
+
+	for _, layout := range service.GetAvailableLayouts() {
+		layoutID := layout["id"].(string)
+		want, ok := expected[layoutID]
+		if !ok {
+			t.Errorf("layout %s has no expected slot sizes", layoutID)
+			continue
+		}
+
+		slots, err := service.GetSlotMetadata(layoutID)
+		if err != nil {
+			t.Fatalf("GetSlotMetadata(%s) error = %v", layoutID, err)
+		}
+		if len(slots) != len(want) {
+			t.Fatalf("GetSlotMetadata(%s) returned %d slots, want %d", layoutID, len(slots), len(want))
+		}
+
+		for i, slot := range slots {
+			width, height := SlotPixelSize(slot, screenWidth, screenHeight, 10)
+			if width != want[i][0] || height != want[i][1] {
+				t.Errorf("%s slot %s with 10px gutter = %dx%d, want %dx%d",
+					layoutID, slot.Position, width, height, want[i][0], want[i][1])
+			}
+
+			// Without a gutter slots cover their exact share of the screen
+			width, height = SlotPixelSize(slot, screenWidth, screenHeight, 0)
+			wantWidth := int(math.Round(slot.Width * screenWidth))
+			wantHeight := int(math.Round(slot.Height * screenHeight))
+			if width != wantWidth || height != wantHeight {
+				t.Errorf("%s slot %s without gutter = %dx%d, want %dx%d",
+					layoutID, slot.Position, width, height, wantWidth, wantHeight)
+			}
+		}
+	}
+}
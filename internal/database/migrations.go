@@ -1080,6 +1080,44 @@ func RunMigrations(logPrefix string) error {
 				return nil
 			},
 		},
+		{
+			ID: "20260415_firmware_release_channels",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&FirmwareVersion{}, "channel") {
+					if err := tx.Exec("ALTER TABLE firmware_versions ADD COLUMN channel VARCHAR(20) DEFAULT 'stable'").Error; err != nil {
+						return fmt.Errorf("failed to add channel column: %w", err)
+					}
+				}
+				if err := tx.Exec("UPDATE firmware_versions SET channel = 'beta' WHERE is_stable = ?", false).Error; err != nil {
+					return fmt.Errorf("failed to assign beta channel: %w", err)
+				}
+				if err := tx.Exec("UPDATE firmware_versions SET channel = 'stable' WHERE is_stable = ?", true).Error; err != nil {
+					return fmt.Errorf("failed to assign stable channel: %w", err)
+				}
+				logging.Info("[MIGRATION] Assigned release channels to firmware versions")
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Exec("ALTER TABLE firmware_versions DROP COLUMN IF EXISTS channel").Error
+			},
+		},
+		{
+			ID: "20260416_widen_friendly_id",
+			Migrate: func(tx *gorm.DB) error {
+				// SQLite doesn't enforce VARCHAR lengths, so only PostgreSQL needs the wider column
+				if tx.Dialector.Name() != "postgres" {
+					return nil
+				}
+				if err := tx.Exec("ALTER TABLE devices ALTER COLUMN friendly_id TYPE VARCHAR(32)").Error; err != nil {
+					return fmt.Errorf("failed to widen friendly_id column: %w", err)
+				}
+				logging.Info("[MIGRATION] Widened devices.friendly_id for configurable friendly ID formats")
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return nil
+			},
+		},
 	}
 
 	// Create migrator with our migrations
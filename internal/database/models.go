@@ -29,6 +29,14 @@ type User struct {
 	// OIDC integration
 	OidcSubject *string `gorm:"column:oidc_subject;uniqueIndex" json:"oidc_subject,omitempty"`
 
+	// Per-user SMTP override for the user's own notifications; system emails always use the global server
+	SMTPHost              string `gorm:"size:255" json:"smtp_host,omitempty"`
+	SMTPPort              int    `json:"smtp_port,omitempty"`
+	SMTPUsername          string `gorm:"size:255" json:"smtp_username,omitempty"`
+	SMTPPasswordEncrypted string `gorm:"type:text" json:"-"` // AES-GCM encrypted, see smtp.EncryptPassword
+	SMTPFrom              string `gorm:"size:255" json:"smtp_from,omitempty"`
+	SMTPUseTLS            bool   `gorm:"default:true" json:"smtp_tls"`
+
 	// Timestamps
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
@@ -227,7 +235,7 @@ type Device struct {
 	ID                      uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
 	UserID                  *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`         // Nullable for unclaimed devices
 	MacAddress              string     `gorm:"size:255;not null;uniqueIndex" json:"mac_address"` // Original MAC address from device
-	FriendlyID              string     `gorm:"size:10;not null;uniqueIndex" json:"friendly_id"`  // Generated short ID like "917F0B"
+	FriendlyID              string     `gorm:"size:32;not null;uniqueIndex" json:"friendly_id"`  // Generated short ID like "917F0B"
 	Name                    string     `gorm:"size:255" json:"name,omitempty"`                   // User-defined name, empty until claimed
 	DeviceModelID           *uint      `gorm:"index" json:"device_model_id,omitempty"`           // Foreign key to DeviceModel.ID
 	ManualModelOverride     bool       `gorm:"default:false" json:"manual_model_override"`       // True if model was manually set by user
@@ -236,13 +244,17 @@ type Device struct {
 	IsClaimed               bool       `gorm:"default:false" json:"is_claimed"`
 	FirmwareVersion         string     `gorm:"size:50" json:"firmware_version,omitempty"`
 	TargetFirmwareVersion   string     `gorm:"size:50" json:"target_firmware_version,omitempty"`
+	FirmwareChannel         string     `gorm:"size:20;default:'stable'" json:"firmware_channel"` // Release channel used when tracking the latest firmware
 	BatteryVoltage          float64    `json:"battery_voltage,omitempty"`
 	BatteryPercent          int        `json:"battery_percent,omitempty"`
 	RSSI                    int        `json:"rssi,omitempty"`
 	RefreshRate             int        `gorm:"default:1800" json:"refresh_rate"` // seconds
+	MinRefreshRate          int        `gorm:"default:60" json:"min_refresh_rate"` // seconds; floor for the refresh rate sent to the device, 0 disables
 	AllowFirmwareUpdates    bool       `gorm:"default:false" json:"allow_firmware_updates"`
 	LastSeen                *time.Time `json:"last_seen,omitempty"`
 	LastPlaylistItemID      *uuid.UUID `gorm:"type:uuid;references:playlist_items(id)" json:"last_playlist_item_id,omitempty"` // Track last shown playlist item by UUID
+	HoldCurrentItem         bool       `gorm:"default:false" json:"hold_current_item"`                   // Serve LastPlaylistItemID again on next check-in instead of advancing
+	LastPlaylistItemDisplayCount int   `gorm:"default:0" json:"last_playlist_item_display_count"`        // Consecutive check-ins that have served LastPlaylistItemID
 	IsActive                bool       `gorm:"default:true" json:"is_active"`
 	IsShareable             bool       `gorm:"default:false" json:"is_shareable"`                        // Whether this device can be mirrored by others
 	MirrorSourceID          *uuid.UUID `gorm:"type:uuid;index" json:"mirror_source_id,omitempty"`        // ID of device being mirrored (nullable)
@@ -251,12 +263,25 @@ type Device struct {
 	SleepStartTime          string     `gorm:"size:5" json:"sleep_start_time,omitempty"`                 // Start time in HH:MM format
 	SleepEndTime            string     `gorm:"size:5" json:"sleep_end_time,omitempty"`                   // End time in HH:MM format
 	SleepShowScreen         bool       `gorm:"default:true" json:"sleep_show_screen"`                    // Whether to show sleep image or last content
+	ActiveHoursEnabled      bool       `gorm:"default:false" json:"active_hours_enabled"`                // Only advance the playlist during active hours
+	ActiveHoursStartTime    string     `gorm:"size:5" json:"active_hours_start_time,omitempty"`          // Start time in HH:MM format
+	ActiveHoursEndTime      string     `gorm:"size:5" json:"active_hours_end_time,omitempty"`            // End time in HH:MM format
+	InactiveHoldItemID      *uuid.UUID `gorm:"type:uuid" json:"inactive_hold_item_id,omitempty"`         // Playlist item held outside active hours; the last shown item when unset
 	FirmwareUpdateStartTime string     `gorm:"size:5;default:'00:00'" json:"firmware_update_start_time"`
 	FirmwareUpdateEndTime   string     `gorm:"size:5;default:'23:59'" json:"firmware_update_end_time"`
 	MaximumCompatibility    bool       `gorm:"default:false" json:"maximum_compatibility"`
 	TouchbarMode            string     `gorm:"size:10;default:'tap'" json:"touchbar_mode"`
 	TemperatureProfile      string     `gorm:"size:10;default:'default'" json:"temperature_profile"`
 	ScreenOrientation       string     `gorm:"size:20;default:'auto'" json:"screen_orientation"`
+	SafeAreaTop             int        `gorm:"default:0" json:"safe_area_top"`    // Pixels of padding applied to rendered content to clear the bezel
+	SafeAreaRight           int        `gorm:"default:0" json:"safe_area_right"`
+	SafeAreaBottom          int        `gorm:"default:0" json:"safe_area_bottom"`
+	SafeAreaLeft            int        `gorm:"default:0" json:"safe_area_left"`
+	EmptyPlaylistMode       string     `gorm:"size:20;default:'default'" json:"empty_playlist_mode"`     // What to show when no playlist item is active: default, setup, image, plugin
+	EmptyPlaylistImageURL   string     `gorm:"size:1000" json:"empty_playlist_image_url,omitempty"`      // Image shown in "image" mode
+	EmptyPlaylistInstanceID *uuid.UUID `gorm:"type:uuid;index" json:"empty_playlist_instance_id,omitempty"` // Plugin instance shown in "plugin" mode
+	ReportedCapabilities    string     `gorm:"type:text" json:"reported_capabilities,omitempty"`            // JSON capabilities reported by the firmware, see DeviceCapabilityReport
+	CapabilitiesReportedAt  *time.Time `json:"capabilities_reported_at,omitempty"`
 	CreatedAt               time.Time  `json:"created_at"`
 	UpdatedAt               time.Time  `json:"updated_at"`
 
@@ -275,6 +300,132 @@ func (d *Device) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// Empty playlist modes control what a device shows when none of its playlist items are active
+const (
+	EmptyPlaylistModeDefault = "default" // Generic placeholder screen
+	EmptyPlaylistModeSetup   = "setup"   // Setup screen
+	EmptyPlaylistModeImage   = "image"   // Custom image URL
+	EmptyPlaylistModePlugin  = "plugin"  // A specific plugin instance
+)
+
+// IsValidEmptyPlaylistMode reports whether mode is a supported empty playlist mode
+func IsValidEmptyPlaylistMode(mode string) bool {
+	switch mode {
+	case EmptyPlaylistModeDefault, EmptyPlaylistModeSetup, EmptyPlaylistModeImage, EmptyPlaylistModePlugin:
+		return true
+	}
+	return false
+}
+
+// Device claim request statuses
+const (
+	ClaimRequestStatusPending  = "pending"
+	ClaimRequestStatusApproved = "approved"
+	ClaimRequestStatusDenied   = "denied"
+)
+
+// DeviceClaimRequest is a user's request to claim a device, created instead of claiming
+// directly when the device_claim_requires_approval setting is enabled
+type DeviceClaimRequest struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	DeviceID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"device_id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name       string     `gorm:"size:255" json:"name"`                                // Device name requested by the user
+	Status     string     `gorm:"size:20;default:'pending';index" json:"status"`       // pending, approved, denied
+	ReviewedBy *uuid.UUID `gorm:"type:uuid" json:"reviewed_by,omitempty"`              // Admin who approved or denied the request
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Associations
+	Device *Device `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE" json:"device,omitempty"`
+	User   *User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (dcr *DeviceClaimRequest) BeforeCreate(tx *gorm.DB) error {
+	if dcr.ID == uuid.Nil {
+		dcr.ID = uuid.New()
+	}
+	return nil
+}
+
+// DevicePreregistration assigns a MAC address to a user before the device first connects, so the
+// device is claimed for that user during setup instead of appearing unclaimed
+type DevicePreregistration struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	MacAddress string     `gorm:"size:17;uniqueIndex;not null" json:"mac_address"` // Normalized AA:BB:CC:DD:EE:FF
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name       string     `gorm:"size:255" json:"name"`                   // Device name given on claim
+	CreatedBy  *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"` // Admin who pre-registered the device
+	DeviceID   *uuid.UUID `gorm:"type:uuid" json:"device_id,omitempty"`  // Device claimed through this entry
+	ClaimedAt  *time.Time `json:"claimed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Associations
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (dp *DevicePreregistration) BeforeCreate(tx *gorm.DB) error {
+	if dp.ID == uuid.Nil {
+		dp.ID = uuid.New()
+	}
+	return nil
+}
+
+// DeviceShareLink is a revocable public token that exposes a device's current screen image
+type DeviceShareLink struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	DeviceID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"device_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Token          string     `gorm:"size:64;not null;uniqueIndex" json:"token"`
+	Name           string     `gorm:"size:255" json:"name,omitempty"` // Optional label, e.g. where the link is embedded
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// Associations
+	Device *Device `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE" json:"-"`
+	User   *User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (dsl *DeviceShareLink) BeforeCreate(tx *gorm.DB) error {
+	if dsl.ID == uuid.Nil {
+		dsl.ID = uuid.New()
+	}
+	return nil
+}
+
+// DeviceSnapshot is a copy of a device's screen kept for troubleshooting. Its image lives outside the
+// rendered content directory so render cleanup never removes it.
+type DeviceSnapshot struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	DeviceID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"device_id"`
+	UserID            uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	PluginInstanceID  *uuid.UUID `gorm:"type:uuid" json:"plugin_instance_id,omitempty"`
+	PluginName        string     `gorm:"size:255" json:"plugin_name,omitempty"` // Kept in case the instance is later deleted
+	RenderedContentID *uuid.UUID `gorm:"type:uuid" json:"rendered_content_id,omitempty"`
+	Note              string     `gorm:"type:text" json:"note,omitempty"`
+	ImagePath         string     `gorm:"size:1000;not null" json:"-"`
+	Width             int        `json:"width"`
+	Height            int        `json:"height"`
+	BitDepth          int        `json:"bit_depth"`
+	FileSize          int64      `json:"file_size"`
+	ContentHash       *string    `gorm:"size:64" json:"content_hash,omitempty"`
+	RenderedAt        time.Time  `json:"rendered_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+
+	// Associations
+	Device *Device `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE" json:"-"`
+	User   *User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (ds *DeviceSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if ds.ID == uuid.Nil {
+		ds.ID = uuid.New()
+	}
+	return nil
+}
+
 // Plugin represents a system-wide plugin type (managed by admins)
 
 // PrivatePluginWebhookData represents webhook data storage for private plugin instances
@@ -301,6 +452,7 @@ type PrivatePluginPollingData struct {
 	Success          bool           `json:"success"`        // Whether polling was successful
 	Errors           datatypes.JSON `json:"errors"`         // Error messages if failed
 	URLCount         int            `json:"url_count"`      // Number of URLs polled
+	LastSuccessAt    *time.Time     `json:"last_success_at,omitempty"` // Kept across failed polls
 }
 
 // Playlist represents a collection of plugins for a specific device
@@ -338,6 +490,7 @@ type PlaylistItem struct {
 	Importance       bool      `gorm:"default:false" json:"importance"` // false=normal, true=important
 	DurationOverride *int      `json:"duration_override,omitempty"`     // override default refresh rate
 	SkipDisplay      bool      `gorm:"default:false" json:"skip_display"` // true if plugin requested to skip display
+	MinDisplayCount  int       `gorm:"default:0" json:"min_display_count"` // minimum consecutive check-ins to show this item before advancing
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 
@@ -409,10 +562,12 @@ type PluginDefinition struct {
 	IsMashup     bool           `gorm:"default:false" json:"is_mashup"`           // True for mashup plugin definitions
 	MashupLayout *string        `gorm:"size:20" json:"mashup_layout,omitempty"`   // "1Lx1R", "1Tx1B", "2x2", etc.
 	MashupSlots  datatypes.JSON `json:"mashup_slots,omitempty"`                   // JSON metadata about each slot
+	SlotGutter   int            `gorm:"default:0" json:"slot_gutter,omitempty"`   // Spacing in pixels between mashup slots
 	
-	// Publishing (for future public plugins)
-	IsPublished bool       `gorm:"default:false" json:"is_published"`
-	PublishedAt *time.Time `json:"published_at,omitempty"`
+	// Publishing to the shared plugin catalog
+	IsPublished        bool       `gorm:"default:false" json:"is_published"`
+	PublishedAt        *time.Time `json:"published_at,omitempty"`
+	SourceDefinitionID *string    `gorm:"size:255;index" json:"source_definition_id,omitempty"` // Catalog plugin this definition was installed from
 	
 	// Screen options (for private plugins)
 	RemoveBleedMargin *bool          `gorm:"default:false" json:"remove_bleed_margin,omitempty"` // Nullable for backward compatibility
@@ -423,6 +578,15 @@ type PluginDefinition struct {
 	// Schema versioning for form field changes
 	SchemaVersion int `gorm:"default:1" json:"schema_version"` // Increments when FormFields change
 	
+	// Minimum device capabilities (zero values mean no requirement)
+	MinScreenWidth  int  `gorm:"default:0" json:"min_screen_width,omitempty"`
+	MinScreenHeight int  `gorm:"default:0" json:"min_screen_height,omitempty"`
+	MinBitDepth     int  `gorm:"default:0" json:"min_bit_depth,omitempty"`
+	RequiresColor   bool `gorm:"default:false" json:"requires_color,omitempty"`
+	
+	// Image regions ignored when checking whether rendered content changed
+	HashIgnoreRegionsJSON datatypes.JSON `gorm:"column:hash_ignore_regions" json:"hash_ignore_regions,omitempty"`
+	
 	// Meta
 	IsActive  bool      `gorm:"default:true" json:"is_active"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
@@ -456,6 +620,22 @@ type PluginInstance struct {
 	LastSchemaVersion   int  `gorm:"default:1" json:"last_schema_version"`      // Schema version this instance was last updated against
 	NeedsConfigUpdate   bool `gorm:"default:false" json:"needs_config_update"`  // Flag when parent plugin schema changes
 	
+	// Render failure notifications - an email is sent once when consecutive failures reach the threshold
+	FailureNotifyThreshold int    `gorm:"default:0" json:"failure_notify_threshold"`      // 0 disables notifications
+	FailureNotifyEmail     string `gorm:"size:255" json:"failure_notify_email,omitempty"` // Recipient, defaults to the owner's email
+	ConsecutiveFailures    int    `gorm:"default:0" json:"consecutive_failures"`          // Failed renders since the last success
+	LastError              string `gorm:"type:text" json:"last_error,omitempty"`          // Error from the most recent failed render
+	
+	// Webhook coalescing - bursts of webhook pushes schedule at most one render per window
+	WebhookCoalesceSeconds int `gorm:"default:0" json:"webhook_coalesce_seconds"` // 0 renders on every push
+	
+	// Settings profiles - named sets of settings; activating one copies it into Settings
+	SettingsProfiles datatypes.JSON `gorm:"type:text" json:"settings_profiles,omitempty"` // JSON object of profile name to settings
+	ActiveProfile    string         `gorm:"size:100" json:"active_profile,omitempty"`      // Profile currently copied into Settings
+	
+	// Render allowlist - when set, the instance only renders for these devices even if other playlists include it
+	RenderDeviceIDs datatypes.JSON `gorm:"type:text" json:"render_device_ids,omitempty"` // JSON array of device IDs, empty renders for all
+	
 	CreatedAt       time.Time     `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt       time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
 	
@@ -473,6 +653,35 @@ func (pi *PluginInstance) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// Plugin instance event types recorded for the activity timeline
+const (
+	PluginInstanceEventSettingsUpdated = "settings_updated"
+	PluginInstanceEventSchemaFlagged   = "schema_update_flagged" // The definition's form fields changed
+	PluginInstanceEventPollFailed      = "poll_failed"
+	PluginInstanceEventWebhookReceived = "webhook_received"
+)
+
+// PluginInstanceEvent records something that happened to a plugin instance outside of rendering,
+// shown in its activity timeline. Renders and render jobs come from their own tables.
+type PluginInstanceEvent struct {
+	ID               uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	PluginInstanceID uuid.UUID      `gorm:"type:uuid;not null;index:idx_plugin_instance_event_time" json:"plugin_instance_id"`
+	Type             string         `gorm:"size:50;not null" json:"type"`
+	Message          string         `gorm:"type:text" json:"message"`
+	Details          datatypes.JSON `gorm:"type:text" json:"details,omitempty"`
+	CreatedAt        time.Time      `gorm:"index:idx_plugin_instance_event_time" json:"created_at"`
+
+	// Associations
+	PluginInstance PluginInstance `gorm:"foreignKey:PluginInstanceID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (e *PluginInstanceEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
 // DeviceLog represents a log entry from a device
 type DeviceLog struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
@@ -496,6 +705,28 @@ func (dl *DeviceLog) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// DeviceResolution records a display resolution reported by a device that differs from its
+// model's native size, so content can be pre-rendered for each variant the firmware requests
+type DeviceResolution struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	DeviceID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_device_resolution" json:"device_id"`
+	Width      int       `gorm:"not null;uniqueIndex:idx_device_resolution" json:"width"`
+	Height     int       `gorm:"not null;uniqueIndex:idx_device_resolution" json:"height"`
+	BitDepth   int       `gorm:"not null;uniqueIndex:idx_device_resolution" json:"bit_depth"`
+	LastSeenAt time.Time `gorm:"not null;index" json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Associations
+	Device Device `gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (dr *DeviceResolution) BeforeCreate(tx *gorm.DB) error {
+	if dr.ID == uuid.Nil {
+		dr.ID = uuid.New()
+	}
+	return nil
+}
+
 // FirmwareVersion represents a firmware version available for devices
 type FirmwareVersion struct {
 	ID               uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
@@ -510,6 +741,7 @@ type FirmwareVersion struct {
 	SHA256           string    `gorm:"size:64" json:"sha256,omitempty"`
 	IsLatest         bool      `gorm:"default:false" json:"is_latest"`
 	IsStable         bool      `gorm:"default:false" json:"is_stable"`
+	Channel          string    `gorm:"size:20;default:'stable';index" json:"channel"` // stable, beta
 	IsDownloaded     bool      `gorm:"default:false" json:"is_downloaded"`
 	DownloadStatus   string    `gorm:"size:20;default:'pending'" json:"download_status"` // pending, downloading, downloaded, failed
 	DownloadProgress int       `gorm:"default:0" json:"download_progress"`               // 0-100
@@ -526,6 +758,30 @@ func (f *FirmwareVersion) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// Firmware release channels. Devices on the beta channel receive stable and beta releases,
+// devices on the stable channel only stable ones.
+const (
+	FirmwareChannelStable = "stable"
+	FirmwareChannelBeta   = "beta"
+)
+
+// IsValidFirmwareChannel reports whether channel is a supported firmware release channel
+func IsValidFirmwareChannel(channel string) bool {
+	switch channel {
+	case FirmwareChannelStable, FirmwareChannelBeta:
+		return true
+	}
+	return false
+}
+
+// firmwareChannelsFor returns the release channels a device on channel may receive
+func firmwareChannelsFor(channel string) []string {
+	if channel == FirmwareChannelBeta {
+		return []string{FirmwareChannelStable, FirmwareChannelBeta}
+	}
+	return []string{FirmwareChannelStable}
+}
+
 // DeviceModel represents a device model with its capabilities
 type DeviceModel struct {
 	ID             uint       `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -536,6 +792,7 @@ type DeviceModel struct {
 	ScreenHeight   int        `gorm:"not null" json:"screen_height"`
 	ColorDepth     int        `gorm:"default:1" json:"color_depth"` // 1=monochrome, 8=grayscale, 24=color
 	BitDepth       int        `gorm:"default:1" json:"bit_depth"`   // Actual bit depth of the display
+	GrayscaleLevels int       `gorm:"default:0" json:"grayscale_levels,omitempty"` // Gray levels to quantize to; 0 uses every level of the bit depth
 	HasWiFi        bool       `gorm:"default:true" json:"has_wifi"`
 	HasBattery     bool       `gorm:"default:true" json:"has_battery"`
 	HasButtons     int        `gorm:"default:0" json:"has_buttons"`            // Number of buttons
@@ -547,6 +804,7 @@ type DeviceModel struct {
 	MimeType       string     `gorm:"size:50;default:'image/png'" json:"mime_type"`
 	MinFirmware    string     `gorm:"size:50" json:"min_firmware,omitempty"`
 	IsActive       bool       `gorm:"default:true" json:"is_active"`
+	NeedsReview    bool       `gorm:"default:false" json:"needs_review"` // Provisional model created for an unknown device report
 	ApiLastSeenAt  *time.Time `json:"api_last_seen_at,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
@@ -577,6 +835,12 @@ type RenderedContent struct {
 	LastCheckedAt  *time.Time `gorm:"index" json:"last_checked_at,omitempty"` // Track hash comparisons even when not saving
 	PreviousHash   *string    `gorm:"size:64" json:"previous_hash,omitempty"`  // Store previous content hash for debugging
 	RenderAttempts int        `gorm:"default:0" json:"render_attempts"`        // Track render failures
+
+	// Bounding box of the pixels that changed since the previous render (PreviousHash), nil when unknown
+	DirtyX      *int `json:"dirty_x,omitempty"`
+	DirtyY      *int `json:"dirty_y,omitempty"`
+	DirtyWidth  *int `json:"dirty_width,omitempty"`
+	DirtyHeight *int `json:"dirty_height,omitempty"`
 	
 	// Associations  
 	PluginInstance PluginInstance `gorm:"foreignKey:PluginInstanceID" json:"-"`
@@ -655,6 +919,10 @@ func GetAllModels() []interface{} {
 		&RestoreExtractionJob{},
 		&DeviceModel{}, // Must come before Device due to foreign key reference
 		&Device{},
+		&DeviceClaimRequest{}, // Must come after Device and User
+		&DeviceShareLink{},    // Must come after Device and User
+		&DeviceSnapshot{},     // Must come after Device and User
+		&DevicePreregistration{}, // Must come after User
 		
 		&PrivatePluginWebhookData{}, // Webhook data for plugin instances
 	&PrivatePluginPollingData{}, // Polling data for plugin instances
@@ -663,11 +931,13 @@ func GetAllModels() []interface{} {
 		&PluginDefinition{}, // Must come after User due to foreign key reference
 		&PluginInstance{},   // Must come after PluginDefinition and User
 		&MashupChild{},      // Must come after PluginInstance
+		&PluginInstanceEvent{}, // Must come after PluginInstance
 		
 		&Playlist{},
 		&PlaylistItem{},
 		&Schedule{},
 		&DeviceLog{},
+		&DeviceResolution{},
 		&FirmwareVersion{},
 		&RenderedContent{},
 		&RenderQueue{},
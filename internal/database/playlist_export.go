@@ -0,0 +1,211 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+)
+
+// PlaylistExportVersion is the format version written to playlist exports
+const PlaylistExportVersion = 1
+
+// PlaylistExport is a playlist with its items and schedules as a standalone JSON document. Plugin
+// instances are referenced by plugin identifier and instance name so the document can be imported
+// for another user or server.
+type PlaylistExport struct {
+	Version    int                  `json:"version"`
+	ExportedAt time.Time            `json:"exported_at"`
+	Playlist   PlaylistExportInfo   `json:"playlist"`
+	Items      []PlaylistExportItem `json:"items"`
+}
+
+// PlaylistExportInfo holds the playlist's own settings
+type PlaylistExportInfo struct {
+	Name      string `json:"name"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// PlaylistExportItem is one playlist item and the plugin instance it shows
+type PlaylistExportItem struct {
+	OrderIndex       int                      `json:"order_index"`
+	IsVisible        bool                     `json:"is_visible"`
+	Importance       bool                     `json:"importance"`
+	DurationOverride *int                     `json:"duration_override,omitempty"`
+	MinDisplayCount  int                      `json:"min_display_count"`
+	PluginInstance   PlaylistExportInstance   `json:"plugin_instance"`
+	Schedules        []PlaylistExportSchedule `json:"schedules"`
+}
+
+// PlaylistExportInstance identifies a plugin instance independently of its ID
+type PlaylistExportInstance struct {
+	ID               uuid.UUID `json:"id"` // Informational, not used on import
+	Name             string    `json:"name"`
+	PluginIdentifier string    `json:"plugin_identifier"`
+	PluginType       string    `json:"plugin_type"`
+	PluginName       string    `json:"plugin_name"`
+}
+
+// PlaylistExportSchedule is a playlist item schedule without its IDs
+type PlaylistExportSchedule struct {
+	Name      string `json:"name,omitempty"`
+	DayMask   int    `json:"day_mask"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Timezone  string `json:"timezone"`
+	IsActive  bool   `json:"is_active"`
+}
+
+// ExportPlaylist builds the export document for a playlist
+func (pls *PlaylistService) ExportPlaylist(playlist *Playlist) (*PlaylistExport, error) {
+	items, err := pls.GetPlaylistItems(playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &PlaylistExport{
+		Version:    PlaylistExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Playlist: PlaylistExportInfo{
+			Name:      playlist.Name,
+			IsDefault: playlist.IsDefault,
+		},
+		Items: make([]PlaylistExportItem, 0, len(items)),
+	}
+	for _, item := range items {
+		exportItem := PlaylistExportItem{
+			OrderIndex:       item.OrderIndex,
+			IsVisible:        item.IsVisible,
+			Importance:       item.Importance,
+			DurationOverride: item.DurationOverride,
+			MinDisplayCount:  item.MinDisplayCount,
+			PluginInstance: PlaylistExportInstance{
+				ID:               item.PluginInstance.ID,
+				Name:             item.PluginInstance.Name,
+				PluginIdentifier: item.PluginInstance.PluginDefinition.Identifier,
+				PluginType:       item.PluginInstance.PluginDefinition.PluginType,
+				PluginName:       item.PluginInstance.PluginDefinition.Name,
+			},
+			Schedules: make([]PlaylistExportSchedule, 0, len(item.Schedules)),
+		}
+		for _, schedule := range item.Schedules {
+			exportItem.Schedules = append(exportItem.Schedules, PlaylistExportSchedule{
+				Name:      schedule.Name,
+				DayMask:   schedule.DayMask,
+				StartTime: schedule.StartTime,
+				EndTime:   schedule.EndTime,
+				Timezone:  schedule.Timezone,
+				IsActive:  schedule.IsActive,
+			})
+		}
+		export.Items = append(export.Items, exportItem)
+	}
+	return export, nil
+}
+
+// ImportPlaylist creates a playlist on a device from an export document. Plugin instances are matched
+// among the user's own instances by plugin identifier and instance name; items that can't be matched and
+// schedules that aren't valid are skipped and described in the returned warnings.
+func (pls *PlaylistService) ImportPlaylist(userID, deviceID uuid.UUID, name string, isDefault bool, export *PlaylistExport) (*Playlist, []PlaylistItem, []string, error) {
+	warnings := []string{}
+
+	// Resolve every instance before creating anything so a bad document leaves no partial playlist behind
+	type resolvedItem struct {
+		source     PlaylistExportItem
+		instanceID uuid.UUID
+	}
+	var resolved []resolvedItem
+	for i, item := range export.Items {
+		ref := item.PluginInstance
+		var instances []PluginInstance
+		err := pls.db.Joins("JOIN plugin_definitions ON plugin_definitions.id = plugin_instances.plugin_definition_id").
+			Where("plugin_instances.user_id = ? AND plugin_definitions.identifier = ? AND plugin_instances.name = ?", userID, ref.PluginIdentifier, ref.Name).
+			Order("plugin_instances.created_at ASC").
+			Find(&instances).Error
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(instances) == 0 {
+			warnings = append(warnings, fmt.Sprintf("Item %d skipped: no plugin instance named %q for plugin %q", i+1, ref.Name, ref.PluginIdentifier))
+			continue
+		}
+		if len(instances) > 1 {
+			warnings = append(warnings, fmt.Sprintf("Item %d: %d plugin instances named %q for plugin %q, using the oldest", i+1, len(instances), ref.Name, ref.PluginIdentifier))
+		}
+		resolved = append(resolved, resolvedItem{source: item, instanceID: instances[0].ID})
+	}
+
+	var playlist *Playlist
+	var items []PlaylistItem
+	err := pls.db.Transaction(func(tx *gorm.DB) error {
+		txService := NewPlaylistService(tx)
+		var err error
+		playlist, err = txService.CreatePlaylist(userID, deviceID, name, isDefault)
+		if err != nil {
+			return err
+		}
+
+		for i, r := range resolved {
+			item := PlaylistItem{
+				PlaylistID:       playlist.ID,
+				PluginInstanceID: r.instanceID,
+			}
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+			// Set the remaining fields with Updates so false values aren't replaced by column defaults
+			updates := map[string]interface{}{
+				"order_index":       i + 1,
+				"is_visible":        r.source.IsVisible,
+				"importance":        r.source.Importance,
+				"duration_override": r.source.DurationOverride,
+				"min_display_count": r.source.MinDisplayCount,
+			}
+			if err := tx.Model(&item).Updates(updates).Error; err != nil {
+				return err
+			}
+
+			for _, s := range r.source.Schedules {
+				timezone := s.Timezone
+				if timezone == "" {
+					timezone = "UTC"
+				}
+				if err := utils.ValidateTimezone(timezone); err != nil {
+					warnings = append(warnings, fmt.Sprintf("Schedule %q of %q skipped: invalid timezone %q", s.Name, r.source.PluginInstance.Name, timezone))
+					continue
+				}
+				if s.DayMask == 0 || s.StartTime == "" || s.EndTime == "" {
+					warnings = append(warnings, fmt.Sprintf("Schedule %q of %q skipped: day mask, start time and end time are required", s.Name, r.source.PluginInstance.Name))
+					continue
+				}
+				schedule := Schedule{
+					PlaylistItemID: item.ID,
+					Name:           s.Name,
+					DayMask:        s.DayMask,
+					StartTime:      s.StartTime,
+					EndTime:        s.EndTime,
+					Timezone:       timezone,
+					IsActive:       s.IsActive,
+				}
+				if err := tx.Create(&schedule).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Preload("PluginInstance").Preload("Schedules").
+			Where("playlist_id = ?", playlist.ID).
+			Order("order_index ASC").
+			Find(&items).Error
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	logging.Info("[PLAYLIST] Imported playlist", "playlist_id", playlist.ID, "device_id", deviceID, "items", len(items), "skipped_items", len(export.Items)-len(resolved))
+	return playlist, items, warnings, nil
+}
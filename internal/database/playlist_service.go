@@ -157,6 +157,23 @@ func (pls *PlaylistService) ReorderPlaylistItems(playlistID uuid.UUID, itemOrder
 	})
 }
 
+// SetPlaylistItemsVisibility shows or hides several items of a playlist in one transaction.
+// It fails without changes if any item does not belong to the playlist.
+func (pls *PlaylistService) SetPlaylistItemsVisibility(playlistID uuid.UUID, visibility map[uuid.UUID]bool) error {
+	return pls.db.Transaction(func(tx *gorm.DB) error {
+		for itemID, visible := range visibility {
+			result := tx.Model(&PlaylistItem{}).Where("id = ? AND playlist_id = ?", itemID, playlistID).Update("is_visible", visible)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
 // ReorderPlaylistItemsByArray updates playlist items to match the provided order array
 func (pls *PlaylistService) ReorderPlaylistItemsByArray(playlistID uuid.UUID, orderedItemIDs []uuid.UUID) error {
 	return pls.db.Transaction(func(tx *gorm.DB) error {
@@ -676,6 +693,85 @@ func (pls *PlaylistService) GetDevicesUsingPluginInstance(pluginInstanceID uuid.
 		Joins("JOIN playlist_items ON playlists.id = playlist_items.playlist_id").
 		Where("playlist_items.plugin_instance_id = ? AND devices.is_active = ?", pluginInstanceID, true).
 		Find(&devices).Error
-	
-	return devices, err
+	if err != nil {
+		return nil, err
+	}
+
+	// Devices showing this instance while their playlist is empty need it rendered too
+	var emptyPlaylistDevices []Device
+	err = pls.db.Preload("DeviceModel").
+		Where("empty_playlist_mode = ? AND empty_playlist_instance_id = ? AND is_active = ?", EmptyPlaylistModePlugin, pluginInstanceID, true).
+		Find(&emptyPlaylistDevices).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range emptyPlaylistDevices {
+		found := false
+		for _, device := range devices {
+			if device.ID == candidate.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			devices = append(devices, candidate)
+		}
+	}
+
+	return devices, nil
+}
+
+// PluginInstanceDeviceUsage describes a device that shows a plugin instance, either directly or
+// as a child of one or more mashups
+type PluginInstanceDeviceUsage struct {
+	Device    Device      `json:"device"`
+	Direct    bool        `json:"direct"`     // In a playlist or shown while the playlist is empty
+	MashupIDs []uuid.UUID `json:"mashup_ids"` // Mashup instances through which the device shows it
+}
+
+// GetDeviceUsageForPluginInstance returns the user's active devices showing a plugin instance, including
+// devices that only show it through a mashup it is a child of
+func (pls *PlaylistService) GetDeviceUsageForPluginInstance(pluginInstanceID, userID uuid.UUID) ([]PluginInstanceDeviceUsage, error) {
+	var usage []PluginInstanceDeviceUsage
+	index := make(map[uuid.UUID]int)
+
+	add := func(devices []Device, mashupID *uuid.UUID) {
+		for _, device := range devices {
+			if device.UserID == nil || *device.UserID != userID {
+				continue
+			}
+			i, exists := index[device.ID]
+			if !exists {
+				usage = append(usage, PluginInstanceDeviceUsage{Device: device, MashupIDs: []uuid.UUID{}})
+				i = len(usage) - 1
+				index[device.ID] = i
+			}
+			if mashupID == nil {
+				usage[i].Direct = true
+			} else {
+				usage[i].MashupIDs = append(usage[i].MashupIDs, *mashupID)
+			}
+		}
+	}
+
+	devices, err := pls.GetDevicesUsingPluginInstance(pluginInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	add(devices, nil)
+
+	var mashupIDs []uuid.UUID
+	if err := pls.db.Model(&MashupChild{}).Where("child_instance_id = ?", pluginInstanceID).
+		Distinct().Pluck("mashup_instance_id", &mashupIDs).Error; err != nil {
+		return nil, err
+	}
+	for i := range mashupIDs {
+		devices, err := pls.GetDevicesUsingPluginInstance(mashupIDs[i])
+		if err != nil {
+			return nil, err
+		}
+		add(devices, &mashupIDs[i])
+	}
+
+	return usage, nil
 }
@@ -0,0 +1,320 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPluginNotPublishable = errors.New("only private plugins can be published")
+	ErrInstallOwnPlugin     = errors.New("cannot install your own plugin from the catalog")
+)
+
+// CatalogPlugin is a published plugin as listed in the shared catalog
+type CatalogPlugin struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Version      string     `json:"version"`
+	Author       string     `json:"author"`       // Original author of the plugin
+	PublishedBy  string     `json:"published_by"` // Username of the user who published it
+	DataStrategy *string    `json:"data_strategy,omitempty"`
+	PublishedAt  *time.Time `json:"published_at,omitempty"`
+	InstallCount int64      `json:"install_count"`
+
+	// Full definition, only included when fetching a single catalog plugin
+	Definition *PluginDefinition `json:"definition,omitempty"`
+}
+
+// publishedDefinitions returns a query over definitions visible in the catalog
+func (s *UnifiedPluginService) publishedDefinitions() *gorm.DB {
+	return s.db.Model(&PluginDefinition{}).
+		Where("is_published = ? AND is_active = ? AND plugin_type = ?", true, true, "private")
+}
+
+// SetPluginDefinitionPublished publishes or unpublishes a private plugin definition. Only the owner can
+// publish; the owner or an admin can unpublish.
+func (s *UnifiedPluginService) SetPluginDefinitionPublished(id string, user *User, published bool) (*PluginDefinition, error) {
+	var definition PluginDefinition
+	query := s.db.Where("id = ? AND is_active = ?", id, true)
+	if published || !user.IsAdmin {
+		query = query.Where("owner_id = ?", user.ID)
+	}
+	if err := query.First(&definition).Error; err != nil {
+		return nil, err
+	}
+	if definition.PluginType != "private" {
+		return nil, ErrPluginNotPublishable
+	}
+
+	updates := map[string]interface{}{"is_published": published}
+	if published {
+		updates["published_at"] = time.Now().UTC()
+	} else {
+		updates["published_at"] = nil
+	}
+	if err := s.db.Model(&definition).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// GetCatalogPlugins lists published plugins, newest first, optionally filtered by name or description
+func (s *UnifiedPluginService) GetCatalogPlugins(search string) ([]CatalogPlugin, error) {
+	query := s.publishedDefinitions().Preload("Owner")
+	if search = strings.TrimSpace(search); search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", pattern, pattern)
+	}
+
+	var definitions []PluginDefinition
+	if err := query.Order("published_at DESC").Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+
+	installCounts, err := s.catalogInstallCounts(definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := make([]CatalogPlugin, 0, len(definitions))
+	for i := range definitions {
+		plugin := newCatalogPlugin(&definitions[i])
+		plugin.InstallCount = installCounts[definitions[i].ID]
+		plugins = append(plugins, plugin)
+	}
+	return plugins, nil
+}
+
+// GetCatalogPlugin returns a published plugin with its full definition
+func (s *UnifiedPluginService) GetCatalogPlugin(id string) (*CatalogPlugin, error) {
+	var definition PluginDefinition
+	if err := s.publishedDefinitions().Preload("Owner").Where("id = ?", id).First(&definition).Error; err != nil {
+		return nil, err
+	}
+
+	installCounts, err := s.catalogInstallCounts([]PluginDefinition{definition})
+	if err != nil {
+		return nil, err
+	}
+
+	plugin := newCatalogPlugin(&definition)
+	plugin.InstallCount = installCounts[definition.ID]
+	// Don't expose the publisher's account or polling credentials through the definition
+	definition.Owner = nil
+	definition.OwnerID = nil
+	definition.PollingConfig = redactPollingConfig(definition.PollingConfig)
+	plugin.Definition = &definition
+	return &plugin, nil
+}
+
+// InstallCatalogPlugin copies a published plugin into the user's account as a new private plugin,
+// keeping the original author and a link to the catalog plugin for attribution
+func (s *UnifiedPluginService) InstallCatalogPlugin(id string, userID uuid.UUID) (*PluginDefinition, error) {
+	var source PluginDefinition
+	if err := s.publishedDefinitions().Preload("Owner").Where("id = ?", id).First(&source).Error; err != nil {
+		return nil, err
+	}
+	if source.OwnerID != nil && *source.OwnerID == userID {
+		return nil, ErrInstallOwnPlugin
+	}
+
+	now := time.Now().UTC()
+	copied := PluginDefinition{
+		PluginType:         "private",
+		OwnerID:            &userID,
+		Identifier:         uuid.New().String(),
+		Name:               source.Name,
+		Description:        source.Description,
+		Version:            source.Version,
+		Author:             catalogAuthor(&source),
+		ConfigSchema:       source.ConfigSchema,
+		RequiresProcessing: source.RequiresProcessing,
+		Status:             "available",
+		MarkupFull:         source.MarkupFull,
+		MarkupHalfVert:     source.MarkupHalfVert,
+		MarkupHalfHoriz:    source.MarkupHalfHoriz,
+		MarkupQuadrant:     source.MarkupQuadrant,
+		SharedMarkup:       source.SharedMarkup,
+		DataStrategy:       source.DataStrategy,
+		PollingConfig:      redactPollingConfig(source.PollingConfig),
+		FormFields:         source.FormFields,
+		RemoveBleedMargin:  source.RemoveBleedMargin,
+		EnableDarkMode:     source.EnableDarkMode,
+		EnableBackdrop:     source.EnableBackdrop,
+		SampleData:         source.SampleData,
+		SchemaVersion:      1,
+		MinScreenWidth:     source.MinScreenWidth,
+		MinScreenHeight:    source.MinScreenHeight,
+		MinBitDepth:        source.MinBitDepth,
+		RequiresColor:      source.RequiresColor,
+		SourceDefinitionID: &source.ID,
+		IsActive:           true,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	copied.HashIgnoreRegionsJSON = source.HashIgnoreRegionsJSON
+
+	if err := s.db.Create(&copied).Error; err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+// secretQueryParamPattern matches query parameter names that usually carry credentials
+var secretQueryParamPattern = regexp.MustCompile(`(?i)(key|token|secret|password|passwd|auth|signature|sig|credential|session|appid)`)
+
+// redactPollingConfig removes the publisher's credentials from a polling config before it is shared.
+// Header values and secret-looking query parameters are blanked unless they come from a template, in which
+// case the installer's own settings fill them in. Header names are kept so installers can see what to set.
+func redactPollingConfig(raw datatypes.JSON) datatypes.JSON {
+	if len(raw) == 0 {
+		return raw
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil
+	}
+
+	if urls, ok := config["urls"].([]interface{}); ok {
+		for _, entry := range urls {
+			urlConfig, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if rawURL, ok := urlConfig["url"].(string); ok {
+				urlConfig["url"] = redactPollingURL(rawURL)
+			}
+			switch headers := urlConfig["headers"].(type) {
+			case string:
+				urlConfig["headers"] = redactHeaderString(headers)
+			case map[string]interface{}:
+				for key, value := range headers {
+					if text, ok := value.(string); !ok || !isTemplatedValue(text) {
+						headers[key] = ""
+					}
+				}
+			}
+		}
+	}
+
+	redacted, err := json.Marshal(config)
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// redactHeaderString blanks the values of a TRMNL-format header string (key=value&key2=value2)
+func redactHeaderString(headers string) string {
+	pairs := strings.Split(headers, "&")
+	for i, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) == 2 && !isTemplatedValue(parts[1]) {
+			pairs[i] = parts[0] + "="
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// redactPollingURL drops credentials in the URL's user info and blanks secret-looking query parameters
+func redactPollingURL(rawURL string) string {
+	// Templated URLs may not parse, and only their literal parts could hold secrets
+	queryStart := strings.Index(rawURL, "?")
+	base, query := rawURL, ""
+	if queryStart >= 0 {
+		base, query = rawURL[:queryStart], rawURL[queryStart+1:]
+	}
+
+	if schemeEnd := strings.Index(base, "://"); schemeEnd >= 0 {
+		rest := base[schemeEnd+3:]
+		hostEnd := strings.Index(rest, "/")
+		if hostEnd < 0 {
+			hostEnd = len(rest)
+		}
+		if at := strings.LastIndex(rest[:hostEnd], "@"); at >= 0 {
+			base = base[:schemeEnd+3] + rest[at+1:]
+		}
+	}
+	if query == "" {
+		return base
+	}
+
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		parts := strings.SplitN(param, "=", 2)
+		if len(parts) == 2 && secretQueryParamPattern.MatchString(parts[0]) && !isTemplatedValue(parts[1]) {
+			params[i] = parts[0] + "="
+		}
+	}
+	return base + "?" + strings.Join(params, "&")
+}
+
+// isTemplatedValue reports whether a value is filled in from settings at poll time rather than stored literally
+func isTemplatedValue(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// catalogInstallCounts counts the installed copies of each definition
+func (s *UnifiedPluginService) catalogInstallCounts(definitions []PluginDefinition) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if len(definitions) == 0 {
+		return counts, nil
+	}
+
+	ids := make([]string, len(definitions))
+	for i, definition := range definitions {
+		ids[i] = definition.ID
+	}
+
+	var rows []struct {
+		SourceDefinitionID string
+		Count              int64
+	}
+	if err := s.db.Model(&PluginDefinition{}).
+		Select("source_definition_id, COUNT(*) AS count").
+		Where("source_definition_id IN ? AND is_active = ?", ids, true).
+		Group("source_definition_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.SourceDefinitionID] = row.Count
+	}
+	return counts, nil
+}
+
+// newCatalogPlugin builds the catalog listing for a published definition
+func newCatalogPlugin(definition *PluginDefinition) CatalogPlugin {
+	plugin := CatalogPlugin{
+		ID:           definition.ID,
+		Name:         definition.Name,
+		Description:  definition.Description,
+		Version:      definition.Version,
+		Author:       catalogAuthor(definition),
+		DataStrategy: definition.DataStrategy,
+		PublishedAt:  definition.PublishedAt,
+	}
+	if definition.Owner != nil {
+		plugin.PublishedBy = definition.Owner.Username
+	}
+	return plugin
+}
+
+// catalogAuthor returns the author to credit for a definition, falling back to its owner's username
+func catalogAuthor(definition *PluginDefinition) string {
+	if definition.Author != "" {
+		return definition.Author
+	}
+	if definition.Owner != nil {
+		return definition.Owner.Username
+	}
+	return ""
+}
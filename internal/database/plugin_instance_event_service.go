@@ -0,0 +1,63 @@
+package database
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// maxPluginInstanceEvents caps the events kept per instance; older ones are pruned as new ones arrive
+const maxPluginInstanceEvents = 500
+
+// RecordPluginInstanceEvent adds an event to a plugin instance's activity timeline. Failures are
+// logged rather than returned so recording never breaks the operation being recorded.
+func RecordPluginInstanceEvent(db *gorm.DB, instanceID uuid.UUID, eventType, message string, details map[string]interface{}) {
+	event := PluginInstanceEvent{
+		PluginInstanceID: instanceID,
+		Type:             eventType,
+		Message:          message,
+	}
+	if len(details) > 0 {
+		if detailsJSON, err := json.Marshal(details); err == nil {
+			event.Details = detailsJSON
+		}
+	}
+
+	if err := db.Create(&event).Error; err != nil {
+		logging.Warn("[PLUGIN_EVENTS] Failed to record plugin instance event", "plugin_instance_id", instanceID, "type", eventType, "error", err)
+		return
+	}
+
+	err := db.Where("plugin_instance_id = ? AND id NOT IN (?)", instanceID,
+		db.Model(&PluginInstanceEvent{}).Select("id").
+			Where("plugin_instance_id = ?", instanceID).
+			Order("created_at DESC").
+			Limit(maxPluginInstanceEvents),
+	).Delete(&PluginInstanceEvent{}).Error
+	if err != nil {
+		logging.Warn("[PLUGIN_EVENTS] Failed to prune plugin instance events", "plugin_instance_id", instanceID, "error", err)
+	}
+}
+
+// recordPluginInstanceEventByID records an event for an instance ID stored as a string, as the
+// polling and webhook data tables do
+func recordPluginInstanceEventByID(db *gorm.DB, instanceID, eventType, message string, details map[string]interface{}) {
+	id, err := uuid.Parse(instanceID)
+	if err != nil {
+		return
+	}
+	RecordPluginInstanceEvent(db, id, eventType, message, details)
+}
+
+// GetPluginInstanceEvents returns an instance's most recent events, newest first
+func GetPluginInstanceEvents(db *gorm.DB, instanceID uuid.UUID, limit int) ([]PluginInstanceEvent, error) {
+	var events []PluginInstanceEvent
+	err := db.Where("plugin_instance_id = ?", instanceID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
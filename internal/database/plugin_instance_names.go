@@ -0,0 +1,43 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// UniquePluginInstanceNamesSettingKey is the system setting that requires each user's plugin instance names to be unique
+const UniquePluginInstanceNamesSettingKey = "unique_plugin_instance_names"
+
+// ErrPluginInstanceNameTaken is returned when unique instance names are enforced and the user already has an instance with the name
+var ErrPluginInstanceNameTaken = errors.New("a plugin instance with this name already exists")
+
+// UniquePluginInstanceNamesEnabled reports whether plugin instance names must be unique per user
+func UniquePluginInstanceNamesEnabled() bool {
+	value, err := GetSystemSetting(UniquePluginInstanceNamesSettingKey)
+	return err == nil && value == "true"
+}
+
+// CheckPluginInstanceName returns ErrPluginInstanceNameTaken when unique names are enforced and another
+// of the user's instances already uses the name, ignoring case and surrounding whitespace. excludeID is
+// the instance being renamed, if any.
+func (s *UnifiedPluginService) CheckPluginInstanceName(userID uuid.UUID, name string, excludeID *uuid.UUID) error {
+	if !UniquePluginInstanceNamesEnabled() {
+		return nil
+	}
+
+	query := s.db.Model(&PluginInstance{}).
+		Where("user_id = ? AND LOWER(TRIM(name)) = ?", userID, strings.ToLower(strings.TrimSpace(name)))
+	if excludeID != nil {
+		query = query.Where("id != ?", *excludeID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPluginInstanceNameTaken
+	}
+	return nil
+}
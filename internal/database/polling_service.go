@@ -26,17 +26,22 @@ func (s *PollingDataService) StorePollingData(data *PrivatePluginPollingData) er
 		return fmt.Errorf("invalid merged data JSON: %w", err)
 	}
 
+	fields := map[string]interface{}{
+		"merged_data":    data.MergedData,
+		"raw_data":       data.RawData,
+		"polled_at":      data.PolledAt,
+		"poll_duration":  data.PollDuration,
+		"success":        data.Success,
+		"errors":         data.Errors,
+		"url_count":      data.URLCount,
+	}
+	if data.Success {
+		fields["last_success_at"] = data.PolledAt
+	}
+
 	// UPSERT: Update existing record or create new one (single record per plugin instance)
 	result := s.db.Where("plugin_instance_id = ?", data.PluginInstanceID).
-		Assign(map[string]interface{}{
-			"merged_data":    data.MergedData,
-			"raw_data":       data.RawData,
-			"polled_at":      data.PolledAt,
-			"poll_duration":  data.PollDuration,
-			"success":        data.Success,
-			"errors":         data.Errors,
-			"url_count":      data.URLCount,
-		}).
+		Assign(fields).
 		FirstOrCreate(&PrivatePluginPollingData{
 			ID:               data.ID,
 			PluginInstanceID: data.PluginInstanceID,
@@ -46,6 +51,13 @@ func (s *PollingDataService) StorePollingData(data *PrivatePluginPollingData) er
 		return fmt.Errorf("failed to store polling data: %w", result.Error)
 	}
 
+	if !data.Success {
+		var pollErrors []string
+		json.Unmarshal(data.Errors, &pollErrors)
+		recordPluginInstanceEventByID(s.db, data.PluginInstanceID, PluginInstanceEventPollFailed, "Polling failed",
+			map[string]interface{}{"errors": pollErrors, "duration_ms": data.PollDuration.Milliseconds()})
+	}
+
 	return nil
 }
 
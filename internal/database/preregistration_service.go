@@ -0,0 +1,154 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidMACAddress       = errors.New("invalid MAC address")
+	ErrMACAlreadyPreregistered = errors.New("MAC address is already pre-registered")
+	ErrDeviceAlreadyRegistered = errors.New("a device with this MAC address has already connected")
+	ErrPreregistrationUser     = errors.New("user not found or inactive")
+)
+
+// PreregistrationService handles pre-registered device database operations
+type PreregistrationService struct {
+	db *gorm.DB
+}
+
+// NewPreregistrationService creates a new pre-registration service
+func NewPreregistrationService(db *gorm.DB) *PreregistrationService {
+	return &PreregistrationService{db: db}
+}
+
+// NormalizeMACAddress validates a MAC address in any of the accepted formats and returns it as AA:BB:CC:DD:EE:FF
+func NormalizeMACAddress(mac string) (string, error) {
+	ds := &DeviceService{}
+	if !ds.isMAC(mac) {
+		return "", ErrInvalidMACAddress
+	}
+	return ds.normalizeMAC(mac), nil
+}
+
+// CreatePreregistration assigns a MAC address to a user ahead of the device's first connection
+func (prs *PreregistrationService) CreatePreregistration(adminID, userID uuid.UUID, macAddress, name string) (*DevicePreregistration, error) {
+	normalized, err := NormalizeMACAddress(macAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if err := prs.db.Select("id").Where("id = ? AND is_active = ?", userID, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPreregistrationUser
+		}
+		return nil, err
+	}
+
+	var count int64
+	if err := prs.db.Model(&Device{}).Where("UPPER(mac_address) = ?", normalized).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrDeviceAlreadyRegistered
+	}
+
+	if err := prs.db.Model(&DevicePreregistration{}).Where("mac_address = ?", normalized).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrMACAlreadyPreregistered
+	}
+
+	preregistration := &DevicePreregistration{
+		MacAddress: normalized,
+		UserID:     userID,
+		Name:       name,
+		CreatedBy:  &adminID,
+	}
+	if err := prs.db.Create(preregistration).Error; err != nil {
+		return nil, err
+	}
+
+	logging.Info("[PREREGISTER] Pre-registered device", "mac_address", normalized, "user_id", userID)
+	return preregistration, nil
+}
+
+// GetPreregistrations returns pre-registered devices with their users, optionally including ones already claimed
+func (prs *PreregistrationService) GetPreregistrations(includeClaimed bool) ([]DevicePreregistration, error) {
+	var preregistrations []DevicePreregistration
+	query := prs.db.Preload("User")
+	if !includeClaimed {
+		query = query.Where("claimed_at IS NULL")
+	}
+	err := query.Order("created_at DESC").Find(&preregistrations).Error
+	return preregistrations, err
+}
+
+// DeletePreregistration removes a pre-registration
+func (prs *PreregistrationService) DeletePreregistration(id uuid.UUID) error {
+	result := prs.db.Delete(&DevicePreregistration{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ClaimPreregisteredDevice claims a newly registered device for the user its MAC address was
+// pre-registered to. It returns nil without an error when the MAC isn't pre-registered.
+func (prs *PreregistrationService) ClaimPreregisteredDevice(device *Device) (*DevicePreregistration, error) {
+	normalized, err := NormalizeMACAddress(device.MacAddress)
+	if err != nil {
+		return nil, nil
+	}
+
+	var preregistration DevicePreregistration
+	err = prs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("mac_address = ? AND claimed_at IS NULL", normalized).First(&preregistration).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&Device{}).
+			Where("id = ? AND is_claimed = ?", device.ID, false).
+			Updates(map[string]interface{}{
+				"user_id":    preregistration.UserID,
+				"name":       preregistration.Name,
+				"is_claimed": true,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDeviceAlreadyClaimed
+		}
+
+		now := time.Now().UTC()
+		preregistration.DeviceID = &device.ID
+		preregistration.ClaimedAt = &now
+		return tx.Model(&preregistration).Updates(map[string]interface{}{
+			"device_id":  device.ID,
+			"claimed_at": now,
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	device.UserID = &preregistration.UserID
+	device.Name = preregistration.Name
+	device.IsClaimed = true
+
+	logging.Info("[PREREGISTER] Claimed pre-registered device", "mac_address", normalized, "friendly_id", device.FriendlyID, "user_id", preregistration.UserID)
+	return &preregistration, nil
+}
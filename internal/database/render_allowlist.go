@@ -0,0 +1,69 @@
+package database
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// RenderDeviceAllowlist returns the devices an instance is limited to rendering for, or nil when it renders for all
+func (pi *PluginInstance) RenderDeviceAllowlist() []uuid.UUID {
+	if len(pi.RenderDeviceIDs) == 0 {
+		return nil
+	}
+	var ids []uuid.UUID
+	if err := json.Unmarshal(pi.RenderDeviceIDs, &ids); err != nil {
+		logging.Warn("[RENDER ALLOWLIST] Failed to decode render device allowlist", "instance_id", pi.ID, "error", err)
+		return nil
+	}
+	return ids
+}
+
+// SetRenderDeviceAllowlist stores the devices an instance renders for; an empty list renders for all devices
+func (pi *PluginInstance) SetRenderDeviceAllowlist(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		pi.RenderDeviceIDs = nil
+		return nil
+	}
+	encoded, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	pi.RenderDeviceIDs = encoded
+	return nil
+}
+
+// RendersForDevice reports whether an instance's allowlist permits rendering for a device
+func (pi *PluginInstance) RendersForDevice(deviceID uuid.UUID) bool {
+	allowlist := pi.RenderDeviceAllowlist()
+	if allowlist == nil {
+		return true
+	}
+	for _, id := range allowlist {
+		if id == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRenderDevicesForPluginInstance returns the devices using a plugin instance that it should render
+// for, applying the instance's render allowlist
+func (pls *PlaylistService) GetRenderDevicesForPluginInstance(pluginInstance PluginInstance) ([]Device, error) {
+	devices, err := pls.GetDevicesUsingPluginInstance(pluginInstance.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pluginInstance.RenderDeviceAllowlist() == nil {
+		return devices, nil
+	}
+
+	allowed := make([]Device, 0, len(devices))
+	for _, device := range devices {
+		if pluginInstance.RendersForDevice(device.ID) {
+			allowed = append(allowed, device)
+		}
+	}
+	return allowed, nil
+}
@@ -69,6 +69,38 @@ func (s *UnifiedPluginService) UpdatePluginDefinition(definition *PluginDefiniti
 	return s.db.Save(definition).Error
 }
 
+// PluginInstanceUsage describes an active instance that would be removed with its plugin definition
+type PluginInstanceUsage struct {
+	InstanceID        uuid.UUID `json:"instance_id"`
+	Name              string    `json:"name"`
+	UserID            uuid.UUID `json:"user_id"`
+	PlaylistItemCount int64     `json:"playlist_item_count"`
+}
+
+// GetPluginDefinitionUsage lists the active instances of a plugin definition and how many
+// playlist items reference each, so callers can warn before a cascading delete
+func (s *UnifiedPluginService) GetPluginDefinitionUsage(id string) ([]PluginInstanceUsage, error) {
+	var instances []PluginInstance
+	if err := s.db.Where("plugin_definition_id = ? AND is_active = ?", id, true).Order("name").Find(&instances).Error; err != nil {
+		return nil, fmt.Errorf("failed to find plugin instances: %w", err)
+	}
+
+	usage := make([]PluginInstanceUsage, 0, len(instances))
+	for _, instance := range instances {
+		var itemCount int64
+		if err := s.db.Model(&PlaylistItem{}).Where("plugin_instance_id = ?", instance.ID).Count(&itemCount).Error; err != nil {
+			return nil, fmt.Errorf("failed to count playlist items for instance %s: %w", instance.ID, err)
+		}
+		usage = append(usage, PluginInstanceUsage{
+			InstanceID:        instance.ID,
+			Name:              instance.Name,
+			UserID:            instance.UserID,
+			PlaylistItemCount: itemCount,
+		})
+	}
+	return usage, nil
+}
+
 // DeletePluginDefinition soft deletes a plugin definition and cascades to all instances
 func (s *UnifiedPluginService) DeletePluginDefinition(id string, ownerID *uuid.UUID) error {
 	return s.db.Transaction(func(tx *gorm.DB) error {
@@ -174,6 +206,10 @@ func (s *UnifiedPluginService) CreatePluginInstance(userID uuid.UUID, definition
 	if definition.PluginType == "private" && (definition.OwnerID == nil || *definition.OwnerID != userID) {
 		return nil, fmt.Errorf("user does not own this private plugin definition")
 	}
+
+	if err := s.CheckPluginInstanceName(userID, name, nil); err != nil {
+		return nil, err
+	}
 	
 	// Convert settings to JSON
 	settingsJSON, err := json.Marshal(settings)
@@ -313,7 +349,10 @@ func (s *UnifiedPluginService) advanceDevicesFromPlaylistItems(tx *gorm.DB, play
 		// If no active items remain, nextItemID stays nil (will clear the reference)
 		
 		// Update device's last_playlist_item_id
-		if err := tx.Model(&Device{}).Where("id = ?", device.ID).Update("last_playlist_item_id", nextItemID).Error; err != nil {
+		if err := tx.Model(&Device{}).Where("id = ?", device.ID).Updates(map[string]interface{}{
+			"last_playlist_item_id":            nextItemID,
+			"last_playlist_item_display_count": 0,
+		}).Error; err != nil {
 			return fmt.Errorf("failed to update device %s playlist reference: %w", device.ID, err)
 		}
 	}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"gorm.io/gorm"
+)
+
+// Behaviors for devices that report a model missing from the device_models table
+const (
+	UnknownModelNone        = "none"        // Register the device without a model
+	UnknownModelReject      = "reject"      // Refuse to register the device
+	UnknownModelDefault     = "default"     // Assign the configured default model
+	UnknownModelProvisional = "provisional" // Create a model entry flagged for admin review
+)
+
+const (
+	// UnknownModelBehaviorSettingKey is the system setting selecting the unknown model behavior
+	UnknownModelBehaviorSettingKey = "unknown_model_behavior"
+	// UnknownModelDefaultSettingKey is the system setting naming the fallback device model
+	UnknownModelDefaultSettingKey = "unknown_model_default"
+)
+
+// ErrUnknownDeviceModel is returned when a device reports an unknown model and the behavior is reject
+var ErrUnknownDeviceModel = errors.New("unknown device model")
+
+// IsValidUnknownModelBehavior reports whether a value is a supported unknown model behavior
+func IsValidUnknownModelBehavior(behavior string) bool {
+	switch behavior {
+	case UnknownModelNone, UnknownModelReject, UnknownModelDefault, UnknownModelProvisional:
+		return true
+	}
+	return false
+}
+
+// GetUnknownModelBehavior returns the configured unknown model behavior, falling back to none
+func GetUnknownModelBehavior() string {
+	value, err := GetSystemSetting(UnknownModelBehaviorSettingKey)
+	if err != nil || !IsValidUnknownModelBehavior(value) {
+		return UnknownModelNone
+	}
+	return value
+}
+
+// findActiveDeviceModel returns the latest active version of a model
+func findActiveDeviceModel(db *gorm.DB, modelName string) (*DeviceModel, error) {
+	var deviceModel DeviceModel
+	err := db.Where("model_name = ? AND is_active = ? AND deleted_at IS NULL", modelName, true).
+		Order("created_at DESC").
+		First(&deviceModel).Error
+	if err != nil {
+		return nil, err
+	}
+	return &deviceModel, nil
+}
+
+// resolveUnknownModel applies the configured behavior to a model name with no matching device model.
+// It returns nil without an error when the device should be left without a model.
+func resolveUnknownModel(db *gorm.DB, modelName string) (*DeviceModel, error) {
+	behavior := GetUnknownModelBehavior()
+	switch behavior {
+	case UnknownModelReject:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDeviceModel, modelName)
+	case UnknownModelDefault:
+		fallback, err := unknownModelFallback(db)
+		if err != nil {
+			logging.Warn("[DEVICE MODEL] Default model for unknown models not found", "model", modelName, "error", err)
+			return nil, nil
+		}
+		logging.Info("[DEVICE MODEL] Assigning default model to unknown model", "model", modelName, "default", fallback.ModelName)
+		return fallback, nil
+	case UnknownModelProvisional:
+		return createProvisionalModel(db, modelName)
+	}
+	return nil, nil
+}
+
+// unknownModelFallback loads the model configured as the default for unknown models
+func unknownModelFallback(db *gorm.DB) (*DeviceModel, error) {
+	name, err := GetSystemSetting(UnknownModelDefaultSettingKey)
+	if err != nil || name == "" {
+		return nil, fmt.Errorf("no default model configured")
+	}
+	return findActiveDeviceModel(db, name)
+}
+
+// createProvisionalModel adds a model entry for an unknown model name, copying the display
+// characteristics of the default model so devices can render until an admin reviews it
+func createProvisionalModel(db *gorm.DB, modelName string) (*DeviceModel, error) {
+	provisional := &DeviceModel{
+		ModelName:    modelName,
+		DisplayName:  modelName + " (provisional)",
+		Description:  "Created automatically for a device reporting an unknown model",
+		ScreenWidth:  800,
+		ScreenHeight: 480,
+		ColorDepth:   1,
+		BitDepth:     1,
+		ScaleFactor:  1.0,
+		MimeType:     "image/png",
+		HasWiFi:      true,
+		HasBattery:   true,
+		IsActive:     true,
+		NeedsReview:  true,
+	}
+
+	if fallback, err := unknownModelFallback(db); err == nil {
+		provisional.ScreenWidth = fallback.ScreenWidth
+		provisional.ScreenHeight = fallback.ScreenHeight
+		provisional.ColorDepth = fallback.ColorDepth
+		provisional.BitDepth = fallback.BitDepth
+		provisional.ScaleFactor = fallback.ScaleFactor
+		provisional.Rotation = fallback.Rotation
+		provisional.OffsetX = fallback.OffsetX
+		provisional.OffsetY = fallback.OffsetY
+		provisional.MimeType = fallback.MimeType
+		provisional.HasButtons = fallback.HasButtons
+		provisional.Capabilities = fallback.Capabilities
+	}
+
+	if err := db.Create(provisional).Error; err != nil {
+		return nil, fmt.Errorf("failed to create provisional model: %w", err)
+	}
+
+	logging.Warn("[DEVICE MODEL] Created provisional model for review", "model", modelName, "id", provisional.ID)
+	return provisional, nil
+}
+
+// ReviewDeviceModel applies an admin's corrections to a device model and clears its review flag
+func (ds *DeviceService) ReviewDeviceModel(id uint, updates map[string]interface{}) (*DeviceModel, error) {
+	var deviceModel DeviceModel
+	if err := ds.db.Where("id = ? AND deleted_at IS NULL", id).First(&deviceModel).Error; err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{"needs_review": false}
+	for key, value := range updates {
+		fields[key] = value
+	}
+	if err := ds.db.Model(&deviceModel).Updates(fields).Error; err != nil {
+		return nil, err
+	}
+	return &deviceModel, nil
+}
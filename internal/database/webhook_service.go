@@ -61,6 +61,14 @@ func (s *WebhookService) StoreWebhookData(data *PrivatePluginWebhookData) error
 		return fmt.Errorf("failed to store webhook data: %w", result.Error)
 	}
 
+	recordPluginInstanceEventByID(s.db, data.PluginInstanceID, PluginInstanceEventWebhookReceived, "Webhook data received",
+		map[string]interface{}{
+			"content_type":   data.ContentType,
+			"content_size":   data.ContentSize,
+			"merge_strategy": data.MergeStrategy,
+			"source_ip":      data.SourceIP,
+		})
+
 	return nil
 }
 
@@ -114,7 +122,7 @@ func (s *WebhookService) processMergeStrategy(pluginInstanceID string, rawDataJS
 	}
 	
 	switch strategy {
-	case "default", "":
+	case "default", "replace", "":
 		// Default strategy: completely replace existing data
 		mergedData, err := json.Marshal(mergeVariables)
 		if err != nil {
@@ -122,6 +130,10 @@ func (s *WebhookService) processMergeStrategy(pluginInstanceID string, rawDataJS
 		}
 		return mergedData, nil
 		
+	case "merge":
+		// Merge strategy: replace top-level keys, keeping keys the new data doesn't mention
+		return s.processShallowMerge(pluginInstanceID, mergeVariables)
+
 	case "deep_merge":
 		// Deep merge strategy: recursively merge with existing data
 		return s.processDeepMerge(pluginInstanceID, mergeVariables)
@@ -139,6 +151,26 @@ func (s *WebhookService) processMergeStrategy(pluginInstanceID string, rawDataJS
 	}
 }
 
+// processShallowMerge sets each top-level key of the new data on the existing data. Nested objects are
+// replaced whole, whatever type the existing value had.
+func (s *WebhookService) processShallowMerge(pluginInstanceID string, newData map[string]interface{}) ([]byte, error) {
+	existingData, err := s.GetWebhookDataTemplate(pluginInstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing data: %w", err)
+	}
+
+	for key, value := range newData {
+		existingData[key] = value
+	}
+
+	mergedJSON, err := json.Marshal(existingData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merged data: %w", err)
+	}
+
+	return mergedJSON, nil
+}
+
 // processDeepMerge recursively merges new data with existing data
 func (s *WebhookService) processDeepMerge(pluginInstanceID string, newData map[string]interface{}) ([]byte, error) {
 	// Get existing data
@@ -217,7 +249,8 @@ func (s *WebhookService) processStream(pluginInstanceID string, newData map[stri
 	return streamedJSON, nil
 }
 
-// deepMerge recursively merges two maps
+// deepMerge recursively merges two maps. Only values that are objects on both sides are merged; when a
+// key's type changes between calls (e.g. an object becomes a string, or a scalar an object) the new value wins.
 func (s *WebhookService) deepMerge(existing, new map[string]interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
 	
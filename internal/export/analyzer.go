@@ -70,6 +70,9 @@ func AnalyzeBackup(backupPath string) (*BackupAnalysis, error) {
 	// If we got this far, the backup is valid
 	analysis.Valid = true
 	analysis.RecommendedAction = "Backup is valid and ready to restore"
+	if !analysis.CompatibleVersion {
+		analysis.RecommendedAction = "Upgrade Stationmaster before restoring this backup"
+	}
 
 	return analysis, nil
 }
@@ -124,7 +127,10 @@ func analyzeArchiveContents(archivePath, tempDir string, analysis *BackupAnalysi
 			analysis.Metadata = &metadata
 
 			// Check version compatibility
-			analysis.CompatibleVersion = true // For now, assume all versions are compatible
+			analysis.CompatibleVersion = IsCompatibleBackup(&metadata)
+			if !analysis.CompatibleVersion {
+				analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("Backup format version %d is newer than supported version %d", metadata.BackupFormatVersion(), BackupFormatVersion))
+			}
 		}
 
 		// Check for database directory
@@ -282,7 +288,7 @@ func AnalyzeBackupFromExtractedDir(extractedDir string) (*BackupAnalysis, error)
 	}
 
 	analysis.Metadata = &metadata
-	analysis.CompatibleVersion = true
+	analysis.CompatibleVersion = IsCompatibleBackup(&metadata)
 
 	// Check for database directory
 	dbDir := filepath.Join(extractedDir, "database")
@@ -306,6 +312,9 @@ func AnalyzeBackupFromExtractedDir(extractedDir string) (*BackupAnalysis, error)
 
 	analysis.Valid = true
 	analysis.RecommendedAction = "Backup is valid and ready to restore"
+	if !analysis.CompatibleVersion {
+		analysis.RecommendedAction = "Upgrade Stationmaster before restoring this backup"
+	}
 
 	return analysis, nil
 }
@@ -13,12 +13,18 @@ import (
 	"github.com/google/uuid"
 	"github.com/rmitchellscott/stationmaster/internal/database"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/version"
 	"gorm.io/gorm"
 )
 
+// BackupFormatVersion is the archive layout version written by this build.
+// Bump it when the archive structure changes in a way older builds cannot restore.
+const BackupFormatVersion = 1
+
 // ExportMetadata contains information about the export
 type ExportMetadata struct {
 	StationmasterVersion string    `json:"stationmaster_version"`
+	FormatVersion        int       `json:"format_version,omitempty"`
 	GitCommit            string    `json:"git_commit"`
 	ExportTimestamp      time.Time `json:"export_timestamp"`
 	DatabaseType         string    `json:"database_type"`
@@ -99,8 +105,9 @@ func (e *Exporter) Export(outputPath string, options ExportOptions) error {
 	}
 
 	// Create metadata
-	metadata.StationmasterVersion = "1.0.0" // TODO: Get from version package
-	metadata.GitCommit = "unknown"          // TODO: Get from version package
+	metadata.StationmasterVersion = version.Version
+	metadata.GitCommit = version.GitCommit
+	metadata.FormatVersion = BackupFormatVersion
 	metadata.ExportTimestamp = time.Now().UTC()
 	metadata.DatabaseType = "sqlite" // TODO: Get from database config
 	metadata.UsersExported = exportedUsers
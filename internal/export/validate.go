@@ -0,0 +1,81 @@
+package export
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotBackupArchive is returned when a file is not a Stationmaster backup archive
+var ErrNotBackupArchive = errors.New("not a Stationmaster backup archive")
+
+// gzipMagic is the two-byte header every gzip stream starts with
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// BackupFormatVersion returns the archive format version, treating backups
+// written before the field existed as format version 1
+func (m *ExportMetadata) BackupFormatVersion() int {
+	if m.FormatVersion <= 0 {
+		return 1
+	}
+	return m.FormatVersion
+}
+
+// IsCompatibleBackup reports whether this build can restore a backup with the given metadata
+func IsCompatibleBackup(metadata *ExportMetadata) bool {
+	return metadata.BackupFormatVersion() <= BackupFormatVersion
+}
+
+// ValidateBackupArchive checks that a file is a gzipped tar archive containing a
+// Stationmaster metadata.json manifest, without extracting the rest of the archive.
+// Errors caused by the file contents wrap ErrNotBackupArchive.
+func ValidateBackupArchive(archivePath string) (*ExportMetadata, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	magic, err := reader.Peek(len(gzipMagic))
+	if err != nil || !bytes.Equal(magic, gzipMagic) {
+		return nil, fmt.Errorf("%w: file is not gzip compressed", ErrNotBackupArchive)
+	}
+
+	gzReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid gzip header: %v", ErrNotBackupArchive, err)
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: metadata.json not found", ErrNotBackupArchive)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid tar archive: %v", ErrNotBackupArchive, err)
+		}
+
+		if header.Name != "metadata.json" {
+			continue
+		}
+
+		var metadata ExportMetadata
+		if err := json.NewDecoder(tarReader).Decode(&metadata); err != nil {
+			return nil, fmt.Errorf("%w: invalid metadata.json: %v", ErrNotBackupArchive, err)
+		}
+		if metadata.StationmasterVersion == "" {
+			return nil, fmt.Errorf("%w: metadata.json is missing stationmaster_version", ErrNotBackupArchive)
+		}
+
+		return &metadata, nil
+	}
+}
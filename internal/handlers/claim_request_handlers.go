@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"gorm.io/gorm"
+)
+
+// GetMyClaimRequestsHandler returns the current user's device claim requests
+func GetMyClaimRequestsHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	requests, err := database.NewClaimRequestService(database.GetDB()).GetUserClaimRequests(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch claim requests"})
+		return
+	}
+
+	// Only expose identifying device fields; the full device record includes its API key
+	response := make([]gin.H, 0, len(requests))
+	for _, request := range requests {
+		item := gin.H{
+			"id":          request.ID,
+			"device_id":   request.DeviceID,
+			"name":        request.Name,
+			"status":      request.Status,
+			"reviewed_at": request.ReviewedAt,
+			"created_at":  request.CreatedAt,
+		}
+		if request.Device != nil {
+			item["friendly_id"] = request.Device.FriendlyID
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"claim_requests": response})
+}
+
+// GetPendingClaimRequestsHandler returns all pending device claim requests (admin only)
+func GetPendingClaimRequestsHandler(c *gin.Context) {
+	requests, err := database.NewClaimRequestService(database.GetDB()).GetPendingClaimRequests()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch claim requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"claim_requests":    requests,
+		"approval_required": database.IsClaimApprovalRequired(),
+	})
+}
+
+// ApproveClaimRequestHandler approves a pending claim request and links the device to the requester (admin only)
+func ApproveClaimRequestHandler(c *gin.Context) {
+	reviewClaimRequest(c, (*database.ClaimRequestService).ApproveClaimRequest)
+}
+
+// DenyClaimRequestHandler denies a pending claim request (admin only)
+func DenyClaimRequestHandler(c *gin.Context) {
+	reviewClaimRequest(c, (*database.ClaimRequestService).DenyClaimRequest)
+}
+
+// reviewClaimRequest applies an approve or deny decision to the claim request in the URL
+func reviewClaimRequest(c *gin.Context, review func(*database.ClaimRequestService, uuid.UUID, uuid.UUID) (*database.DeviceClaimRequest, error)) {
+	admin, ok := auth.RequireAdmin(c)
+	if !ok {
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid claim request ID"})
+		return
+	}
+
+	request, err := review(database.NewClaimRequestService(database.GetDB()), requestID, admin.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Claim request not found"})
+		case errors.Is(err, database.ErrClaimRequestNotPending):
+			c.JSON(http.StatusConflict, gin.H{"error": "Claim request has already been reviewed"})
+		case errors.Is(err, database.ErrDeviceAlreadyClaimed):
+			c.JSON(http.StatusConflict, gin.H{"error": "Device has already been claimed"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update claim request"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"claim_request": request})
+}
@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +16,7 @@ import (
 	"github.com/rmitchellscott/stationmaster/internal/logging"
 	"github.com/rmitchellscott/stationmaster/internal/sse"
 	"github.com/rmitchellscott/stationmaster/internal/trmnl"
+	"gorm.io/gorm"
 )
 
 // GetDevicesHandler returns all devices for the current user
@@ -55,6 +58,30 @@ func ClaimDeviceHandler(c *gin.Context) {
 	}
 
 	db := database.GetDB()
+
+	// In approval mode non-admins file a request that an admin must approve before the device is linked
+	if database.IsClaimApprovalRequired() && !user.IsAdmin {
+		claimRequest, err := database.NewClaimRequestService(db).CreateClaimRequest(userUUID, req.FriendlyID, req.Name)
+		if err != nil {
+			if errors.Is(err, database.ErrDeviceAlreadyClaimed) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Device already claimed by another user"})
+			} else if errors.Is(err, database.ErrClaimRequestAlreadyPending) {
+				c.JSON(http.StatusConflict, gin.H{"error": "You already have a pending claim request for this device"})
+			} else if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Device not found. Please check the device ID or MAC address."})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create claim request"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"claim_request":    claimRequest,
+			"pending_approval": true,
+		})
+		return
+	}
+
 	deviceService := database.NewDeviceService(db)
 
 	device, err := deviceService.ClaimDeviceByIdentifier(userUUID, req.FriendlyID, req.Name)
@@ -151,13 +178,14 @@ func GetDeviceHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"device": device})
+	c.JSON(http.StatusOK, gin.H{"device": device, "capabilities": device.EffectiveCapabilities()})
 }
 
 // To add a new device setting: add the field to Device in models.go, then add a line here.
 var deviceSettingsFields = map[string]string{
 	"name":                       "name",
 	"refresh_rate":               "refresh_rate",
+	"min_refresh_rate":           "min_refresh_rate",
 	"is_active":                  "is_active",
 	"allow_firmware_updates":     "allow_firmware_updates",
 	"is_shareable":               "is_shareable",
@@ -165,13 +193,25 @@ var deviceSettingsFields = map[string]string{
 	"sleep_start_time":           "sleep_start_time",
 	"sleep_end_time":             "sleep_end_time",
 	"sleep_show_screen":          "sleep_show_screen",
+	"active_hours_enabled":       "active_hours_enabled",
+	"active_hours_start_time":    "active_hours_start_time",
+	"active_hours_end_time":      "active_hours_end_time",
+	"inactive_hold_item_id":      "inactive_hold_item_id",
 	"firmware_update_start_time": "firmware_update_start_time",
 	"firmware_update_end_time":   "firmware_update_end_time",
 	"target_firmware_version":    "target_firmware_version",
+	"firmware_channel":           "firmware_channel",
 	"maximum_compatibility":      "maximum_compatibility",
 	"touchbar_mode":              "touchbar_mode",
 	"temperature_profile":        "temperature_profile",
 	"screen_orientation":         "screen_orientation",
+	"safe_area_top":              "safe_area_top",
+	"safe_area_right":            "safe_area_right",
+	"safe_area_bottom":           "safe_area_bottom",
+	"safe_area_left":             "safe_area_left",
+	"empty_playlist_mode":        "empty_playlist_mode",
+	"empty_playlist_image_url":   "empty_playlist_image_url",
+	"empty_playlist_instance_id": "empty_playlist_instance_id",
 }
 
 var timeFields = map[string]string{
@@ -211,6 +251,135 @@ func buildDeviceUpdates(raw map[string]interface{}) (map[string]interface{}, err
 	return updates, nil
 }
 
+// validateEmptyPlaylistSettings checks the empty playlist fields of a device update,
+// replacing the raw instance ID with a parsed UUID owned by the user
+func validateEmptyPlaylistSettings(db *gorm.DB, userID uuid.UUID, raw map[string]interface{}) error {
+	if val, ok := raw["empty_playlist_mode"]; ok {
+		mode, isString := val.(string)
+		if !isString || !database.IsValidEmptyPlaylistMode(mode) {
+			return fmt.Errorf("invalid empty_playlist_mode: must be one of default, setup, image, plugin")
+		}
+	}
+
+	if val, ok := raw["empty_playlist_image_url"]; ok && val != nil {
+		imageURL, isString := val.(string)
+		if !isString {
+			return fmt.Errorf("invalid empty_playlist_image_url")
+		}
+		imageURL = strings.TrimSpace(imageURL)
+		if imageURL != "" && !strings.HasPrefix(imageURL, "http://") && !strings.HasPrefix(imageURL, "https://") && !strings.HasPrefix(imageURL, "/") {
+			return fmt.Errorf("invalid empty_playlist_image_url: must be an http(s) URL or an absolute path")
+		}
+		raw["empty_playlist_image_url"] = imageURL
+	}
+
+	if val, ok := raw["empty_playlist_instance_id"]; ok {
+		idStr, _ := val.(string)
+		if val == nil || idStr == "" {
+			raw["empty_playlist_instance_id"] = nil
+			return nil
+		}
+		instanceID, err := uuid.Parse(idStr)
+		if err != nil {
+			return fmt.Errorf("invalid empty_playlist_instance_id")
+		}
+		var count int64
+		db.Model(&database.PluginInstance{}).Where("id = ? AND user_id = ?", instanceID, userID).Count(&count)
+		if count == 0 {
+			return fmt.Errorf("empty playlist plugin instance not found")
+		}
+		raw["empty_playlist_instance_id"] = &instanceID
+	}
+
+	return nil
+}
+
+// validateActiveHoursSettings checks the active hours fields of a device update, replacing the raw
+// hold item ID with a parsed UUID from one of the device's playlists
+func validateActiveHoursSettings(db *gorm.DB, deviceID uuid.UUID, raw map[string]interface{}) error {
+	for _, key := range []string{"active_hours_start_time", "active_hours_end_time"} {
+		val, ok := raw[key]
+		if !ok {
+			continue
+		}
+		timeStr, isString := val.(string)
+		if val != nil && !isString {
+			return fmt.Errorf("invalid %s format", key)
+		}
+		if timeStr != "" {
+			if err := validateTimeFormat(timeStr); err != nil {
+				return fmt.Errorf("invalid %s format: %w", key, err)
+			}
+		}
+		raw[key] = timeStr
+	}
+
+	if val, ok := raw["inactive_hold_item_id"]; ok {
+		idStr, _ := val.(string)
+		if val == nil || idStr == "" {
+			raw["inactive_hold_item_id"] = nil
+			return nil
+		}
+		itemID, err := uuid.Parse(idStr)
+		if err != nil {
+			return fmt.Errorf("invalid inactive_hold_item_id")
+		}
+		var count int64
+		db.Model(&database.PlaylistItem{}).
+			Joins("JOIN playlists ON playlists.id = playlist_items.playlist_id").
+			Where("playlist_items.id = ? AND playlists.device_id = ?", itemID, deviceID).
+			Count(&count)
+		if count == 0 {
+			return fmt.Errorf("inactive hold playlist item not found")
+		}
+		raw["inactive_hold_item_id"] = &itemID
+	}
+
+	return nil
+}
+
+// onlineGraceSeconds is added to a device's refresh rate before it is no longer considered recently seen
+const onlineGraceSeconds = 60
+
+// maxSafeAreaPadding caps the per-edge safe area padding in pixels
+const maxSafeAreaPadding = 200
+
+// safeAreaFields lists the device settings that inset rendered content
+var safeAreaFields = []string{"safe_area_top", "safe_area_right", "safe_area_bottom", "safe_area_left"}
+
+// validateSafeAreaSettings checks that safe area padding values are whole pixel counts within range
+func validateSafeAreaSettings(raw map[string]interface{}) error {
+	for _, key := range safeAreaFields {
+		val, ok := raw[key]
+		if !ok {
+			continue
+		}
+		pixels, isNumber := val.(float64)
+		if !isNumber || pixels != float64(int(pixels)) || pixels < 0 || pixels > maxSafeAreaPadding {
+			return fmt.Errorf("invalid %s: must be a whole number of pixels between 0 and %d", key, maxSafeAreaPadding)
+		}
+		raw[key] = int(pixels)
+	}
+	return nil
+}
+
+// maxMinRefreshRate caps the per-device refresh rate floor in seconds
+const maxMinRefreshRate = 86400
+
+// validateMinRefreshRate checks that the refresh rate floor is a whole number of seconds within range
+func validateMinRefreshRate(raw map[string]interface{}) error {
+	val, ok := raw["min_refresh_rate"]
+	if !ok {
+		return nil
+	}
+	seconds, isNumber := val.(float64)
+	if !isNumber || seconds != float64(int(seconds)) || seconds < 0 || seconds > maxMinRefreshRate {
+		return fmt.Errorf("invalid min_refresh_rate: must be a whole number of seconds between 0 and %d", maxMinRefreshRate)
+	}
+	raw["min_refresh_rate"] = int(seconds)
+	return nil
+}
+
 func UpdateDeviceHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
 	if !ok {
@@ -281,6 +450,33 @@ func UpdateDeviceHandler(c *gin.Context) {
 		delete(raw, "device_model_id")
 	}
 
+	if err := validateEmptyPlaylistSettings(db, userUUID, raw); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateSafeAreaSettings(raw); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateMinRefreshRate(raw); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := validateActiveHoursSettings(db, device.ID, raw); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if val, ok := raw["firmware_channel"]; ok {
+		if channel, isString := val.(string); !isString || !database.IsValidFirmwareChannel(channel) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid firmware_channel: must be stable or beta"})
+			return
+		}
+	}
+
 	updates, err := buildDeviceUpdates(raw)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -297,7 +493,17 @@ func UpdateDeviceHandler(c *gin.Context) {
 
 	device, _ = deviceService.GetDeviceByID(deviceID)
 
+	rerender := false
 	if _, changed := raw["screen_orientation"]; changed {
+		rerender = true
+	}
+	for _, key := range safeAreaFields {
+		if _, changed := raw[key]; changed {
+			rerender = true
+		}
+	}
+
+	if rerender {
 		playlistService := database.NewPlaylistService(db)
 		playlist, err := playlistService.GetDefaultPlaylistForDevice(deviceID)
 		if err == nil && playlist != nil {
@@ -374,6 +580,7 @@ func UnclaimDeviceHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlink device"})
 		return
 	}
+	trmnl.ClearLastRequestHeaders(deviceID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Device unlinked successfully"})
 }
@@ -394,6 +601,44 @@ func GetAllDevicesHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"devices": devices})
 }
 
+// GetDeviceLastRequestHeadersHandler returns the headers captured on the device's most recent /api/display call.
+// Headers are kept in memory only, so nothing is returned until the device checks in after a restart.
+func GetDeviceLastRequestHeadersHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	db := database.GetDB()
+	deviceService := database.NewDeviceService(db)
+
+	device, err := deviceService.GetDeviceByID(deviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	// Owners and admins can inspect headers
+	if !user.IsAdmin && (device.UserID == nil || *device.UserID != user.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	captured, found := trmnl.GetLastRequestHeaders(device.ID)
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No display request has been received from this device since the server started"})
+		return
+	}
+
+	c.JSON(http.StatusOK, captured)
+}
+
 // UnlinkDeviceHandler unlinks a device from its user account (admin only)
 func UnlinkDeviceHandler(c *gin.Context) {
 	deviceIDStr := c.Param("id")
@@ -412,6 +657,7 @@ func UnlinkDeviceHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlink device"})
 		return
 	}
+	trmnl.ClearLastRequestHeaders(deviceID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Device unlinked successfully"})
 }
@@ -433,10 +679,91 @@ func AdminDeleteDeviceHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete device"})
 		return
 	}
+	trmnl.ClearLastRequestHeaders(deviceID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Device deleted successfully"})
 }
 
+// ReassignDeviceHandler transfers a device to another user (admin only)
+func ReassignDeviceHandler(c *gin.Context) {
+	admin, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	var req struct {
+		UserID          string `json:"user_id" binding:"required"`
+		TransferContent bool   `json:"transfer_content"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	newUserID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	db := database.GetDB()
+	deviceService := database.NewDeviceService(db)
+
+	device, err := deviceService.GetDeviceByID(deviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+	if device.UserID != nil && *device.UserID == newUserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Device is already owned by this user"})
+		return
+	}
+
+	userService := database.NewUserService(db)
+	newUser, err := userService.GetUserByID(newUserID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Target user not found"})
+		return
+	}
+	if !newUser.IsActive {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Target user is not active"})
+		return
+	}
+
+	result, err := deviceService.ReassignDevice(deviceID, newUserID, req.TransferContent)
+	if err != nil {
+		logging.Error("[ADMIN] Failed to reassign device", "device_id", deviceID, "new_user_id", newUserID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reassign device"})
+		return
+	}
+	trmnl.ClearLastRequestHeaders(deviceID)
+
+	logging.Info("[AUDIT] Device reassigned",
+		"admin_id", admin.ID,
+		"admin_username", admin.Username,
+		"device_id", deviceID,
+		"friendly_id", device.FriendlyID,
+		"previous_user_id", result.PreviousUserID,
+		"new_user_id", newUserID,
+		"transfer_content", req.TransferContent,
+		"playlists_transferred", result.PlaylistsTransferred,
+		"playlists_removed", result.PlaylistsRemoved,
+		"instances_transferred", result.InstancesTransferred,
+		"instances_copied", result.InstancesCopied,
+		"definitions_copied", result.DefinitionsCopied)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Device reassigned successfully",
+		"result":  result,
+	})
+}
+
 // GetDeviceStatsHandler returns device statistics (admin only)
 func GetDeviceStatsHandler(c *gin.Context) {
 	db := database.GetDB()
@@ -517,6 +844,44 @@ func GetDeviceLogsHandler(c *gin.Context) {
 	})
 }
 
+// GetDeviceOnlineStatusHandler reports whether a device has active event stream connections and how
+// long it has been since its last /api/display check-in. A device is considered recently checked in
+// while the time since its last check-in is within its refresh rate plus a grace period.
+func GetDeviceOnlineStatusHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	device, ok := ownedDeviceFromParam(c, user.ID)
+	if !ok {
+		return
+	}
+
+	connections, connectedSince := sse.GetSSEService().GetDeviceConnectionInfo(device.ID)
+
+	response := gin.H{
+		"device_id":          device.ID,
+		"connected":          connections > 0,
+		"connections":        connections,
+		"connected_since":    connectedSince,
+		"last_seen":          device.LastSeen,
+		"seconds_since_seen": nil,
+		"recently_seen":      false,
+	}
+
+	if device.LastSeen != nil {
+		secondsSinceSeen := int64(time.Since(*device.LastSeen).Seconds())
+		if secondsSinceSeen < 0 {
+			secondsSinceSeen = 0
+		}
+		response["seconds_since_seen"] = secondsSinceSeen
+		response["recently_seen"] = secondsSinceSeen <= int64(device.RefreshRate)+onlineGraceSeconds
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // DeviceEventsHandler handles SSE connections for device events
 func DeviceEventsHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
@@ -972,6 +1337,104 @@ func UnmirrorDeviceHandler(c *gin.Context) {
 	})
 }
 
+// SetCurrentPlaylistItemHandler pins a playlist item as the device's current screen so its next check-in serves it
+func SetCurrentPlaylistItemHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+	userUUID := user.ID
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	var req struct {
+		PlaylistItemID uuid.UUID `json:"playlist_item_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	deviceService := database.NewDeviceService(db)
+	playlistService := database.NewPlaylistService(db)
+
+	device, err := deviceService.GetDeviceByID(deviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	// Verify ownership
+	if device.UserID == nil || *device.UserID != userUUID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	// Verify the item is on one of this device's playlists
+	item, err := playlistService.GetPlaylistItemByID(req.PlaylistItemID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist item not found"})
+		return
+	}
+
+	playlist, err := playlistService.GetPlaylistByID(item.PlaylistID)
+	if err != nil || playlist.DeviceID != device.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Playlist item is not on this device's playlist"})
+		return
+	}
+
+	if err := deviceService.SetCurrentPlaylistItem(device.ID, item.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set current playlist item"})
+		return
+	}
+
+	logging.Info("[PLAYLIST] Current playlist item set via API", "device_id", device.ID, "item_id", item.ID)
+
+	activeItems, _ := playlistService.GetActivePlaylistItemsForTime(device.ID, time.Now().UTC())
+	currentIndex := -1
+	for i, activeItem := range activeItems {
+		if activeItem.ID == item.ID {
+			currentIndex = i
+			break
+		}
+	}
+
+	userTimezone := "UTC"
+	if user.Timezone != "" {
+		userTimezone = user.Timezone
+	}
+
+	sseService := sse.GetSSEService()
+	sseService.BroadcastToDevice(device.ID, sse.Event{
+		Type: "playlist_index_changed",
+		Data: map[string]interface{}{
+			"device_id":     device.ID.String(),
+			"current_index": currentIndex, // -1 if not in active items (e.g., hidden or outside schedule)
+			"current_item":  *item,
+			"active_items":  activeItems,
+			"timestamp":     time.Now().UTC(),
+			"sleep_config": map[string]interface{}{
+				"enabled":            device.SleepEnabled,
+				"start_time":         device.SleepStartTime,
+				"end_time":           device.SleepEndTime,
+				"show_screen":        device.SleepShowScreen,
+				"currently_sleeping": trmnl.IsInSleepPeriod(device, userTimezone),
+			},
+		},
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Current playlist item updated",
+		"playlist_item": item,
+		"is_active":     currentIndex >= 0,
+	})
+}
+
 // validateTimeFormat validates that a time string is in HH:MM format
 func validateTimeFormat(timeStr string) error {
 	_, err := time.Parse("15:04", timeStr)
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/rendering"
+	"github.com/rmitchellscott/stationmaster/internal/trmnl"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+)
+
+const (
+	verifyImageFetchTimeout = 15 * time.Second
+	// verifyImageMaxBytes caps how much of the served image is read back
+	verifyImageMaxBytes = 50 * 1024 * 1024
+)
+
+// imageFileInfo describes one copy of an image being compared
+type imageFileInfo struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// VerifyDeviceImageHandler resolves the image a device would be served on its next check-in, fetches it
+// back through the server and compares it with the rendered content file it should be. Mismatches point
+// at URL rewriting or caching problems rather than rendering ones. The playlist is not advanced.
+// GET /api/admin/devices/:id/verify-image
+func VerifyDeviceImageHandler(c *gin.Context) {
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	db := database.GetDB()
+	device, err := database.NewDeviceService(db).GetDeviceByID(deviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+
+	result, err := trmnl.SimulateDisplay(c.Request, device, trmnl.DisplaySimulationOptions{})
+	if err != nil {
+		logging.Error("[IMAGE_VERIFY] Failed to simulate display request", "device_id", deviceID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve the served image"})
+		return
+	}
+
+	imageURL, _ := result.Response["image_url"].(string)
+	filename, _ := result.Response["filename"].(string)
+	mismatches := []string{}
+	response := gin.H{
+		"device_id":   device.ID,
+		"friendly_id": device.FriendlyID,
+		"image_url":   imageURL,
+		"filename":    filename,
+	}
+	respond := func() {
+		response["mismatches"] = mismatches
+		response["match"] = len(mismatches) == 0
+		c.JSON(http.StatusOK, response)
+	}
+
+	if result.StatusCode != http.StatusOK || imageURL == "" {
+		mismatches = append(mismatches, fmt.Sprintf("Display endpoint returned status %d without an image URL", result.StatusCode))
+		respond()
+		return
+	}
+
+	// Rendered images are served under their own file name, anything else is a status or setup screen
+	var served database.RenderedContent
+	if filename == "" || db.Where("image_path = ? OR image_path LIKE ?", filename, "%/"+filename).
+		Order("rendered_at DESC").First(&served).Error != nil {
+		response["rendered_content"] = nil
+		response["note"] = "The served image is not rendered content, so there is nothing to compare it with"
+		respond()
+		return
+	}
+	response["rendered_content"] = gin.H{
+		"id":                 served.ID,
+		"plugin_instance_id": served.PluginInstanceID,
+		"image_path":         served.ImagePath,
+		"file_size":          served.FileSize,
+		"rendered_at":        served.RenderedAt,
+	}
+
+	// A newer render at the same resolution means the device is being handed an old image
+	var latest database.RenderedContent
+	latestQuery := db.Where("plugin_instance_id = ? AND width = ? AND height = ? AND bit_depth = ?",
+		served.PluginInstanceID, served.Width, served.Height, served.BitDepth)
+	if served.DeviceID != nil {
+		latestQuery = latestQuery.Where("device_id = ?", *served.DeviceID)
+	} else {
+		latestQuery = latestQuery.Where("device_id IS NULL")
+	}
+	if latestQuery.Order("rendered_at DESC").First(&latest).Error == nil && latest.ID != served.ID {
+		mismatches = append(mismatches, fmt.Sprintf("A newer render from %s exists than the served one from %s",
+			latest.RenderedAt.Format(time.RFC3339), served.RenderedAt.Format(time.RFC3339)))
+		response["latest_rendered_content_id"] = latest.ID
+	}
+
+	if strings.HasPrefix(served.ImagePath, "http://") || strings.HasPrefix(served.ImagePath, "https://") {
+		response["note"] = "The rendered content is a remote URL, so there is no local file to compare with"
+		respond()
+		return
+	}
+
+	expectedPrefix := utils.BaseURLFromRequest(c.Request) + "/static/rendered/"
+	if !strings.HasPrefix(imageURL, expectedPrefix) || !strings.HasSuffix(imageURL, "/"+filename) {
+		mismatches = append(mismatches, fmt.Sprintf("Image URL does not point at %s under %s", filename, expectedPrefix))
+	}
+
+	renderedDir := filepath.Join(config.Get("STATIC_DIR", "./static"), "rendered")
+	localPath, ok := resolveRenderedImagePath(renderedDir, served.ImagePath)
+	if !ok {
+		localPath, ok = rendering.ResolveRenderedFile(renderedDir, filename)
+	}
+	var local *imageFileInfo
+	if ok {
+		local, err = hashImageFile(localPath)
+	}
+	if !ok || err != nil {
+		mismatches = append(mismatches, "Rendered image file is missing from the rendered directories")
+	} else {
+		response["local"] = local
+		if served.FileSize > 0 && local.Size != served.FileSize {
+			mismatches = append(mismatches, fmt.Sprintf("Rendered file is %d bytes but the database records %d", local.Size, served.FileSize))
+		}
+	}
+
+	fetched, err := fetchServedImage(imageURL)
+	if err != nil {
+		mismatches = append(mismatches, "Fetching the served image failed: "+err.Error())
+		respond()
+		return
+	}
+	response["fetched"] = fetched
+	if local != nil && (fetched.Size != local.Size || fetched.SHA256 != local.SHA256) {
+		mismatches = append(mismatches, "Fetched image differs from the rendered file")
+	}
+
+	if len(mismatches) > 0 {
+		logging.Warn("[IMAGE_VERIFY] Served image does not match rendered content", "device_id", device.ID, "image_url", imageURL, "mismatches", mismatches)
+	}
+	respond()
+}
+
+// hashImageFile returns the size and SHA-256 of a file on disk
+func hashImageFile(path string) (*imageFileInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return hashImage(file)
+}
+
+// fetchServedImage downloads an image URL the way a device would and returns its size and SHA-256
+func fetchServedImage(imageURL string) (*imageFileInfo, error) {
+	client := &http.Client{Timeout: verifyImageFetchTimeout}
+	resp, err := client.Get(imageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return hashImage(io.LimitReader(resp.Body, verifyImageMaxBytes))
+}
+
+func hashImage(r io.Reader) (*imageFileInfo, error) {
+	hash := sha256.New()
+	size, err := io.Copy(hash, r)
+	if err != nil {
+		return nil, err
+	}
+	return &imageFileInfo{Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
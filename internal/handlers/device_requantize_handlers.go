@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/rendering"
+)
+
+// RequantizeDeviceContentHandler brings a device's rendered content in line with its current model.
+// By default the latest images are converted to the model's bit depth without re-running plugins and
+// anything that can't be converted is scheduled for a fresh render; mode=render schedules fresh
+// renders for everything instead.
+func RequantizeDeviceContentHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	device, ok := ownedDeviceFromParam(c, user.ID)
+	if !ok {
+		return
+	}
+	if device.DeviceModel == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Device has no model configured"})
+		return
+	}
+
+	mode := c.DefaultQuery("mode", "requantize")
+	if mode != "requantize" && mode != "render" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be requantize or render"})
+		return
+	}
+
+	db := database.GetDB()
+
+	if mode == "render" {
+		var instanceIDs []uuid.UUID
+		err := db.Model(&database.RenderedContent{}).
+			Where("device_id = ?", device.ID).
+			Distinct().
+			Pluck("plugin_instance_id", &instanceIDs).Error
+		if err != nil {
+			logging.Error("[REQUANTIZE] Failed to list rendered plugin instances", "device_id", device.ID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load rendered content"})
+			return
+		}
+
+		ScheduleRenderForInstances(instanceIDs)
+		c.JSON(http.StatusOK, rendering.RequantizeResult{Requantized: []uuid.UUID{}, NeedsRender: instanceIDs})
+		return
+	}
+
+	worker, err := rendering.NewRenderWorker(db, config.Get("STATIC_DIR", "./static"))
+	if err != nil {
+		logging.Error("[REQUANTIZE] Failed to create render worker", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to access rendered directory"})
+		return
+	}
+
+	result, err := worker.RequantizeForDevice(c.Request.Context(), *device)
+	if err != nil {
+		logging.Error("[REQUANTIZE] Failed to requantize device content", "device_id", device.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to requantize rendered content"})
+		return
+	}
+
+	if len(result.NeedsRender) > 0 {
+		ScheduleRenderForInstances(result.NeedsRender)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
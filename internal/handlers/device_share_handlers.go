@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+	"gorm.io/gorm"
+)
+
+// ownedDeviceFromParam loads the device in the :id route parameter, writing an error response
+// unless it belongs to the given user
+func ownedDeviceFromParam(c *gin.Context, userID uuid.UUID) (*database.Device, bool) {
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return nil, false
+	}
+
+	device, err := database.NewDeviceService(database.GetDB()).GetDeviceByID(deviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return nil, false
+	}
+
+	if device.UserID == nil || *device.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil, false
+	}
+
+	return device, true
+}
+
+// shareLinkResponse adds the public image URL to a share link
+func shareLinkResponse(c *gin.Context, link database.DeviceShareLink) gin.H {
+	baseURL := strings.TrimSuffix(config.Get("SITE_URL", ""), "/")
+	if baseURL == "" {
+		baseURL = utils.BaseURLFromRequest(c.Request)
+	}
+
+	return gin.H{
+		"id":               link.ID,
+		"device_id":        link.DeviceID,
+		"name":             link.Name,
+		"token":            link.Token,
+		"url":              fmt.Sprintf("%s/api/public/devices/%s/current.png", baseURL, link.Token),
+		"last_accessed_at": link.LastAccessedAt,
+		"created_at":       link.CreatedAt,
+	}
+}
+
+// CreateDeviceShareLinkHandler creates a public link to a device's current screen
+func CreateDeviceShareLinkHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	device, ok := ownedDeviceFromParam(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := database.NewDeviceShareService(database.GetDB()).CreateShareLink(device.ID, user.ID, strings.TrimSpace(req.Name))
+	if err != nil {
+		logging.Error("[SHARE] Failed to create share link", "device_id", device.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share link"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"share_link": shareLinkResponse(c, *link)})
+}
+
+// GetDeviceShareLinksHandler lists the public links to a device's current screen
+func GetDeviceShareLinksHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	device, ok := ownedDeviceFromParam(c, user.ID)
+	if !ok {
+		return
+	}
+
+	links, err := database.NewDeviceShareService(database.GetDB()).GetShareLinksForDevice(device.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch share links"})
+		return
+	}
+
+	response := make([]gin.H, 0, len(links))
+	for _, link := range links {
+		response = append(response, shareLinkResponse(c, link))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"share_links": response})
+}
+
+// RevokeDeviceShareLinkHandler deletes a public link so it stops serving the device's screen
+func RevokeDeviceShareLinkHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	device, ok := ownedDeviceFromParam(c, user.ID)
+	if !ok {
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("shareId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share link ID"})
+		return
+	}
+
+	if err := database.NewDeviceShareService(database.GetDB()).RevokeShareLink(device.ID, linkID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke share link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Share link revoked"})
+}
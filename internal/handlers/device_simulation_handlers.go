@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/trmnl"
+)
+
+// SimulateDeviceDisplayHandler runs the device display logic for a device without the physical device
+// and returns the response it would receive, with timing. The playlist only advances with "advance": true.
+// POST /api/admin/devices/:id/simulate-display
+func SimulateDeviceDisplayHandler(c *gin.Context) {
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	var opts trmnl.DisplaySimulationOptions
+	if err := c.ShouldBindJSON(&opts); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (opts.Width > 0) != (opts.Height > 0) || opts.Width < 0 || opts.Height < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "width and height must be given together as positive values"})
+		return
+	}
+
+	device, err := database.NewDeviceService(database.GetDB()).GetDeviceByID(deviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+	if !device.IsActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "Device is inactive and would be rejected by the display endpoint"})
+		return
+	}
+
+	result, err := trmnl.SimulateDisplay(c.Request, device, opts)
+	if err != nil {
+		logging.Error("[DISPLAY SIMULATION] Failed to simulate display request", "device_id", deviceID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to simulate display request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"device_id":   device.ID,
+		"friendly_id": device.FriendlyID,
+		"simulation":  result,
+	})
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/trmnl"
+)
+
+const (
+	// maxSnapshotsPerDevice keeps snapshots from growing without bound, since cleanup never touches them
+	maxSnapshotsPerDevice = 100
+	maxSnapshotNoteLength = 2000
+)
+
+// snapshotDir returns where a device's snapshot images are stored, outside the rendered content directory
+func snapshotDir(deviceID uuid.UUID) string {
+	return filepath.Join(config.Get("DATA_DIR", "/data"), "snapshots", deviceID.String())
+}
+
+// CreateDeviceSnapshotHandler copies the screen a device is currently showing into its snapshots, with
+// an optional note, so it survives rendered content cleanup
+func CreateDeviceSnapshotHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	device, ok := ownedDeviceFromParam(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if len(req.Note) > maxSnapshotNoteLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Note is too long", "max_length": maxSnapshotNoteLength})
+		return
+	}
+
+	db := database.GetDB()
+	var count int64
+	if err := db.Model(&database.DeviceSnapshot{}).Where("device_id = ?", device.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create snapshot"})
+		return
+	}
+	if count >= maxSnapshotsPerDevice {
+		c.JSON(http.StatusConflict, gin.H{"error": "Snapshot limit reached, delete old snapshots first", "max_snapshots": maxSnapshotsPerDevice})
+		return
+	}
+
+	renderedContent, currentItem, err := trmnl.CurrentRenderedContent(db, device)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No screen is currently available"})
+		return
+	}
+
+	renderedDir := filepath.Join(config.Get("STATIC_DIR", "./static"), "rendered")
+	sourcePath, ok := resolveRenderedImagePath(renderedDir, renderedContent.ImagePath)
+	if !ok {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The current screen is not a locally rendered image"})
+		return
+	}
+
+	snapshot := &database.DeviceSnapshot{
+		ID:                uuid.New(),
+		DeviceID:          device.ID,
+		UserID:            user.ID,
+		PluginInstanceID:  &currentItem.PluginInstanceID,
+		RenderedContentID: &renderedContent.ID,
+		Note:              req.Note,
+		Width:             renderedContent.Width,
+		Height:            renderedContent.Height,
+		BitDepth:          renderedContent.BitDepth,
+		ContentHash:       renderedContent.ContentHash,
+		RenderedAt:        renderedContent.RenderedAt,
+	}
+	db.Model(&database.PluginInstance{}).Select("name").Where("id = ?", currentItem.PluginInstanceID).Scan(&snapshot.PluginName)
+
+	dir := snapshotDir(device.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logging.Error("[SNAPSHOT] Failed to create snapshot directory", "dir", dir, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create snapshot"})
+		return
+	}
+	snapshot.ImagePath = filepath.Join(dir, snapshot.ID.String()+".png")
+	size, err := copySnapshotImage(sourcePath, snapshot.ImagePath)
+	if err != nil {
+		logging.Error("[SNAPSHOT] Failed to copy current screen", "device_id", device.ID, "source", sourcePath, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create snapshot"})
+		return
+	}
+	snapshot.FileSize = size
+
+	if err := database.NewDeviceSnapshotService(db).CreateSnapshot(snapshot); err != nil {
+		os.Remove(snapshot.ImagePath)
+		logging.Error("[SNAPSHOT] Failed to record snapshot", "device_id", device.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create snapshot"})
+		return
+	}
+
+	logging.Info("[SNAPSHOT] Captured device screen", "device", device.FriendlyID, "snapshot_id", snapshot.ID, "plugin_instance_id", currentItem.PluginInstanceID)
+	c.JSON(http.StatusCreated, gin.H{"snapshot": snapshot})
+}
+
+// GetDeviceSnapshotsHandler lists a device's snapshots, newest first
+func GetDeviceSnapshotsHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	device, ok := ownedDeviceFromParam(c, user.ID)
+	if !ok {
+		return
+	}
+
+	snapshots, err := database.NewDeviceSnapshotService(database.GetDB()).GetSnapshotsForDevice(device.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch snapshots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"snapshots": snapshots})
+}
+
+// GetDeviceSnapshotImageHandler serves a snapshot's image
+func GetDeviceSnapshotImageHandler(c *gin.Context) {
+	snapshot, ok := deviceSnapshotFromParams(c)
+	if !ok {
+		return
+	}
+
+	if _, err := os.Stat(snapshot.ImagePath); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Snapshot image not found"})
+		return
+	}
+
+	// Snapshots never change once taken
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"snapshot_%s.png\"", snapshot.CreatedAt.UTC().Format("20060102_150405")))
+	c.Header("Content-Type", "image/png")
+	c.File(snapshot.ImagePath)
+}
+
+// DeleteDeviceSnapshotHandler deletes a snapshot and its image
+func DeleteDeviceSnapshotHandler(c *gin.Context) {
+	snapshot, ok := deviceSnapshotFromParams(c)
+	if !ok {
+		return
+	}
+
+	if _, err := database.NewDeviceSnapshotService(database.GetDB()).DeleteSnapshot(snapshot.DeviceID, snapshot.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete snapshot"})
+		return
+	}
+
+	if err := os.Remove(snapshot.ImagePath); err != nil && !os.IsNotExist(err) {
+		logging.Warn("[SNAPSHOT] Failed to remove snapshot image", "path", snapshot.ImagePath, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Snapshot deleted"})
+}
+
+// deviceSnapshotFromParams loads the snapshot in the :snapshotId route parameter for the user's device in :id
+func deviceSnapshotFromParams(c *gin.Context) (*database.DeviceSnapshot, bool) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return nil, false
+	}
+
+	device, ok := ownedDeviceFromParam(c, user.ID)
+	if !ok {
+		return nil, false
+	}
+
+	snapshotID, err := uuid.Parse(c.Param("snapshotId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid snapshot ID"})
+		return nil, false
+	}
+
+	snapshot, err := database.NewDeviceSnapshotService(database.GetDB()).GetSnapshot(device.ID, snapshotID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Snapshot not found"})
+		return nil, false
+	}
+	return snapshot, true
+}
+
+// copySnapshotImage copies a rendered image to its snapshot path and returns the number of bytes written
+func copySnapshotImage(sourcePath, destPath string) (int64, error) {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return 0, err
+	}
+	size, err := io.Copy(dest, source)
+	if closeErr := dest.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(destPath)
+		return 0, err
+	}
+	return size, nil
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/imageprocessing"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
 	"github.com/rmitchellscott/stationmaster/internal/pollers"
+	"github.com/rmitchellscott/stationmaster/internal/trmnl"
+	"gorm.io/gorm"
 )
 
 // GetFirmwareVersionsHandler returns all firmware versions
@@ -27,12 +31,25 @@ func GetFirmwareVersionsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"firmware_versions": versions})
 }
 
-// GetLatestFirmwareVersionHandler returns the latest firmware version
+// GetLatestFirmwareVersionHandler returns the latest firmware version. With a channel query parameter
+// it returns the newest release a device on that channel would receive, for the optional family.
 func GetLatestFirmwareVersionHandler(c *gin.Context) {
 	db := database.GetDB()
 	firmwareService := database.NewFirmwareService(db)
 
-	version, err := firmwareService.GetLatestFirmwareVersion()
+	var version *database.FirmwareVersion
+	var err error
+	if channel := c.Query("channel"); channel != "" {
+		if !database.IsValidFirmwareChannel(channel) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid firmware channel (must be stable or beta)"})
+			return
+		}
+		version, err = firmwareService.GetLatestFirmwareVersionForChannel(c.DefaultQuery("family", "trmnl"), channel)
+	} else if family := c.Query("family"); family != "" {
+		version, err = firmwareService.GetLatestFirmwareVersionForFamily(family)
+	} else {
+		version, err = firmwareService.GetLatestFirmwareVersion()
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "No firmware versions available"})
 		return
@@ -41,6 +58,85 @@ func GetLatestFirmwareVersionHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, version)
 }
 
+// UpdateFirmwareVersionChannelHandler moves a firmware version to another release channel
+func UpdateFirmwareVersionChannelHandler(c *gin.Context) {
+	versionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid firmware version ID"})
+		return
+	}
+
+	var req struct {
+		Channel string `json:"channel" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !database.IsValidFirmwareChannel(req.Channel) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid firmware channel (must be stable or beta)"})
+		return
+	}
+
+	firmwareService := database.NewFirmwareService(database.GetDB())
+	if err := firmwareService.SetFirmwareVersionChannel(versionID, req.Channel); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Firmware version not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update firmware channel"})
+		return
+	}
+
+	version, err := firmwareService.GetFirmwareVersionByID(versionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get firmware version"})
+		return
+	}
+
+	logging.Info("[FIRMWARE] Changed firmware release channel", "version", version.Version, "family", version.ModelFamily, "channel", req.Channel)
+	c.JSON(http.StatusOK, version)
+}
+
+// firmwareReleaseNotes describes what a firmware version changes, for reviewing a rollout
+type firmwareReleaseNotes struct {
+	ID           uuid.UUID `json:"id"`
+	Version      string    `json:"version"`
+	Family       string    `json:"family"`
+	Channel      string    `json:"channel"`
+	ReleasedAt   time.Time `json:"released_at"`
+	ReleaseNotes string    `json:"release_notes"`
+}
+
+func newFirmwareReleaseNotes(version *database.FirmwareVersion) firmwareReleaseNotes {
+	return firmwareReleaseNotes{
+		ID:           version.ID,
+		Version:      version.Version,
+		Family:       version.ModelFamily,
+		Channel:      version.Channel,
+		ReleasedAt:   version.ReleasedAt,
+		ReleaseNotes: version.ReleaseNotes,
+	}
+}
+
+// GetFirmwareReleaseNotesHandler returns the upstream release notes of a firmware version
+// GET /api/admin/firmware/versions/:id/release-notes
+func GetFirmwareReleaseNotesHandler(c *gin.Context) {
+	versionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid firmware version ID"})
+		return
+	}
+
+	version, err := database.NewFirmwareService(database.GetDB()).GetFirmwareVersionByID(versionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Firmware version not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newFirmwareReleaseNotes(version))
+}
+
 // DeleteFirmwareVersionHandler deletes a firmware version
 func DeleteFirmwareVersionHandler(c *gin.Context) {
 	versionIDStr := c.Param("id")
@@ -94,6 +190,114 @@ func GetDeviceModelsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"device_models": models})
 }
 
+// ReviewDeviceModelHandler clears the review flag of a provisional device model, optionally correcting
+// the display characteristics it copied from the default model
+// PUT /api/admin/device-models/:id/review
+func ReviewDeviceModelHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device model ID"})
+		return
+	}
+
+	var req struct {
+		DisplayName  *string `json:"display_name"`
+		ScreenWidth  *int    `json:"screen_width"`
+		ScreenHeight *int    `json:"screen_height"`
+		BitDepth     *int    `json:"bit_depth"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	updates := make(map[string]interface{})
+	if req.DisplayName != nil && *req.DisplayName != "" {
+		updates["display_name"] = *req.DisplayName
+	}
+	if req.ScreenWidth != nil {
+		if *req.ScreenWidth <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "screen_width must be positive"})
+			return
+		}
+		updates["screen_width"] = *req.ScreenWidth
+	}
+	if req.ScreenHeight != nil {
+		if *req.ScreenHeight <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "screen_height must be positive"})
+			return
+		}
+		updates["screen_height"] = *req.ScreenHeight
+	}
+	if req.BitDepth != nil {
+		if !imageprocessing.IsSupportedBitDepth(*req.BitDepth) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bit_depth must be 1, 2, 4 or 8"})
+			return
+		}
+		updates["bit_depth"] = *req.BitDepth
+	}
+
+	deviceModel, err := database.NewDeviceService(database.GetDB()).ReviewDeviceModel(uint(id), updates)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Device model not found"})
+			return
+		}
+		logging.Error("[DEVICE MODEL] Failed to review device model", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update device model"})
+		return
+	}
+
+	logging.Info("[DEVICE MODEL] Device model reviewed", "id", id, "model", deviceModel.ModelName)
+	c.JSON(http.StatusOK, gin.H{"device_model": deviceModel})
+}
+
+// UpdateDeviceModelGrayscaleLevelsHandler overrides how many gray levels a device model's images are
+// quantized to. The count must fit the model's bit depth; 0 restores the bit depth default.
+// PUT /api/admin/device-models/:id/grayscale-levels
+func UpdateDeviceModelGrayscaleLevelsHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device model ID"})
+		return
+	}
+
+	var req struct {
+		GrayscaleLevels *int `json:"grayscale_levels" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deviceService := database.NewDeviceService(database.GetDB())
+	deviceModel, err := deviceService.ValidateDeviceModelByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device model not found"})
+		return
+	}
+
+	if !imageprocessing.ValidGrayscaleLevels(deviceModel.BitDepth, *req.GrayscaleLevels) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":      "grayscale_levels must be 0 or between 2 and the levels the bit depth supports",
+			"bit_depth":  deviceModel.BitDepth,
+			"max_levels": imageprocessing.GetColorLevels(deviceModel.BitDepth),
+		})
+		return
+	}
+
+	if err := deviceService.UpdateDeviceModelGrayscaleLevels(deviceModel, *req.GrayscaleLevels); err != nil {
+		logging.Error("[DEVICE MODEL] Failed to update grayscale levels", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update device model"})
+		return
+	}
+
+	logging.Info("[DEVICE MODEL] Grayscale levels updated", "id", id, "model", deviceModel.ModelName, "grayscale_levels", deviceModel.GrayscaleLevels)
+	c.JSON(http.StatusOK, gin.H{"device_model": deviceModel})
+}
+
 // GetFirmwareStatsHandler returns firmware-related statistics
 func GetFirmwareStatsHandler(c *gin.Context) {
 	db := database.GetDB()
@@ -298,6 +502,93 @@ func GetFirmwareModeHandler(c *gin.Context) {
 	})
 }
 
+// firmwareUpdateCandidate describes a device in the firmware update candidates report
+type firmwareUpdateCandidate struct {
+	DeviceID        uuid.UUID  `json:"device_id"`
+	FriendlyID      string     `json:"friendly_id"`
+	Name            string     `json:"name,omitempty"`
+	UserID          *uuid.UUID `json:"user_id,omitempty"`
+	Model           string     `json:"model,omitempty"`
+	Family          string     `json:"family,omitempty"`
+	CurrentVersion  string     `json:"current_version"`
+	TargetVersion   string     `json:"target_version,omitempty"`
+	UpdateType      string     `json:"update_type,omitempty"`
+	ExclusionReason string     `json:"exclusion_reason,omitempty"`
+}
+
+// GetFirmwareUpdateCandidatesHandler lists which devices would be offered a firmware update (admin only).
+// It applies the same checks as device check-ins except the per-device update schedule window.
+// The optional version parameter previews a rollout of that version to devices tracking the latest firmware.
+func GetFirmwareUpdateCandidatesHandler(c *gin.Context) {
+	version := c.Query("version")
+
+	db := database.GetDB()
+	deviceService := database.NewDeviceService(db)
+
+	devices, err := deviceService.GetAllDevices()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get devices"})
+		return
+	}
+
+	firmwareService := database.NewFirmwareService(db)
+	candidates := []firmwareUpdateCandidate{}
+	excluded := []firmwareUpdateCandidate{}
+	releaseNotes := []firmwareReleaseNotes{}
+	seenTargets := make(map[string]bool)
+	for i := range devices {
+		device := &devices[i]
+		eligibility := trmnl.EvaluateFirmwareUpdate(device, version)
+
+		entry := firmwareUpdateCandidate{
+			DeviceID:       device.ID,
+			FriendlyID:     device.FriendlyID,
+			Name:           device.Name,
+			UserID:         device.UserID,
+			Family:         eligibility.Family,
+			CurrentVersion: device.FirmwareVersion,
+			TargetVersion:  eligibility.TargetVersion,
+		}
+		if device.DeviceModel != nil {
+			entry.Model = device.DeviceModel.ModelName
+		}
+
+		if !eligibility.Eligible {
+			entry.ExclusionReason = eligibility.Reason
+			excluded = append(excluded, entry)
+			continue
+		}
+
+		entry.UpdateType = "upgrade"
+		if device.FirmwareVersion > eligibility.TargetVersion {
+			entry.UpdateType = "downgrade"
+		}
+		candidates = append(candidates, entry)
+
+		target := eligibility.Family + "/" + eligibility.TargetVersion
+		if !seenTargets[target] {
+			seenTargets[target] = true
+			if fw, err := firmwareService.GetFirmwareVersionForFamily(eligibility.TargetVersion, eligibility.Family); err == nil {
+				releaseNotes = append(releaseNotes, newFirmwareReleaseNotes(fw))
+			}
+		}
+	}
+
+	firmwareMode := os.Getenv("FIRMWARE_MODE")
+	if firmwareMode == "" {
+		firmwareMode = "proxy"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version":       version,
+		"firmware_mode": firmwareMode,
+		"total_devices": len(devices),
+		"candidates":    candidates,
+		"excluded":      excluded,
+		"release_notes": releaseNotes,
+	})
+}
+
 // TriggerModelPollHandler triggers a manual model poll
 func TriggerModelPollHandler(c *gin.Context) {
 	db := database.GetDB()
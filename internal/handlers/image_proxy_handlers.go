@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+)
+
+const (
+	imageProxyFetchTimeout = 15 * time.Second
+	imageProxyMaxRedirects = 5
+)
+
+// imageProxyCacheMu serializes cache writes with eviction so concurrent misses don't over-delete
+var imageProxyCacheMu sync.Mutex
+
+// imageProxyCacheDir is where proxied images are cached, next to the rendered images
+func imageProxyCacheDir() string {
+	return config.Get("IMAGE_PROXY_CACHE_DIR", filepath.Join(config.Get("STATIC_DIR", "./static"), "image-cache"))
+}
+
+// imageProxyMaxSize returns the largest remote image the proxy will fetch, in bytes
+func imageProxyMaxSize() int64 {
+	return int64(config.GetInt("IMAGE_PROXY_MAX_SIZE_MB", 10)) * 1024 * 1024
+}
+
+// imageProxyCacheMaxSize returns the total size the image cache may grow to, in bytes
+func imageProxyCacheMaxSize() int64 {
+	return int64(config.GetInt("IMAGE_PROXY_CACHE_MAX_MB", 500)) * 1024 * 1024
+}
+
+// imageProxyClient fetches remote images, validating every redirect target against the URL policy. It
+// never connects to loopback, private or link-local addresses, whatever BLOCK_PRIVATE_IPS is set to.
+func imageProxyClient() *http.Client {
+	client := utils.NewPublicHTTPClient(imageProxyFetchTimeout)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= imageProxyMaxRedirects {
+			return fmt.Errorf("too many redirects")
+		}
+		return utils.ValidateURL(req.URL.String())
+	}
+	return client
+}
+
+// cachedImagePaths returns the data and content type files for a cached URL
+func cachedImagePaths(imageURL string) (string, string) {
+	sum := sha256.Sum256([]byte(imageURL))
+	base := filepath.Join(imageProxyCacheDir(), hex.EncodeToString(sum[:]))
+	return base + ".img", base + ".type"
+}
+
+// readCachedImage loads a cached image, reporting its age. Images cached before their type was removed
+// from the allowlist are treated as missing.
+func readCachedImage(imageURL string) ([]byte, string, time.Duration, bool) {
+	dataPath, typePath := cachedImagePaths(imageURL)
+	info, err := os.Stat(dataPath)
+	if err != nil {
+		return nil, "", 0, false
+	}
+	data, err := os.ReadFile(dataPath)
+	if err != nil {
+		return nil, "", 0, false
+	}
+	contentType, err := os.ReadFile(typePath)
+	if err != nil || utils.ValidateImageMIMEType(string(contentType)) != nil {
+		return nil, "", 0, false
+	}
+	// Record the access for eviction on the .type file; the age comes from the .img file, which keeps its fetch time
+	now := time.Now()
+	os.Chtimes(typePath, now, now)
+	return data, string(contentType), time.Since(info.ModTime()), true
+}
+
+// writeCachedImage stores a fetched image, logging rather than failing the request on errors
+func writeCachedImage(imageURL string, data []byte, contentType string) {
+	imageProxyCacheMu.Lock()
+	defer imageProxyCacheMu.Unlock()
+
+	if err := os.MkdirAll(imageProxyCacheDir(), 0755); err != nil {
+		logging.Warn("[IMAGE_PROXY] Failed to create cache directory", "error", err)
+		return
+	}
+	dataPath, typePath := cachedImagePaths(imageURL)
+	if err := os.WriteFile(typePath, []byte(contentType), 0644); err != nil {
+		logging.Warn("[IMAGE_PROXY] Failed to cache image", "url", imageURL, "error", err)
+		return
+	}
+	if err := os.WriteFile(dataPath, data, 0644); err != nil {
+		logging.Warn("[IMAGE_PROXY] Failed to cache image", "url", imageURL, "error", err)
+		return
+	}
+	evictCachedImages(imageProxyCacheMaxSize())
+}
+
+// evictCachedImages deletes the least recently used cached images until the cache fits in maxSize
+func evictCachedImages(maxSize int64) {
+	dir := imageProxyCacheDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	type cachedImage struct {
+		base       string
+		size       int64
+		lastAccess time.Time
+	}
+	images := make(map[string]*cachedImage)
+	var total int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if ext != ".img" && ext != ".type" {
+			continue
+		}
+		base := strings.TrimSuffix(name, ext)
+		img, ok := images[base]
+		if !ok {
+			img = &cachedImage{base: base}
+			images[base] = img
+		}
+		img.size += info.Size()
+		total += info.Size()
+		// Reads touch the .type file, so it carries the last access time
+		if ext == ".type" || img.lastAccess.IsZero() {
+			img.lastAccess = info.ModTime()
+		}
+	}
+	if total <= maxSize {
+		return
+	}
+
+	ordered := make([]*cachedImage, 0, len(images))
+	for _, img := range images {
+		ordered = append(ordered, img)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].lastAccess.Before(ordered[j].lastAccess)
+	})
+
+	evicted := 0
+	for _, img := range ordered {
+		if total <= maxSize {
+			break
+		}
+		os.Remove(filepath.Join(dir, img.base+".img"))
+		os.Remove(filepath.Join(dir, img.base+".type"))
+		total -= img.size
+		evicted++
+	}
+	logging.Debug("[IMAGE_PROXY] Evicted cached images", "count", evicted, "cache_bytes", total)
+}
+
+// fetchRemoteImage downloads an image, rejecting types outside IMAGE_ALLOWED_MIME_TYPES and oversized bodies
+func fetchRemoteImage(imageURL string) ([]byte, string, error) {
+	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "Stationmaster-ImageProxy/1.0")
+	req.Header.Set("Accept", strings.Join(utils.AllowedImageMIMETypes(), ","))
+
+	resp, err := imageProxyClient().Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("upstream returned HTTP %d", resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if err := utils.ValidateImageMIMEType(contentType); err != nil {
+		return nil, "", fmt.Errorf("upstream content rejected: %w", err)
+	}
+
+	maxSize := imageProxyMaxSize()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, "", fmt.Errorf("image exceeds %d bytes", maxSize)
+	}
+
+	return data, contentType, nil
+}
+
+// ImageProxyHandler fetches a remote image on behalf of plugin templates and caches it on disk so
+// renders don't depend on the upstream host every time. Cached copies are served for
+// IMAGE_PROXY_CACHE_TTL and past it when the upstream fetch fails. Only URLs signed by the renderer are
+// fetched, so the endpoint can't be used as an open proxy.
+// GET /api/image-proxy?url=...&sig=...
+func ImageProxyHandler(c *gin.Context) {
+	if !config.GetBool("IMAGE_PROXY_ENABLED", true) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image proxy is disabled"})
+		return
+	}
+
+	imageURL := c.Query("url")
+	if imageURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url parameter is required"})
+		return
+	}
+	if !utils.VerifyImageProxySignature(imageURL, c.Query("sig")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or missing image proxy signature"})
+		return
+	}
+	if err := utils.ValidateURL(imageURL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "URL not allowed", "details": err.Error()})
+		return
+	}
+
+	ttl := config.GetDuration("IMAGE_PROXY_CACHE_TTL", time.Hour)
+	var cached []byte
+	var cachedType string
+	var age time.Duration
+	var hasCache bool
+	if ttl > 0 {
+		cached, cachedType, age, hasCache = readCachedImage(imageURL)
+		if hasCache && age < ttl {
+			c.Header("X-Cache", "HIT")
+			c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int((ttl-age).Seconds())))
+			serveProxiedImage(c, cachedType, cached)
+			return
+		}
+	}
+
+	data, contentType, err := fetchRemoteImage(imageURL)
+	if err != nil {
+		if hasCache {
+			logging.Warn("[IMAGE_PROXY] Fetch failed, serving stale cached image", "url", imageURL, "age", age, "error", err)
+			c.Header("X-Cache", "STALE")
+			serveProxiedImage(c, cachedType, cached)
+			return
+		}
+		logging.Warn("[IMAGE_PROXY] Failed to fetch image", "url", imageURL, "error", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch image", "details": err.Error()})
+		return
+	}
+
+	if ttl > 0 {
+		writeCachedImage(imageURL, data, contentType)
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(ttl.Seconds())))
+	} else {
+		c.Header("Cache-Control", "no-store")
+	}
+	c.Header("X-Cache", "MISS")
+	serveProxiedImage(c, contentType, data)
+}
+
+// serveProxiedImage writes an image with headers that stop SVG content from running scripts on our origin
+func serveProxiedImage(c *gin.Context, contentType string, data []byte) {
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// ExportPlaylistHandler returns a playlist with its items and schedules as a single JSON document
+// GET /api/playlists/:id/export
+func ExportPlaylistHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	playlistID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	playlistService := database.NewPlaylistService(database.GetDB())
+	playlist, err := playlistService.GetPlaylistByID(playlistID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+	if playlist.UserID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	export, err := playlistService.ExportPlaylist(playlist)
+	if err != nil {
+		logging.Error("[PLAYLIST] Failed to export playlist", "playlist_id", playlistID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export playlist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// ImportPlaylistHandler creates a playlist on one of the user's devices from an exported playlist
+// document. Items whose plugin instances can't be found are skipped and listed in the warnings.
+// POST /api/playlists/import
+func ImportPlaylistHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		database.PlaylistExport
+		DeviceID  uuid.UUID `json:"device_id" binding:"required"`
+		Name      string    `json:"name"`       // Overrides the exported playlist name
+		IsDefault *bool     `json:"is_default"` // Overrides the exported default flag
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Version > database.PlaylistExportVersion {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported playlist export version", "max_version": database.PlaylistExportVersion})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = strings.TrimSpace(req.Playlist.Name)
+	}
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Playlist name is required"})
+		return
+	}
+	isDefault := req.Playlist.IsDefault
+	if req.IsDefault != nil {
+		isDefault = *req.IsDefault
+	}
+
+	db := database.GetDB()
+	device, err := database.NewDeviceService(db).GetDeviceByID(req.DeviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+	if device.UserID == nil || *device.UserID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	playlist, items, warnings, err := database.NewPlaylistService(db).ImportPlaylist(user.ID, device.ID, name, isDefault, &req.PlaylistExport)
+	if err != nil {
+		logging.Error("[PLAYLIST] Failed to import playlist", "device_id", device.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import playlist"})
+		return
+	}
+
+	// Render the imported instances right away, as adding them one by one would
+	scheduled := make(map[uuid.UUID]bool)
+	for _, item := range items {
+		if scheduled[item.PluginInstanceID] {
+			continue
+		}
+		scheduled[item.PluginInstanceID] = true
+		instanceID := item.PluginInstanceID
+		renderJob := database.RenderQueue{
+			ID:                uuid.New(),
+			PluginInstanceID:  &instanceID,
+			Priority:          999,
+			ScheduledFor:      time.Now().UTC(),
+			Status:            "pending",
+			IndependentRender: true,
+		}
+		if err := db.Create(&renderJob).Error; err != nil {
+			logging.Error("[PLAYLIST] Failed to schedule render for imported item", "plugin_instance_id", instanceID, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"playlist":       playlist,
+		"items":          items,
+		"imported_items": len(items),
+		"skipped_items":  len(req.Items) - len(items),
+		"warnings":       warnings,
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -160,6 +161,7 @@ func GetPlaylistHandler(c *gin.Context) {
 			"importance":        item.Importance,
 			"duration_override": item.DurationOverride,
 			"skip_display":      item.SkipDisplay,
+			"min_display_count": item.MinDisplayCount,
 			"created_at":        item.CreatedAt,
 			"updated_at":        item.UpdatedAt,
 			"schedules":         item.Schedules,
@@ -325,6 +327,7 @@ func AddPlaylistItemHandler(c *gin.Context) {
 		PluginInstanceID uuid.UUID `json:"plugin_instance_id" binding:"required"`
 		Importance       bool      `json:"importance"`
 		DurationOverride *int      `json:"duration_override"`
+		Force            bool      `json:"force"` // Add even if the device does not meet the plugin's requirements
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -360,12 +363,33 @@ func AddPlaylistItemHandler(c *gin.Context) {
 		return
 	}
 
+	// Block plugins the playlist's device can't display unless explicitly forced
+	var unmetRequirements []string
+	if device, err := database.NewDeviceService(db).GetDeviceByID(playlist.DeviceID); err == nil {
+		unmetRequirements = pluginInstance.PluginDefinition.DeviceRequirements().UnmetBy(device)
+	}
+	if len(unmetRequirements) > 0 && !req.Force {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":              "Device does not meet the plugin's requirements",
+			"unmet_requirements": unmetRequirements,
+		})
+		return
+	}
+
 	item, err := playlistService.AddItemToPlaylist(playlistID, req.PluginInstanceID, req.Importance, req.DurationOverride)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add item to playlist"})
 		return
 	}
 
+	// Warn (but don't block) when the instance is missing required settings
+	preflight, preflightErr := preflightPluginInstance(pluginInstance)
+	if preflightErr != nil {
+		logging.Warn("[PLAYLIST] Failed to preflight plugin instance", "plugin_instance_id", req.PluginInstanceID, "error", preflightErr)
+	} else if !preflight.Ready {
+		logging.Info("[PLAYLIST] Plugin instance added with missing required settings", "plugin_instance_id", req.PluginInstanceID, "missing", len(preflight.MissingSettings))
+	}
+
 	// Schedule immediate independent render for the plugin instance
 	piID := req.PluginInstanceID
 	renderJob := database.RenderQueue{
@@ -433,9 +457,22 @@ func AddPlaylistItemHandler(c *gin.Context) {
 		},
 	})
 
-	c.JSON(http.StatusCreated, gin.H{"playlist_item": item})
+	response := gin.H{"playlist_item": item}
+	if preflightErr == nil && !preflight.Ready {
+		response["preflight"] = preflight
+		response["warning"] = "Plugin instance is missing required settings and may fail to render"
+	}
+	if len(unmetRequirements) > 0 {
+		response["warning"] = "Device does not meet the plugin's requirements"
+		response["unmet_requirements"] = unmetRequirements
+	}
+
+	c.JSON(http.StatusCreated, response)
 }
 
+// maxMinDisplayCount caps how many consecutive check-ins an item can require before advancing
+const maxMinDisplayCount = 100
+
 // UpdatePlaylistItemHandler updates a playlist item
 func UpdatePlaylistItemHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
@@ -455,6 +492,7 @@ func UpdatePlaylistItemHandler(c *gin.Context) {
 		IsVisible        *bool `json:"is_visible"`
 		Importance       *bool `json:"importance"`
 		DurationOverride *int  `json:"duration_override"`
+		MinDisplayCount  *int  `json:"min_display_count"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -462,6 +500,11 @@ func UpdatePlaylistItemHandler(c *gin.Context) {
 		return
 	}
 
+	if req.MinDisplayCount != nil && (*req.MinDisplayCount < 0 || *req.MinDisplayCount > maxMinDisplayCount) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("min_display_count must be between 0 and %d", maxMinDisplayCount)})
+		return
+	}
+
 	db := database.GetDB()
 	playlistService := database.NewPlaylistService(db)
 
@@ -485,6 +528,9 @@ func UpdatePlaylistItemHandler(c *gin.Context) {
 	if req.Importance != nil {
 		item.Importance = *req.Importance
 	}
+	if req.MinDisplayCount != nil {
+		item.MinDisplayCount = *req.MinDisplayCount
+	}
 	// Always update duration_override field when provided (including null values)
 	item.DurationOverride = req.DurationOverride
 
@@ -515,6 +561,109 @@ func UpdatePlaylistItemHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"playlist_item": item})
 }
 
+// UpdatePlaylistVisibilityHandler shows or hides several items of a playlist at once
+func UpdatePlaylistVisibilityHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+	userUUID := user.ID
+	playlistIDStr := c.Param("id")
+
+	playlistID, err := uuid.Parse(playlistIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid playlist ID"})
+		return
+	}
+
+	var req struct {
+		Visibility map[string]bool `json:"visibility" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(req.Visibility) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No items provided"})
+		return
+	}
+
+	db := database.GetDB()
+	playlistService := database.NewPlaylistService(db)
+
+	// Verify playlist ownership
+	playlist, err := playlistService.GetPlaylistByID(playlistID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Playlist not found"})
+		return
+	}
+
+	if playlist.UserID != userUUID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	// Convert string keys to UUIDs
+	visibility := make(map[uuid.UUID]bool, len(req.Visibility))
+	for itemIDStr, visible := range req.Visibility {
+		itemID, err := uuid.Parse(itemIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID in visibility map"})
+			return
+		}
+		visibility[itemID] = visible
+	}
+
+	// Reject the whole request if any item belongs to another playlist
+	var playlistItemIDs []uuid.UUID
+	if err := db.Model(&database.PlaylistItem{}).Where("playlist_id = ?", playlistID).Pluck("id", &playlistItemIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load playlist items"})
+		return
+	}
+	inPlaylist := make(map[uuid.UUID]bool, len(playlistItemIDs))
+	for _, id := range playlistItemIDs {
+		inPlaylist[id] = true
+	}
+	var invalidItemIDs []string
+	for itemID := range visibility {
+		if !inPlaylist[itemID] {
+			invalidItemIDs = append(invalidItemIDs, itemID.String())
+		}
+	}
+	if len(invalidItemIDs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Items do not belong to this playlist", "invalid_item_ids": invalidItemIDs})
+		return
+	}
+
+	if err := playlistService.SetPlaylistItemsVisibility(playlistID, visibility); err != nil {
+		logging.Error("[PLAYLIST] Failed to update playlist item visibility", "playlist_id", playlistID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update playlist item visibility"})
+		return
+	}
+
+	// Broadcast per-item events so clients handle them like single visibility changes
+	sseService := sse.GetSSEService()
+	for itemID := range visibility {
+		item, err := playlistService.GetPlaylistItemByID(itemID)
+		if err != nil {
+			continue
+		}
+		sseService.BroadcastToDevice(playlist.DeviceID, sse.Event{
+			Type: "playlist_item_visibility_changed",
+			Data: map[string]interface{}{
+				"device_id":     playlist.DeviceID.String(),
+				"playlist_id":   playlist.ID.String(),
+				"playlist_item": item,
+				"timestamp":     time.Now().UTC(),
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Playlist item visibility updated successfully", "updated": len(visibility)})
+}
+
 // DeletePlaylistItemHandler removes an item from a playlist
 func DeletePlaylistItemHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"gorm.io/gorm"
+)
+
+// PublishPluginDefinitionHandler publishes one of the user's private plugins to the shared catalog
+func PublishPluginDefinitionHandler(c *gin.Context) {
+	setPluginDefinitionPublished(c, true)
+}
+
+// UnpublishPluginDefinitionHandler removes a plugin from the shared catalog. Installed copies are kept.
+// Admins can unpublish any plugin.
+func UnpublishPluginDefinitionHandler(c *gin.Context) {
+	setPluginDefinitionPublished(c, false)
+}
+
+// setPluginDefinitionPublished updates a definition's catalog visibility
+func setPluginDefinitionPublished(c *gin.Context, published bool) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	service := database.NewUnifiedPluginService(database.GetDB())
+	definition, err := service.SetPluginDefinitionPublished(c.Param("id"), user, published)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plugin definition not found"})
+		case errors.Is(err, database.ErrPluginNotPublishable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			logging.Error("[PLUGIN CATALOG] Failed to update publish state", "definition_id", c.Param("id"), "published", published, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plugin definition"})
+		}
+		return
+	}
+
+	logging.Info("[PLUGIN CATALOG] Plugin publish state changed", "definition_id", definition.ID, "name", definition.Name, "published", published, "username", user.Username)
+	c.JSON(http.StatusOK, gin.H{"plugin_definition": definition})
+}
+
+// GetPluginCatalogHandler lists the plugins published to the shared catalog, filtered with ?search=
+func GetPluginCatalogHandler(c *gin.Context) {
+	if _, ok := auth.RequireUser(c); !ok {
+		return
+	}
+
+	plugins, err := database.NewUnifiedPluginService(database.GetDB()).GetCatalogPlugins(c.Query("search"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plugin catalog"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"plugins": plugins})
+}
+
+// GetCatalogPluginHandler returns a published plugin with its markup and settings so it can be reviewed before installing
+func GetCatalogPluginHandler(c *gin.Context) {
+	if _, ok := auth.RequireUser(c); !ok {
+		return
+	}
+
+	plugin, err := database.NewUnifiedPluginService(database.GetDB()).GetCatalogPlugin(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plugin not found in catalog"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch catalog plugin"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"plugin": plugin})
+}
+
+// InstallCatalogPluginHandler copies a published plugin into the user's account as a private plugin
+func InstallCatalogPluginHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	definition, err := database.NewUnifiedPluginService(database.GetDB()).InstallCatalogPlugin(c.Param("id"), user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plugin not found in catalog"})
+		case errors.Is(err, database.ErrInstallOwnPlugin):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			logging.Error("[PLUGIN CATALOG] Failed to install catalog plugin", "definition_id", c.Param("id"), "user_id", user.ID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to install plugin"})
+		}
+		return
+	}
+
+	logging.Info("[PLUGIN CATALOG] Installed catalog plugin", "source_definition_id", c.Param("id"), "definition_id", definition.ID, "username", user.Username)
+	c.JSON(http.StatusCreated, gin.H{"plugin_definition": definition})
+}
@@ -23,6 +23,7 @@ type ValidateTRMNLYAMLResponse struct {
 	ConvertedPlugin map[string]interface{} `json:"converted_plugin,omitempty"`
 	Errors          []string               `json:"errors,omitempty"`
 	Warnings        []string               `json:"warnings,omitempty"`
+	Issues          []YAMLIssue            `json:"issues,omitempty"`
 }
 
 // ValidationInfo provides detailed validation information
@@ -66,12 +67,27 @@ func ValidateTRMNLYAMLHandler(c *gin.Context) {
 	// Create TRMNL export service for validation
 	exportService := NewTRMNLExportService()
 
-	// Step 1: Parse YAML
+	// Step 1: Check the YAML against the settings schema so problems can be located by line
+	response.Issues = ValidateTRMNLSettingsSchema([]byte(req.YAML))
+	schemaErrors := 0
+	for _, issue := range response.Issues {
+		if issue.Severity == "error" {
+			response.Errors = append(response.Errors, issue.String())
+			schemaErrors++
+		} else {
+			response.Warnings = append(response.Warnings, issue.String())
+		}
+	}
+
+	// Step 2: Parse YAML
 	def, err := exportService.ParseSettingsYAML([]byte(req.YAML))
 	if err != nil {
 		logging.Error("[TRMNL DEBUG] YAML parsing failed", "error", err)
 		response.Valid = false
-		response.Errors = append(response.Errors, "YAML parsing failed: "+err.Error())
+		// Schema errors already describe the failure with positions
+		if schemaErrors == 0 {
+			response.Errors = append(response.Errors, "YAML parsing failed: "+err.Error())
+		}
 		c.JSON(http.StatusOK, response)
 		return
 	}
@@ -102,7 +118,7 @@ func ValidateTRMNLYAMLHandler(c *gin.Context) {
 		}
 	}
 
-	// Step 2: Detailed validation checks
+	// Step 3: Detailed validation checks
 	response.ValidationInfo.RequiredFields = validateRequiredFields(def)
 	response.ValidationInfo.Strategy = validateStrategy(def)
 	response.ValidationInfo.RefreshInterval = validateRefreshInterval(def)
@@ -110,7 +126,7 @@ func ValidateTRMNLYAMLHandler(c *gin.Context) {
 	response.ValidationInfo.FormFields = validateFormFields(def)
 	response.ValidationInfo.ScreenOptions = validateScreenOptions(def)
 
-	// Step 3: Template validation if available
+	// Step 4: Template validation if available
 	if def.MarkupFull != nil || def.MarkupHalfVert != nil || def.MarkupHalfHoriz != nil || def.MarkupQuadrant != nil {
 		validator := validation.NewTemplateValidator()
 		validationResult := validator.ValidateAllTemplates(
@@ -222,14 +238,14 @@ func validateStrategy(def *database.PluginDefinition) ValidationStatus {
 	}
 	
 	strategy := *def.DataStrategy
-	validStrategies := []string{"polling", "webhook", "static"}
+	validStrategies := []string{"polling", "webhook", "static", "image"}
 	for _, valid := range validStrategies {
 		if strategy == valid {
 			return ValidationStatus{Valid: true, Message: "Valid strategy: " + strategy}
 		}
 	}
 	
-	return ValidationStatus{Valid: false, Message: "Invalid strategy: " + strategy, Details: "Must be polling, webhook, or static"}
+	return ValidationStatus{Valid: false, Message: "Invalid strategy: " + strategy, Details: "Must be polling, webhook, static, or image"}
 }
 
 func validateRefreshInterval(def *database.PluginDefinition) ValidationStatus {
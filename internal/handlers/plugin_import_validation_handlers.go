@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/validation"
+)
+
+// ValidatePluginImportResponse is the report for a plugin ZIP checked before import
+type ValidatePluginImportResponse struct {
+	Valid          bool                   `json:"valid"`
+	Plugin         map[string]interface{} `json:"plugin,omitempty"`          // Summary of the plugin that would be created
+	ValidationInfo *ValidationInfo        `json:"validation_info,omitempty"` // Only set once settings.yml converts
+	Errors         []string               `json:"errors"`
+	Warnings       []string               `json:"warnings"`
+	Issues         []YAMLIssue            `json:"issues,omitempty"` // settings.yml schema issues with positions
+}
+
+// ValidatePluginImportHandler runs the same checks as an import of a TRMNL-compatible ZIP file, plus
+// template and form field validation, and reports the result without creating anything. Problems with
+// the bundle are reported in the response body rather than as request errors.
+// POST /api/plugin-definitions/import/validate
+func ValidatePluginImportHandler(c *gin.Context) {
+	if _, ok := auth.RequireUser(c); !ok {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded", "details": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	response := ValidatePluginImportResponse{
+		Errors:   []string{},
+		Warnings: []string{},
+	}
+	zipService := NewTRMNLZipService()
+
+	if err := zipService.ValidateZipStructure(file, header); err != nil {
+		response.Errors = append(response.Errors, "Invalid ZIP file: "+err.Error())
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if _, err := file.Seek(0, 0); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process uploaded file"})
+		return
+	}
+
+	zipData, err := zipService.ExtractTRMNLZip(file, header)
+	if err != nil {
+		response.Errors = append(response.Errors, "Invalid TRMNL ZIP format: "+err.Error())
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	// Schema issues point at the offending settings.yml lines, which conversion errors don't
+	response.Issues = ValidateTRMNLSettingsSchema(zipData.SettingsYAML)
+	schemaErrors := 0
+	for _, issue := range response.Issues {
+		if issue.Severity == "error" {
+			response.Errors = append(response.Errors, issue.String())
+			schemaErrors++
+		} else {
+			response.Warnings = append(response.Warnings, issue.String())
+		}
+	}
+
+	def, err := zipService.ConvertZipDataToPluginDefinition(zipData)
+	if err != nil {
+		if schemaErrors == 0 {
+			response.Errors = append(response.Errors, "Failed to process plugin data: "+err.Error())
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	response.ValidationInfo = &ValidationInfo{
+		RequiredFields:  validateRequiredFields(def),
+		Strategy:        validateStrategy(def),
+		RefreshInterval: validateRefreshInterval(def),
+		PollingConfig:   validatePollingConfig(def),
+		FormFields:      validateImportFormFields(def),
+		ScreenOptions:   validateScreenOptions(def),
+	}
+	for _, status := range []ValidationStatus{
+		response.ValidationInfo.RequiredFields,
+		response.ValidationInfo.Strategy,
+		response.ValidationInfo.RefreshInterval,
+		response.ValidationInfo.PollingConfig,
+		response.ValidationInfo.FormFields,
+	} {
+		if !status.Valid {
+			response.Errors = append(response.Errors, status.Message)
+		}
+	}
+
+	templateResult := validation.NewTemplateValidator().ValidateAllTemplates(
+		getStringValue(def.MarkupFull),
+		getStringValue(def.MarkupHalfVert),
+		getStringValue(def.MarkupHalfHoriz),
+		getStringValue(def.MarkupQuadrant),
+		getStringValue(def.SharedMarkup),
+	)
+	if !templateResult.Valid {
+		response.Errors = append(response.Errors, templateResult.Errors...)
+	}
+	response.Warnings = append(response.Warnings, templateResult.Warnings...)
+
+	response.Plugin = map[string]interface{}{
+		"name":                def.Name,
+		"description":         def.Description,
+		"version":             def.Version,
+		"data_strategy":       getStringValue(def.DataStrategy),
+		"enable_dark_mode":    getBoolPointerValue(def.EnableDarkMode),
+		"remove_bleed_margin": getBoolPointerValue(def.RemoveBleedMargin),
+		"has_form_fields":     def.FormFields != nil,
+		"templates": map[string]bool{
+			"full":            def.MarkupFull != nil,
+			"half_vertical":   def.MarkupHalfVert != nil,
+			"half_horizontal": def.MarkupHalfHoriz != nil,
+			"quadrant":        def.MarkupQuadrant != nil,
+			"shared":          def.SharedMarkup != nil,
+		},
+	}
+
+	response.Valid = len(response.Errors) == 0
+	c.JSON(http.StatusOK, response)
+}
+
+// validateImportFormFields checks that an imported definition's form fields convert to a settings schema
+func validateImportFormFields(def *database.PluginDefinition) ValidationStatus {
+	if def.FormFields == nil {
+		return validateFormFields(def)
+	}
+
+	var formFields interface{}
+	if err := json.Unmarshal(def.FormFields, &formFields); err != nil {
+		return ValidationStatus{Valid: false, Message: "Form fields could not be parsed", Details: err.Error()}
+	}
+	if _, err := validation.ValidateFormFields(formFields); err != nil {
+		return ValidationStatus{Valid: false, Message: "Invalid form fields", Details: err.Error()}
+	}
+	return ValidationStatus{Valid: true, Message: "Form fields are valid"}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// GetPluginInstanceDevicesHandler lists the user's devices that show a plugin instance, directly in a
+// playlist or through a mashup, so the impact of editing the instance is visible
+func GetPluginInstanceDevicesHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	instance, ok := ownedPluginInstance(c, user.ID)
+	if !ok {
+		return
+	}
+
+	usage, err := database.NewPlaylistService(database.GetDB()).GetDeviceUsageForPluginInstance(instance.ID, user.ID)
+	if err != nil {
+		logging.Error("[PLUGIN_INSTANCE] Failed to get devices using plugin instance", "instance_id", instance.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get devices"})
+		return
+	}
+
+	devices := make([]gin.H, 0, len(usage))
+	for _, entry := range usage {
+		device := gin.H{
+			"id":          entry.Device.ID,
+			"friendly_id": entry.Device.FriendlyID,
+			"name":        entry.Device.Name,
+			"direct":      entry.Direct,
+			"mashup_ids":  entry.MashupIDs,
+		}
+		if entry.Device.DeviceModel != nil {
+			device["model"] = gin.H{
+				"name":          entry.Device.DeviceModel.ModelName,
+				"display_name":  entry.Device.DeviceModel.DisplayName,
+				"screen_width":  entry.Device.DeviceModel.ScreenWidth,
+				"screen_height": entry.Device.DeviceModel.ScreenHeight,
+				"bit_depth":     entry.Device.DeviceModel.BitDepth,
+			}
+		}
+		devices = append(devices, device)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"instance_id": instance.ID,
+		"devices":     devices,
+	})
+}
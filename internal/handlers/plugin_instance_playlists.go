@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/sse"
+	"gorm.io/gorm"
+)
+
+// maxBulkPlaylistTargets caps how many playlists one bulk assignment can touch
+const maxBulkPlaylistTargets = 100
+
+// Per-playlist outcomes of a bulk assignment
+const (
+	bulkPlaylistAdded             = "added"
+	bulkPlaylistDuplicate         = "duplicate"
+	bulkPlaylistNotFound          = "not_found"
+	bulkPlaylistUnmetRequirements = "unmet_requirements"
+)
+
+// bulkPlaylistTarget is one playlist in a bulk assignment request
+type bulkPlaylistTarget struct {
+	PlaylistID       uuid.UUID `json:"playlist_id" binding:"required"`
+	DurationOverride *int      `json:"duration_override"`
+}
+
+// bulkPlaylistResult reports what happened for one playlist
+type bulkPlaylistResult struct {
+	PlaylistID        uuid.UUID              `json:"playlist_id"`
+	Status            string                 `json:"status"`
+	PlaylistItem      *database.PlaylistItem `json:"playlist_item,omitempty"`
+	UnmetRequirements []string               `json:"unmet_requirements,omitempty"`
+	deviceID          uuid.UUID
+}
+
+// AddPluginInstanceToPlaylistsHandler adds a plugin instance to several of the user's playlists in one
+// transaction. Playlists that already contain the instance, aren't the user's, or belong to a device
+// that doesn't meet the plugin's requirements (unless forced) are skipped and reported per playlist.
+func AddPluginInstanceToPlaylistsHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	instance, ok := ownedPluginInstance(c, user.ID)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Playlists  []bulkPlaylistTarget `json:"playlists" binding:"required,min=1,dive"`
+		Importance bool                 `json:"importance"`
+		Force      bool                 `json:"force"` // Add even if a device does not meet the plugin's requirements
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Playlists) > maxBulkPlaylistTargets {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many playlists", "max_playlists": maxBulkPlaylistTargets})
+		return
+	}
+	for _, target := range req.Playlists {
+		if target.DurationOverride != nil && *target.DurationOverride <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "duration_override must be positive", "playlist_id": target.PlaylistID})
+			return
+		}
+	}
+
+	requirements := instance.PluginDefinition.DeviceRequirements()
+	results := make([]bulkPlaylistResult, 0, len(req.Playlists))
+	db := database.GetDB()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		playlistService := database.NewPlaylistService(tx)
+		deviceService := database.NewDeviceService(tx)
+		seen := make(map[uuid.UUID]bool)
+
+		for _, target := range req.Playlists {
+			result := bulkPlaylistResult{PlaylistID: target.PlaylistID}
+
+			playlist, err := playlistService.GetPlaylistByID(target.PlaylistID)
+			if err != nil || playlist.UserID != user.ID {
+				result.Status = bulkPlaylistNotFound
+				results = append(results, result)
+				continue
+			}
+			result.deviceID = playlist.DeviceID
+
+			var existing int64
+			if err := tx.Model(&database.PlaylistItem{}).
+				Where("playlist_id = ? AND plugin_instance_id = ?", playlist.ID, instance.ID).
+				Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 || seen[playlist.ID] {
+				result.Status = bulkPlaylistDuplicate
+				results = append(results, result)
+				continue
+			}
+			seen[playlist.ID] = true
+
+			if device, err := deviceService.GetDeviceByID(playlist.DeviceID); err == nil {
+				result.UnmetRequirements = requirements.UnmetBy(device)
+			}
+			if len(result.UnmetRequirements) > 0 && !req.Force {
+				result.Status = bulkPlaylistUnmetRequirements
+				results = append(results, result)
+				continue
+			}
+
+			item, err := playlistService.AddItemToPlaylist(playlist.ID, instance.ID, req.Importance, target.DurationOverride)
+			if err != nil {
+				return err
+			}
+			result.Status = bulkPlaylistAdded
+			result.PlaylistItem = item
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		logging.Error("[PLAYLIST] Failed to add plugin instance to playlists", "plugin_instance_id", instance.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add plugin instance to playlists"})
+		return
+	}
+
+	added := 0
+	sseService := sse.GetSSEService()
+	for _, result := range results {
+		if result.Status != bulkPlaylistAdded {
+			continue
+		}
+		added++
+		sseService.BroadcastToDevice(result.deviceID, sse.Event{
+			Type: "playlist_item_added",
+			Data: map[string]interface{}{
+				"device_id":     result.deviceID.String(),
+				"playlist_id":   result.PlaylistID.String(),
+				"playlist_item": result.PlaylistItem,
+				"timestamp":     time.Now().UTC(),
+			},
+		})
+	}
+
+	if added > 0 {
+		instanceIDs := []uuid.UUID{instance.ID}
+		if instance.PluginDefinition.PluginType == "mashup" {
+			children, err := database.NewMashupService(db).GetChildren(instance.ID)
+			if err != nil {
+				logging.Warn("[PLAYLIST] Failed to get mashup children for render scheduling", "mashup_id", instance.ID, "error", err)
+			}
+			for _, child := range children {
+				instanceIDs = append(instanceIDs, child.ChildInstanceID)
+			}
+		}
+		ScheduleRenderForInstances(instanceIDs)
+	}
+
+	logging.Info("[PLAYLIST] Bulk added plugin instance to playlists", "plugin_instance_id", instance.ID, "requested", len(req.Playlists), "added", added)
+	c.JSON(http.StatusOK, gin.H{
+		"instance_id": instance.ID,
+		"added":       added,
+		"results":     results,
+	})
+}
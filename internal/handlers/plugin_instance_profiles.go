@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+const (
+	// maxSettingsProfiles caps how many profiles a single plugin instance can store
+	maxSettingsProfiles = 20
+	// maxProfileNameLength matches the size of the active_profile column
+	maxProfileNameLength = 100
+)
+
+// settingsProfiles decodes an instance's stored profiles, returning an empty map when there are none
+func settingsProfiles(instance database.PluginInstance) map[string]json.RawMessage {
+	profiles := make(map[string]json.RawMessage)
+	if len(instance.SettingsProfiles) > 0 {
+		if err := json.Unmarshal(instance.SettingsProfiles, &profiles); err != nil {
+			logging.Warn("[PROFILES] Failed to decode settings profiles", "instance_id", instance.ID, "error", err)
+			return make(map[string]json.RawMessage)
+		}
+	}
+	return profiles
+}
+
+// settingsProfileNames returns an instance's profile names in sorted order
+func settingsProfileNames(instance database.PluginInstance) []string {
+	profiles := settingsProfiles(instance)
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// storeSettingsProfiles encodes profiles back onto the instance
+func storeSettingsProfiles(instance *database.PluginInstance, profiles map[string]json.RawMessage) error {
+	encoded, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	instance.SettingsProfiles = encoded
+	return nil
+}
+
+// ownedPluginInstance loads a plugin instance belonging to the user, writing an error response when it can't
+func ownedPluginInstance(c *gin.Context, userID uuid.UUID) (*database.PluginInstance, bool) {
+	var instance database.PluginInstance
+	err := database.GetDB().Preload("PluginDefinition").Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&instance).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plugin instance not found"})
+		return nil, false
+	}
+	return &instance, true
+}
+
+// profileNameParam validates the :name path parameter
+func profileNameParam(c *gin.Context) (string, bool) {
+	name := strings.TrimSpace(c.Param("name"))
+	if name == "" || len(name) > maxProfileNameLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Profile name must be between 1 and %d characters", maxProfileNameLength)})
+		return "", false
+	}
+	return name, true
+}
+
+// GetPluginInstanceProfilesHandler lists the settings profiles of a plugin instance
+func GetPluginInstanceProfilesHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	instance, ok := ownedPluginInstance(c, user.ID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profiles":       settingsProfiles(*instance),
+		"active_profile": instance.ActiveProfile,
+	})
+}
+
+// SavePluginInstanceProfileHandler creates or replaces a named settings profile. When no settings are
+// provided the instance's current settings are saved under the name.
+func SavePluginInstanceProfileHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	name, ok := profileNameParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Settings map[string]interface{} `json:"settings"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	instance, ok := ownedPluginInstance(c, user.ID)
+	if !ok {
+		return
+	}
+
+	profiles := settingsProfiles(*instance)
+	if _, exists := profiles[name]; !exists && len(profiles) >= maxSettingsProfiles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A plugin instance can have at most %d profiles", maxSettingsProfiles)})
+		return
+	}
+
+	settings := json.RawMessage(instance.Settings)
+	if req.Settings != nil {
+		encoded, err := json.Marshal(req.Settings)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to process settings: " + err.Error()})
+			return
+		}
+		settings = encoded
+	}
+	if len(settings) == 0 {
+		settings = json.RawMessage("{}")
+	}
+	profiles[name] = settings
+
+	// Saving over the active profile applies it straight away
+	settingsChanged := name == instance.ActiveProfile && req.Settings != nil
+	if settingsChanged {
+		instance.Settings = []byte(settings)
+	}
+
+	if err := storeSettingsProfiles(instance, profiles); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save profile"})
+		return
+	}
+	if err := database.GetDB().Save(instance).Error; err != nil {
+		logging.Error("[PROFILES] Failed to save settings profile", "instance_id", instance.ID, "profile", name, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save profile"})
+		return
+	}
+
+	if settingsChanged && instance.PluginDefinition.RequiresProcessing {
+		ScheduleRenderForInstances([]uuid.UUID{instance.ID})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profiles":       profiles,
+		"active_profile": instance.ActiveProfile,
+	})
+}
+
+// DeletePluginInstanceProfileHandler removes a settings profile. The active profile can't be deleted.
+func DeletePluginInstanceProfileHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	name, ok := profileNameParam(c)
+	if !ok {
+		return
+	}
+
+	instance, ok := ownedPluginInstance(c, user.ID)
+	if !ok {
+		return
+	}
+
+	profiles := settingsProfiles(*instance)
+	if _, exists := profiles[name]; !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Profile not found"})
+		return
+	}
+	if name == instance.ActiveProfile {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot delete the active profile; activate another profile first"})
+		return
+	}
+
+	delete(profiles, name)
+	if err := storeSettingsProfiles(instance, profiles); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete profile"})
+		return
+	}
+	if err := database.GetDB().Save(instance).Error; err != nil {
+		logging.Error("[PROFILES] Failed to delete settings profile", "instance_id", instance.ID, "profile", name, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Profile deleted successfully"})
+}
+
+// ActivatePluginInstanceProfileHandler copies a profile into the instance settings and triggers a re-render.
+// Edits made to the previously active profile's settings are kept in that profile.
+func ActivatePluginInstanceProfileHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	name, ok := profileNameParam(c)
+	if !ok {
+		return
+	}
+
+	instance, ok := ownedPluginInstance(c, user.ID)
+	if !ok {
+		return
+	}
+
+	profiles := settingsProfiles(*instance)
+	settings, exists := profiles[name]
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Profile not found"})
+		return
+	}
+
+	if _, activeExists := profiles[instance.ActiveProfile]; activeExists && instance.ActiveProfile != name && len(instance.Settings) > 0 {
+		profiles[instance.ActiveProfile] = json.RawMessage(instance.Settings)
+	}
+
+	instance.Settings = []byte(settings)
+	instance.ActiveProfile = name
+	if err := storeSettingsProfiles(instance, profiles); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to activate profile"})
+		return
+	}
+	if err := database.GetDB().Save(instance).Error; err != nil {
+		logging.Error("[PROFILES] Failed to activate settings profile", "instance_id", instance.ID, "profile", name, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to activate profile"})
+		return
+	}
+
+	logging.Info("[PROFILES] Activated settings profile", "instance_id", instance.ID, "profile", name)
+
+	if instance.PluginDefinition.RequiresProcessing {
+		ScheduleRenderForInstances([]uuid.UUID{instance.ID})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"instance": instance})
+}
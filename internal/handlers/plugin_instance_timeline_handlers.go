@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTimelineLimit = 100
+	maxTimelineLimit     = 500
+)
+
+// TimelineEntry is one item in a plugin instance's activity feed
+type TimelineEntry struct {
+	Time     time.Time              `json:"time"`
+	Type     string                 `json:"type"`   // created, rendered, render_unchanged, render_failed, render_scheduled, or a recorded event type
+	Source   string                 `json:"source"` // Table the entry was read from
+	Message  string                 `json:"message,omitempty"`
+	DeviceID string                 `json:"device_id,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// GetPluginInstanceTimelineHandler returns a plugin instance's activity as one chronological feed,
+// newest first, built from its renders, queued jobs and recorded events
+// GET /api/plugin-instances/:id/timeline
+func GetPluginInstanceTimelineHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	instance, ok := ownedPluginInstance(c, user.ID)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTimelineLimit)))
+	if err != nil || limit <= 0 {
+		limit = defaultTimelineLimit
+	}
+	if limit > maxTimelineLimit {
+		limit = maxTimelineLimit
+	}
+
+	db := database.GetDB()
+	entries := []TimelineEntry{{
+		Time:    instance.CreatedAt,
+		Type:    "created",
+		Source:  "plugin_instance",
+		Message: "Instance created",
+	}}
+
+	var rendered []database.RenderedContent
+	if err := db.Where("plugin_instance_id = ?", instance.ID).Order("rendered_at DESC").Limit(limit).Find(&rendered).Error; err != nil {
+		logging.Error("[PLUGIN_TIMELINE] Failed to get rendered content", "instance_id", instance.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get timeline"})
+		return
+	}
+	for _, rc := range rendered {
+		deviceID := ""
+		if rc.DeviceID != nil {
+			deviceID = rc.DeviceID.String()
+		}
+		entries = append(entries, TimelineEntry{
+			Time:     rc.RenderedAt,
+			Type:     "rendered",
+			Source:   "rendered_content",
+			Message:  "Content changed and was rendered",
+			DeviceID: deviceID,
+			Details: map[string]interface{}{
+				"width":     rc.Width,
+				"height":    rc.Height,
+				"bit_depth": rc.BitDepth,
+				"file_size": rc.FileSize,
+			},
+		})
+		// Unchanged renders only update the last check time of the previous render
+		if rc.LastCheckedAt != nil && rc.LastCheckedAt.After(rc.RenderedAt) {
+			entries = append(entries, TimelineEntry{
+				Time:     *rc.LastCheckedAt,
+				Type:     "render_unchanged",
+				Source:   "rendered_content",
+				Message:  "Content unchanged since the last render",
+				DeviceID: deviceID,
+			})
+		}
+	}
+
+	var jobs []database.RenderQueue
+	if err := db.Where("plugin_instance_id = ? AND status IN ?", instance.ID, []string{"pending", "processing", "failed"}).
+		Order("updated_at DESC").Limit(limit).Find(&jobs).Error; err != nil {
+		logging.Error("[PLUGIN_TIMELINE] Failed to get render queue", "instance_id", instance.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get timeline"})
+		return
+	}
+	for _, job := range jobs {
+		details := map[string]interface{}{
+			"job_id":   job.ID,
+			"attempts": job.Attempts,
+		}
+		if job.Status == "failed" {
+			failedAt := job.UpdatedAt
+			if job.LastAttempt != nil {
+				failedAt = *job.LastAttempt
+			}
+			entries = append(entries, TimelineEntry{
+				Time:    failedAt,
+				Type:    "render_failed",
+				Source:  "render_queue",
+				Message: job.ErrorMessage,
+				Details: details,
+			})
+			continue
+		}
+		details["status"] = job.Status
+		details["scheduled_for"] = job.ScheduledFor
+		entries = append(entries, TimelineEntry{
+			Time:    job.CreatedAt,
+			Type:    "render_scheduled",
+			Source:  "render_queue",
+			Message: "Render queued",
+			Details: details,
+		})
+	}
+
+	events, err := database.GetPluginInstanceEvents(db, instance.ID, limit)
+	if err != nil {
+		logging.Error("[PLUGIN_TIMELINE] Failed to get plugin instance events", "instance_id", instance.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get timeline"})
+		return
+	}
+	for _, event := range events {
+		entry := TimelineEntry{
+			Time:    event.CreatedAt,
+			Type:    event.Type,
+			Source:  "plugin_instance_events",
+			Message: event.Message,
+		}
+		if len(event.Details) > 0 {
+			json.Unmarshal(event.Details, &entry.Details)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"instance_id":          instance.ID,
+		"last_error":           instance.LastError,
+		"consecutive_failures": instance.ConsecutiveFailures,
+		"entries":              entries,
+	})
+}
+
+// recordSettingsUpdatedEvent adds a settings_updated event listing what an update changed, if anything
+func recordSettingsUpdatedEvent(db *gorm.DB, previous, updated *database.PluginInstance) {
+	details := map[string]interface{}{}
+	if previous.Name != updated.Name {
+		details["name"] = updated.Name
+	}
+	if previous.RefreshInterval != updated.RefreshInterval {
+		details["refresh_interval"] = updated.RefreshInterval
+	}
+	if keys := changedSettingKeys(previous.Settings, updated.Settings); len(keys) > 0 {
+		details["changed_settings"] = keys
+	}
+	if len(details) == 0 {
+		return
+	}
+	database.RecordPluginInstanceEvent(db, updated.ID, database.PluginInstanceEventSettingsUpdated, "Instance settings updated", details)
+}
+
+// changedSettingKeys lists the setting keys added, removed or changed between two settings objects.
+// Values are not included since settings may hold credentials.
+func changedSettingKeys(before, after datatypes.JSON) []string {
+	var oldSettings, newSettings map[string]interface{}
+	if len(before) > 0 {
+		json.Unmarshal(before, &oldSettings)
+	}
+	if len(after) > 0 {
+		json.Unmarshal(after, &newSettings)
+	}
+
+	var keys []string
+	for key, value := range newSettings {
+		if oldValue, ok := oldSettings[key]; !ok || !reflect.DeepEqual(oldValue, value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range oldSettings {
+		if _, ok := newSettings[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// pollStaleIntervals is how many refresh intervals may pass without a successful poll before data is stale
+const pollStaleIntervals = 2
+
+// PollStatusEntry is the polling health of one plugin instance
+type PollStatusEntry struct {
+	InstanceID      string     `json:"instance_id"`
+	InstanceName    string     `json:"instance_name"`
+	PluginName      string     `json:"plugin_name"`
+	RefreshInterval int        `json:"refresh_interval"`
+	Status          string     `json:"status"` // ok, stale, failing or never_polled
+	LastPolledAt    *time.Time `json:"last_polled_at"`
+	LastSuccessAt   *time.Time `json:"last_success_at"`
+	LastPollSuccess bool       `json:"last_poll_success"`
+	LastErrors      []string   `json:"last_errors,omitempty"`
+	AgeSeconds      *int64     `json:"age_seconds"`     // Since the last successful poll
+	StalenessRatio  *float64   `json:"staleness_ratio"` // Age as a multiple of the refresh interval
+}
+
+// GetPollStatusHandler reports how fresh the polled data of each of the user's polling instances is,
+// stalest first, so an upstream API breaking several plugins at once is easy to spot
+// GET /api/plugin-instances/poll-status
+func GetPollStatusHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	db := database.GetDB()
+	var instances []database.PluginInstance
+	err := db.Preload("PluginDefinition").
+		Joins("JOIN plugin_definitions ON plugin_definitions.id = plugin_instances.plugin_definition_id").
+		Where("plugin_instances.user_id = ? AND plugin_instances.is_active = ? AND plugin_definitions.data_strategy = ?", user.ID, true, "polling").
+		Find(&instances).Error
+	if err != nil {
+		logging.Error("[POLL_STATUS] Failed to get polling instances", "user_id", user.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get poll status"})
+		return
+	}
+
+	instanceIDs := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		instanceIDs = append(instanceIDs, instance.ID.String())
+	}
+	var pollRows []database.PrivatePluginPollingData
+	if len(instanceIDs) > 0 {
+		if err := db.Select("plugin_instance_id", "polled_at", "success", "errors", "last_success_at").
+			Where("plugin_instance_id IN ?", instanceIDs).Find(&pollRows).Error; err != nil {
+			logging.Error("[POLL_STATUS] Failed to get polling data", "user_id", user.ID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get poll status"})
+			return
+		}
+	}
+	polls := make(map[string]database.PrivatePluginPollingData, len(pollRows))
+	for _, row := range pollRows {
+		polls[row.PluginInstanceID] = row
+	}
+
+	now := time.Now().UTC()
+	summary := map[string]int{"ok": 0, "stale": 0, "failing": 0, "never_polled": 0}
+	entries := make([]PollStatusEntry, 0, len(instances))
+	for _, instance := range instances {
+		entry := PollStatusEntry{
+			InstanceID:      instance.ID.String(),
+			InstanceName:    instance.Name,
+			PluginName:      instance.PluginDefinition.Name,
+			RefreshInterval: instance.RefreshInterval,
+			Status:          "never_polled",
+		}
+
+		if poll, found := polls[entry.InstanceID]; found {
+			polledAt := poll.PolledAt
+			entry.LastPolledAt = &polledAt
+			entry.LastPollSuccess = poll.Success
+			entry.LastSuccessAt = poll.LastSuccessAt
+			if entry.LastSuccessAt == nil && poll.Success {
+				// Rows written before last success was tracked
+				entry.LastSuccessAt = &polledAt
+			}
+			if len(poll.Errors) > 0 {
+				json.Unmarshal(poll.Errors, &entry.LastErrors)
+			}
+
+			if entry.LastSuccessAt != nil {
+				age := int64(now.Sub(*entry.LastSuccessAt).Seconds())
+				entry.AgeSeconds = &age
+				if instance.RefreshInterval > 0 {
+					ratio := float64(age) / float64(instance.RefreshInterval)
+					entry.StalenessRatio = &ratio
+				}
+			}
+
+			switch {
+			case !poll.Success:
+				entry.Status = "failing"
+			case entry.StalenessRatio != nil && *entry.StalenessRatio > pollStaleIntervals:
+				entry.Status = "stale"
+			default:
+				entry.Status = "ok"
+			}
+		}
+
+		summary[entry.Status]++
+		entries = append(entries, entry)
+	}
+
+	// Failing and stale instances first, then by how far behind they are
+	statusOrder := map[string]int{"failing": 0, "stale": 1, "never_polled": 2, "ok": 3}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if statusOrder[entries[i].Status] != statusOrder[entries[j].Status] {
+			return statusOrder[entries[i].Status] < statusOrder[entries[j].Status]
+		}
+		return stalenessOf(entries[i]) > stalenessOf(entries[j])
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"instances": entries,
+		"summary":   summary,
+	})
+}
+
+func stalenessOf(entry PollStatusEntry) float64 {
+	if entry.StalenessRatio == nil {
+		return 0
+	}
+	return *entry.StalenessRatio
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/plugins/external"
+)
+
+// MissingSetting describes a required config-schema field that has no value on a plugin instance
+type MissingSetting struct {
+	Key   string `json:"key"`
+	Title string `json:"title,omitempty"`
+}
+
+// PreflightResult reports whether a plugin instance is configured well enough to render
+type PreflightResult struct {
+	Ready           bool             `json:"ready"`
+	MissingSettings []MissingSetting `json:"missing_settings"`
+}
+
+// preflightPluginInstance checks the instance settings against the required fields of its config schema.
+// The instance must have its PluginDefinition loaded.
+func preflightPluginInstance(instance *database.PluginInstance) (PreflightResult, error) {
+	result := PreflightResult{Ready: true, MissingSettings: []MissingSetting{}}
+
+	schemaJSON := instance.PluginDefinition.ConfigSchema
+	if instance.PluginDefinition.PluginType == "external" {
+		schemaJSON = external.NewExternalPlugin(&instance.PluginDefinition, instance).ConfigSchema()
+	}
+	if strings.TrimSpace(schemaJSON) == "" {
+		return result, nil
+	}
+
+	var schema struct {
+		Properties map[string]struct {
+			Title   string      `json:"title"`
+			Default interface{} `json:"default"`
+		} `json:"properties"`
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		return result, fmt.Errorf("failed to parse config schema: %w", err)
+	}
+
+	settings := map[string]interface{}{}
+	if len(instance.Settings) > 0 {
+		if err := json.Unmarshal(instance.Settings, &settings); err != nil {
+			return result, fmt.Errorf("failed to parse instance settings: %w", err)
+		}
+	}
+
+	for _, key := range schema.Required {
+		property := schema.Properties[key]
+		if !isEmptySettingValue(settings[key]) || !isEmptySettingValue(property.Default) {
+			continue
+		}
+		result.MissingSettings = append(result.MissingSettings, MissingSetting{Key: key, Title: property.Title})
+	}
+
+	result.Ready = len(result.MissingSettings) == 0
+	return result, nil
+}
+
+// isEmptySettingValue reports whether a setting value should be treated as not provided
+func isEmptySettingValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// GetPluginInstancePreflightHandler reports whether all required settings of a plugin instance are filled in
+func GetPluginInstancePreflightHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	instanceID := c.Param("id")
+	if instanceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Instance ID is required"})
+		return
+	}
+
+	db := database.GetDB()
+	var pluginInstance database.PluginInstance
+	err := db.Preload("PluginDefinition").Where("id = ? AND user_id = ?", instanceID, user.ID).First(&pluginInstance).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plugin instance not found"})
+		return
+	}
+
+	result, err := preflightPluginInstance(&pluginInstance)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check plugin instance settings", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
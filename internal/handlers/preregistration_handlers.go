@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"gorm.io/gorm"
+)
+
+// maxPreregistrationBatch caps how many devices one request can pre-register
+const maxPreregistrationBatch = 1000
+
+// GetPreregistrationsHandler lists pre-registered devices; claimed entries are included with ?include_claimed=true (admin only)
+func GetPreregistrationsHandler(c *gin.Context) {
+	preregistrations, err := database.NewPreregistrationService(database.GetDB()).GetPreregistrations(c.Query("include_claimed") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pre-registered devices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"preregistrations": preregistrations})
+}
+
+// CreatePreregistrationsHandler pre-registers MAC addresses to users so the devices are claimed for
+// them on first setup. Each entry is validated on its own and reported in the results (admin only).
+func CreatePreregistrationsHandler(c *gin.Context) {
+	admin, ok := auth.RequireAdmin(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Devices []struct {
+			MacAddress string    `json:"mac_address" binding:"required"`
+			UserID     uuid.UUID `json:"user_id" binding:"required"`
+			Name       string    `json:"name"`
+		} `json:"devices" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Devices) > maxPreregistrationBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many devices", "max_devices": maxPreregistrationBatch})
+		return
+	}
+
+	preregistrationService := database.NewPreregistrationService(database.GetDB())
+	results := make([]gin.H, 0, len(req.Devices))
+	created := 0
+	for _, entry := range req.Devices {
+		result := gin.H{"mac_address": entry.MacAddress, "user_id": entry.UserID}
+
+		preregistration, err := preregistrationService.CreatePreregistration(admin.ID, entry.UserID, entry.MacAddress, entry.Name)
+		switch {
+		case err == nil:
+			created++
+			result["status"] = "created"
+			result["preregistration"] = preregistration
+		case errors.Is(err, database.ErrInvalidMACAddress):
+			result["status"] = "invalid"
+			result["error"] = "Invalid MAC address"
+		case errors.Is(err, database.ErrPreregistrationUser):
+			result["status"] = "invalid"
+			result["error"] = "User not found or inactive"
+		case errors.Is(err, database.ErrMACAlreadyPreregistered):
+			result["status"] = "conflict"
+			result["error"] = "MAC address is already pre-registered"
+		case errors.Is(err, database.ErrDeviceAlreadyRegistered):
+			result["status"] = "conflict"
+			result["error"] = "A device with this MAC address has already connected; claim or reassign it instead"
+		default:
+			logging.Error("[PREREGISTER] Failed to pre-register device", "mac_address", entry.MacAddress, "error", err)
+			result["status"] = "error"
+			result["error"] = "Failed to pre-register device"
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"created": created,
+		"results": results,
+	})
+}
+
+// DeletePreregistrationHandler removes a pre-registration (admin only)
+func DeletePreregistrationHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pre-registration ID"})
+		return
+	}
+
+	if err := database.NewPreregistrationService(database.GetDB()).DeletePreregistration(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pre-registration not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pre-registration"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pre-registration deleted"})
+}
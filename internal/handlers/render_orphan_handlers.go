@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/rendering"
+)
+
+// newOrphanRenderWorker creates a render worker for inspecting the rendered directory
+func newOrphanRenderWorker(c *gin.Context) (*rendering.RenderWorker, bool) {
+	worker, err := rendering.NewRenderWorker(database.GetDB(), config.Get("STATIC_DIR", "./static"))
+	if err != nil {
+		logging.Error("[ORPHANED_FILES] Failed to create render worker", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to access rendered directory"})
+		return nil, false
+	}
+	return worker, true
+}
+
+// GetOrphanedRenderedFilesHandler lists rendered files with no RenderedContent record (admin only)
+func GetOrphanedRenderedFilesHandler(c *gin.Context) {
+	worker, ok := newOrphanRenderWorker(c)
+	if !ok {
+		return
+	}
+
+	files, err := worker.FindOrphanedFiles(c.Request.Context())
+	if err != nil {
+		logging.Error("[ORPHANED_FILES] Failed to find orphaned files", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list orphaned files"})
+		return
+	}
+
+	var totalBytes int64
+	for _, file := range files {
+		totalBytes += file.SizeBytes
+	}
+	if files == nil {
+		files = []rendering.OrphanedFile{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"files":       files,
+		"count":       len(files),
+		"total_bytes": totalBytes,
+	})
+}
+
+// DeleteOrphanedRenderedFilesHandler deletes rendered files with no RenderedContent record (admin only).
+// Orphans are re-detected at deletion time, so files that gained a record since listing are kept.
+func DeleteOrphanedRenderedFilesHandler(c *gin.Context) {
+	worker, ok := newOrphanRenderWorker(c)
+	if !ok {
+		return
+	}
+
+	deleted, reclaimedBytes, err := worker.DeleteOrphanedFiles(c.Request.Context())
+	if err != nil {
+		logging.Error("[ORPHANED_FILES] Failed to delete orphaned files", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete orphaned files"})
+		return
+	}
+
+	logging.Info("[ORPHANED_FILES] Deleted orphaned files", "count", deleted, "bytes", reclaimedBytes)
+
+	c.JSON(http.StatusOK, gin.H{
+		"deleted":         deleted,
+		"reclaimed_bytes": reclaimedBytes,
+	})
+}
+
+// defaultCleanupMaxAge is how old rendered content must be before on-demand cleanup removes it outright
+const defaultCleanupMaxAge = 30 * 24 * time.Hour
+
+// RunRenderCleanupHandler runs smart retention cleanup, old-content cleanup and orphaned-file cleanup
+// immediately instead of waiting for the scheduled pass (admin only). The optional max_age_hours
+// query parameter sets the old-content cutoff; 0 skips that step.
+// POST /api/admin/render/cleanup
+func RunRenderCleanupHandler(c *gin.Context) {
+	maxAge := defaultCleanupMaxAge
+	if value := c.Query("max_age_hours"); value != "" {
+		hours, err := strconv.Atoi(value)
+		if err != nil || hours < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "max_age_hours must be zero or a positive number of hours"})
+			return
+		}
+		maxAge = time.Duration(hours) * time.Hour
+	}
+
+	worker, ok := newOrphanRenderWorker(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	smart, err := worker.CleanupOldContentSmart(ctx)
+	if err != nil {
+		logging.Error("[RENDER_CLEANUP] Smart cleanup failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Smart cleanup failed", "details": err.Error()})
+		return
+	}
+
+	var oldContent rendering.CleanupStats
+	if maxAge > 0 {
+		oldContent, err = worker.CleanupOldContent(ctx, maxAge)
+		if err != nil {
+			logging.Error("[RENDER_CLEANUP] Old content cleanup failed", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Old content cleanup failed", "details": err.Error()})
+			return
+		}
+	}
+
+	orphanedFiles, orphanedBytes, err := worker.DeleteOrphanedFiles(ctx)
+	if err != nil {
+		logging.Error("[RENDER_CLEANUP] Orphaned file cleanup failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Orphaned file cleanup failed", "details": err.Error()})
+		return
+	}
+	orphaned := rendering.CleanupStats{FilesRemoved: orphanedFiles, BytesFreed: orphanedBytes}
+
+	var total rendering.CleanupStats
+	total.Add(smart)
+	total.Add(oldContent)
+	total.Add(orphaned)
+
+	logging.Info("[RENDER_CLEANUP] On-demand cleanup completed",
+		"records_removed", total.RecordsRemoved, "files_removed", total.FilesRemoved, "bytes_freed", total.BytesFreed)
+
+	c.JSON(http.StatusOK, gin.H{
+		"smart":          smart,
+		"old_content":    oldContent,
+		"orphaned_files": orphaned,
+		"total":          total,
+		"max_age_hours":  int(maxAge.Hours()),
+	})
+}
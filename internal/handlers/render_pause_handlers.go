@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/rendering"
+)
+
+// renderPauseStatus returns the current pause state along with the number of jobs waiting in the queue
+func renderPauseStatus(c *gin.Context) {
+	var pendingJobs int64
+	if err := database.GetDB().Model(&database.RenderQueue{}).
+		Where("status = ?", "pending").
+		Count(&pendingJobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count pending render jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"paused":       rendering.IsRenderingPaused(),
+		"pending_jobs": pendingJobs,
+	})
+}
+
+// GetRenderPauseHandler reports whether background rendering is paused (admin only)
+func GetRenderPauseHandler(c *gin.Context) {
+	renderPauseStatus(c)
+}
+
+// UpdateRenderPauseHandler pauses or resumes background rendering (admin only).
+// Pending jobs are left in the queue while paused and processed once rendering resumes.
+func UpdateRenderPauseHandler(c *gin.Context) {
+	user, ok := auth.RequireAdmin(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Paused *bool `json:"paused" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "paused must be true or false"})
+		return
+	}
+
+	if err := rendering.SetRenderingPaused(*req.Paused, &user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update render pause state"})
+		return
+	}
+
+	logging.Info("[RENDER_PAUSE] Render pause state changed", "paused", *req.Paused, "username", user.Username)
+
+	renderPauseStatus(c)
+}
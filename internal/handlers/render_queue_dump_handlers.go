@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+const (
+	defaultQueueDumpLimit = 100
+	maxQueueDumpLimit     = 500
+)
+
+// renderQueueDumpEntry is one non-terminal render job as shown in the queue dump
+type renderQueueDumpEntry struct {
+	ID                uuid.UUID  `json:"id"`
+	PluginInstanceID  *uuid.UUID `json:"plugin_instance_id,omitempty"`
+	InstanceName      string     `json:"instance_name,omitempty"`
+	NeedsConfigUpdate bool       `json:"needs_config_update"` // The worker skips instances waiting on a config update
+	DeviceCount       int64      `json:"device_count" gorm:"-"`
+	QueuedJobs        int64      `json:"queued_jobs" gorm:"-"` // Non-terminal jobs for the same instance; more than one means duplicates
+	Priority          int        `json:"priority"`
+	ScheduledFor      time.Time  `json:"scheduled_for"`
+	Due               bool       `json:"due" gorm:"-"`
+	Attempts          int        `json:"attempts"`
+	LastAttempt       *time.Time `json:"last_attempt,omitempty"`
+	Status            string     `json:"status"`
+	IsPreview         bool       `json:"is_preview"`
+	IndependentRender bool       `json:"independent_render"`
+	ErrorMessage      string     `json:"error_message,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// GetRenderQueueDumpHandler returns the pending and processing render jobs in the order the worker would
+// take them, with each job's instance name, device count and the number of queued jobs for its instance.
+// Paginated with ?limit= and ?offset= (admin only).
+func GetRenderQueueDumpHandler(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultQueueDumpLimit)))
+	if err != nil || limit <= 0 {
+		limit = defaultQueueDumpLimit
+	}
+	if limit > maxQueueDumpLimit {
+		limit = maxQueueDumpLimit
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	db := database.GetDB()
+	statuses := []string{"pending", "processing"}
+
+	var total int64
+	if err := db.Model(&database.RenderQueue{}).Where("status IN ?", statuses).Count(&total).Error; err != nil {
+		logging.Error("[RENDER_QUEUE] Failed to count queued jobs", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch render queue"})
+		return
+	}
+
+	// Jobs already being processed come first, then pending jobs in the worker's priority order
+	var jobs []renderQueueDumpEntry
+	if err := db.Raw(`
+		SELECT rq.id, rq.plugin_instance_id, pi.name AS instance_name,
+			COALESCE(pi.needs_config_update, false) AS needs_config_update,
+			rq.priority, rq.scheduled_for, rq.attempts, rq.last_attempt, rq.status,
+			rq.is_preview, rq.independent_render, rq.error_message, rq.created_at
+		FROM render_queues rq
+		LEFT JOIN plugin_instances pi ON pi.id = rq.plugin_instance_id
+		WHERE rq.status IN ?
+		ORDER BY CASE WHEN rq.status = ? THEN 0 ELSE 1 END, rq.priority DESC, rq.scheduled_for ASC, rq.id
+		LIMIT ? OFFSET ?
+	`, statuses, "processing", limit, offset).Scan(&jobs).Error; err != nil {
+		logging.Error("[RENDER_QUEUE] Failed to fetch queued jobs", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch render queue"})
+		return
+	}
+
+	var instanceIDs []uuid.UUID
+	for _, job := range jobs {
+		if job.PluginInstanceID != nil {
+			instanceIDs = append(instanceIDs, *job.PluginInstanceID)
+		}
+	}
+
+	type instanceCount struct {
+		PluginInstanceID uuid.UUID
+		Count            int64
+	}
+	deviceCounts := make(map[uuid.UUID]int64)
+	queuedJobs := make(map[uuid.UUID]int64)
+	if len(instanceIDs) > 0 {
+		var rows []instanceCount
+		if err := db.Raw(`
+			SELECT plugin_instance_id, COUNT(DISTINCT device_id) AS count FROM (
+				SELECT playlist_items.plugin_instance_id AS plugin_instance_id, playlists.device_id AS device_id
+				FROM playlist_items
+				JOIN playlists ON playlists.id = playlist_items.playlist_id
+				WHERE playlist_items.plugin_instance_id IN ?
+				UNION
+				SELECT mashup_children.child_instance_id AS plugin_instance_id, playlists.device_id AS device_id
+				FROM mashup_children
+				JOIN playlist_items ON playlist_items.plugin_instance_id = mashup_children.mashup_instance_id
+				JOIN playlists ON playlists.id = playlist_items.playlist_id
+				WHERE mashup_children.child_instance_id IN ?
+			) instance_devices
+			GROUP BY plugin_instance_id
+		`, instanceIDs, instanceIDs).Scan(&rows).Error; err != nil {
+			logging.Warn("[RENDER_QUEUE] Failed to count devices for queued jobs", "error", err)
+		}
+		for _, row := range rows {
+			deviceCounts[row.PluginInstanceID] = row.Count
+		}
+
+		rows = nil
+		if err := db.Model(&database.RenderQueue{}).
+			Select("plugin_instance_id, COUNT(*) AS count").
+			Where("plugin_instance_id IN ? AND status IN ?", instanceIDs, statuses).
+			Group("plugin_instance_id").
+			Scan(&rows).Error; err != nil {
+			logging.Warn("[RENDER_QUEUE] Failed to count queued jobs per instance", "error", err)
+		}
+		for _, row := range rows {
+			queuedJobs[row.PluginInstanceID] = row.Count
+		}
+	}
+
+	now := time.Now().UTC()
+	for i := range jobs {
+		jobs[i].Due = !jobs[i].ScheduledFor.After(now)
+		if id := jobs[i].PluginInstanceID; id != nil {
+			jobs[i].DeviceCount = deviceCounts[*id]
+			jobs[i].QueuedJobs = queuedJobs[*id]
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":        jobs,
+		"total_count": total,
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
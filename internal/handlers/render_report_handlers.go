@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+const (
+	defaultRenderReportRange = 7 * 24 * time.Hour
+	maxRenderReportRange     = 90 * 24 * time.Hour
+)
+
+// renderReportDay aggregates render activity for a single UTC day
+type renderReportDay struct {
+	jobs            int
+	failures        int
+	durationTotalMs int64
+	durationCount   int
+	images          int
+	plugins         map[uuid.UUID]struct{}
+	devices         map[uuid.UUID]struct{}
+}
+
+// GetRenderReportCSVHandler streams a per-day CSV of render activity (admin only).
+// Jobs, failures and durations come from the render queue; images and devices from rendered content.
+// Both tables are pruned over time, so the report only covers history that is still retained.
+func GetRenderReportCSVHandler(c *gin.Context) {
+	reportRange := defaultRenderReportRange
+	if rangeStr := c.Query("range"); rangeStr != "" {
+		parsed, err := config.ParseDuration(rangeStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid range. Use a duration like 7d or 48h"})
+			return
+		}
+		if parsed > maxRenderReportRange {
+			parsed = maxRenderReportRange
+		}
+		reportRange = parsed
+	}
+
+	now := time.Now().UTC()
+	since := now.Add(-reportRange)
+	firstDay := since.Truncate(24 * time.Hour)
+
+	db := database.GetDB()
+
+	var jobs []database.RenderQueue
+	if err := db.Select("plugin_instance_id", "status", "render_duration_ms", "updated_at").
+		Where("is_preview = ? AND status IN ? AND updated_at >= ?", false, []string{"completed", "failed"}, since).
+		Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load render jobs"})
+		return
+	}
+
+	var contents []database.RenderedContent
+	if err := db.Select("plugin_instance_id", "device_id", "rendered_at").
+		Where("rendered_at >= ?", since).
+		Find(&contents).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load rendered content"})
+		return
+	}
+
+	days := make(map[string]*renderReportDay)
+	dayFor := func(t time.Time) *renderReportDay {
+		key := t.UTC().Format("2006-01-02")
+		day, ok := days[key]
+		if !ok {
+			day = &renderReportDay{
+				plugins: make(map[uuid.UUID]struct{}),
+				devices: make(map[uuid.UUID]struct{}),
+			}
+			days[key] = day
+		}
+		return day
+	}
+
+	for _, job := range jobs {
+		day := dayFor(job.UpdatedAt)
+		day.jobs++
+		if job.Status == "failed" {
+			day.failures++
+		} else if job.RenderDurationMs > 0 {
+			day.durationTotalMs += int64(job.RenderDurationMs)
+			day.durationCount++
+		}
+		if job.PluginInstanceID != nil {
+			day.plugins[*job.PluginInstanceID] = struct{}{}
+		}
+	}
+
+	for _, content := range contents {
+		day := dayFor(content.RenderedAt)
+		day.images++
+		day.plugins[content.PluginInstanceID] = struct{}{}
+		if content.DeviceID != nil {
+			day.devices[*content.DeviceID] = struct{}{}
+		}
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=render-report-%s.csv", now.Format("20060102")))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"date", "render_jobs", "failures", "avg_duration_ms", "rendered_images", "unique_plugins", "unique_devices"})
+
+	// Emit every day in the range, including days without activity
+	for d := firstDay; !d.After(now); d = d.Add(24 * time.Hour) {
+		key := d.Format("2006-01-02")
+		row := []string{key, "0", "0", "0", "0", "0", "0"}
+
+		if day, ok := days[key]; ok {
+			avgDuration := int64(0)
+			if day.durationCount > 0 {
+				avgDuration = day.durationTotalMs / int64(day.durationCount)
+			}
+
+			row = []string{
+				key,
+				strconv.Itoa(day.jobs),
+				strconv.Itoa(day.failures),
+				strconv.FormatInt(avgDuration, 10),
+				strconv.Itoa(day.images),
+				strconv.Itoa(len(day.plugins)),
+				strconv.Itoa(len(day.devices)),
+			}
+		}
+
+		if err := writer.Write(row); err != nil {
+			logging.Error("[RENDER_REPORT] Failed to write CSV row", "error", err)
+			return
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		logging.Error("[RENDER_REPORT] Failed to flush CSV", "error", err)
+	}
+}
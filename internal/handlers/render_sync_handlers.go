@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/rendering"
+)
+
+const (
+	defaultSyncRenderTimeout = 60 * time.Second
+	maxSyncRenderTimeout     = 120 * time.Second
+)
+
+// RenderPluginInstanceSyncHandler renders a plugin instance for one of the user's devices inline
+// and responds with the resulting PNG instead of scheduling a background render job
+func RenderPluginInstanceSyncHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		DeviceID       string `json:"device_id" binding:"required"`
+		TimeoutSeconds int    `json:"timeout_seconds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "device_id is required"})
+		return
+	}
+
+	deviceID, err := uuid.Parse(req.DeviceID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	timeout := defaultSyncRenderTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+		if timeout > maxSyncRenderTimeout {
+			timeout = maxSyncRenderTimeout
+		}
+	}
+
+	db := database.GetDB()
+
+	var pluginInstance database.PluginInstance
+	err = db.Preload("User").Preload("PluginDefinition").
+		Where("id = ? AND user_id = ?", c.Param("id"), user.ID).
+		First(&pluginInstance).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plugin instance not found"})
+		return
+	}
+
+	device, err := database.NewDeviceService(db).GetDeviceByID(deviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+	if device.UserID == nil || *device.UserID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+	if device.DeviceModel == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Device has no device model"})
+		return
+	}
+	if !pluginInstance.RendersForDevice(device.ID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Plugin instance is limited to rendering for other devices"})
+		return
+	}
+
+	worker, err := rendering.NewRenderWorker(db, config.Get("STATIC_DIR", "./static"))
+	if err != nil {
+		logging.Error("[RENDER_SYNC] Failed to create render worker", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize renderer"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	content, skipDisplay, err := worker.RenderForDevice(ctx, pluginInstance, *device)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Render did not finish in time", "timeout_seconds": int(timeout.Seconds())})
+			return
+		}
+		logging.Warn("[RENDER_SYNC] Render failed", "plugin_instance_id", pluginInstance.ID, "device_id", device.ID, "error", err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Render failed", "details": err.Error()})
+		return
+	}
+
+	imageData, err := os.ReadFile(content.ImagePath)
+	if err != nil {
+		logging.Error("[RENDER_SYNC] Failed to read rendered image", "path", content.ImagePath, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read rendered image"})
+		return
+	}
+
+	logging.Info("[RENDER_SYNC] Rendered plugin instance", "plugin_instance_id", pluginInstance.ID, "device_id", device.ID, "duration", time.Since(startTime))
+
+	c.Header("X-Render-Duration-Ms", strconv.FormatInt(time.Since(startTime).Milliseconds(), 10))
+	c.Header("X-Skip-Display", strconv.FormatBool(skipDisplay))
+	c.Data(http.StatusOK, "image/png", imageData)
+}
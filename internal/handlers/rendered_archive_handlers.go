@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/rendering"
+)
+
+const (
+	defaultRenderedArchiveLimit = 50
+	maxRenderedArchiveLimit     = 200
+	maxRenderedArchiveBytes     = 100 * 1024 * 1024 // Stop adding images once the archive would exceed this
+)
+
+// renderedArchiveEntry describes one image in a rendered content archive manifest
+type renderedArchiveEntry struct {
+	File             string    `json:"file"`
+	PluginInstanceID uuid.UUID `json:"plugin_instance_id"`
+	PluginName       string    `json:"plugin_name"`
+	RenderedAt       time.Time `json:"rendered_at"`
+	Width            int       `json:"width"`
+	Height           int       `json:"height"`
+	BitDepth         int       `json:"bit_depth"`
+	FileSize         int64     `json:"file_size"`
+}
+
+// renderedArchiveManifest is written to manifest.json at the root of a rendered content archive
+type renderedArchiveManifest struct {
+	DeviceID    uuid.UUID              `json:"device_id"`
+	DeviceName  string                 `json:"device_name"`
+	GeneratedAt time.Time              `json:"generated_at"`
+	Files       []renderedArchiveEntry `json:"files"`
+	Skipped     int                    `json:"skipped"`   // Records without a readable local image
+	Truncated   bool                   `json:"truncated"` // True when the size cap was reached
+}
+
+// resolveRenderedImagePath returns the local file for a rendered image path, or false if the
+// path is a URL reference or points outside the rendered directories
+func resolveRenderedImagePath(renderedDir, imagePath string) (string, bool) {
+	if imagePath == "" || strings.HasPrefix(imagePath, "http://") || strings.HasPrefix(imagePath, "https://") {
+		return "", false
+	}
+
+	absPath, err := filepath.Abs(imagePath)
+	if err != nil {
+		return "", false
+	}
+	for _, dir := range rendering.RenderedDirs(renderedDir) {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(absDir, absPath)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return absPath, true
+		}
+	}
+	return "", false
+}
+
+// GetDeviceRenderedArchiveHandler streams a zip of the device's most recently rendered images
+// together with a manifest mapping each file to its plugin and render time
+func GetDeviceRenderedArchiveHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+		return
+	}
+
+	limit := defaultRenderedArchiveLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		limit = min(parsed, maxRenderedArchiveLimit)
+	}
+
+	db := database.GetDB()
+	device, err := database.NewDeviceService(db).GetDeviceByID(deviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return
+	}
+	if device.UserID == nil || *device.UserID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	var contents []database.RenderedContent
+	if err := db.Preload("PluginInstance").
+		Where("device_id = ?", deviceID).
+		Order("rendered_at DESC").
+		Limit(limit).
+		Find(&contents).Error; err != nil {
+		logging.Error("[RENDERED_ARCHIVE] Failed to load rendered content", "device_id", deviceID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load rendered content"})
+		return
+	}
+
+	renderedDir := filepath.Join(config.Get("STATIC_DIR", "./static"), "rendered")
+	manifest := renderedArchiveManifest{
+		DeviceID:    device.ID,
+		DeviceName:  device.Name,
+		GeneratedAt: time.Now().UTC(),
+		Files:       []renderedArchiveEntry{},
+	}
+
+	archiveName := fmt.Sprintf("rendered_%s_%s.zip", device.FriendlyID, manifest.GeneratedAt.Format("20060102_150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", archiveName))
+
+	zipWriter := zip.NewWriter(c.Writer)
+	var totalBytes int64
+	for _, content := range contents {
+		path, ok := resolveRenderedImagePath(renderedDir, content.ImagePath)
+		if !ok {
+			manifest.Skipped++
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			manifest.Skipped++
+			continue
+		}
+		if totalBytes+info.Size() > maxRenderedArchiveBytes {
+			manifest.Truncated = true
+			break
+		}
+
+		name := fmt.Sprintf("%03d_%s.png", len(manifest.Files)+1, content.RenderedAt.UTC().Format("20060102_150405"))
+		if err := addFileToZip(zipWriter, name, path); err != nil {
+			logging.Warn("[RENDERED_ARCHIVE] Failed to add image to archive", "path", path, "error", err)
+			manifest.Skipped++
+			continue
+		}
+		totalBytes += info.Size()
+
+		manifest.Files = append(manifest.Files, renderedArchiveEntry{
+			File:             name,
+			PluginInstanceID: content.PluginInstanceID,
+			PluginName:       content.PluginInstance.Name,
+			RenderedAt:       content.RenderedAt,
+			Width:            content.Width,
+			Height:           content.Height,
+			BitDepth:         content.BitDepth,
+			FileSize:         info.Size(),
+		})
+	}
+
+	manifestWriter, err := zipWriter.Create("manifest.json")
+	if err == nil {
+		encoder := json.NewEncoder(manifestWriter)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(manifest)
+	}
+	if err == nil {
+		err = zipWriter.Close()
+	}
+	if err != nil {
+		// Headers are already sent, so the client sees a truncated archive
+		logging.Error("[RENDERED_ARCHIVE] Failed to write archive", "device_id", deviceID, "error", err)
+		return
+	}
+
+	logging.Info("[RENDERED_ARCHIVE] Served rendered content archive", "device_id", deviceID, "files", len(manifest.Files), "skipped", manifest.Skipped, "truncated", manifest.Truncated)
+}
+
+// addFileToZip copies a file on disk into the archive under the given name
+func addFileToZip(zipWriter *zip.Writer, name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, file)
+	return err
+}
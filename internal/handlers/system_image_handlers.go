@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/trmnl"
+)
+
+// GetSystemImageHandler redirects to the image used for a built-in system screen
+// (low_battery, sleep, timeout_error, generic_error, empty_state).
+// An optional device_id query parameter selects the variant served to that device's model.
+func GetSystemImageHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	var device *database.Device
+	if deviceIDStr := c.Query("device_id"); deviceIDStr != "" {
+		deviceID, err := uuid.Parse(deviceIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+			return
+		}
+
+		deviceService := database.NewDeviceService(database.GetDB())
+		device, err = deviceService.GetDeviceByID(deviceID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+			return
+		}
+
+		// Verify ownership
+		if device.UserID == nil || *device.UserID != user.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+	}
+
+	imageURL, ok := trmnl.SystemImageURL(c.Param("name"), device)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown system image"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, imageURL)
+}
@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+const (
+	// MaxAccountExportSize caps the size of an uploaded TRMNL account export
+	MaxAccountExportSize = 50 * 1024 * 1024 // 50MB
+	// MaxAccountExportUncompressedSize caps the bytes read from an account export, nested ZIPs included
+	MaxAccountExportUncompressedSize = 100 * 1024 * 1024 // 100MB
+	// MaxAccountExportPlugins caps how many plugin folders and archives one account export may hold
+	MaxAccountExportPlugins = 500
+)
+
+// errAccountExportTooLarge aborts an import whose entries expand past MaxAccountExportUncompressedSize
+var errAccountExportTooLarge = fmt.Errorf("account export expands to more than %d bytes", MaxAccountExportUncompressedSize)
+
+// accountExportBudget tracks the bytes left to read from an account export, so highly compressed
+// entries can't expand past MaxAccountExportUncompressedSize in total
+type accountExportBudget struct {
+	remaining int64
+}
+
+// read reads a ZIP entry, failing once the export's total uncompressed size is exceeded
+func (b *accountExportBudget) read(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer rc.Close()
+
+	limit := b.remaining
+	if limit > MaxTotalZipSize {
+		limit = MaxTotalZipSize
+	}
+	content, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	b.remaining -= int64(len(content))
+	if b.remaining < 0 {
+		return nil, errAccountExportTooLarge
+	}
+	return content, nil
+}
+
+// TRMNLAccountExportPlugin is one plugin found in an account export, keyed by the folder or archive it came from
+type TRMNLAccountExportPlugin struct {
+	Source string
+	Data   *ZipExportData
+}
+
+// TRMNLImportedPlugin describes a plugin definition and instance created from an account export
+type TRMNLImportedPlugin struct {
+	Source       string    `json:"source"`
+	DefinitionID string    `json:"definition_id"`
+	InstanceID   uuid.UUID `json:"instance_id"`
+	Name         string    `json:"name"`
+}
+
+// TRMNLUnsupportedItem is an entry of an account export that couldn't be imported
+type TRMNLUnsupportedItem struct {
+	Source string `json:"source"`
+	Reason string `json:"reason"`
+}
+
+// trmnlPluginFiles maps the files of a TRMNL plugin export to the fields they fill
+var trmnlPluginFiles = map[string]func(*ZipExportData, []byte){
+	"settings.yml":           func(d *ZipExportData, b []byte) { d.SettingsYAML = b },
+	"full.liquid":            func(d *ZipExportData, b []byte) { d.FullTemplate = string(b) },
+	"half_horizontal.liquid": func(d *ZipExportData, b []byte) { d.HalfHorizontal = string(b) },
+	"half_vertical.liquid":   func(d *ZipExportData, b []byte) { d.HalfVertical = string(b) },
+	"quadrant.liquid":        func(d *ZipExportData, b []byte) { d.QuadrantTemplate = string(b) },
+	"shared.liquid":          func(d *ZipExportData, b []byte) { d.SharedMarkup = string(b) },
+}
+
+// ExtractTRMNLAccountExport reads a TRMNL account export: a ZIP holding one folder per plugin, each laid
+// out like a single plugin export, and/or nested plugin export ZIPs. Files that don't belong to a plugin
+// are reported as unsupported rather than failing the whole import.
+func (s *TRMNLZipService) ExtractTRMNLAccountExport(data []byte) ([]TRMNLAccountExportPlugin, []TRMNLUnsupportedItem, error) {
+	if len(data) > MaxAccountExportSize {
+		return nil, nil, fmt.Errorf("account export too large: %d bytes (max %d bytes)", len(data), MaxAccountExportSize)
+	}
+
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read ZIP file: %w", err)
+	}
+
+	plugins := make(map[string]*ZipExportData)
+	var unsupported []TRMNLUnsupportedItem
+	budget := &accountExportBudget{remaining: MaxAccountExportUncompressedSize}
+	addPlugin := func(source string, pluginData *ZipExportData) error {
+		if len(plugins) >= MaxAccountExportPlugins {
+			return fmt.Errorf("account export has more than %d plugins", MaxAccountExportPlugins)
+		}
+		plugins[source] = pluginData
+		return nil
+	}
+
+	for _, f := range zipReader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(strings.ReplaceAll(f.Name, "\\", "/"))
+		base := strings.ToLower(path.Base(name))
+		if strings.HasPrefix(base, ".") || strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+
+		if f.UncompressedSize64 > MaxTotalZipSize {
+			unsupported = append(unsupported, TRMNLUnsupportedItem{Source: name, Reason: "file exceeds the 10MB plugin size limit"})
+			continue
+		}
+
+		content, err := budget.read(f)
+		if errors.Is(err, errAccountExportTooLarge) {
+			return nil, nil, err
+		}
+		if err != nil {
+			unsupported = append(unsupported, TRMNLUnsupportedItem{Source: name, Reason: err.Error()})
+			continue
+		}
+
+		if strings.HasSuffix(base, ".zip") {
+			pluginData, err := extractNestedPluginZip(content, budget)
+			if errors.Is(err, errAccountExportTooLarge) {
+				return nil, nil, err
+			}
+			if err != nil {
+				unsupported = append(unsupported, TRMNLUnsupportedItem{Source: name, Reason: err.Error()})
+				continue
+			}
+			if err := addPlugin(name, pluginData); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		assign, known := trmnlPluginFiles[base]
+		if !known {
+			unsupported = append(unsupported, TRMNLUnsupportedItem{Source: name, Reason: "not part of a plugin export"})
+			continue
+		}
+		if len(content) > MaxTemplateFileSize {
+			unsupported = append(unsupported, TRMNLUnsupportedItem{Source: name, Reason: "file exceeds 1MB limit"})
+			continue
+		}
+
+		folder := path.Dir(name)
+		if plugins[folder] == nil {
+			if err := addPlugin(folder, &ZipExportData{}); err != nil {
+				return nil, nil, err
+			}
+		}
+		assign(plugins[folder], content)
+	}
+
+	sources := make([]string, 0, len(plugins))
+	for source := range plugins {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var result []TRMNLAccountExportPlugin
+	for _, source := range sources {
+		pluginData := plugins[source]
+		if err := validatePluginExportData(pluginData); err != nil {
+			unsupported = append(unsupported, TRMNLUnsupportedItem{Source: source, Reason: err.Error()})
+			continue
+		}
+		result = append(result, TRMNLAccountExportPlugin{Source: source, Data: pluginData})
+	}
+
+	logging.Info("[TRMNL ACCOUNT IMPORT] Read account export", "plugins", len(result), "unsupported", len(unsupported))
+	return result, unsupported, nil
+}
+
+// extractNestedPluginZip reads a single plugin export stored inside an account export, counting what it
+// reads against the account export's budget
+func extractNestedPluginZip(data []byte, budget *accountExportBudget) (*ZipExportData, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nested ZIP file: %w", err)
+	}
+
+	pluginData := &ZipExportData{}
+	for _, f := range zipReader.File {
+		assign, known := trmnlPluginFiles[strings.ToLower(path.Base(f.Name))]
+		if !known || f.FileInfo().IsDir() {
+			continue
+		}
+		if f.UncompressedSize64 > MaxTemplateFileSize {
+			return nil, fmt.Errorf("file %s exceeds 1MB limit", f.Name)
+		}
+		content, err := budget.read(f)
+		if err != nil {
+			return nil, err
+		}
+		// The declared size can't be trusted
+		if len(content) > MaxTemplateFileSize {
+			return nil, fmt.Errorf("file %s exceeds 1MB limit", f.Name)
+		}
+		assign(pluginData, content)
+	}
+	return pluginData, nil
+}
+
+// validatePluginExportData applies the required-file rules of a single plugin import
+func validatePluginExportData(data *ZipExportData) error {
+	if len(data.SettingsYAML) == 0 {
+		return fmt.Errorf("settings.yml is required but not found")
+	}
+	if data.FullTemplate == "" && data.HalfHorizontal == "" && data.HalfVertical == "" && data.QuadrantTemplate == "" {
+		return fmt.Errorf("at least one template file is required (full.liquid, half_horizontal.liquid, half_vertical.liquid, or quadrant.liquid)")
+	}
+	return nil
+}
+
+// trmnlInstanceDefaults holds the parts of settings.yml used to configure the imported instance
+type trmnlInstanceDefaults struct {
+	RefreshInterval int              `yaml:"refresh_interval"`
+	CustomFields    []TRMNLFormField `yaml:"custom_fields"`
+}
+
+// instanceSettingsFromExport builds instance settings from the custom field defaults in settings.yml,
+// returning the refresh interval in seconds alongside them
+func instanceSettingsFromExport(settingsYAML []byte) (map[string]interface{}, int) {
+	settings := make(map[string]interface{})
+	refreshInterval := 3600
+
+	var defaults trmnlInstanceDefaults
+	if err := yaml.Unmarshal(settingsYAML, &defaults); err != nil {
+		return settings, refreshInterval
+	}
+	if defaults.RefreshInterval > 0 {
+		refreshInterval = defaults.RefreshInterval * 60
+	}
+	for _, field := range defaults.CustomFields {
+		key := field.Keyname
+		if key == "" {
+			key = field.ID
+		}
+		if key != "" && field.Default != nil {
+			settings[key] = field.Default
+		}
+	}
+	return settings, refreshInterval
+}
+
+// ImportTRMNLAccountExportHandler imports every plugin in a TRMNL account export as a private plugin
+// definition with one instance, reporting the entries that couldn't be mapped
+func ImportTRMNLAccountExportHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded", "details": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	if !strings.HasSuffix(strings.ToLower(header.Filename), ".zip") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account export", "details": "file must be a ZIP archive"})
+		return
+	}
+	if header.Size > MaxAccountExportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account export", "details": fmt.Sprintf("file exceeds %d bytes", MaxAccountExportSize)})
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxAccountExportSize+1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process uploaded file"})
+		return
+	}
+
+	zipService := NewTRMNLZipService()
+	plugins, unsupported, err := zipService.ExtractTRMNLAccountExport(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid account export", "details": err.Error()})
+		return
+	}
+
+	unifiedService := database.NewUnifiedPluginService(database.GetDB())
+	imported := []TRMNLImportedPlugin{}
+	var renderIDs []uuid.UUID
+
+	for _, plugin := range plugins {
+		def, err := zipService.ConvertZipDataToPluginDefinition(plugin.Data)
+		if err != nil {
+			unsupported = append(unsupported, TRMNLUnsupportedItem{Source: plugin.Source, Reason: err.Error()})
+			continue
+		}
+		def.OwnerID = &user.ID
+		def.Author = user.Username
+
+		if err := unifiedService.CreatePluginDefinition(def); err != nil {
+			logging.Error("[TRMNL ACCOUNT IMPORT] Failed to create plugin definition", "source", plugin.Source, "error", err)
+			unsupported = append(unsupported, TRMNLUnsupportedItem{Source: plugin.Source, Reason: "failed to create plugin definition"})
+			continue
+		}
+
+		settings, refreshInterval := instanceSettingsFromExport(plugin.Data.SettingsYAML)
+		instance, err := unifiedService.CreatePluginInstance(user.ID, def.ID, def.Name, settings, refreshInterval)
+		if err != nil {
+			logging.Error("[TRMNL ACCOUNT IMPORT] Failed to create plugin instance", "source", plugin.Source, "error", err)
+			reason := "plugin definition imported but its instance could not be created"
+			if errors.Is(err, database.ErrPluginInstanceNameTaken) {
+				reason = "plugin definition imported but an instance with its name already exists"
+			}
+			unsupported = append(unsupported, TRMNLUnsupportedItem{Source: plugin.Source, Reason: reason})
+			continue
+		}
+
+		imported = append(imported, TRMNLImportedPlugin{
+			Source:       plugin.Source,
+			DefinitionID: def.ID,
+			InstanceID:   instance.ID,
+			Name:         def.Name,
+		})
+		if def.RequiresProcessing {
+			renderIDs = append(renderIDs, instance.ID)
+		}
+	}
+
+	if len(renderIDs) > 0 {
+		ScheduleRenderForInstances(renderIDs)
+	}
+
+	logging.Info("[TRMNL ACCOUNT IMPORT] Imported account export",
+		"user_id", user.ID, "imported", len(imported), "unsupported", len(unsupported))
+
+	status := http.StatusCreated
+	if len(imported) == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	if unsupported == nil {
+		unsupported = []TRMNLUnsupportedItem{}
+	}
+	c.JSON(status, gin.H{
+		"imported":    imported,
+		"unsupported": unsupported,
+	})
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML issue kinds reported by the TRMNL settings schema check
+const (
+	YAMLIssueSyntax       = "syntax_error"
+	YAMLIssueUnknownKey   = "unknown_key"
+	YAMLIssueWrongType    = "wrong_type"
+	YAMLIssueMissingField = "missing_field"
+	YAMLIssueInvalidValue = "invalid_value"
+)
+
+// YAMLIssue is a schema violation in a TRMNL settings.yml, located by line and column
+type YAMLIssue struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Path     string `json:"path,omitempty"`
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"` // "error" or "warning"
+	Message  string `json:"message"`
+}
+
+// String formats the issue with its position for the plain errors and warnings lists
+func (i YAMLIssue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("line %d, column %d: %s", i.Line, i.Column, i.Message)
+}
+
+// yamlValueKind describes the node shapes a settings key accepts
+type yamlValueKind int
+
+const (
+	yamlScalar yamlValueKind = iota
+	yamlInt
+	yamlMapping
+	yamlMappingOrScalar
+	yamlSequenceOfMappings
+)
+
+func (k yamlValueKind) describe() string {
+	switch k {
+	case yamlInt:
+		return "an integer"
+	case yamlMapping:
+		return "a mapping"
+	case yamlMappingOrScalar:
+		return "a mapping or a string"
+	case yamlSequenceOfMappings:
+		return "a list of mappings"
+	default:
+		return "a string"
+	}
+}
+
+// trmnlSettingsSchema lists the settings.yml keys understood by TRMNLSettings
+var trmnlSettingsSchema = map[string]yamlValueKind{
+	"id":                yamlScalar,
+	"name":              yamlScalar,
+	"strategy":          yamlScalar,
+	"refresh_interval":  yamlInt,
+	"polling_url":       yamlScalar,
+	"polling_verb":      yamlScalar,
+	"polling_headers":   yamlMappingOrScalar,
+	"polling_body":      yamlScalar,
+	"dark_mode":         yamlScalar,
+	"no_screen_padding": yamlScalar,
+	"static_data":       yamlMappingOrScalar,
+	"custom_fields":     yamlSequenceOfMappings,
+	"url":               yamlScalar,
+	"headers":           yamlMapping,
+	"http_verb":         yamlScalar,
+	"screen_padding":    yamlScalar,
+	"form_fields":       yamlSequenceOfMappings,
+}
+
+// trmnlFormFieldKeys lists the keys understood by TRMNLFormField
+var trmnlFormFieldKeys = map[string]bool{
+	"keyname": true, "name": true, "field_type": true, "description": true, "optional": true,
+	"help_text": true, "id": true, "type": true, "label": true, "required": true,
+	"default": true, "options": true, "placeholder": true,
+}
+
+var yamlErrorLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// ValidateTRMNLSettingsSchema checks a TRMNL settings.yml against the fields Stationmaster
+// understands, reporting each violation with its position. Unknown keys are warnings because
+// they are ignored on import; everything else is an error.
+func ValidateTRMNLSettingsSchema(yamlData []byte) []YAMLIssue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(yamlData, &doc); err != nil {
+		return []YAMLIssue{yamlSyntaxIssue(err)}
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return []YAMLIssue{{Line: 1, Column: 1, Kind: YAMLIssueWrongType, Severity: "error", Message: "settings must be a YAML mapping"}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []YAMLIssue{wrongTypeIssue(root, "", yamlMapping)}
+	}
+
+	var issues []YAMLIssue
+	values := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keyNode, valueNode := root.Content[i], root.Content[i+1]
+		key := keyNode.Value
+
+		kind, known := trmnlSettingsSchema[key]
+		if !known {
+			issues = append(issues, YAMLIssue{
+				Line: keyNode.Line, Column: keyNode.Column, Path: key,
+				Kind: YAMLIssueUnknownKey, Severity: "warning",
+				Message: fmt.Sprintf("unknown key %q will be ignored", key),
+			})
+			continue
+		}
+		values[key] = valueNode
+
+		if !yamlNodeMatches(valueNode, kind) {
+			issues = append(issues, wrongTypeIssue(valueNode, key, kind))
+			continue
+		}
+		if kind == yamlSequenceOfMappings {
+			issues = append(issues, validateTRMNLFormFieldNodes(key, valueNode)...)
+		}
+	}
+
+	for _, required := range []string{"name", "strategy"} {
+		if node, ok := values[required]; !ok || (node.Kind == yaml.ScalarNode && strings.TrimSpace(node.Value) == "") {
+			issues = append(issues, YAMLIssue{
+				Line: root.Line, Column: root.Column, Path: required,
+				Kind: YAMLIssueMissingField, Severity: "error",
+				Message: fmt.Sprintf("required field %q is missing", required),
+			})
+		}
+	}
+
+	strategyNode := values["strategy"]
+	if strategyNode != nil && strategyNode.Kind == yaml.ScalarNode && strategyNode.Value != "" {
+		switch strategyNode.Value {
+		case "polling", "webhook", "static":
+		default:
+			issues = append(issues, YAMLIssue{
+				Line: strategyNode.Line, Column: strategyNode.Column, Path: "strategy",
+				Kind: YAMLIssueInvalidValue, Severity: "error",
+				Message: fmt.Sprintf("invalid strategy %q (must be polling, webhook, or static)", strategyNode.Value),
+			})
+		}
+	}
+
+	if strategyNode != nil && strategyNode.Value == "polling" {
+		issues = append(issues, validateTRMNLPollingNodes(root, values)...)
+	}
+
+	return issues
+}
+
+// validateTRMNLPollingNodes checks the refresh interval and URL required by the polling strategy
+func validateTRMNLPollingNodes(root *yaml.Node, values map[string]*yaml.Node) []YAMLIssue {
+	var issues []YAMLIssue
+
+	if intervalNode, ok := values["refresh_interval"]; !ok {
+		issues = append(issues, YAMLIssue{
+			Line: root.Line, Column: root.Column, Path: "refresh_interval",
+			Kind: YAMLIssueMissingField, Severity: "error",
+			Message: "refresh_interval is required for polling strategy",
+		})
+	} else if interval, err := strconv.Atoi(intervalNode.Value); err == nil {
+		switch interval {
+		case 15, 60, 360, 720, 1440:
+		default:
+			issues = append(issues, YAMLIssue{
+				Line: intervalNode.Line, Column: intervalNode.Column, Path: "refresh_interval",
+				Kind: YAMLIssueInvalidValue, Severity: "error",
+				Message: fmt.Sprintf("invalid refresh_interval %d (must be 15, 60, 360, 720, or 1440 minutes)", interval),
+			})
+		}
+	}
+
+	hasURL := false
+	for _, key := range []string{"polling_url", "url"} {
+		if node, ok := values[key]; ok && strings.TrimSpace(node.Value) != "" {
+			hasURL = true
+		}
+	}
+	if !hasURL {
+		issues = append(issues, YAMLIssue{
+			Line: root.Line, Column: root.Column, Path: "polling_url",
+			Kind: YAMLIssueMissingField, Severity: "error",
+			Message: "polling_url or url is required for polling strategy",
+		})
+	}
+
+	return issues
+}
+
+// validateTRMNLFormFieldNodes checks each custom field entry for unknown keys and a field key
+func validateTRMNLFormFieldNodes(key string, sequence *yaml.Node) []YAMLIssue {
+	var issues []YAMLIssue
+	for index, item := range sequence.Content {
+		path := fmt.Sprintf("%s[%d]", key, index)
+		if item.Kind != yaml.MappingNode {
+			issues = append(issues, wrongTypeIssue(item, path, yamlMapping))
+			continue
+		}
+
+		hasKeyname := false
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			fieldKey := item.Content[i]
+			switch fieldKey.Value {
+			case "keyname", "id":
+				hasKeyname = true
+			}
+			if !trmnlFormFieldKeys[fieldKey.Value] {
+				issues = append(issues, YAMLIssue{
+					Line: fieldKey.Line, Column: fieldKey.Column, Path: path + "." + fieldKey.Value,
+					Kind: YAMLIssueUnknownKey, Severity: "warning",
+					Message: fmt.Sprintf("unknown custom field key %q will be ignored", fieldKey.Value),
+				})
+			}
+		}
+
+		if !hasKeyname {
+			issues = append(issues, YAMLIssue{
+				Line: item.Line, Column: item.Column, Path: path + ".keyname",
+				Kind: YAMLIssueMissingField, Severity: "warning",
+				Message: "custom field has no keyname and will not be available to templates",
+			})
+		}
+	}
+	return issues
+}
+
+// yamlNodeMatches reports whether a node has the shape expected for a key. Null values are
+// accepted everywhere since they unmarshal to the zero value.
+func yamlNodeMatches(node *yaml.Node, kind yamlValueKind) bool {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return true
+	}
+	switch kind {
+	case yamlInt:
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!int"
+	case yamlMapping:
+		return node.Kind == yaml.MappingNode
+	case yamlMappingOrScalar:
+		return node.Kind == yaml.MappingNode || node.Kind == yaml.ScalarNode
+	case yamlSequenceOfMappings:
+		return node.Kind == yaml.SequenceNode
+	default:
+		return node.Kind == yaml.ScalarNode
+	}
+}
+
+func wrongTypeIssue(node *yaml.Node, path string, kind yamlValueKind) YAMLIssue {
+	message := fmt.Sprintf("expected %s", kind.describe())
+	if path != "" {
+		message = fmt.Sprintf("%s must be %s", path, kind.describe())
+	}
+	return YAMLIssue{
+		Line: node.Line, Column: node.Column, Path: path,
+		Kind: YAMLIssueWrongType, Severity: "error",
+		Message: message,
+	}
+}
+
+// yamlSyntaxIssue converts a parser error to an issue, recovering the line number from the message
+func yamlSyntaxIssue(err error) YAMLIssue {
+	issue := YAMLIssue{Kind: YAMLIssueSyntax, Severity: "error", Message: err.Error()}
+	if match := yamlErrorLinePattern.FindStringSubmatch(err.Error()); match != nil {
+		issue.Line, _ = strconv.Atoi(match[1])
+		issue.Column = 1
+	}
+	return issue
+}
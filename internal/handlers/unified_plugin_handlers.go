@@ -1,20 +1,28 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image/png"
 	"io"
 	"net/http"
+	"net/mail"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/config"
 	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/imageprocessing"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
 	"github.com/rmitchellscott/stationmaster/internal/plugins"
 	"github.com/rmitchellscott/stationmaster/internal/plugins/external"
@@ -22,6 +30,7 @@ import (
 	"github.com/rmitchellscott/stationmaster/internal/rendering"
 	"github.com/rmitchellscott/stationmaster/internal/validation"
 	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
 )
 
 // UnifiedPluginDefinition represents a plugin definition that can be system, private, or external
@@ -39,11 +48,21 @@ type UnifiedPluginDefinition struct {
 	RequiresProcessing bool   `json:"requires_processing"`
 	Status             string `json:"status"`             // "available", "unavailable", "error"
 	OAuthConfig        json.RawMessage `json:"oauth_config,omitempty"` // OAuth configuration for external plugins
+	DeviceRequirements *database.DeviceRequirements `json:"device_requirements,omitempty"` // Minimum device capabilities, if any
 
 	// Private plugin specific fields
 	InstanceCount      *int   `json:"instance_count,omitempty"` // Number of instances user has created
 }
 
+// deviceRequirementsOrNil returns the definition's device requirements, or nil when it declares none
+func deviceRequirementsOrNil(def *database.PluginDefinition) *database.DeviceRequirements {
+	requirements := def.DeviceRequirements()
+	if requirements.IsEmpty() {
+		return nil
+	}
+	return &requirements
+}
+
 // stripOAuthSecrets removes sensitive fields (client_id, client_secret) from OAuth config before sending to UI
 func stripOAuthSecrets(oauthConfig []byte) json.RawMessage {
 	if len(oauthConfig) == 0 {
@@ -63,7 +82,136 @@ func stripOAuthSecrets(oauthConfig []byte) json.RawMessage {
 	return json.RawMessage(cleaned)
 }
 
-// GetAvailablePluginDefinitionsHandler returns both system and private plugins available to the user
+// pluginTypeSortOrder orders plugin types when sorting by type
+var pluginTypeSortOrder = map[string]int{"system": 0, "external": 1, "private": 2}
+
+// sortPluginDefinitions sorts plugin definitions in place by name, recent update, the user's instance count or type.
+// Ties are always broken by name. It returns false for an unknown sort option.
+func sortPluginDefinitions(db *gorm.DB, userID uuid.UUID, plugins []UnifiedPluginDefinition, sortBy string) bool {
+	var less func(a, b UnifiedPluginDefinition) (bool, bool)
+
+	switch sortBy {
+	case "name":
+		less = func(a, b UnifiedPluginDefinition) (bool, bool) { return false, false }
+	case "recent":
+		var rows []struct {
+			ID        string
+			UpdatedAt time.Time
+		}
+		ids := make([]string, 0, len(plugins))
+		for _, plugin := range plugins {
+			ids = append(ids, plugin.ID)
+		}
+		if err := db.Model(&database.PluginDefinition{}).Select("id", "updated_at").Where("id IN ?", ids).Find(&rows).Error; err != nil {
+			logging.Warn("[PLUGINS] Failed to load plugin update times for sorting", "error", err)
+		}
+		updatedAt := make(map[string]time.Time, len(rows))
+		for _, row := range rows {
+			updatedAt[row.ID] = row.UpdatedAt
+		}
+		less = func(a, b UnifiedPluginDefinition) (bool, bool) {
+			ta, tb := updatedAt[a.ID], updatedAt[b.ID]
+			return ta.After(tb), !ta.Equal(tb)
+		}
+	case "instance_count":
+		var rows []struct {
+			PluginDefinitionID string
+			Count              int
+		}
+		if err := db.Model(&database.PluginInstance{}).
+			Select("plugin_definition_id, COUNT(*) AS count").
+			Where("user_id = ?", userID).
+			Group("plugin_definition_id").
+			Scan(&rows).Error; err != nil {
+			logging.Warn("[PLUGINS] Failed to count plugin instances for sorting", "error", err)
+		}
+		counts := make(map[string]int, len(rows))
+		for _, row := range rows {
+			counts[row.PluginDefinitionID] = row.Count
+		}
+		less = func(a, b UnifiedPluginDefinition) (bool, bool) {
+			ca, cb := counts[a.ID], counts[b.ID]
+			return ca > cb, ca != cb
+		}
+	case "type":
+		less = func(a, b UnifiedPluginDefinition) (bool, bool) {
+			oa, ob := pluginTypeSortOrder[a.Type], pluginTypeSortOrder[b.Type]
+			return oa < ob, oa != ob
+		}
+	default:
+		return false
+	}
+
+	sort.SliceStable(plugins, func(i, j int) bool {
+		if result, decided := less(plugins[i], plugins[j]); decided {
+			return result
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+	return true
+}
+
+// pluginDefinitionFilter narrows the plugin definition list by source, output kind and processing needs
+type pluginDefinitionFilter struct {
+	Type               string // "system", "private" or "external"; empty for all
+	OutputType         string // "image" or "data"; empty for all
+	RequiresProcessing *bool
+}
+
+// parsePluginDefinitionFilter reads the type, plugin_type and requires_processing query parameters.
+// plugin_type values other than image/data select which private definitions are listed and are
+// handled by the caller for backward compatibility.
+func parsePluginDefinitionFilter(c *gin.Context) (pluginDefinitionFilter, error) {
+	var filter pluginDefinitionFilter
+
+	switch sourceType := c.Query("type"); sourceType {
+	case "", "system", "private", "external":
+		filter.Type = sourceType
+	default:
+		return filter, fmt.Errorf("Invalid type. Must be one of: system, private, external")
+	}
+
+	switch pluginType := c.Query("plugin_type"); pluginType {
+	case string(plugins.PluginTypeImage), string(plugins.PluginTypeData):
+		filter.OutputType = pluginType
+	}
+
+	if value := c.Query("requires_processing"); value != "" {
+		requiresProcessing, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid requires_processing. Must be true or false")
+		}
+		filter.RequiresProcessing = &requiresProcessing
+	}
+
+	return filter, nil
+}
+
+// pluginOutputType reports whether a plugin produces an image or data. Only system plugins can be
+// data plugins; private and external plugins are always rendered to images.
+func pluginOutputType(plugin UnifiedPluginDefinition) string {
+	if plugin.Type == "system" {
+		return plugin.PluginType
+	}
+	return string(plugins.PluginTypeImage)
+}
+
+// matches reports whether a plugin definition passes the filter
+func (f pluginDefinitionFilter) matches(plugin UnifiedPluginDefinition) bool {
+	if f.Type != "" && plugin.Type != f.Type {
+		return false
+	}
+	if f.OutputType != "" && pluginOutputType(plugin) != f.OutputType {
+		return false
+	}
+	if f.RequiresProcessing != nil && plugin.RequiresProcessing != *f.RequiresProcessing {
+		return false
+	}
+	return true
+}
+
+// GetAvailablePluginDefinitionsHandler returns both system and private plugins available to the user.
+// Results can be filtered with the type, plugin_type and requires_processing query parameters.
 func GetAvailablePluginDefinitionsHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
 	if !ok {
@@ -71,6 +219,12 @@ func GetAvailablePluginDefinitionsHandler(c *gin.Context) {
 	}
 	userID := user.ID
 
+	filter, err := parsePluginDefinitionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var allPlugins []UnifiedPluginDefinition
 
 	// Get system plugins from registry
@@ -97,7 +251,7 @@ func GetAvailablePluginDefinitionsHandler(c *gin.Context) {
 	// Only include plugins with status = "available" so unavailable plugins don't show in "Add Plugin" UI
 	db := database.GetDB()
 	var externalPlugins []database.PluginDefinition
-	err := db.Where("plugin_type = ? AND is_active = ? AND status = ?", "external", true, "available").Find(&externalPlugins).Error
+	err = db.Where("plugin_type = ? AND is_active = ? AND status = ?", "external", true, "available").Find(&externalPlugins).Error
 	if err == nil {
 		for _, extPlugin := range externalPlugins {
 			// Create external plugin instance to get properly processed ConfigSchema
@@ -118,6 +272,7 @@ func GetAvailablePluginDefinitionsHandler(c *gin.Context) {
 				RequiresProcessing: extPlugin.RequiresProcessing,
 				Status:             extPlugin.Status, // Include availability status
 				OAuthConfig:        stripOAuthSecrets(extPlugin.OAuthConfig), // Strip secrets before sending to UI
+				DeviceRequirements: deviceRequirementsOrNil(&extPlugin),
 				// No InstanceCount for external plugins (like system plugins)
 			}
 			allPlugins = append(allPlugins, unifiedPlugin)
@@ -126,8 +281,11 @@ func GetAvailablePluginDefinitionsHandler(c *gin.Context) {
 
 	// Get user's private plugins from unified plugin_definitions table
 	
-	// Filter by plugin_type query parameter if provided
+	// Filter by plugin_type query parameter if provided; image/data are output filters, not definition types
 	pluginType := c.Query("plugin_type")
+	if filter.OutputType != "" {
+		pluginType = ""
+	}
 	
 	var privatePlugins []database.PluginDefinition
 	query := db.Where("owner_id = ?", userID)
@@ -160,15 +318,25 @@ func GetAvailablePluginDefinitionsHandler(c *gin.Context) {
 				RequiresProcessing: privatePlugin.RequiresProcessing,
 				Status:             privatePlugin.Status, // Include availability status
 				InstanceCount:      &instances,
+				DeviceRequirements: deviceRequirementsOrNil(&privatePlugin),
 			}
 			allPlugins = append(allPlugins, unifiedPlugin)
 		}
 	}
 
-	// Sort plugins by name only
-	sort.Slice(allPlugins, func(i, j int) bool {
-		return allPlugins[i].Name < allPlugins[j].Name
-	})
+	filtered := allPlugins[:0]
+	for _, plugin := range allPlugins {
+		if filter.matches(plugin) {
+			filtered = append(filtered, plugin)
+		}
+	}
+	allPlugins = filtered
+
+	// Sort the merged list, defaulting to name
+	if !sortPluginDefinitions(db, userID, allPlugins, c.DefaultQuery("sort", "name")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort. Must be one of: name, recent, instance_count, type"})
+		return
+	}
 
 	// If requesting only private plugins, transform to PrivatePluginList format
 	if pluginType == "private" {
@@ -201,6 +369,7 @@ func GetAvailablePluginDefinitionsHandler(c *gin.Context) {
 						"polling_config":    nil,
 						"form_fields":       nil,
 						"sample_data":       nil,
+						"device_requirements": plugin.DeviceRequirements,
 					}
 					
 					// Add markup fields if they exist
@@ -253,6 +422,10 @@ type UnifiedPluginInstance struct {
 	NeedsConfigUpdate  bool                   `json:"needs_config_update"`
 	LastSchemaVersion  int                    `json:"last_schema_version"`
 	
+	// Settings profiles
+	Profiles           []string               `json:"profiles,omitempty"`
+	ActiveProfile      string                 `json:"active_profile,omitempty"`
+	
 	// Plugin info
 	Plugin struct {
 		ID                 string          `json:"id"`
@@ -341,6 +514,8 @@ func GetPluginInstancesHandler(c *gin.Context) {
 				IsUsedInPlaylists: isUsedInPlaylists,
 				NeedsConfigUpdate: pluginInstance.NeedsConfigUpdate,
 				LastSchemaVersion: pluginInstance.LastSchemaVersion,
+				Profiles:          settingsProfileNames(pluginInstance),
+				ActiveProfile:     pluginInstance.ActiveProfile,
 			}
 
 			// Fill plugin info from PluginDefinition
@@ -406,6 +581,9 @@ func GetPluginInstancesHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"plugin_instances": allInstances})
 }
 
+// maxFailureNotifyThreshold caps how many consecutive render failures can be configured before notifying
+const maxFailureNotifyThreshold = 100
+
 // UpdatePluginInstanceHandler updates a plugin instance (handles both legacy and unified)
 func UpdatePluginInstanceHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
@@ -421,9 +599,13 @@ func UpdatePluginInstanceHandler(c *gin.Context) {
 	}
 
 	type UpdateInstanceRequest struct {
-		Name            string                 `json:"name" binding:"required"`
-		Settings        map[string]interface{} `json:"settings"`
-		RefreshInterval int                    `json:"refresh_interval"`
+		Name                   string                 `json:"name" binding:"required"`
+		Settings               map[string]interface{} `json:"settings"`
+		RefreshInterval        int                    `json:"refresh_interval"`
+		FailureNotifyThreshold *int                   `json:"failure_notify_threshold"`
+		FailureNotifyEmail     *string                `json:"failure_notify_email"`
+		WebhookCoalesceSeconds *int                   `json:"webhook_coalesce_seconds"`
+		RenderDeviceIDs        *[]uuid.UUID           `json:"render_device_ids"`
 	}
 
 	var req UpdateInstanceRequest
@@ -440,6 +622,18 @@ func UpdatePluginInstanceHandler(c *gin.Context) {
 	if err == nil {
 		// Update unified instance
 		logging.Info("[PLUGIN_UPDATE] Updating plugin instance", "instance_id", instanceID, "name", req.Name)
+		if req.Name != unifiedInstance.Name {
+			err := database.NewUnifiedPluginService(db).CheckPluginInstanceName(userID, req.Name, &unifiedInstance.ID)
+			if errors.Is(err, database.ErrPluginInstanceNameTaken) {
+				c.JSON(http.StatusConflict, gin.H{"error": "You already have a plugin instance named \"" + req.Name + "\""})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check plugin instance name"})
+				return
+			}
+		}
+		previous := unifiedInstance
 		unifiedInstance.Name = req.Name
 		
 		// Convert settings map to datatypes.JSON
@@ -465,10 +659,66 @@ func UpdatePluginInstanceHandler(c *gin.Context) {
 			unifiedInstance.Settings = settingsJSON
 			logging.Info("[PLUGIN_UPDATE] Empty settings saved", "instance_id", instanceID)
 		}
-		if req.RefreshInterval > 0 {
+		if req.RefreshInterval > 0 && req.RefreshInterval != unifiedInstance.RefreshInterval {
+			frequentEnabled := frequentRefreshesEnabled()
+			if !database.IsValidRefreshRateWithFrequent(req.RefreshInterval, frequentEnabled) {
+				options := database.GetRefreshRateOptionsWithFrequent(frequentEnabled)
+				validValues := make([]string, 0, len(options))
+				for _, option := range options {
+					validValues = append(validValues, fmt.Sprintf("%d (%s)", option.Value, option.Label))
+				}
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":                fmt.Sprintf("Invalid refresh interval %d. Valid values in seconds: %s", req.RefreshInterval, strings.Join(validValues, ", ")),
+					"refresh_rate_options": options,
+				})
+				return
+			}
 			unifiedInstance.RefreshInterval = req.RefreshInterval
 		}
 
+		if req.FailureNotifyThreshold != nil {
+			if *req.FailureNotifyThreshold < 0 || *req.FailureNotifyThreshold > maxFailureNotifyThreshold {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failure_notify_threshold must be between 0 and %d", maxFailureNotifyThreshold)})
+				return
+			}
+			unifiedInstance.FailureNotifyThreshold = *req.FailureNotifyThreshold
+		}
+		if req.FailureNotifyEmail != nil {
+			email := strings.TrimSpace(*req.FailureNotifyEmail)
+			if email != "" {
+				if _, err := mail.ParseAddress(email); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "failure_notify_email must be a valid email address"})
+					return
+				}
+			}
+			unifiedInstance.FailureNotifyEmail = email
+		}
+		if req.WebhookCoalesceSeconds != nil {
+			if *req.WebhookCoalesceSeconds < 0 || *req.WebhookCoalesceSeconds > maxWebhookCoalesceSeconds {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("webhook_coalesce_seconds must be between 0 and %d", maxWebhookCoalesceSeconds)})
+				return
+			}
+			unifiedInstance.WebhookCoalesceSeconds = *req.WebhookCoalesceSeconds
+		}
+		if req.RenderDeviceIDs != nil {
+			deviceIDs := *req.RenderDeviceIDs
+			if len(deviceIDs) > 0 {
+				var owned int64
+				if err := db.Model(&database.Device{}).Where("id IN ? AND user_id = ?", deviceIDs, userID).Count(&owned).Error; err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate render devices"})
+					return
+				}
+				if int(owned) != len(deviceIDs) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "render_device_ids must only contain your own devices, each listed once"})
+					return
+				}
+			}
+			if err := unifiedInstance.SetRenderDeviceAllowlist(deviceIDs); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to process render_device_ids: " + err.Error()})
+				return
+			}
+		}
+
 		// Clear config update flag and sync schema version when instance is updated
 		if unifiedInstance.NeedsConfigUpdate {
 			unifiedInstance.NeedsConfigUpdate = false
@@ -483,6 +733,7 @@ func UpdatePluginInstanceHandler(c *gin.Context) {
 		}
 		
 		logging.Info("[PLUGIN_UPDATE] Plugin instance updated successfully", "instance_id", instanceID, "name", unifiedInstance.Name)
+		recordSettingsUpdatedEvent(db, &previous, &unifiedInstance)
 
 		// Schedule immediate independent render for updated plugin instance if it requires processing
 		if unifiedInstance.PluginDefinition.RequiresProcessing {
@@ -541,6 +792,7 @@ func DeletePluginInstanceHandler(c *gin.Context) {
 	err = unifiedPluginService.DeletePluginInstance(instanceUUID, userID)
 	if err == nil {
 		logging.Info("[DELETE] Successfully deleted unified PluginInstance", "instance_id", instanceID)
+		deleteWebhookImages(c.Request.Context(), []uuid.UUID{instanceUUID})
 		c.JSON(http.StatusOK, gin.H{"message": "Plugin instance deleted successfully"})
 		return
 	}
@@ -549,6 +801,15 @@ func DeletePluginInstanceHandler(c *gin.Context) {
 	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete plugin instance: " + err.Error()})
 }
 
+// deleteWebhookImages removes the images pushed to the webhooks of deleted plugin instances
+func deleteWebhookImages(ctx context.Context, instanceIDs []uuid.UUID) {
+	for _, instanceID := range instanceIDs {
+		if err := private.DeleteWebhookImage(ctx, instanceID); err != nil {
+			logging.Warn("[DELETE] Failed to delete webhook image", "instance_id", instanceID, "error", err)
+		}
+	}
+}
+
 // ForceRefreshPluginInstanceHandler forces refresh of a plugin instance (handles both legacy and unified)
 func ForceRefreshPluginInstanceHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
@@ -569,53 +830,101 @@ func ForceRefreshPluginInstanceHandler(c *gin.Context) {
 	var unifiedInstance database.PluginInstance
 	err := db.Preload("PluginDefinition").Where("id = ? AND user_id = ?", instanceID, userID).First(&unifiedInstance).Error
 	if err == nil {
-		// For unified instances, clear any pre-rendered content and cached polling data
-		db.Where("plugin_instance_id = ?", unifiedInstance.ID).Delete(&database.RenderedContent{})
-		db.Where("plugin_instance_id = ?", unifiedInstance.ID.String()).Delete(&database.PrivatePluginPollingData{})
+		if _, err := forceRefreshPluginInstance(db, &unifiedInstance); err != nil {
+			logging.Error("[FORCE_REFRESH] Failed to schedule immediate render job", "instance_id", instanceID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule render job"})
+			return
+		}
 
-		// Check if this plugin requires processing (rendering)
-		if unifiedInstance.PluginDefinition.RequiresProcessing {
-			// Determine if this should be an independent render based on refresh rate type
-			dailyRates := []int{
-				database.RefreshRateDaily,   // Daily
-				database.RefreshRate2xDay,   // Twice daily 
-				database.RefreshRate3xDay,   // 3 times daily
-				database.RefreshRate4xDay,   // 4 times daily
-			}
-			
-			isDaily := false
-			for _, dailyRate := range dailyRates {
-				if unifiedInstance.RefreshInterval == dailyRate {
-					isDaily = true
-					break
-				}
-			}
-			
-			refreshID := unifiedInstance.ID
-			renderJob := database.RenderQueue{
-				ID:               uuid.New(),
-				PluginInstanceID: &refreshID,
-				Priority:         999,
-				ScheduledFor:     time.Now().UTC(),
-				Status:           "pending",
-				IndependentRender: isDaily, // Daily rates: independent, interval rates: reschedule from now
-			}
+		c.JSON(http.StatusOK, gin.H{"message": "Plugin refresh triggered successfully"})
+		return
+	}
 
-			err = db.Create(&renderJob).Error
-			if err != nil {
-				logging.Error("[FORCE_REFRESH] Failed to schedule immediate render job", "instance_id", instanceID, "error", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule render job"})
-				return
-			}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Plugin instance not found"})
+}
+
+// forceRefreshPluginInstance clears an instance's rendered content and cached polling data and, for plugins
+// that render, queues a high priority render job. It returns the job, or nil when nothing needs rendering.
+func forceRefreshPluginInstance(db *gorm.DB, instance *database.PluginInstance) (*database.RenderQueue, error) {
+	db.Where("plugin_instance_id = ?", instance.ID).Delete(&database.RenderedContent{})
+	db.Where("plugin_instance_id = ?", instance.ID.String()).Delete(&database.PrivatePluginPollingData{})
 
-			logging.Info("[FORCE_REFRESH] Scheduled immediate render job", "instance_name", unifiedInstance.Name, "instance_id", instanceID, "job_id", renderJob.ID)
+	if !instance.PluginDefinition.RequiresProcessing {
+		return nil, nil
+	}
+
+	// Daily rates render independently, interval rates reschedule from now
+	isDaily := false
+	for _, dailyRate := range []int{database.RefreshRateDaily, database.RefreshRate2xDay, database.RefreshRate3xDay, database.RefreshRate4xDay} {
+		if instance.RefreshInterval == dailyRate {
+			isDaily = true
+			break
 		}
+	}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Plugin refresh triggered successfully"})
+	refreshID := instance.ID
+	renderJob := database.RenderQueue{
+		ID:                uuid.New(),
+		PluginInstanceID:  &refreshID,
+		Priority:          999,
+		ScheduledFor:      time.Now().UTC(),
+		Status:            "pending",
+		IndependentRender: isDaily,
+	}
+	if err := db.Create(&renderJob).Error; err != nil {
+		return nil, err
+	}
+
+	logging.Info("[FORCE_REFRESH] Scheduled immediate render job", "instance_name", instance.Name, "instance_id", instance.ID, "job_id", renderJob.ID)
+	return &renderJob, nil
+}
+
+// ForceRefreshDefinitionInstancesHandler force refreshes every one of the user's instances of a plugin
+// definition, e.g. after editing its templates. The definition must be a system plugin or the user's own.
+func ForceRefreshDefinitionInstancesHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusNotFound, gin.H{"error": "Plugin instance not found"})
+	db := database.GetDB()
+	def, err := database.NewUnifiedPluginService(db).GetPluginDefinitionByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plugin definition not found"})
+		return
+	}
+	if def.PluginType != "system" && (def.OwnerID == nil || *def.OwnerID != user.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	var instances []database.PluginInstance
+	if err := db.Preload("PluginDefinition").Where("plugin_definition_id = ? AND user_id = ?", def.ID, user.ID).Find(&instances).Error; err != nil {
+		logging.Error("[FORCE_REFRESH] Failed to get definition instances", "definition_id", def.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get plugin instances"})
+		return
+	}
+
+	scheduled := 0
+	var failed []string
+	for i := range instances {
+		job, err := forceRefreshPluginInstance(db, &instances[i])
+		if err != nil {
+			logging.Error("[FORCE_REFRESH] Failed to schedule immediate render job", "instance_id", instances[i].ID, "error", err)
+			failed = append(failed, instances[i].ID.String())
+			continue
+		}
+		if job != nil {
+			scheduled++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":             "Plugin refresh triggered successfully",
+		"instance_count":      len(instances),
+		"scheduled_count":     scheduled,
+		"failed_instance_ids": failed,
+	})
 }
 
 // CreatePluginInstanceFromDefinitionHandler creates a plugin instance from a unified definition
@@ -632,6 +941,8 @@ func CreatePluginInstanceFromDefinitionHandler(c *gin.Context) {
 		Name            string                 `json:"name" binding:"required"`
 		Settings        map[string]interface{} `json:"settings"`
 		RefreshInterval int                    `json:"refresh_interval"`
+		DeviceID        string                 `json:"device_id"` // Optional target device to check requirements against
+		Force           bool                   `json:"force"`     // Create even if the device does not meet the requirements
 	}
 
 	var req CreateInstanceRequest
@@ -666,8 +977,38 @@ func CreatePluginInstanceFromDefinitionHandler(c *gin.Context) {
 	}
 	// System plugins: accessible to all users (no additional check needed)
 
+	var unmetRequirements []string
+	if req.DeviceID != "" {
+		deviceID, err := uuid.Parse(req.DeviceID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid device ID"})
+			return
+		}
+		device, err := database.NewDeviceService(db).GetDeviceByID(deviceID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+			return
+		}
+		if device.UserID == nil || *device.UserID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		unmetRequirements = pluginDefinition.DeviceRequirements().UnmetBy(device)
+		if len(unmetRequirements) > 0 && !req.Force {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":              "Device does not meet the plugin's requirements",
+				"unmet_requirements": unmetRequirements,
+			})
+			return
+		}
+	}
+
 	// Create the PluginInstance using unified service
 	pluginInstance, err := unifiedPluginService.CreatePluginInstance(userID, pluginDefinition.ID, req.Name, req.Settings, req.RefreshInterval)
+	if errors.Is(err, database.ErrPluginInstanceNameTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "You already have a plugin instance named \"" + req.Name + "\""})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create plugin instance: " + err.Error()})
 		return
@@ -678,18 +1019,24 @@ func CreatePluginInstanceFromDefinitionHandler(c *gin.Context) {
 		ScheduleRenderForInstances([]uuid.UUID{pluginInstance.ID})
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"instance": pluginInstance})
+	response := gin.H{"instance": pluginInstance}
+	if len(unmetRequirements) > 0 {
+		response["warning"] = "Device does not meet the plugin's requirements"
+		response["unmet_requirements"] = unmetRequirements
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// frequentRefreshesEnabled reports whether the admin has enabled sub-15-minute refresh rates
+func frequentRefreshesEnabled() bool {
+	enabledStr, err := database.GetSystemSetting("enable_frequent_refreshes")
+	return err == nil && enabledStr == "true"
 }
 
 // GetRefreshRateOptionsHandler returns available refresh rate options
 func GetRefreshRateOptionsHandler(c *gin.Context) {
-	// Check if frequent refreshes are enabled
-	frequentRefreshesEnabled := false
-	if enabledStr, err := database.GetSystemSetting("enable_frequent_refreshes"); err == nil {
-		frequentRefreshesEnabled = enabledStr == "true"
-	}
-	
-	options := database.GetRefreshRateOptionsWithFrequent(frequentRefreshesEnabled)
+	options := database.GetRefreshRateOptionsWithFrequent(frequentRefreshesEnabled())
 	c.JSON(http.StatusOK, gin.H{"refresh_rate_options": options})
 }
 
@@ -805,6 +1152,8 @@ func CreatePluginDefinitionHandler(c *gin.Context) {
 		SampleData        interface{} `json:"sample_data"`
 		RemoveBleedMargin bool        `json:"remove_bleed_margin"`
 		EnableDarkMode    bool        `json:"enable_dark_mode"`
+		DeviceRequirements *database.DeviceRequirements `json:"device_requirements"`
+		HashIgnoreRegions  *[]database.HashIgnoreRegion `json:"hash_ignore_regions"` // Omit to keep, empty list to clear
 	}
 
 	var req CreatePluginRequest
@@ -825,6 +1174,20 @@ func CreatePluginDefinitionHandler(c *gin.Context) {
 		return
 	}
 
+	if req.DeviceRequirements != nil {
+		if err := req.DeviceRequirements.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Device requirements validation failed", "details": err.Error()})
+			return
+		}
+	}
+
+	if req.HashIgnoreRegions != nil {
+		if err := database.ValidateHashIgnoreRegions(*req.HashIgnoreRegions); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Hash ignore regions validation failed", "details": err.Error()})
+			return
+		}
+	}
+
 	// Validate and convert form fields to JSON schema
 	configSchema, err := validation.ValidateFormFields(req.FormFields)
 	if err != nil {
@@ -895,6 +1258,15 @@ func CreatePluginDefinitionHandler(c *gin.Context) {
 		CreatedAt:          time.Now().UTC(),
 		UpdatedAt:          time.Now().UTC(),
 	}
+	if req.DeviceRequirements != nil {
+		pluginDefinition.SetDeviceRequirements(*req.DeviceRequirements)
+	}
+	if req.HashIgnoreRegions != nil {
+		if err := pluginDefinition.SetHashIgnoreRegions(*req.HashIgnoreRegions); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hash ignore regions"})
+			return
+		}
+	}
 
 	if err := db.Create(&pluginDefinition).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create plugin definition: " + err.Error()})
@@ -936,6 +1308,8 @@ func UpdatePluginDefinitionHandler(c *gin.Context) {
 		SampleData        interface{} `json:"sample_data"`
 		RemoveBleedMargin bool        `json:"remove_bleed_margin"`
 		EnableDarkMode    bool        `json:"enable_dark_mode"`
+		DeviceRequirements *database.DeviceRequirements `json:"device_requirements"`
+		HashIgnoreRegions  *[]database.HashIgnoreRegion `json:"hash_ignore_regions"` // Omit to keep, empty list to clear
 	}
 
 	var req UpdatePluginRequest
@@ -950,6 +1324,20 @@ func UpdatePluginDefinitionHandler(c *gin.Context) {
 		return
 	}
 
+	if req.DeviceRequirements != nil {
+		if err := req.DeviceRequirements.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Device requirements validation failed", "details": err.Error()})
+			return
+		}
+	}
+
+	if req.HashIgnoreRegions != nil {
+		if err := database.ValidateHashIgnoreRegions(*req.HashIgnoreRegions); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Hash ignore regions validation failed", "details": err.Error()})
+			return
+		}
+	}
+
 	// Validate and convert form fields to JSON schema
 	configSchema, err := validation.ValidateFormFields(req.FormFields)
 	if err != nil {
@@ -1029,6 +1417,15 @@ func UpdatePluginDefinitionHandler(c *gin.Context) {
 	pluginDefinition.SampleData = sampleDataJSON
 	pluginDefinition.RemoveBleedMargin = &req.RemoveBleedMargin
 	pluginDefinition.EnableDarkMode = &req.EnableDarkMode
+	if req.DeviceRequirements != nil {
+		pluginDefinition.SetDeviceRequirements(*req.DeviceRequirements)
+	}
+	if req.HashIgnoreRegions != nil {
+		if err := pluginDefinition.SetHashIgnoreRegions(*req.HashIgnoreRegions); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hash ignore regions"})
+			return
+		}
+	}
 	pluginDefinition.UpdatedAt = time.Now().UTC()
 
 	// Increment schema version if form fields changed
@@ -1055,6 +1452,13 @@ func UpdatePluginDefinitionHandler(c *gin.Context) {
 			logging.Error("[PLUGIN_UPDATE] Failed to flag instances for config updates", "plugin_id", pluginDefinition.ID, "error", result.Error)
 		} else {
 			logging.Info("[PLUGIN_UPDATE] Flagged instances for config updates", "plugin_id", pluginDefinition.ID, "affected_instances", result.RowsAffected)
+			var flaggedIDs []uuid.UUID
+			db.Model(&database.PluginInstance{}).Where("plugin_definition_id = ?", pluginDefinition.ID).Pluck("id", &flaggedIDs)
+			for _, id := range flaggedIDs {
+				database.RecordPluginInstanceEvent(db, id, database.PluginInstanceEventSchemaFlagged,
+					"Plugin form fields changed, settings need review",
+					map[string]interface{}{"schema_version": pluginDefinition.SchemaVersion})
+			}
 		}
 	}
 
@@ -1077,7 +1481,28 @@ func UpdatePluginDefinitionHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"plugin_definition": pluginDefinition})
 }
 
-// DeletePluginDefinitionHandler deletes a plugin definition
+// blockDeletionInUse responds with 409 and the affected instances when a plugin definition still has
+// active instances. It returns true when the response has been written.
+func blockDeletionInUse(c *gin.Context, service *database.UnifiedPluginService, definitionID string) bool {
+	usage, err := service.GetPluginDefinitionUsage(definitionID)
+	if err != nil {
+		logging.Error("Failed to check plugin definition usage", "definition_id", definitionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check plugin usage"})
+		return true
+	}
+	if len(usage) == 0 {
+		return false
+	}
+
+	c.JSON(http.StatusConflict, gin.H{
+		"error":              fmt.Sprintf("Plugin is used by %d active instance(s); pass force=true to delete them as well", len(usage)),
+		"affected_instances": usage,
+	})
+	return true
+}
+
+// DeletePluginDefinitionHandler deletes a plugin definition. Deletion is refused while active
+// instances exist unless force=true is passed.
 func DeletePluginDefinitionHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
 	if !ok {
@@ -1096,13 +1521,29 @@ func DeletePluginDefinitionHandler(c *gin.Context) {
 
 	db := database.GetDB()
 	service := database.NewUnifiedPluginService(db)
+
+	// Refuse to cascade into live instances unless explicitly forced
+	if c.Query("force") != "true" {
+		definition, err := service.GetPluginDefinitionByID(definitionID)
+		if err != nil || definition.OwnerID == nil || *definition.OwnerID != userID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Plugin definition not found"})
+			return
+		}
+		if blockDeletionInUse(c, service, definitionID) {
+			return
+		}
+	}
 	
+	var instanceIDs []uuid.UUID
+	db.Model(&database.PluginInstance{}).Where("plugin_definition_id = ? AND is_active = ?", definitionID, true).Pluck("id", &instanceIDs)
+
 	// Use the service method which properly handles cascading deletions
 	err := service.DeletePluginDefinition(definitionID, &userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete plugin definition: " + err.Error()})
 		return
 	}
+	deleteWebhookImages(c.Request.Context(), instanceIDs)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Plugin definition deleted successfully"})
 }
@@ -1147,18 +1588,20 @@ func ValidatePluginDefinitionHandler(c *gin.Context) {
 
 // TestPlugin represents a plugin for testing purposes
 type TestPlugin struct {
-	Name             string      `json:"name"`
-	Description      string      `json:"description"`
-	MarkupFull       string      `json:"markup_full"`
-	MarkupHalfVert   string      `json:"markup_half_vert"`
-	MarkupHalfHoriz  string      `json:"markup_half_horiz"`
-	MarkupQuadrant   string      `json:"markup_quadrant"`
-	SharedMarkup     string      `json:"shared_markup"`
-	DataStrategy     string      `json:"data_strategy"`
-	PollingConfig    interface{} `json:"polling_config"`
-	FormFields       interface{} `json:"form_fields"`
-	Version          string      `json:"version"`
-	PluginType       string      `json:"plugin_type"`
+	Name              string      `json:"name"`
+	Description       string      `json:"description"`
+	MarkupFull        string      `json:"markup_full"`
+	MarkupHalfVert    string      `json:"markup_half_vert"`
+	MarkupHalfHoriz   string      `json:"markup_half_horiz"`
+	MarkupQuadrant    string      `json:"markup_quadrant"`
+	SharedMarkup      string      `json:"shared_markup"`
+	DataStrategy      string      `json:"data_strategy"`
+	PollingConfig     interface{} `json:"polling_config"`
+	FormFields        interface{} `json:"form_fields"`
+	Version           string      `json:"version"`
+	PluginType        string      `json:"plugin_type"`
+	RemoveBleedMargin bool        `json:"remove_bleed_margin"`
+	EnableDarkMode    bool        `json:"enable_dark_mode"`
 }
 
 // extractFormFieldDefaults extracts default values from form field configuration
@@ -1263,7 +1706,23 @@ func getPollingDataForPreview(plugin TestPlugin, formDefaults map[string]interfa
 	return result.Data, nil
 }
 
-// TestPluginDefinitionHandler tests plugin template rendering
+// resolvePreviewTemplateData returns polled data for polling plugins, falling back to sample data
+func resolvePreviewTemplateData(plugin TestPlugin, sampleData map[string]interface{}) map[string]interface{} {
+	if plugin.DataStrategy != "polling" || plugin.PollingConfig == nil {
+		return sampleData
+	}
+
+	formDefaults := extractFormFieldDefaults(plugin.FormFields)
+	realData, err := getPollingDataForPreview(plugin, formDefaults)
+	if err != nil {
+		logging.Warn("[TestPlugin] Polling failed, using sample data", "error", err)
+		return sampleData
+	}
+	return realData
+}
+
+// TestPluginDefinitionHandler tests plugin template rendering. It queues a preview image render, or with
+// render_mode "html" returns the server-side rendered HTML right away without involving browserless.
 func TestPluginDefinitionHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
 	if !ok {
@@ -1281,6 +1740,9 @@ func TestPluginDefinitionHandler(c *gin.Context) {
 		ScreenOrientation string                 `json:"screen_orientation"`
 		LayoutWidth       int                    `json:"layout_width"`
 		LayoutHeight      int                    `json:"layout_height"`
+		RenderTime        string                 `json:"render_time"`
+		BitDepth          int                    `json:"bit_depth"`   // Optional output bit depth override for comparing quantization
+		RenderMode        string                 `json:"render_mode"` // "image" (default) or "html" to skip the browser render
 	}
 
 	var req TestRequest
@@ -1289,6 +1751,31 @@ func TestPluginDefinitionHandler(c *gin.Context) {
 		return
 	}
 
+	// Optional bit depth override forces the quantized output depth regardless of the device model
+	if req.BitDepth != 0 && !imageprocessing.IsSupportedBitDepth(req.BitDepth) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bit_depth must be 1, 2, 4, or 8"})
+		return
+	}
+
+	// Optional render time lets time-dependent templates be previewed as of another moment
+	var renderTime time.Time
+	if req.RenderTime != "" {
+		parsed, err := time.Parse(time.RFC3339, req.RenderTime)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "render_time must be an RFC3339 timestamp"})
+			return
+		}
+		renderTime = parsed
+	}
+
+	if req.RenderMode == "" {
+		req.RenderMode = "image"
+	}
+	if req.RenderMode != "image" && req.RenderMode != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "render_mode must be image or html"})
+		return
+	}
+
 	var layoutTemplate string
 	switch req.Layout {
 	case "full":
@@ -1308,20 +1795,7 @@ func TestPluginDefinitionHandler(c *gin.Context) {
 		return
 	}
 
-	// Build template data from polling or sample data
-	var templateData map[string]interface{}
-	if req.Plugin.DataStrategy == "polling" && req.Plugin.PollingConfig != nil {
-		formDefaults := extractFormFieldDefaults(req.Plugin.FormFields)
-		realData, err := getPollingDataForPreview(req.Plugin, formDefaults)
-		if err != nil {
-			logging.Warn("[TestPlugin] Polling failed, using sample data", "error", err)
-			templateData = req.SampleData
-		} else {
-			templateData = realData
-		}
-	} else {
-		templateData = req.SampleData
-	}
+	templateData := resolvePreviewTemplateData(req.Plugin, req.SampleData)
 
 	// Build TRMNL data using shared builder
 	trmnlBuilder := rendering.NewTRNMLDataBuilder()
@@ -1329,6 +1803,8 @@ func TestPluginDefinitionHandler(c *gin.Context) {
 		user,
 		req.DeviceWidth, req.DeviceHeight, req.DeviceBitDepth,
 		req.DeviceModelName, req.Plugin.Name, req.Plugin.DataStrategy,
+		req.Plugin.EnableDarkMode,
+		renderTime,
 		extractFormFieldDefaults(req.Plugin.FormFields),
 	)
 
@@ -1338,6 +1814,39 @@ func TestPluginDefinitionHandler(c *gin.Context) {
 	}
 	finalTemplateData["trmnl"] = trmnlData
 
+	// HTML mode returns the server-side rendered template directly, without queueing a browser render
+	if req.RenderMode == "html" {
+		htmlRenderer, err := private.NewPrivatePluginRenderer(".")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create renderer"})
+			return
+		}
+		renderWidth, renderHeight := rendering.RenderDimensions(req.DeviceWidth, req.DeviceHeight, req.ScreenOrientation)
+		html, err := htmlRenderer.RenderToServerSideHTML(c.Request.Context(), private.RenderOptions{
+			SharedMarkup:      req.Plugin.SharedMarkup,
+			LayoutTemplate:    layoutTemplate,
+			Data:              finalTemplateData,
+			Width:             renderWidth,
+			Height:            renderHeight,
+			PluginName:        req.Plugin.Name,
+			InstanceID:        "preview_html",
+			RemoveBleedMargin: req.Plugin.RemoveBleedMargin,
+			EnableDarkMode:    req.Plugin.EnableDarkMode,
+			Layout:            req.Layout,
+			LayoutWidth:       req.LayoutWidth,
+			LayoutHeight:      req.LayoutHeight,
+			DeviceModelName:   req.DeviceModelName,
+			BitDepth:          req.DeviceBitDepth,
+			ScreenOrientation: req.ScreenOrientation,
+		})
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("Template render failed: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"render_mode": "html", "html": rendering.SignImageProxyURLs(html)})
+		return
+	}
+
 	// Serialize preview data and queue a render job
 	previewData := rendering.PreviewRenderData{
 		SharedMarkup:      req.Plugin.SharedMarkup,
@@ -1346,9 +1855,12 @@ func TestPluginDefinitionHandler(c *gin.Context) {
 		TemplateData:      finalTemplateData,
 		DeviceModelName:   req.DeviceModelName,
 		BitDepth:          req.DeviceBitDepth,
+		OutputBitDepth:    req.BitDepth,
 		ScreenWidth:       req.DeviceWidth,
 		ScreenHeight:      req.DeviceHeight,
 		ScreenOrientation: req.ScreenOrientation,
+		RemoveBleedMargin: req.Plugin.RemoveBleedMargin,
+		EnableDarkMode:    req.Plugin.EnableDarkMode,
 		PluginName:        req.Plugin.Name,
 	}
 
@@ -1379,7 +1891,95 @@ func TestPluginDefinitionHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"job_id": jobID.String()})
 }
 
-// GetPreviewResultHandler polls for the result of a preview render job
+// flattenTemplateVariables collects dotted variable paths for every key in the template data.
+// Arrays contribute their own path plus the paths of their first element using index notation.
+func flattenTemplateVariables(prefix string, value interface{}, paths map[string]bool) {
+	if prefix != "" {
+		paths[prefix] = true
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenTemplateVariables(path, child, paths)
+		}
+	case []interface{}:
+		if len(v) > 0 && prefix != "" {
+			flattenTemplateVariables(prefix+"[0]", v[0], paths)
+		}
+	}
+}
+
+// GetTemplateVariablesHandler returns the variable paths available to a plugin's templates
+func GetTemplateVariablesHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	type VariablesRequest struct {
+		Plugin          TestPlugin             `json:"plugin"`
+		SampleData      map[string]interface{} `json:"sample_data"`
+		DeviceWidth     int                    `json:"device_width"`
+		DeviceHeight    int                    `json:"device_height"`
+		DeviceModelName string                 `json:"device_model_name"`
+		DeviceBitDepth  int                    `json:"device_bit_depth"`
+	}
+
+	var req VariablesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	templateData := resolvePreviewTemplateData(req.Plugin, req.SampleData)
+
+	trmnlBuilder := rendering.NewTRNMLDataBuilder()
+	trmnlData := trmnlBuilder.BuildPreviewData(
+		user,
+		req.DeviceWidth, req.DeviceHeight, req.DeviceBitDepth,
+		req.DeviceModelName, req.Plugin.Name, req.Plugin.DataStrategy,
+		req.Plugin.EnableDarkMode,
+		time.Time{},
+		extractFormFieldDefaults(req.Plugin.FormFields),
+	)
+
+	finalTemplateData := make(map[string]interface{})
+	for key, value := range templateData {
+		finalTemplateData[key] = value
+	}
+	finalTemplateData["trmnl"] = trmnlData
+
+	// Round-trip through JSON so typed values (structs, typed maps and slices) flatten uniformly
+	dataJSON, err := json.Marshal(finalTemplateData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to serialize template data"})
+		return
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(dataJSON, &normalized); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to serialize template data"})
+		return
+	}
+
+	pathSet := make(map[string]bool)
+	flattenTemplateVariables("", normalized, pathSet)
+
+	variables := make([]string, 0, len(pathSet))
+	for path := range pathSet {
+		variables = append(variables, path)
+	}
+	sort.Strings(variables)
+
+	c.JSON(http.StatusOK, gin.H{"variables": variables})
+}
+
+// GetPreviewResultHandler polls for the result of a preview render job. Pass format=webp to get the
+// preview as a lossless WebP, which is smaller to send to the editor; devices always get PNG.
 func GetPreviewResultHandler(c *gin.Context) {
 	_, ok := auth.RequireUser(c)
 	if !ok {
@@ -1392,6 +1992,12 @@ func GetPreviewResultHandler(c *gin.Context) {
 		return
 	}
 
+	format := c.DefaultQuery("format", "png")
+	if format != "png" && format != "webp" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be png or webp"})
+		return
+	}
+
 	db := database.GetDB()
 	var job database.RenderQueue
 	if err := db.Where("id = ? AND is_preview = true", jobID).First(&job).Error; err != nil {
@@ -1405,9 +2011,19 @@ func GetPreviewResultHandler(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Preview image not found"})
 			return
 		}
+		previewPath := job.PreviewImagePath
+		if format == "webp" {
+			previewPath, err = previewWebPPath(job.PreviewImagePath)
+			if err != nil {
+				logging.Error("[PREVIEW] Failed to encode WebP preview", "job_id", job.ID, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode WebP preview"})
+				return
+			}
+		}
 		c.JSON(http.StatusOK, gin.H{
 			"status":      "completed",
-			"preview_url": "/static/" + job.PreviewImagePath,
+			"format":      format,
+			"preview_url": "/static/" + previewPath,
 		})
 
 	case "failed":
@@ -1421,6 +2037,33 @@ func GetPreviewResultHandler(c *gin.Context) {
 	}
 }
 
+// previewWebPPath returns the static path of a WebP copy of a PNG preview, encoding it on first request.
+// The copy sits next to the PNG in the rendered directory, so orphan cleanup removes both together.
+func previewWebPPath(pngPath string) (string, error) {
+	staticDir := config.Get("STATIC_DIR", "./static")
+	webpPath := strings.TrimSuffix(pngPath, filepath.Ext(pngPath)) + ".webp"
+	if _, err := os.Stat(filepath.Join(staticDir, webpPath)); err == nil {
+		return webpPath, nil
+	}
+
+	pngData, err := os.ReadFile(filepath.Join(staticDir, pngPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to read preview: %w", err)
+	}
+	img, err := png.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode preview: %w", err)
+	}
+	webpData, err := imageprocessing.EncodeWebPLossless(img)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(staticDir, webpPath), webpData, 0644); err != nil {
+		return "", fmt.Errorf("failed to save WebP preview: %w", err)
+	}
+	return webpPath, nil
+}
+
 // GetPluginInstanceSchemaDiffHandler returns schema differences for an instance that needs config updates
 func GetPluginInstanceSchemaDiffHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
@@ -1604,6 +2247,7 @@ func CreateMashupHandler(c *gin.Context) {
 		Description string `json:"description" binding:"max=1000"`
 		Layout      string `json:"layout" binding:"required"`
 		Backdrop    bool   `json:"backdrop"`
+		SlotGutter  int    `json:"slot_gutter"` // Pixels between slots
 	}
 
 	var req CreateMashupRequest
@@ -1625,26 +2269,34 @@ func CreateMashupHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid layout", "valid_layouts": validLayouts})
 		return
 	}
+	if req.SlotGutter < 0 || req.SlotGutter > database.MaxMashupSlotGutter {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("slot_gutter must be between 0 and %d pixels", database.MaxMashupSlotGutter)})
+		return
+	}
 
 	db := database.GetDB()
 	mashupService := database.NewMashupService(db)
 
+	slots, _ := mashupService.GetSlotMetadata(req.Layout)
+	if maxSlots := database.MashupMaxSlots(); len(slots) > maxSlots {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Layout %s has %d slots, more than the limit of %d", req.Layout, len(slots), maxSlots), "max_slots": maxSlots})
+		return
+	}
+
 	// Create mashup definition
-	definition, err := mashupService.CreateMashupDefinition(user.ID, req.Name, req.Layout, req.Backdrop)
+	definition, err := mashupService.CreateMashupDefinition(user.ID, req.Name, req.Layout, req.Backdrop, req.SlotGutter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create mashup", "details": err.Error()})
 		return
 	}
 
-	// Get slot metadata for the response
-	slots, _ := mashupService.GetSlotMetadata(req.Layout)
-
 	c.JSON(http.StatusCreated, gin.H{
 		"mashup": gin.H{
 			"id":          definition.ID,
 			"name":        definition.Name,
 			"description": definition.Description,
 			"layout":      req.Layout,
+			"slot_gutter": definition.SlotGutter,
 			"slots":       slots,
 		},
 	})
@@ -1667,6 +2319,7 @@ func UpdateMashupHandler(c *gin.Context) {
 		Name        string `json:"name" binding:"required,min=1,max=255"`
 		Description string `json:"description" binding:"max=1000"`
 		Backdrop    bool   `json:"backdrop"`
+		SlotGutter  *int   `json:"slot_gutter"` // Left unchanged when omitted
 	}
 
 	var req UpdateMashupRequest
@@ -1674,11 +2327,15 @@ func UpdateMashupHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
 		return
 	}
+	if req.SlotGutter != nil && (*req.SlotGutter < 0 || *req.SlotGutter > database.MaxMashupSlotGutter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("slot_gutter must be between 0 and %d pixels", database.MaxMashupSlotGutter)})
+		return
+	}
 
 	db := database.GetDB()
 	mashupService := database.NewMashupService(db)
 
-	if err := mashupService.UpdateMashupDefinition(definitionID, user.ID, req.Name, req.Description, req.Backdrop); err != nil {
+	if err := mashupService.UpdateMashupDefinition(definitionID, user.ID, req.Name, req.Description, req.Backdrop, req.SlotGutter); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update mashup", "details": err.Error()})
 		return
 	}
@@ -1760,6 +2417,7 @@ func AssignMashupChildrenHandler(c *gin.Context) {
 
 	// Convert string UUIDs to UUID type and validate child instances
 	assignments := make(map[string]uuid.UUID)
+	var children []database.PluginInstance
 	mashupService := database.NewMashupService(db)
 
 	for slot, childIDStr := range req.Assignments {
@@ -1788,6 +2446,13 @@ func AssignMashupChildrenHandler(c *gin.Context) {
 		}
 
 		assignments[slot] = childUUID
+		children = append(children, childInstance)
+	}
+
+	warnings, err := database.CheckMashupRenderLoad(children)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	// Assign children to slots
@@ -1803,7 +2468,7 @@ func AssignMashupChildrenHandler(c *gin.Context) {
 		logging.Info("[MASHUP] Updated mashup refresh rate", "mashup", mashupInstance.Name, "new_rate", refreshRate, "old_rate", mashupInstance.RefreshInterval)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Children assigned successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Children assigned successfully", "warnings": warnings})
 }
 
 // GetMashupChildrenHandler returns the current child assignments for a mashup
@@ -1969,7 +2634,8 @@ func AdminGetExternalPluginsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"plugins": result})
 }
 
-// AdminDeleteExternalPluginHandler deletes an external plugin definition and all its instances
+// AdminDeleteExternalPluginHandler deletes an external plugin definition and all its instances.
+// Deletion is refused while active instances exist unless force=true is passed.
 func AdminDeleteExternalPluginHandler(c *gin.Context) {
 	user, ok := auth.RequireUser(c)
 	if !ok {
@@ -1999,6 +2665,10 @@ func AdminDeleteExternalPluginHandler(c *gin.Context) {
 
 	// Use the unified plugin service to delete (handles cascading deletes)
 	pluginService := database.NewUnifiedPluginService(db)
+	if c.Query("force") != "true" && blockDeletionInUse(c, pluginService, pluginID) {
+		return
+	}
+
 	err = pluginService.DeletePluginDefinition(pluginID, nil)
 	if err != nil {
 		logging.Error("Failed to delete external plugin", "plugin_id", pluginID, "error", err)
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// maxWebhookCoalesceSeconds caps the coalescing window that can be configured on a webhook instance
+const maxWebhookCoalesceSeconds = 3600
+
+// webhookCoalescer limits webhook-triggered renders to one per window per plugin instance.
+// The first push in a quiet period renders immediately; later pushes within the window
+// schedule a single trailing render so the latest stored payload is always shown.
+type webhookCoalescer struct {
+	mu            sync.Mutex
+	lastScheduled map[uuid.UUID]time.Time
+	pending       map[uuid.UUID]*time.Timer
+}
+
+var webhookRenderCoalescer = &webhookCoalescer{
+	lastScheduled: make(map[uuid.UUID]time.Time),
+	pending:       make(map[uuid.UUID]*time.Timer),
+}
+
+// schedule requests a render for the instance, returning when it will run
+func (wc *webhookCoalescer) schedule(instanceID uuid.UUID, window time.Duration) time.Time {
+	now := time.Now().UTC()
+	if window <= 0 {
+		ScheduleRenderForInstances([]uuid.UUID{instanceID})
+		return now
+	}
+
+	wc.mu.Lock()
+
+	// A trailing render is already queued and will pick up this payload
+	if _, ok := wc.pending[instanceID]; ok {
+		runAt := wc.lastScheduled[instanceID].Add(window)
+		wc.mu.Unlock()
+		return runAt
+	}
+
+	last, ok := wc.lastScheduled[instanceID]
+	if !ok || now.Sub(last) >= window {
+		wc.lastScheduled[instanceID] = now
+		wc.mu.Unlock()
+		ScheduleRenderForInstances([]uuid.UUID{instanceID})
+		return now
+	}
+
+	runAt := last.Add(window)
+	wc.pending[instanceID] = time.AfterFunc(runAt.Sub(now), func() {
+		wc.mu.Lock()
+		delete(wc.pending, instanceID)
+		wc.lastScheduled[instanceID] = time.Now().UTC()
+		wc.mu.Unlock()
+
+		logging.Debug("[WEBHOOK] Running coalesced render", "plugin_instance_id", instanceID)
+		ScheduleRenderForInstances([]uuid.UUID{instanceID})
+	})
+	wc.mu.Unlock()
+
+	return runAt
+}
+
+// scheduleWebhookRender schedules a render for a webhook push, coalescing bursts when the
+// instance has a coalescing window configured
+func scheduleWebhookRender(instanceID uuid.UUID, coalesceSeconds int) time.Time {
+	return webhookRenderCoalescer.schedule(instanceID, time.Duration(coalesceSeconds)*time.Second)
+}
@@ -1,19 +1,28 @@
 package handlers
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/auth"
+	"github.com/rmitchellscott/stationmaster/internal/config"
 	"github.com/rmitchellscott/stationmaster/internal/database"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/plugins/private"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+	qrcode "github.com/skip2/go-qrcode"
 )
 
 // WebhookHandler handles webhook data submission for private plugin instances
-// Rate limiting and request size limiting should be applied via middleware before calling this handler
+// Rate limiting and request size limiting should be applied via middleware before calling this handler.
+// With ?wait=true&device_id=... the response waits for the triggered render and includes the device's image URL.
 func WebhookHandler(c *gin.Context) {
 	// Get plugin instance from context (set by rate limiting middleware)
 	pluginInstanceInterface, exists := c.Get("plugin_instance")
@@ -29,6 +38,12 @@ func WebhookHandler(c *gin.Context) {
 		return
 	}
 
+	// Optionally hold the response until the triggered render is done
+	wait, ok := parseWebhookRenderWait(c, pluginInstance)
+	if !ok {
+		return
+	}
+
 	// Read request body
 	bodyBytes, err := io.ReadAll(c.Request.Body)
 	if err != nil {
@@ -37,9 +52,27 @@ func WebhookHandler(c *gin.Context) {
 		return
 	}
 
+	contentType := c.GetHeader("Content-Type")
+
+	// Image plugins take the raw image body instead of merge variables
+	var pluginDefinition database.PluginDefinition
+	if err := database.GetDB().Where("id = ?", pluginInstance.PluginDefinitionID).First(&pluginDefinition).Error; err != nil {
+		logging.Error("[WEBHOOK] Failed to load plugin definition", "error", err, "plugin_instance_id", pluginInstance.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load plugin definition"})
+		return
+	}
+	isImagePlugin := pluginDefinition.DataStrategy != nil && *pluginDefinition.DataStrategy == private.DataStrategyImage
+	if isImagePlugin {
+		handleImageWebhook(c, pluginInstance, wait, contentType, bodyBytes)
+		return
+	}
+	if isImageContentType(contentType, bodyBytes) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "This plugin does not accept image data. Use the image data strategy to push images"})
+		return
+	}
+
 	// Parse JSON data
 	var webhookPayload map[string]interface{}
-	contentType := c.GetHeader("Content-Type")
 	
 	if contentType == "application/json" || contentType == "" {
 		if err := json.Unmarshal(bodyBytes, &webhookPayload); err != nil {
@@ -63,22 +96,27 @@ func WebhookHandler(c *gin.Context) {
 		return
 	}
 
-	// Determine merge strategy
+	// Determine merge strategy, preferring ?merge_strategy= over the payload's merge_strategy
 	mergeStrategy := "default"
 	if strategy, ok := webhookPayload["merge_strategy"].(string); ok {
 		mergeStrategy = strategy
 	}
+	if strategy, ok := c.GetQuery("merge_strategy"); ok {
+		mergeStrategy = strategy
+	}
 
 	// Validate merge strategy
 	validStrategies := map[string]bool{
 		"default":    true,
 		"":           true, // Empty means default
+		"replace":    true, // Same as default
+		"merge":      true,
 		"deep_merge": true,
 		"stream":     true,
 	}
 	if !validStrategies[mergeStrategy] {
 		logging.Warn("[WEBHOOK] Invalid merge strategy", "strategy", mergeStrategy, "plugin_instance_id", pluginInstance.ID, "ip", c.ClientIP())
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid merge strategy: %s. Valid options: default, deep_merge, stream", mergeStrategy)})
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid merge strategy: %s. Valid options: replace, merge, deep_merge, stream", mergeStrategy)})
 		return
 	}
 
@@ -111,19 +149,103 @@ func WebhookHandler(c *gin.Context) {
 		return
 	}
 
+	renderAt := scheduleWebhookRender(pluginInstance.ID, pluginInstance.WebhookCoalesceSeconds)
+
 	logging.Info("[WEBHOOK] Data received and processed successfully", 
 		"plugin_instance_id", pluginInstance.ID, 
 		"plugin_instance_name", pluginInstance.Name,
 		"merge_strategy", mergeStrategy,
 		"content_size", len(bodyBytes),
+		"render_at", renderAt,
 		"ip", c.ClientIP())
 
-	c.JSON(http.StatusOK, gin.H{
+	respondWebhook(c, wait, pluginInstance, webhookRecord.ReceivedAt, gin.H{
 		"message":            "Webhook data received successfully",
 		"plugin_instance_id": pluginInstance.ID,
 		"merge_strategy":     mergeStrategy,
 		"received_at":        webhookRecord.ReceivedAt,
+		"render_at":          renderAt,
+		"size":               len(bodyBytes),
+	})
+}
+
+// isImageContentType reports whether a webhook body is an image, sniffing the body when no specific type was sent
+func isImageContentType(contentType string, body []byte) bool {
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(body)
+	}
+	return strings.HasPrefix(contentType, "image/")
+}
+
+// handleImageWebhook stores an image pushed to an image plugin instance and schedules a render with it
+func handleImageWebhook(c *gin.Context, pluginInstance *database.PluginInstance, wait *webhookRenderWait, contentType string, bodyBytes []byte) {
+	if !isImageContentType(contentType, bodyBytes) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Image plugins only accept PNG or JPEG image data"})
+		return
+	}
+
+	width, height, err := private.StoreWebhookImage(c.Request.Context(), pluginInstance.ID, bodyBytes)
+	if errors.Is(err, private.ErrWebhookImageTooLarge) {
+		logging.Warn("[WEBHOOK] Image dimensions too large", "error", err, "plugin_instance_id", pluginInstance.ID, "ip", c.ClientIP())
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Image dimensions are too large. Send an image close to the device's screen size"})
+		return
+	}
+	if err != nil {
+		logging.Warn("[WEBHOOK] Invalid image data", "error", err, "plugin_instance_id", pluginInstance.ID, "ip", c.ClientIP())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image data. Send a PNG or JPEG image"})
+		return
+	}
+
+	// Keep a record of the upload so webhook info reports when an image was last received
+	rawDataJSON, err := json.Marshal(map[string]interface{}{
+		"merge_variables": map[string]interface{}{
+			"image_content_type": contentType,
+			"image_width":        width,
+			"image_height":       height,
+		},
+	})
+	if err != nil {
+		logging.Error("[WEBHOOK] Failed to marshal image metadata", "error", err, "plugin_instance_id", pluginInstance.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process webhook data"})
+		return
+	}
+
+	webhookRecord := &database.PrivatePluginWebhookData{
+		ID:               pluginInstance.ID.String() + "_webhook_data",
+		PluginInstanceID: pluginInstance.ID.String(),
+		RawData:          rawDataJSON,
+		MergeStrategy:    "default",
+		ReceivedAt:       time.Now().UTC(),
+		ContentType:      contentType,
+		ContentSize:      len(bodyBytes),
+		SourceIP:         c.ClientIP(),
+	}
+
+	webhookService := database.NewWebhookService(database.GetDB())
+	if err := webhookService.StoreWebhookData(webhookRecord); err != nil {
+		logging.Error("[WEBHOOK] Failed to store webhook image metadata", "error", err, "plugin_instance_id", pluginInstance.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store webhook data"})
+		return
+	}
+
+	renderAt := scheduleWebhookRender(pluginInstance.ID, pluginInstance.WebhookCoalesceSeconds)
+
+	logging.Info("[WEBHOOK] Image received and stored successfully",
+		"plugin_instance_id", pluginInstance.ID,
+		"plugin_instance_name", pluginInstance.Name,
+		"width", width,
+		"height", height,
+		"content_size", len(bodyBytes),
+		"ip", c.ClientIP())
+
+	respondWebhook(c, wait, pluginInstance, webhookRecord.ReceivedAt, gin.H{
+		"message":            "Webhook image received successfully",
+		"plugin_instance_id": pluginInstance.ID,
+		"received_at":        webhookRecord.ReceivedAt,
+		"render_at":          renderAt,
 		"size":               len(bodyBytes),
+		"width":              width,
+		"height":             height,
 	})
 }
 
@@ -151,4 +273,75 @@ func GetWebhookDataHandler(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"webhook_data": webhookData})
-}
\ No newline at end of file
+}
+
+// GetWebhookInfoHandler returns the absolute webhook URL for a private plugin instance
+// along with a QR code so external data sources can be connected without building the URL by hand
+func GetWebhookInfoHandler(c *gin.Context) {
+	user, ok := auth.RequireUser(c)
+	if !ok {
+		return
+	}
+
+	instanceID := c.Param("id")
+	if instanceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Instance ID is required"})
+		return
+	}
+
+	db := database.GetDB()
+
+	var instance database.PluginInstance
+	if err := db.Preload("PluginDefinition").Where("id = ? AND user_id = ?", instanceID, user.ID).First(&instance).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plugin instance not found"})
+		return
+	}
+
+	dataStrategy := ""
+	if instance.PluginDefinition.DataStrategy != nil {
+		dataStrategy = *instance.PluginDefinition.DataStrategy
+	}
+	if instance.PluginDefinition.PluginType != "private" || (dataStrategy != "webhook" && dataStrategy != private.DataStrategyImage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Plugin instance does not use the webhook data strategy"})
+		return
+	}
+	contentType := "application/json"
+	if dataStrategy == private.DataStrategyImage {
+		contentType = "image/png, image/jpeg"
+	}
+
+	baseURL := strings.TrimSuffix(config.Get("SITE_URL", ""), "/")
+	if baseURL == "" {
+		baseURL = utils.BaseURLFromRequest(c.Request)
+	}
+	webhookURL := fmt.Sprintf("%s/api/webhooks/instance/%s", baseURL, instance.ID.String())
+
+	qrPNG, err := qrcode.Encode(webhookURL, qrcode.Medium, 256)
+	if err != nil {
+		logging.Error("[WEBHOOK] Failed to generate QR code", "error", err, "plugin_instance_id", instance.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate QR code"})
+		return
+	}
+
+	webhookService := database.NewWebhookService(db)
+	webhookData, err := webhookService.GetLatestWebhookData(instance.ID.String())
+	if err != nil {
+		logging.Warn("[WEBHOOK] Failed to get webhook data for info", "error", err, "plugin_instance_id", instance.ID)
+	}
+
+	var lastReceivedAt *time.Time
+	if webhookData != nil {
+		lastReceivedAt = &webhookData.ReceivedAt
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"plugin_instance_id": instance.ID,
+		"webhook_url":        webhookURL,
+		"method":             "POST",
+		"content_type":       contentType,
+		"authentication":     "instance_id", // No separate secret is issued; the unguessable instance ID authenticates
+		"is_active":          instance.IsActive,
+		"last_received_at":   lastReceivedAt,
+		"qr_code":            "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrPNG),
+	})
+}
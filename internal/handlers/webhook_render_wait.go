@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+)
+
+const (
+	defaultWebhookWaitSeconds = 10
+	maxWebhookWaitSeconds     = 30
+	webhookWaitPollInterval   = 250 * time.Millisecond
+)
+
+// webhookRenderWait is a webhook push's request to wait for the render it triggers
+type webhookRenderWait struct {
+	device  *database.Device
+	timeout time.Duration
+}
+
+// parseWebhookRenderWait reads ?wait=true&device_id=...&timeout=... from a webhook request. It returns
+// nil when the caller doesn't want to wait, and writes a 400 response when the parameters are invalid.
+func parseWebhookRenderWait(c *gin.Context, pluginInstance *database.PluginInstance) (*webhookRenderWait, bool) {
+	if wait, _ := strconv.ParseBool(c.Query("wait")); !wait {
+		return nil, true
+	}
+
+	device, ok := webhookDeviceParam(c, pluginInstance)
+	if !ok {
+		return nil, false
+	}
+
+	seconds := defaultWebhookWaitSeconds
+	if timeoutStr := c.Query("timeout"); timeoutStr != "" {
+		parsed, err := strconv.Atoi(timeoutStr)
+		if err != nil || parsed < 1 || parsed > maxWebhookWaitSeconds {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "timeout must be between 1 and 30 seconds"})
+			return nil, false
+		}
+		seconds = parsed
+	}
+
+	return &webhookRenderWait{device: device, timeout: time.Duration(seconds) * time.Second}, true
+}
+
+// webhookDeviceParam loads the device named by ?device_id=, which must belong to the instance's owner
+func webhookDeviceParam(c *gin.Context, pluginInstance *database.PluginInstance) (*database.Device, bool) {
+	deviceID, err := uuid.Parse(c.Query("device_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "device_id is required to wait for a render"})
+		return nil, false
+	}
+	device, err := database.NewDeviceService(database.GetDB()).GetDeviceByID(deviceID)
+	if err != nil || device.UserID == nil || *device.UserID != pluginInstance.UserID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Device not found"})
+		return nil, false
+	}
+	return device, true
+}
+
+// respondWebhook sends a webhook response, first waiting for the triggered render when requested. The
+// render job is looked up by the time the push was received, so coalesced renders are found too.
+func respondWebhook(c *gin.Context, wait *webhookRenderWait, pluginInstance *database.PluginInstance, receivedAt time.Time, response gin.H) {
+	if wait == nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	deadline := time.Now().Add(wait.timeout)
+	for {
+		status := webhookRenderStatus(c, pluginInstance, wait.device, receivedAt, nil)
+		for key, value := range status {
+			response[key] = value
+		}
+		if status["render_status"] != "pending" {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+		if time.Now().After(deadline) || c.Request.Context().Err() != nil {
+			// The caller can keep polling the job instead
+			c.JSON(http.StatusAccepted, response)
+			return
+		}
+		time.Sleep(webhookWaitPollInterval)
+	}
+}
+
+// webhookRenderStatus reports the render triggered after receivedAt: pending, completed with the device's
+// image URL, or failed. A specific job is checked when jobID is set.
+func webhookRenderStatus(c *gin.Context, pluginInstance *database.PluginInstance, device *database.Device, receivedAt time.Time, jobID *uuid.UUID) gin.H {
+	db := database.GetDB()
+	status := gin.H{"render_status": "pending", "device_id": device.ID}
+
+	var job database.RenderQueue
+	query := db.Where("plugin_instance_id = ?", pluginInstance.ID)
+	if jobID != nil {
+		query = query.Where("id = ?", *jobID)
+	} else {
+		query = query.Where("created_at >= ?", receivedAt)
+	}
+	if err := query.Order("created_at DESC").First(&job).Error; err != nil {
+		// Coalesced pushes don't create a job until their window ends
+		return status
+	}
+	status["job_id"] = job.ID
+	if jobID != nil {
+		receivedAt = job.CreatedAt
+	}
+
+	switch job.Status {
+	case "failed":
+		status["render_status"] = "failed"
+		status["error"] = job.ErrorMessage
+		return status
+	case "completed", "cancelled":
+		// Cancelled jobs were superseded by another render of the same instance
+	default:
+		return status
+	}
+
+	content, ok := freshRenderedContent(pluginInstance.ID, device, receivedAt)
+	if !ok {
+		if job.Status == "completed" {
+			status["render_status"] = "completed"
+			status["image_url"] = nil
+			status["message"] = "The render completed but produced no image for this device. Is the instance in its playlist?"
+		}
+		return status
+	}
+
+	status["render_status"] = "completed"
+	status["rendered_at"] = content.RenderedAt
+	status["image_url"] = content.ImagePath
+	if !strings.HasPrefix(content.ImagePath, "http://") && !strings.HasPrefix(content.ImagePath, "https://") {
+		status["image_url"] = utils.BaseURLFromRequest(c.Request) + "/static/rendered/" + filepath.Base(content.ImagePath)
+	}
+	return status
+}
+
+// freshRenderedContent returns the device's rendered content for an instance if it was rendered, or
+// checked and found unchanged, at or after since
+func freshRenderedContent(instanceID uuid.UUID, device *database.Device, since time.Time) (*database.RenderedContent, bool) {
+	db := database.GetDB()
+	var content database.RenderedContent
+	err := db.Where("plugin_instance_id = ? AND device_id = ?", instanceID, device.ID).
+		Order("rendered_at DESC").First(&content).Error
+	if err != nil && device.DeviceModel != nil {
+		err = db.Where("plugin_instance_id = ? AND device_id IS NULL AND width = ? AND height = ? AND bit_depth = ?",
+			instanceID, device.DeviceModel.ScreenWidth, device.DeviceModel.ScreenHeight, device.DeviceModel.BitDepth).
+			Order("rendered_at DESC").First(&content).Error
+	}
+	if err != nil {
+		return nil, false
+	}
+	if content.RenderedAt.Before(since) && (content.LastCheckedAt == nil || content.LastCheckedAt.Before(since)) {
+		return nil, false
+	}
+	return &content, true
+}
+
+// GetWebhookRenderStatusHandler reports on a render job started by a webhook push, for callers whose
+// wait timed out. Like the webhook itself, the instance ID authenticates the request.
+// GET /api/webhooks/instance/:id/renders/:jobId?device_id=...
+func GetWebhookRenderStatusHandler(c *gin.Context) {
+	var pluginInstance database.PluginInstance
+	if err := database.GetDB().Where("id = ? AND is_active = ?", c.Param("id"), true).First(&pluginInstance).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid instance ID"})
+		return
+	}
+
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	device, ok := webhookDeviceParam(c, &pluginInstance)
+	if !ok {
+		return
+	}
+
+	status := webhookRenderStatus(c, &pluginInstance, device, time.Time{}, &jobID)
+	if _, found := status["job_id"]; !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Render job not found"})
+		return
+	}
+	status["plugin_instance_id"] = pluginInstance.ID
+	c.JSON(http.StatusOK, status)
+}
@@ -160,4 +160,29 @@ func QuantizeToGrayscalePalette(img image.Image, bitDepth int) *image.Paletted {
 	}
 	
 	return paletted
-}
\ No newline at end of file
+}
+// QuantizeToGrayscaleLevels quantizes an image to a custom number of gray levels within the bit depth,
+// without dithering. A levels value of 0 or the bit depth's full count behaves like QuantizeToGrayscalePalette.
+func QuantizeToGrayscaleLevels(img image.Image, bitDepth, levels int) *image.Paletted {
+	if img == nil {
+		return nil
+	}
+	if levels <= 0 || levels >= GetColorLevels(bitDepth) || !ValidGrayscaleLevels(bitDepth, levels) {
+		return QuantizeToGrayscalePalette(img, bitDepth)
+	}
+
+	grayscale := ToGrayscale(img)
+	palette := GrayscaleLevelsPalette(bitDepth, levels)
+
+	bounds := grayscale.Bounds()
+	paletted := image.NewPaletted(bounds, palette)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray := grayscale.At(x, y).(color.Gray)
+			// Even bands like QuantizeColor, mapped onto the custom palette
+			index := int(gray.Y) * levels / 256
+			paletted.SetColorIndex(x, y, uint8(index))
+		}
+	}
+	return paletted
+}
@@ -0,0 +1,50 @@
+package imageprocessing
+
+import (
+	"image"
+	"image/color"
+)
+
+// ChangedBounds returns the smallest rectangle containing every pixel that differs between two images,
+// relative to the images' top-left corner. Pixels are compared by gray level. It returns false when the
+// images aren't the same size, and an empty rectangle when they are identical.
+func ChangedBounds(previous, next image.Image) (image.Rectangle, bool) {
+	if previous == nil || next == nil {
+		return image.Rectangle{}, false
+	}
+
+	pb := previous.Bounds()
+	nb := next.Bounds()
+	if pb.Dx() != nb.Dx() || pb.Dy() != nb.Dy() {
+		return image.Rectangle{}, false
+	}
+
+	minX, minY := nb.Dx(), nb.Dy()
+	maxX, maxY := -1, -1
+	for y := 0; y < nb.Dy(); y++ {
+		for x := 0; x < nb.Dx(); x++ {
+			prevGray := color.GrayModel.Convert(previous.At(pb.Min.X+x, pb.Min.Y+y)).(color.Gray)
+			nextGray := color.GrayModel.Convert(next.At(nb.Min.X+x, nb.Min.Y+y)).(color.Gray)
+			if prevGray.Y == nextGray.Y {
+				continue
+			}
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if y > maxY {
+				maxY = y
+			}
+		}
+	}
+
+	if maxX < 0 {
+		return image.Rectangle{}, true
+	}
+	return image.Rect(minX, minY, maxX+1, maxY+1), true
+}
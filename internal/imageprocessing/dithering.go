@@ -9,12 +9,17 @@ import (
 
 // DitherFloydSteinberg applies Floyd-Steinberg dithering using the high-quality dither library
 func DitherFloydSteinberg(img image.Image, bitDepth int) image.Image {
+	return DitherFloydSteinbergLevels(img, bitDepth, 0)
+}
+
+// DitherFloydSteinbergLevels dithers to the given number of gray levels, or every level of the bit depth when levels is 0
+func DitherFloydSteinbergLevels(img image.Image, bitDepth, levels int) image.Image {
 	if img == nil {
 		return nil
 	}
 
-	// Create appropriate color palette based on bit depth
-	palette := createGrayscalePalette(bitDepth)
+	// Create appropriate color palette based on bit depth and level count
+	palette := GrayscaleLevelsPalette(bitDepth, levels)
 	
 	// Create a Floyd-Steinberg ditherer with the palette
 	ditherer := dither.NewDitherer(palette)
@@ -42,4 +47,26 @@ func createGrayscalePalette(bitDepth int) color.Palette {
 	}
 	
 	return palette
-}
\ No newline at end of file
+}
+
+// ValidGrayscaleLevels reports whether a gray level count fits the bit depth. 0 means the bit depth's default.
+func ValidGrayscaleLevels(bitDepth, levels int) bool {
+	return levels == 0 || (levels >= 2 && levels <= GetColorLevels(bitDepth))
+}
+
+// GrayscaleLevelsPalette returns a palette of evenly spread gray levels that the bit depth can represent
+// exactly, or the bit depth's full palette when levels is 0 or out of range
+func GrayscaleLevelsPalette(bitDepth, levels int) color.Palette {
+	maxLevels := GetColorLevels(bitDepth)
+	if levels <= 0 || levels >= maxLevels || !ValidGrayscaleLevels(bitDepth, levels) {
+		return createGrayscalePalette(bitDepth)
+	}
+
+	palette := make(color.Palette, levels)
+	for i := 0; i < levels; i++ {
+		// Snap each level to the nearest sample value of the bit depth so PNG encoding is lossless
+		sample := (i*(maxLevels-1) + (levels-1)/2) / (levels - 1)
+		palette[i] = color.Gray{Y: uint8(sample * 255 / (maxLevels - 1))}
+	}
+	return palette
+}
@@ -7,8 +7,18 @@ import (
 	"fmt"
 	"hash/crc32"
 	"image"
+	"image/color"
 )
 
+// IsSupportedBitDepth reports whether bitDepth can be written by EncodePalettedPNG
+func IsSupportedBitDepth(bitDepth int) bool {
+	switch bitDepth {
+	case 1, 2, 4, 8:
+		return true
+	}
+	return false
+}
+
 // EncodePalettedPNG encodes a paletted image to PNG as grayscale with the correct bit depth
 // This mimics ImageMagick's approach: PNG color type 0 (grayscale) instead of palette
 func EncodePalettedPNG(img image.Image, bitDepth int) ([]byte, error) {
@@ -22,7 +32,7 @@ func EncodePalettedPNG(img image.Image, bitDepth int) ([]byte, error) {
 	height := bounds.Dy()
 
 	// Validate bit depth
-	if bitDepth != 1 && bitDepth != 2 && bitDepth != 4 && bitDepth != 8 {
+	if !IsSupportedBitDepth(bitDepth) {
 		return nil, fmt.Errorf("unsupported bit depth: %d", bitDepth)
 	}
 
@@ -81,8 +91,14 @@ func packGrayscaleImageData(paletted *image.Paletted, bitDepth int) ([]byte, err
 	// Add filter byte at the start of each row
 	data := make([]byte, height*(bytesPerRow+1))
 	
-	// Note: We use palette index directly as grayscale level since our dithering
-	// creates palettes with the correct grayscale values at each index
+	// Map each palette entry to its sample value at this bit depth. For the full bit depth palettes
+	// this is the palette index itself; reduced level palettes skip samples.
+	maxSample := GetColorLevels(bitDepth) - 1
+	samples := make([]uint8, len(paletted.Palette))
+	for i, c := range paletted.Palette {
+		gray := color.GrayModel.Convert(c).(color.Gray)
+		samples[i] = uint8((int(gray.Y)*maxSample + 127) / 255)
+	}
 	
 	for y := 0; y < height; y++ {
 		rowStart := y * (bytesPerRow + 1)
@@ -92,9 +108,7 @@ func packGrayscaleImageData(paletted *image.Paletted, bitDepth int) ([]byte, err
 			// Get palette index
 			pixelIndex := paletted.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y)
 			
-			// Use palette index directly as grayscale level since our dithering 
-			// already created the palette with the correct grayscale values at each index
-			grayLevel := pixelIndex
+			grayLevel := samples[pixelIndex]
 			
 			byteIndex := rowStart + 1 + x/pixelsPerByte
 			bitOffset := (pixelsPerByte - 1 - (x % pixelsPerByte)) * bitDepth
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"image"
 	"image/draw"
+	_ "image/gif"  // Register GIF decoder
 	_ "image/jpeg" // Register JPEG decoder
 	_ "image/png"  // Register PNG decoder
 	"net/http"
 	"time"
 
+	_ "golang.org/x/image/webp" // Register WebP decoder
+
 	"github.com/rmitchellscott/stationmaster/internal/database"
 	"github.com/rmitchellscott/stationmaster/internal/utils"
 )
@@ -46,7 +49,7 @@ func ProcessForDeviceWithOptions(img image.Image, deviceModel *database.DeviceMo
 	grayscale := ToGrayscale(resized)
 
 	// Step 3: Apply Floyd-Steinberg dithering based on device bit depth
-	dithered := DitherFloydSteinberg(grayscale, deviceModel.BitDepth)
+	dithered := DitherFloydSteinbergLevels(grayscale, deviceModel.BitDepth, deviceModel.GrayscaleLevels)
 
 	return dithered, nil
 }
@@ -56,7 +59,7 @@ func LoadImageFromURL(url string, timeout time.Duration) (image.Image, string, e
 		return nil, "", fmt.Errorf("URL validation failed: %w", err)
 	}
 
-	client := &http.Client{Timeout: timeout}
+	client := utils.NewHTTPClient(timeout)
 	
 	resp, err := client.Get(url)
 	if err != nil {
@@ -68,11 +71,21 @@ func LoadImageFromURL(url string, timeout time.Duration) (image.Image, string, e
 		return nil, "", fmt.Errorf("failed to download image: HTTP %d", resp.StatusCode)
 	}
 
+	// Servers that omit Content-Type are still held to the allowlist through the decoded format below
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		if err := utils.ValidateImageMIMEType(contentType); err != nil {
+			return nil, "", fmt.Errorf("rejected image: %w", err)
+		}
+	}
+
 	// Try to decode the image directly from the response body
 	img, format, err := image.Decode(resp.Body)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to decode image: %w", err)
 	}
+	if err := utils.ValidateImageFormat(format); err != nil {
+		return nil, "", fmt.Errorf("rejected image: %w", err)
+	}
 
 	return img, format, nil
 }
@@ -141,4 +141,29 @@ func ResizeToFill(img image.Image, targetWidth, targetHeight int) image.Image {
 	draw.Draw(canvas, canvas.Bounds(), resized, srcRect.Min, draw.Src)
 
 	return canvas
-}
\ No newline at end of file
+}
+
+// InsetImage scales an image into the area left after the given padding and draws it onto a white
+// canvas of the original size, so content clears bezels that overlap the screen edges
+func InsetImage(img image.Image, top, right, bottom, left int) image.Image {
+	if img == nil {
+		return nil
+	}
+
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+	innerWidth := width - left - right
+	innerHeight := height - top - bottom
+	if innerWidth <= 0 || innerHeight <= 0 {
+		return img
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+
+	targetRect := image.Rect(left, top, left+innerWidth, top+innerHeight)
+	xdraw.BiLinear.Scale(canvas, targetRect, img, bounds, xdraw.Over, nil)
+
+	return canvas
+}
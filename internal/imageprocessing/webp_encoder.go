@@ -0,0 +1,518 @@
+package imageprocessing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+)
+
+// Lossless WebP (VP8L) encoding. The encoder uses the subtract-green transform, which turns the red and
+// blue channels of grayscale images into constants that cost no bits, and LZ77 backward references over
+// a single set of prefix codes. That is enough to make e-ink renders smaller than their PNGs without the
+// size of a full encoder.
+
+const (
+	vp8lSignature      = 0x2f
+	vp8lMaxDimension   = 1 << 14
+	vp8lNumLiterals    = 256
+	vp8lNumLengthCodes = 24
+	vp8lNumDistCodes   = 40
+	vp8lMaxCodeLength  = 15
+	// Code-length codes are written with 3-bit lengths
+	vp8lMaxCodeLengthCodeLength = 7
+
+	// Distance codes up to 120 refer to nearby pixels in 2D; larger codes are a plain distance plus 120
+	vp8lDistanceCodeOffset = 120
+	vp8lMinMatch           = 3
+	vp8lMaxMatch           = 4096
+	// Distances must fit prefix code 39, whose largest value is 3<<18 + 2^18
+	vp8lMaxDistance = 1<<20 - vp8lDistanceCodeOffset
+	vp8lHashBits    = 16
+	vp8lMaxChain    = 32
+)
+
+// Order in which code-length code lengths are written
+var vp8lCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// EncodeWebPLossless encodes an image as a lossless WebP
+func EncodeWebPLossless(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= 0 || height <= 0 || width > vp8lMaxDimension || height > vp8lMaxDimension {
+		return nil, fmt.Errorf("unsupported WebP dimensions: %dx%d", width, height)
+	}
+
+	argb := make([]uint32, width*height)
+	hasAlpha := false
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			if c.A != 0xff {
+				hasAlpha = true
+			}
+			// Subtract-green transform
+			r := c.R - c.G
+			b := c.B - c.G
+			argb[y*width+x] = uint32(c.A)<<24 | uint32(r)<<16 | uint32(c.G)<<8 | uint32(b)
+		}
+	}
+
+	w := &vp8lBitWriter{}
+	w.writeBits(vp8lSignature, 8)
+	w.writeBits(uint32(width-1), 14)
+	w.writeBits(uint32(height-1), 14)
+	if hasAlpha {
+		w.writeBits(1, 1)
+	} else {
+		w.writeBits(0, 1)
+	}
+	w.writeBits(0, 3) // Version
+
+	// One transform: subtract green
+	w.writeBits(1, 1)
+	w.writeBits(2, 2)
+	w.writeBits(0, 1)
+
+	w.writeBits(0, 1) // No color cache
+	w.writeBits(0, 1) // No meta prefix codes
+
+	tokens := vp8lBackwardReferences(argb, width)
+
+	var histograms [5][]int
+	histograms[0] = make([]int, vp8lNumLiterals+vp8lNumLengthCodes)
+	histograms[1] = make([]int, vp8lNumLiterals)
+	histograms[2] = make([]int, vp8lNumLiterals)
+	histograms[3] = make([]int, vp8lNumLiterals)
+	histograms[4] = make([]int, vp8lNumDistCodes)
+	for _, t := range tokens {
+		if t.length == 0 {
+			histograms[0][(t.argb>>8)&0xff]++
+			histograms[1][(t.argb>>16)&0xff]++
+			histograms[2][t.argb&0xff]++
+			histograms[3][t.argb>>24]++
+			continue
+		}
+		lengthCode, _, _ := vp8lPrefixEncode(t.length)
+		distCode, _, _ := vp8lPrefixEncode(t.distCode)
+		histograms[0][vp8lNumLiterals+lengthCode]++
+		histograms[4][distCode]++
+	}
+
+	var codes [5]vp8lPrefixCode
+	for i := range histograms {
+		codes[i] = w.writePrefixCode(histograms[i])
+	}
+
+	for _, t := range tokens {
+		if t.length == 0 {
+			codes[0].write(w, int((t.argb>>8)&0xff))
+			codes[1].write(w, int((t.argb>>16)&0xff))
+			codes[2].write(w, int(t.argb&0xff))
+			codes[3].write(w, int(t.argb>>24))
+			continue
+		}
+		lengthCode, lengthBits, lengthExtra := vp8lPrefixEncode(t.length)
+		codes[0].write(w, vp8lNumLiterals+lengthCode)
+		w.writeBits(lengthExtra, lengthBits)
+		distCode, distBits, distExtra := vp8lPrefixEncode(t.distCode)
+		codes[4].write(w, distCode)
+		w.writeBits(distExtra, distBits)
+	}
+
+	data := w.bytes()
+
+	var buf bytes.Buffer
+	chunkSize := len(data)
+	padding := chunkSize & 1
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+chunkSize+padding))
+	buf.WriteString("WEBP")
+	buf.WriteString("VP8L")
+	binary.Write(&buf, binary.LittleEndian, uint32(chunkSize))
+	buf.Write(data)
+	if padding == 1 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes(), nil
+}
+
+// vp8lToken is a literal pixel, or a backward reference when length is non-zero
+type vp8lToken struct {
+	argb     uint32
+	length   int
+	distCode int
+}
+
+// vp8lBackwardReferences greedily replaces repeated pixel runs with backward references, trying the row
+// above first since rendered screens repeat vertically far more often than anywhere else
+func vp8lBackwardReferences(argb []uint32, width int) []vp8lToken {
+	n := len(argb)
+	tokens := make([]vp8lToken, 0, n/4)
+
+	head := make([]int32, 1<<vp8lHashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	prev := make([]int32, n)
+
+	hash := func(i int) uint32 {
+		h := argb[i]*0x1e35a7bd ^ argb[i+1]*0x9e3779b1 ^ argb[i+2]*0x85ebca6b
+		return h >> (32 - vp8lHashBits)
+	}
+	insert := func(i int) {
+		if i+vp8lMinMatch > n {
+			return
+		}
+		h := hash(i)
+		prev[i] = head[h]
+		head[h] = int32(i)
+	}
+	matchLength := func(i, candidate int) int {
+		limit := n - i
+		if limit > vp8lMaxMatch {
+			limit = vp8lMaxMatch
+		}
+		length := 0
+		for length < limit && argb[candidate+length] == argb[i+length] {
+			length++
+		}
+		return length
+	}
+
+	for i := 0; i < n; {
+		bestLength, bestDistance := 0, 0
+		if i+vp8lMinMatch <= n {
+			if i >= width {
+				bestLength = matchLength(i, i-width)
+				bestDistance = width
+			}
+			if bestLength < vp8lMaxMatch {
+				candidate := head[hash(i)]
+				for chain := 0; candidate >= 0 && chain < vp8lMaxChain; chain++ {
+					distance := i - int(candidate)
+					if distance > vp8lMaxDistance {
+						break
+					}
+					if length := matchLength(i, int(candidate)); length > bestLength {
+						bestLength, bestDistance = length, distance
+						if length == vp8lMaxMatch {
+							break
+						}
+					}
+					candidate = prev[candidate]
+				}
+			}
+		}
+
+		if bestLength < vp8lMinMatch {
+			tokens = append(tokens, vp8lToken{argb: argb[i]})
+			insert(i)
+			i++
+			continue
+		}
+
+		tokens = append(tokens, vp8lToken{length: bestLength, distCode: vp8lDistanceCode(bestDistance, width)})
+		for j := i; j < i+bestLength; j++ {
+			insert(j)
+		}
+		i += bestLength
+	}
+	return tokens
+}
+
+// vp8lDistanceCode maps a pixel distance to a distance code, using the short codes for the pixel above
+// and the pixel to the left
+func vp8lDistanceCode(distance, width int) int {
+	switch distance {
+	case width:
+		return 1
+	case 1:
+		return 2
+	}
+	return distance + vp8lDistanceCodeOffset
+}
+
+// vp8lPrefixEncode splits a length or distance code into its prefix symbol and extra bits
+func vp8lPrefixEncode(value int) (symbol int, extraBits int, extraValue uint32) {
+	v := value - 1
+	if v < 4 {
+		return v, 0, 0
+	}
+	highest := 0
+	for x := v; x > 1; x >>= 1 {
+		highest++
+	}
+	second := (v >> (highest - 1)) & 1
+	extraBits = highest - 1
+	extraValue = uint32(v & (1<<extraBits - 1))
+	return 2*highest + second, extraBits, extraValue
+}
+
+// vp8lPrefixCode is a canonical prefix code, with codes stored bit-reversed for the LSB-first writer
+type vp8lPrefixCode struct {
+	codes   []uint32
+	lengths []int
+}
+
+func (p vp8lPrefixCode) write(w *vp8lBitWriter, symbol int) {
+	w.writeBits(p.codes[symbol], p.lengths[symbol])
+}
+
+// writePrefixCode writes the prefix code for a histogram and returns it for coding symbols
+func (w *vp8lBitWriter) writePrefixCode(histogram []int) vp8lPrefixCode {
+	var used []int
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+
+	code := vp8lPrefixCode{codes: make([]uint32, len(histogram)), lengths: make([]int, len(histogram))}
+
+	// A single symbol is coded with zero bits, either as a simple code or as a one-entry normal code
+	if len(used) <= 1 {
+		symbol := 0
+		if len(used) == 1 {
+			symbol = used[0]
+		}
+		if symbol < vp8lNumLiterals {
+			w.writeBits(1, 1) // Simple code
+			w.writeBits(0, 1) // One symbol
+			if symbol < 2 {
+				w.writeBits(0, 1)
+				w.writeBits(uint32(symbol), 1)
+			} else {
+				w.writeBits(1, 1)
+				w.writeBits(uint32(symbol), 8)
+			}
+			return code
+		}
+	}
+
+	lengths := vp8lCodeLengths(histogram, vp8lMaxCodeLength)
+	if len(used) == 1 {
+		lengths[used[0]] = 1
+	}
+	w.writeBits(0, 1) // Normal code
+	w.writeCodeLengths(lengths)
+
+	if len(used) > 1 {
+		code.codes = vp8lCanonicalCodes(lengths)
+		code.lengths = lengths
+	}
+	return code
+}
+
+// writeCodeLengths writes a code's lengths, run-length coding zeros and prefix coding the result
+func (w *vp8lBitWriter) writeCodeLengths(lengths []int) {
+	type lengthToken struct {
+		symbol    int
+		extraBits int
+		extra     uint32
+	}
+	var tokens []lengthToken
+	for i := 0; i < len(lengths); {
+		if lengths[i] != 0 {
+			tokens = append(tokens, lengthToken{symbol: lengths[i]})
+			i++
+			continue
+		}
+		run := 0
+		for i+run < len(lengths) && lengths[i+run] == 0 {
+			run++
+		}
+		for run > 0 {
+			switch {
+			case run < 3:
+				tokens = append(tokens, lengthToken{symbol: 0})
+				run--
+				i++
+			case run <= 10:
+				tokens = append(tokens, lengthToken{symbol: 17, extraBits: 3, extra: uint32(run - 3)})
+				i += run
+				run = 0
+			default:
+				chunk := run
+				if chunk > 138 {
+					chunk = 138
+				}
+				tokens = append(tokens, lengthToken{symbol: 18, extraBits: 7, extra: uint32(chunk - 11)})
+				i += chunk
+				run -= chunk
+			}
+		}
+	}
+
+	histogram := make([]int, 19)
+	for _, t := range tokens {
+		histogram[t.symbol]++
+	}
+	codeLengthLengths := vp8lCodeLengths(histogram, vp8lMaxCodeLengthCodeLength)
+	used := 0
+	for _, count := range histogram {
+		if count > 0 {
+			used++
+		}
+	}
+	if used == 1 {
+		for symbol, count := range histogram {
+			if count > 0 {
+				codeLengthLengths[symbol] = 1
+			}
+		}
+	}
+
+	numCodes := 4
+	for i, symbol := range vp8lCodeLengthOrder {
+		if codeLengthLengths[symbol] != 0 && i+1 > numCodes {
+			numCodes = i + 1
+		}
+	}
+	w.writeBits(uint32(numCodes-4), 4)
+	for _, symbol := range vp8lCodeLengthOrder[:numCodes] {
+		w.writeBits(uint32(codeLengthLengths[symbol]), 3)
+	}
+	w.writeBits(0, 1) // Code lengths cover the whole alphabet
+
+	lengthCode := vp8lPrefixCode{codes: make([]uint32, 19), lengths: make([]int, 19)}
+	if used > 1 {
+		lengthCode.codes = vp8lCanonicalCodes(codeLengthLengths)
+		lengthCode.lengths = codeLengthLengths
+	}
+	for _, t := range tokens {
+		lengthCode.write(w, t.symbol)
+		w.writeBits(t.extra, t.extraBits)
+	}
+}
+
+// vp8lCodeLengths builds Huffman code lengths no longer than maxLength, flattening the histogram until
+// the tree is shallow enough
+func vp8lCodeLengths(histogram []int, maxLength int) []int {
+	counts := append([]int(nil), histogram...)
+	for {
+		lengths := vp8lHuffmanLengths(counts)
+		longest := 0
+		for _, l := range lengths {
+			if l > longest {
+				longest = l
+			}
+		}
+		if longest <= maxLength {
+			return lengths
+		}
+		for i, c := range counts {
+			if c > 0 {
+				counts[i] = c/2 + 1
+			}
+		}
+	}
+}
+
+// vp8lHuffmanLengths returns the Huffman code length of each symbol, 0 for unused symbols
+func vp8lHuffmanLengths(counts []int) []int {
+	type node struct {
+		count       int
+		symbol      int
+		left, right int
+	}
+	var nodes []node
+	var active []int
+	for symbol, count := range counts {
+		if count > 0 {
+			nodes = append(nodes, node{count: count, symbol: symbol, left: -1, right: -1})
+			active = append(active, len(nodes)-1)
+		}
+	}
+	lengths := make([]int, len(counts))
+	if len(active) < 2 {
+		return lengths
+	}
+
+	for len(active) > 1 {
+		sort.Slice(active, func(i, j int) bool {
+			a, b := nodes[active[i]], nodes[active[j]]
+			if a.count != b.count {
+				return a.count < b.count
+			}
+			return active[i] < active[j]
+		})
+		nodes = append(nodes, node{count: nodes[active[0]].count + nodes[active[1]].count, symbol: -1, left: active[0], right: active[1]})
+		active = append([]int{len(nodes) - 1}, active[2:]...)
+	}
+
+	var walk func(index, depth int)
+	walk = func(index, depth int) {
+		n := nodes[index]
+		if n.symbol >= 0 {
+			lengths[n.symbol] = depth
+			return
+		}
+		walk(n.left, depth+1)
+		walk(n.right, depth+1)
+	}
+	walk(active[0], 0)
+	return lengths
+}
+
+// vp8lCanonicalCodes assigns canonical codes to code lengths, bit-reversed for the LSB-first writer
+func vp8lCanonicalCodes(lengths []int) []uint32 {
+	var lengthCounts [vp8lMaxCodeLength + 1]uint32
+	for _, l := range lengths {
+		if l > 0 {
+			lengthCounts[l]++
+		}
+	}
+	var nextCode [vp8lMaxCodeLength + 2]uint32
+	code := uint32(0)
+	for l := 1; l <= vp8lMaxCodeLength; l++ {
+		code = (code + lengthCounts[l-1]) << 1
+		nextCode[l] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for symbol, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		c := nextCode[l]
+		nextCode[l]++
+		reversed := uint32(0)
+		for i := 0; i < l; i++ {
+			reversed = reversed<<1 | (c>>i)&1
+		}
+		codes[symbol] = reversed
+	}
+	return codes
+}
+
+// vp8lBitWriter writes bits least-significant first, as VP8L requires
+type vp8lBitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits int
+}
+
+func (w *vp8lBitWriter) writeBits(value uint32, n int) {
+	if n == 0 {
+		return
+	}
+	w.acc |= uint64(value&(1<<n-1)) << w.nbits
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nbits -= 8
+	}
+}
+
+func (w *vp8lBitWriter) bytes() []byte {
+	if w.nbits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc = 0
+		w.nbits = 0
+	}
+	return w.buf
+}
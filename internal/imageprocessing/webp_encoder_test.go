@@ -0,0 +1,90 @@
+package imageprocessing
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+// gradientImage builds an image with gray ramps and a few colored blocks, so it has both runs and noise
+func gradientImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8((x*7 + y*13) % 256)
+			c := color.NRGBA{R: v, G: v, B: v, A: 255}
+			if (x/16+y/16)%5 == 0 {
+				c = color.NRGBA{R: uint8(x * 3), G: uint8(y * 5), B: uint8(x ^ y), A: uint8(128 + x%128)}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func assertWebPRoundTrip(t *testing.T, name string, src image.Image) {
+	t.Helper()
+
+	data, err := EncodeWebPLossless(src)
+	if err != nil {
+		t.Fatalf("%s: EncodeWebPLossless error = %v", name, err)
+	}
+	decoded, err := webp.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s: webp.Decode error = %v", name, err)
+	}
+
+	bounds := src.Bounds()
+	if decoded.Bounds().Dx() != bounds.Dx() || decoded.Bounds().Dy() != bounds.Dy() {
+		t.Fatalf("%s: decoded size = %v, want %dx%d", name, decoded.Bounds().Size(), bounds.Dx(), bounds.Dy())
+	}
+
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			want := color.NRGBAModel.Convert(src.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			got := color.NRGBAModel.Convert(decoded.At(decoded.Bounds().Min.X+x, decoded.Bounds().Min.Y+y)).(color.NRGBA)
+			if got != want {
+				t.Fatalf("%s: pixel (%d, %d) = %v, want %v", name, x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestEncodeWebPLossless_RoundTrip(t *testing.T) {
+	sizes := []image.Point{{1, 1}, {3, 5}, {17, 9}, {101, 67}, {800, 480}}
+
+	for _, size := range sizes {
+		assertWebPRoundTrip(t, "color "+size.String(), gradientImage(size.X, size.Y))
+	}
+
+	for _, bitDepth := range []int{1, 2, 8} {
+		for _, size := range []image.Point{{13, 7}, {331, 199}} {
+			quantized := QuantizeToGrayscalePalette(gradientImage(size.X, size.Y), bitDepth)
+			if quantized == nil {
+				t.Fatalf("QuantizeToGrayscalePalette(%d) returned nil", bitDepth)
+			}
+			assertWebPRoundTrip(t, fmt.Sprintf("%d-bit gray %s", bitDepth, size), quantized)
+		}
+	}
+}
+
+func TestEncodeWebPLossless_FlatImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 799, 481))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	assertWebPRoundTrip(t, "flat", img)
+
+	data, err := EncodeWebPLossless(img)
+	if err != nil {
+		t.Fatalf("EncodeWebPLossless error = %v", err)
+	}
+	// A single color should compress to almost nothing
+	if len(data) > 1024 {
+		t.Errorf("flat image encoded to %d bytes, want at most 1024", len(data))
+	}
+}
@@ -0,0 +1,126 @@
+package locales
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats holds the date, time and number conventions for a locale.
+// Month name slices follow the Rails i18n layout where index 0 is empty so months index from 1.
+type Formats struct {
+	Locale         string   `json:"locale"`
+	DayNames       []string `json:"day_names"`
+	AbbrDayNames   []string `json:"abbr_day_names"`
+	MonthNames     []string `json:"month_names"`
+	AbbrMonthNames []string `json:"abbr_month_names"`
+	DateFormat     string   `json:"date_format"`
+	TimeFormat     string   `json:"time_format"`
+	Separator      string   `json:"separator"`
+	Delimiter      string   `json:"delimiter"`
+}
+
+// formatsFile mirrors the layout of the embedded formats/*.yml files
+type formatsFile struct {
+	Date struct {
+		DayNames       []string `yaml:"day_names"`
+		AbbrDayNames   []string `yaml:"abbr_day_names"`
+		MonthNames     []string `yaml:"month_names"`
+		AbbrMonthNames []string `yaml:"abbr_month_names"`
+		Formats        struct {
+			Default string `yaml:"default"`
+		} `yaml:"formats"`
+	} `yaml:"date"`
+	Time struct {
+		Formats struct {
+			Default string `yaml:"default"`
+		} `yaml:"formats"`
+	} `yaml:"time"`
+	Number struct {
+		Format struct {
+			Separator string `yaml:"separator"`
+			Delimiter string `yaml:"delimiter"`
+		} `yaml:"format"`
+	} `yaml:"number"`
+}
+
+// loadEmbeddedFormats loads all embedded date/time/number format files
+func (lm *LocaleManager) loadEmbeddedFormats() error {
+	entries, err := localeFiles.ReadDir("formats")
+	if err != nil {
+		return fmt.Errorf("failed to read embedded formats directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yml") {
+			continue
+		}
+
+		locale := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+
+		data, err := localeFiles.ReadFile("formats/" + entry.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read formats file %s: %w", entry.Name(), err)
+		}
+
+		// Files are keyed by locale at the top level, like the translation files
+		var parsed map[string]formatsFile
+		if err := yaml.Unmarshal(data, &parsed); err != nil {
+			return fmt.Errorf("failed to parse formats for locale %s: %w", locale, err)
+		}
+
+		file, exists := parsed[locale]
+		if !exists {
+			return fmt.Errorf("formats file %s has no %q section", entry.Name(), locale)
+		}
+		monthNames := padMonthNames(file.Date.MonthNames)
+		abbrMonthNames := padMonthNames(file.Date.AbbrMonthNames)
+		if len(file.Date.DayNames) != 7 || len(file.Date.AbbrDayNames) != 7 ||
+			len(monthNames) != 13 || len(abbrMonthNames) != 13 {
+			return fmt.Errorf("formats file %s must define 7 day names and 12 month names", entry.Name())
+		}
+
+		lm.mutex.Lock()
+		lm.formats[locale] = Formats{
+			Locale:         locale,
+			DayNames:       file.Date.DayNames,
+			AbbrDayNames:   file.Date.AbbrDayNames,
+			MonthNames:     monthNames,
+			AbbrMonthNames: abbrMonthNames,
+			DateFormat:     file.Date.Formats.Default,
+			TimeFormat:     file.Time.Formats.Default,
+			Separator:      file.Number.Format.Separator,
+			Delimiter:      file.Number.Format.Delimiter,
+		}
+		lm.mutex.Unlock()
+	}
+
+	return nil
+}
+
+// GetFormats returns the date, time and number formats for a locale, falling back to English
+func (lm *LocaleManager) GetFormats(locale string) (Formats, bool) {
+	lm.mutex.RLock()
+	defer lm.mutex.RUnlock()
+
+	if formats, exists := lm.formats[locale]; exists {
+		return formats, true
+	}
+
+	if formats, exists := lm.formats[normalizeLocale(locale)]; exists {
+		return formats, true
+	}
+
+	formats, exists := lm.formats["en"]
+	return formats, exists
+}
+
+// padMonthNames restores the empty leading entry, which the YAML decoder drops from string slices
+func padMonthNames(names []string) []string {
+	if len(names) == 12 {
+		return append([]string{""}, names...)
+	}
+	return names
+}
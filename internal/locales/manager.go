@@ -11,12 +11,13 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-//go:embed *.yml
+//go:embed *.yml formats/*.yml
 var localeFiles embed.FS
 
 // LocaleManager manages TRMNL locale files and provides translation lookups
 type LocaleManager struct {
 	translations map[string]map[string]interface{}
+	formats      map[string]Formats
 	mutex        sync.RWMutex
 }
 
@@ -24,11 +25,16 @@ type LocaleManager struct {
 func NewLocaleManager() (*LocaleManager, error) {
 	lm := &LocaleManager{
 		translations: make(map[string]map[string]interface{}),
+		formats:      make(map[string]Formats),
 	}
 	
 	if err := lm.loadEmbeddedLocales(); err != nil {
 		return nil, fmt.Errorf("failed to load embedded locales: %w", err)
 	}
+
+	if err := lm.loadEmbeddedFormats(); err != nil {
+		return nil, fmt.Errorf("failed to load embedded formats: %w", err)
+	}
 	
 	return lm, nil
 }
@@ -21,4 +21,5 @@ const (
 	ComponentExport         = "export"
 	ComponentImport         = "import"
 	ComponentPlaylist       = "playlist"
+	ComponentDeviceCleanup  = "device-cleanup"
 )
\ No newline at end of file
@@ -0,0 +1,205 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bracketPrefix matches the "[COMPONENT] " prefix most log messages start with
+var bracketPrefix = regexp.MustCompile(`^\[([^\]]+)\]\s*`)
+
+// jsonLogLine is the shape of every line written in JSON format
+type jsonLogLine struct {
+	Timestamp  string         `json:"timestamp"`
+	Level      string         `json:"level"`
+	Component  string         `json:"component"`
+	Message    string         `json:"message"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// ComponentJSONHandler writes one JSON object per record with fixed top-level fields. The component
+// comes from the component attribute or, failing that, the message's bracketed prefix.
+type ComponentJSONHandler struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	level  slog.Leveler
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewComponentJSONHandler creates a JSON handler writing to w at the given minimum level
+func NewComponentJSONHandler(w io.Writer, level slog.Leveler) *ComponentJSONHandler {
+	return &ComponentJSONHandler{w: w, mu: &sync.Mutex{}, level: level}
+}
+
+// Enabled reports whether records at the level are written
+func (h *ComponentJSONHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle encodes a record as a single JSON line
+func (h *ComponentJSONHandler) Handle(_ context.Context, r slog.Record) error {
+	line := jsonLogLine{
+		Timestamp:  r.Time.UTC().Format(time.RFC3339Nano),
+		Level:      levelName(r.Level),
+		Message:    r.Message,
+		Attributes: make(map[string]any),
+	}
+
+	var component string
+	for _, attr := range h.attrs {
+		component = addJSONAttr(line.Attributes, attr, component)
+	}
+
+	var recordAttrs []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		recordAttrs = append(recordAttrs, a)
+		return true
+	})
+	target := line.Attributes
+	for _, group := range h.groups {
+		nested, ok := target[group].(map[string]any)
+		if !ok {
+			nested = make(map[string]any)
+			target[group] = nested
+		}
+		target = nested
+	}
+	for _, attr := range recordAttrs {
+		if len(h.groups) == 0 {
+			component = addJSONAttr(target, attr, component)
+		} else {
+			addJSONAttr(target, attr, "")
+		}
+	}
+
+	if match := bracketPrefix.FindStringSubmatch(line.Message); match != nil {
+		line.Message = line.Message[len(match[0]):]
+		if component == "" {
+			component = match[1]
+		}
+	}
+	line.Component = normalizeComponent(component)
+
+	encoded, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	encoded = append(encoded, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.w.Write(encoded)
+	return err
+}
+
+// WithAttrs returns a handler that adds the attributes to every record
+func (h *ComponentJSONHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	clone.attrs = append([]slog.Attr{}, h.attrs...)
+	if len(h.groups) == 0 {
+		clone.attrs = append(clone.attrs, attrs...)
+	} else {
+		// Nest the attributes under the open groups, innermost first
+		grouped := slog.Attr{Key: h.groups[len(h.groups)-1], Value: slog.GroupValue(attrs...)}
+		for i := len(h.groups) - 2; i >= 0; i-- {
+			grouped = slog.Attr{Key: h.groups[i], Value: slog.GroupValue(grouped)}
+		}
+		clone.attrs = append(clone.attrs, grouped)
+	}
+	return &clone
+}
+
+// WithGroup returns a handler that nests later attributes under the group name
+func (h *ComponentJSONHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]string{}, h.groups...), name)
+	return &clone
+}
+
+// addJSONAttr stores an attribute in the map, returning the component when the attribute carries it
+func addJSONAttr(target map[string]any, attr slog.Attr, component string) string {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return component
+	}
+	if attr.Key == "component" {
+		return attr.Value.String()
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupAttrs := attr.Value.Group()
+		if len(groupAttrs) == 0 {
+			return component
+		}
+		if attr.Key == "" {
+			for _, a := range groupAttrs {
+				component = addJSONAttr(target, a, component)
+			}
+			return component
+		}
+		nested, ok := target[attr.Key].(map[string]any)
+		if !ok {
+			nested = make(map[string]any)
+			target[attr.Key] = nested
+		}
+		for _, a := range groupAttrs {
+			addJSONAttr(nested, a, "")
+		}
+		return component
+	}
+
+	target[attr.Key] = jsonAttrValue(attr.Value)
+	return component
+}
+
+// jsonAttrValue converts a log value into something encoding/json renders sensibly
+func jsonAttrValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().UTC().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindAny:
+		value := v.Any()
+		switch typed := value.(type) {
+		case error:
+			return typed.Error()
+		case fmt.Stringer:
+			return typed.String()
+		}
+		if _, err := json.Marshal(value); err != nil {
+			return fmt.Sprintf("%+v", value)
+		}
+		return value
+	default:
+		return v.Any()
+	}
+}
+
+// normalizeComponent turns "RENDER_WORKER" or "MODEL POLLER" into "render-worker" and "model-poller"
+func normalizeComponent(component string) string {
+	component = strings.ToLower(strings.TrimSpace(component))
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(component)
+}
+
+// levelName names a level, including the custom browserless level
+func levelName(level slog.Level) string {
+	if level == LevelBrowserless {
+		return "BROWSERLESS"
+	}
+	return level.String()
+}
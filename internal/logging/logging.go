@@ -86,9 +86,7 @@ func setupLogger() {
 	var handler slog.Handler
 
 	if format == "json" {
-		handler = slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
-			Level: level,
-		})
+		handler = NewComponentJSONHandler(os.Stderr, level)
 	} else {
 		handler = &ComponentTintHandler{
 			Handler: tint.NewHandler(os.Stderr, &tint.Options{
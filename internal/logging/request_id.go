@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RequestIDHeader is the header carrying the correlation ID of a request
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the given request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// FromContext returns a logger that adds the request ID carried by ctx to every record,
+// so work triggered by a single request can be correlated across goroutines
+func FromContext(ctx context.Context) *slog.Logger {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		return logger.With("request_id", requestID)
+	}
+	return logger
+}
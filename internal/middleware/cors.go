@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// devicePathPrefixes are the endpoints TRMNL devices call. Firmware doesn't need CORS, but browser-based
+// device simulators do, so these allow any origin.
+var devicePathPrefixes = []string{
+	"/api/setup",
+	"/api/display",
+	"/api/current_screen",
+	"/api/log",
+	"/api/capabilities",
+	"/api/device-config",
+	"/api/trmnl/",
+	"/api/public/devices/",
+	"/static/rendered/",
+}
+
+// deviceHeaders are the request headers TRMNL devices and simulators send
+var deviceHeaders = []string{
+	"Origin",
+	"Content-Type",
+	"Accept",
+	"Authorization",
+	"ID",
+	"Access-Token",
+	"Refresh-Rate",
+	"Battery-Voltage",
+	"Fw-Version",
+	"Rssi",
+	"Model",
+	"Width",
+	"Height",
+	"User-Agent",
+	logging.RequestIDHeader,
+}
+
+// CORS allows cross-origin requests to the device endpoints from anywhere, and to the rest of the API only
+// from allowedOrigins. Requests from other origins get no CORS headers, so browsers won't let other sites
+// read management API responses. An allowedOrigins entry of "*" allows every origin.
+func CORS(allowedOrigins []string) gin.HandlerFunc {
+	deviceConfig := cors.DefaultConfig()
+	deviceConfig.AllowAllOrigins = true
+	deviceConfig.AllowMethods = []string{"GET", "POST", "OPTIONS"}
+	deviceConfig.AllowHeaders = deviceHeaders
+	deviceConfig.ExposeHeaders = []string{logging.RequestIDHeader}
+	deviceCORS := cors.New(deviceConfig)
+
+	allowAll := false
+	allowed := make(map[string]bool)
+	var origins []string
+	for _, origin := range allowedOrigins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			allowAll = true
+			continue
+		}
+		allowed[origin] = true
+		origins = append(origins, origin)
+	}
+
+	var managementCORS gin.HandlerFunc
+	if allowAll || len(origins) > 0 {
+		managementConfig := cors.DefaultConfig()
+		if allowAll {
+			managementConfig.AllowAllOrigins = true
+		} else {
+			managementConfig.AllowOrigins = origins
+			managementConfig.AllowCredentials = true
+		}
+		managementConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+		managementConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", logging.RequestIDHeader}
+		managementConfig.ExposeHeaders = []string{logging.RequestIDHeader}
+		managementCORS = cors.New(managementConfig)
+	}
+
+	return func(c *gin.Context) {
+		if isDevicePath(c.Request.URL.Path) {
+			deviceCORS(c)
+			return
+		}
+		// Leave other origins to the browser's same-origin policy rather than rejecting them here, since
+		// the UI's own origin may not match the Host header behind a reverse proxy
+		if managementCORS != nil && (allowAll || allowed[c.GetHeader("Origin")]) {
+			managementCORS(c)
+		}
+	}
+}
+
+func isDevicePath(path string) bool {
+	for _, prefix := range devicePathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
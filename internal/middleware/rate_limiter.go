@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -92,8 +95,12 @@ func (wrl *WebhookRateLimiter) RateLimit() gin.HandlerFunc {
 // RequestSizeLimit middleware enforces configurable request size limits
 func (wrl *WebhookRateLimiter) RequestSizeLimit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get max request size from database settings
-		maxSizeKB, err := wrl.getMaxRequestSizeKB()
+		// Get max request size from database settings, images have their own larger limit
+		getMaxSizeKB := wrl.getMaxRequestSizeKB
+		if requestIsImage(c) {
+			getMaxSizeKB = wrl.getMaxImageSizeKB
+		}
+		maxSizeKB, err := getMaxSizeKB()
 		if err != nil {
 			logging.Error("[WEBHOOK] Failed to get max request size setting", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check request size limit"})
@@ -115,10 +122,38 @@ func (wrl *WebhookRateLimiter) RequestSizeLimit() gin.HandlerFunc {
 			return
 		}
 
+		// Enforce the limit for requests without a Content-Length as well
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSizeBytes)
+
 		c.Next()
 	}
 }
 
+// requestIsImage reports whether a request carries image data, detecting it the way the webhook handler
+// does: by Content-Type, sniffing the start of the body when the type is missing or application/octet-stream.
+// The sniffed bytes are put back in front of the body.
+func requestIsImage(c *gin.Context) bool {
+	contentType := c.GetHeader("Content-Type")
+	if contentType != "" && contentType != "application/octet-stream" {
+		return strings.HasPrefix(contentType, "image/")
+	}
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return false
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(c.Request.Body, head)
+	head = head[:n]
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false
+	}
+	return strings.HasPrefix(http.DetectContentType(head), "image/")
+}
+
 // allowRequest checks if a request should be allowed based on rate limiting
 func (wrl *WebhookRateLimiter) allowRequest(userKey string, rateLimit int) bool {
 	wrl.mutex.Lock()
@@ -182,6 +217,24 @@ func (wrl *WebhookRateLimiter) getMaxRequestSizeKB() (int, error) {
 	return maxSize, nil
 }
 
+// getMaxImageSizeKB fetches the max image upload size setting from the database
+func (wrl *WebhookRateLimiter) getMaxImageSizeKB() (int, error) {
+	var setting database.SystemSetting
+	if err := wrl.db.Where("key = ?", "webhook_max_image_size_kb").First(&setting).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 2048, nil // Default value
+		}
+		return 0, err
+	}
+
+	maxSize, err := strconv.Atoi(setting.Value)
+	if err != nil {
+		return 2048, nil // Default value on parsing error
+	}
+
+	return maxSize, nil
+}
+
 // cleanupRoutine removes expired rate limit entries
 func (wrl *WebhookRateLimiter) cleanupRoutine() {
 	ticker := time.NewTicker(time.Hour)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/rmitchellscott/stationmaster/internal/plugins"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
 )
 
 // CoreProxyPlugin implements a plugin that proxies requests to TRMNL's official server
@@ -127,12 +128,10 @@ func (p *CoreProxyPlugin) Process(ctx plugins.PluginContext) (plugins.PluginResp
 	}
 
 	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: time.Duration(timeoutSeconds) * time.Second,
-	}
+	client := utils.NewHTTPClient(time.Duration(timeoutSeconds) * time.Second)
 
 	// Create request to TRMNL's API
-	req, err := http.NewRequest("GET", "https://usetrmnl.com/api/display", nil)
+	req, err := http.NewRequestWithContext(ctx.Context(), "GET", "https://usetrmnl.com/api/display", nil)
 	if err != nil {
 		return plugins.CreateErrorResponse(fmt.Sprintf("Failed to create request: %v", err)),
 			fmt.Errorf("failed to create request: %w", err)
@@ -177,7 +177,7 @@ func (p *ExternalPlugin) Process(ctx plugins.PluginContext) (plugins.PluginRespo
 	}
 	defer browserRenderer.Close()
 
-	renderCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	renderCtx, cancel := context.WithTimeout(ctx.Context(), 30*time.Second)
 	defer cancel()
 
 	renderResult, err := browserRenderer.RenderHTMLWithResult(
@@ -254,7 +254,7 @@ func (p *ExternalPlugin) fetchRenderedHTML(settings map[string]interface{}, layo
 	}
 	
 	// Create POST request
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonData)))
+	req, err := http.NewRequestWithContext(ctx.Context(), "POST", url, strings.NewReader(string(jsonData)))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -171,11 +171,17 @@ func (f *UnifiedPluginFactory) validatePrivateDefinition(def *database.PluginDef
 		"webhook": true,
 		"polling": true,
 		"static":  true,
+		"image":   true,
 	}
 	
 	if !validStrategies[*def.DataStrategy] {
 		return fmt.Errorf("invalid data strategy: %s", *def.DataStrategy)
 	}
+
+	// Image plugins display the image received by their webhook and need no template
+	if *def.DataStrategy == "image" {
+		return nil
+	}
 	
 	// Validate that at least one layout template is provided
 	if (def.MarkupFull == nil || *def.MarkupFull == "") &&
@@ -0,0 +1,414 @@
+package health_check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rmitchellscott/stationmaster/internal/imageprocessing"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/plugins"
+	"github.com/rmitchellscott/stationmaster/internal/rendering"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+)
+
+const (
+	maxChecks         = 10
+	defaultTimeout    = 5
+	maxTimeout        = 30
+	minCacheSeconds   = 60
+	defaultCheckTitle = "Uptime"
+)
+
+// defaultMarkup lists each check with its status and latency
+const defaultMarkup = `<div class="layout layout--col layout--top gap">
+  {% for check in checks %}
+  <div class="item">
+    <div class="meta"></div>
+    <div class="content">
+      <span class="title title--small">{% if check.up %}&#9650;{% else %}&#9660;{% endif %} {{ check.name }}</span>
+      <span class="label label--small">{% if check.up %}Up{% else %}Down{% endif %}{% if check.status_code > 0 %} &middot; HTTP {{ check.status_code }}{% endif %} &middot; {{ check.latency_ms }} ms{% if check.error != "" %} &middot; {{ check.error }}{% endif %}</span>
+    </div>
+  </div>
+  {% endfor %}
+</div>
+<div class="title_bar">
+  <span class="title">{{ title }}</span>
+  <span class="instance">{{ up_count }}/{{ total }} up</span>
+</div>`
+
+// HealthCheckPlugin pings a list of endpoints and renders their up/down status
+type HealthCheckPlugin struct{}
+
+// checkTarget is one endpoint parsed from the checks setting
+type checkTarget struct {
+	Name string
+	URL  string
+}
+
+// cachedResults holds the last check results of an instance so that rendering for several devices
+// doesn't ping the endpoints more often than the instance's refresh interval
+type cachedResults struct {
+	checkedAt time.Time
+	results   []map[string]interface{}
+}
+
+var (
+	resultCache   = make(map[uuid.UUID]cachedResults)
+	resultCacheMu sync.Mutex
+)
+
+// Type returns the plugin type identifier
+func (p *HealthCheckPlugin) Type() string {
+	return "health_check"
+}
+
+// PluginType returns that this is an image plugin
+func (p *HealthCheckPlugin) PluginType() plugins.PluginType {
+	return plugins.PluginTypeImage
+}
+
+// Name returns the human-readable name
+func (p *HealthCheckPlugin) Name() string {
+	return "Health Check"
+}
+
+// Description returns the plugin description
+func (p *HealthCheckPlugin) Description() string {
+	return "Checks a list of URLs and shows whether each one is up, with its response time"
+}
+
+// Author returns the plugin author
+func (p *HealthCheckPlugin) Author() string {
+	return "Stationmaster"
+}
+
+// Version returns the plugin version
+func (p *HealthCheckPlugin) Version() string {
+	return "1.0.0"
+}
+
+// RequiresProcessing returns true since the status page is rendered through browserless
+func (p *HealthCheckPlugin) RequiresProcessing() bool {
+	return true
+}
+
+// ConfigSchema returns the JSON schema for configuration
+func (p *HealthCheckPlugin) ConfigSchema() string {
+	return fmt.Sprintf(`{
+		"type": "object",
+		"properties": {
+			"title": {
+				"type": "string",
+				"title": "Title",
+				"description": "Shown in the title bar",
+				"default": "%s"
+			},
+			"checks": {
+				"type": "string",
+				"format": "textarea",
+				"title": "Endpoints",
+				"description": "One endpoint per line, optionally named as: Name | https://example.com/health (up to %d)",
+				"examples": ["API | https://api.example.com/health\nhttps://example.com"]
+			},
+			"method": {
+				"type": "string",
+				"title": "Request Method",
+				"description": "HEAD is lighter; servers that reject HEAD are retried with GET",
+				"enum": ["HEAD", "GET"],
+				"default": "HEAD"
+			},
+			"timeout": {
+				"type": "integer",
+				"title": "Timeout (seconds)",
+				"description": "How long to wait for each endpoint before marking it down",
+				"minimum": 1,
+				"maximum": %d,
+				"default": %d
+			},
+			"markup": {
+				"type": "string",
+				"format": "textarea",
+				"title": "Custom Markup",
+				"description": "Optional Liquid markup. Available variables: title, checks (name, url, up, status_code, latency_ms, error), up_count, down_count, total, checked_at"
+			}
+		},
+		"required": ["checks"]
+	}`, defaultCheckTitle, maxChecks, maxTimeout, defaultTimeout)
+}
+
+// parseChecks parses the checks setting into endpoints, one per non-empty line
+func parseChecks(value string) ([]checkTarget, error) {
+	var targets []checkTarget
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		target := checkTarget{URL: line}
+		if name, url, found := strings.Cut(line, "|"); found {
+			target.Name = strings.TrimSpace(name)
+			target.URL = strings.TrimSpace(url)
+		}
+		if target.Name == "" {
+			target.Name = target.URL
+		}
+
+		if !strings.HasPrefix(target.URL, "http://") && !strings.HasPrefix(target.URL, "https://") {
+			return nil, fmt.Errorf("%s must be an HTTP or HTTPS URL", target.URL)
+		}
+		targets = append(targets, target)
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("at least one endpoint is required")
+	}
+	if len(targets) > maxChecks {
+		return nil, fmt.Errorf("at most %d endpoints can be checked, got %d", maxChecks, len(targets))
+	}
+	return targets, nil
+}
+
+// Validate validates the plugin settings
+func (p *HealthCheckPlugin) Validate(settings map[string]interface{}) error {
+	checks, ok := settings["checks"].(string)
+	if !ok {
+		return fmt.Errorf("checks is required and must be a list of URLs")
+	}
+	targets, err := parseChecks(checks)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if err := utils.ValidateURL(target.URL); err != nil {
+			return fmt.Errorf("url validation failed for %s: %w", target.URL, err)
+		}
+	}
+
+	if method, exists := settings["method"]; exists && method != nil && method != "" {
+		if method != "HEAD" && method != "GET" {
+			return fmt.Errorf("method must be HEAD or GET")
+		}
+	}
+
+	if value, exists := settings["timeout"]; exists && value != nil && value != "" {
+		timeout, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("timeout must be a number (seconds)")
+		}
+		if timeout < 1 || timeout > maxTimeout {
+			return fmt.Errorf("timeout must be between 1 and %d seconds", maxTimeout)
+		}
+	}
+
+	return nil
+}
+
+// runCheck requests one endpoint and reports its status and latency
+func runCheck(client *http.Client, method string, target checkTarget) map[string]interface{} {
+	result := map[string]interface{}{
+		"name":        target.Name,
+		"url":         target.URL,
+		"up":          false,
+		"status_code": 0,
+		"latency_ms":  0,
+		"error":       "",
+	}
+
+	if err := utils.ValidateURL(target.URL); err != nil {
+		result["error"] = "URL not allowed"
+		return result
+	}
+
+	start := time.Now()
+	resp, err := doRequest(client, method, target.URL)
+	if err == nil && method == http.MethodHead && resp.StatusCode == http.StatusMethodNotAllowed {
+		resp.Body.Close()
+		start = time.Now()
+		resp, err = doRequest(client, http.MethodGet, target.URL)
+	}
+	result["latency_ms"] = time.Since(start).Milliseconds()
+
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			result["error"] = "timed out"
+		} else {
+			result["error"] = "unreachable"
+		}
+		return result
+	}
+	resp.Body.Close()
+
+	result["status_code"] = resp.StatusCode
+	result["up"] = resp.StatusCode < 400
+	return result
+}
+
+func doRequest(client *http.Client, method, url string) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Stationmaster-HealthCheck/1.0")
+	return client.Do(req)
+}
+
+// checkAll runs the checks concurrently, reusing cached results newer than the refresh interval
+func checkAll(instanceID uuid.UUID, refreshInterval int, method string, timeout time.Duration, targets []checkTarget) ([]map[string]interface{}, time.Time) {
+	maxAge := time.Duration(refreshInterval) * time.Second
+	if maxAge < minCacheSeconds*time.Second {
+		maxAge = minCacheSeconds * time.Second
+	}
+
+	resultCacheMu.Lock()
+	cached, ok := resultCache[instanceID]
+	resultCacheMu.Unlock()
+	if ok && len(cached.results) == len(targets) && time.Since(cached.checkedAt) < maxAge {
+		return cached.results, cached.checkedAt
+	}
+
+	client := utils.NewHTTPClient(timeout)
+	results := make([]map[string]interface{}, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target checkTarget) {
+			defer wg.Done()
+			results[i] = runCheck(client, method, target)
+		}(i, target)
+	}
+	wg.Wait()
+
+	checkedAt := time.Now().UTC()
+	resultCacheMu.Lock()
+	resultCache[instanceID] = cachedResults{checkedAt: checkedAt, results: results}
+	resultCacheMu.Unlock()
+
+	return results, checkedAt
+}
+
+// Process executes the plugin logic
+func (p *HealthCheckPlugin) Process(ctx plugins.PluginContext) (plugins.PluginResponse, error) {
+	targets, err := parseChecks(ctx.GetStringSetting("checks", ""))
+	if err != nil {
+		return plugins.CreateErrorResponse(fmt.Sprintf("Invalid endpoints: %v", err)),
+			fmt.Errorf("failed to parse checks: %w", err)
+	}
+
+	if ctx.Device == nil || ctx.Device.DeviceModel == nil {
+		return plugins.CreateErrorResponse("Device model information not available"),
+			fmt.Errorf("device model is required for health check rendering")
+	}
+
+	method := ctx.GetStringSetting("method", http.MethodHead)
+	if method != http.MethodGet {
+		method = http.MethodHead
+	}
+	timeoutSeconds := ctx.GetIntSetting("timeout", defaultTimeout)
+	if timeoutSeconds < 1 || timeoutSeconds > maxTimeout {
+		timeoutSeconds = defaultTimeout
+	}
+
+	var instanceID uuid.UUID
+	refreshInterval := 0
+	if ctx.PluginInstance != nil {
+		instanceID = ctx.PluginInstance.ID
+		refreshInterval = ctx.PluginInstance.RefreshInterval
+	}
+
+	results, checkedAt := checkAll(instanceID, refreshInterval, method, time.Duration(timeoutSeconds)*time.Second, targets)
+
+	upCount := 0
+	checks := make([]interface{}, len(results))
+	for i, result := range results {
+		if up, _ := result["up"].(bool); up {
+			upCount++
+		}
+		checks[i] = result
+	}
+
+	logging.Debug("[HEALTH_CHECK] Checked endpoints", "instance_id", instanceID, "total", len(results), "up", upCount)
+
+	markup := strings.TrimSpace(ctx.GetStringSetting("markup", ""))
+	if markup == "" {
+		markup = defaultMarkup
+	}
+
+	templateData := map[string]interface{}{
+		"title":      ctx.GetStringSetting("title", defaultCheckTitle),
+		"checks":     checks,
+		"up_count":   upCount,
+		"down_count": len(results) - upCount,
+		"total":      len(results),
+		"checked_at": checkedAt.Format(time.RFC3339),
+	}
+
+	renderWidth, renderHeight := rendering.RenderDimensions(
+		ctx.Device.DeviceModel.ScreenWidth,
+		ctx.Device.DeviceModel.ScreenHeight,
+		ctx.Device.ScreenOrientation,
+	)
+
+	renderCtx, cancel := context.WithTimeout(ctx.Context(), 30*time.Second)
+	defer cancel()
+
+	html, err := rendering.NewUnifiedRenderer().RenderToHTML(renderCtx, rendering.PluginRenderOptions{
+		LayoutTemplate:    markup,
+		Data:              templateData,
+		Width:             renderWidth,
+		Height:            renderHeight,
+		PluginName:        p.Name(),
+		InstanceID:        instanceID.String(),
+		DeviceModelName:   ctx.Device.DeviceModel.ModelName,
+		BitDepth:          ctx.Device.DeviceModel.BitDepth,
+		ScreenOrientation: ctx.Device.ScreenOrientation,
+	})
+	if err != nil {
+		return plugins.CreateErrorResponse(fmt.Sprintf("Template rendering failed: %v", err)),
+			fmt.Errorf("failed to render health check template: %w", err)
+	}
+
+	browserRenderer, err := rendering.NewBrowserlessRenderer()
+	if err != nil {
+		return plugins.CreateErrorResponse(fmt.Sprintf("Failed to create renderer: %v", err)),
+			fmt.Errorf("failed to create browserless renderer: %w", err)
+	}
+	defer browserRenderer.Close()
+
+	imageData, err := browserRenderer.RenderHTML(renderCtx, html, renderWidth, renderHeight)
+	if err != nil {
+		return plugins.CreateErrorResponse(fmt.Sprintf("Failed to render HTML: %v", err)),
+			fmt.Errorf("failed to render HTML to image: %w", err)
+	}
+
+	if rotation := rendering.ImageRotation(ctx.Device.DeviceModel.ScreenWidth, ctx.Device.DeviceModel.ScreenHeight, ctx.Device.ScreenOrientation); rotation != "none" {
+		rotated, rotErr := imageprocessing.RotatePNGBytes(imageData, rotation)
+		if rotErr != nil {
+			return plugins.CreateErrorResponse(fmt.Sprintf("Failed to rotate image: %v", rotErr)),
+				fmt.Errorf("failed to rotate image: %w", rotErr)
+		}
+		imageData = rotated
+	}
+
+	filename := fmt.Sprintf("health_check_%s_%dx%d.png",
+		time.Now().UTC().Format("20060102_150405"),
+		ctx.Device.DeviceModel.ScreenWidth,
+		ctx.Device.DeviceModel.ScreenHeight)
+
+	// Return image data response (RenderWorker will quantize and store it)
+	return plugins.CreateImageDataResponse(imageData, filename), nil
+}
+
+// Register the plugin when this package is imported
+func init() {
+	plugins.Register(&HealthCheckPlugin{})
+}
@@ -1,6 +1,8 @@
 package plugins
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/rmitchellscott/stationmaster/internal/database"
 )
@@ -19,6 +21,17 @@ type PluginContext struct {
 	PluginInstance *database.PluginInstance
 	User           *database.User
 	Settings       map[string]interface{}
+	// Ctx is cancelled when the render is abandoned, such as on timeout. Plugins should read it through
+	// Context() and pass it to outbound HTTP and browserless calls.
+	Ctx context.Context
+}
+
+// Context returns the context bounding the plugin's work, or context.Background() if none was set
+func (c PluginContext) Context() context.Context {
+	if c.Ctx == nil {
+		return context.Background()
+	}
+	return c.Ctx
 }
 
 // PluginResponse is the response format returned by plugins
@@ -166,7 +166,7 @@ func (p *MashupPlugin) Process(ctx plugins.PluginContext) (plugins.PluginRespons
 				logging.Debug("[MASHUP] Stored polling data stale, actively polling", "instance_id", childInstanceID, "slot", child.SlotPosition)
 				unifiedRenderer := rendering.NewUnifiedRenderer()
 				poller := private.NewEnhancedDataPoller(unifiedRenderer)
-				pollingCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				pollingCtx, cancel := context.WithTimeout(ctx.Context(), 30*time.Second)
 				defer cancel()
 
 				pollStartTime := time.Now().UTC()
@@ -504,7 +504,7 @@ func (p *MashupPlugin) renderMashupParallel(layout string, childData map[string]
 					ScreenOrientation: ctx.Device.ScreenOrientation,
 				}
 
-				slotHTML, err = unifiedRenderer.ProcessTemplate(context.Background(), renderOptions)
+				slotHTML, err = unifiedRenderer.ProcessTemplate(ctx.Context(), renderOptions)
 				if err != nil {
 					htmlResultChan <- slotHTMLResult{
 						position: slotInfo.Position,
@@ -557,7 +557,7 @@ func (p *MashupPlugin) renderMashupParallel(layout string, childData map[string]
 
 			// Render to PNG using browserless with flag detection
 			renderResult, err := browserlessRenderer.RenderHTMLWithResult(
-				context.Background(),
+				ctx.Context(),
 				fullHTML,
 				ctx.Device.DeviceModel.ScreenWidth,
 				ctx.Device.DeviceModel.ScreenHeight,
@@ -684,9 +684,15 @@ func (p *MashupPlugin) buildMashupHTML(layout string, renderedSlots map[string]s
 		EnableBackdrop:    p.definition.EnableBackdrop != nil && *p.definition.EnableBackdrop,
 	})
 
+	// The gutter spaces the slots apart in the layout itself, so slot content flows into the smaller size
+	mashupStyle := ""
+	if p.definition.SlotGutter > 0 {
+		mashupStyle = fmt.Sprintf(` style="gap: %dpx"`, p.definition.SlotGutter)
+	}
+
 	contentBuilder.WriteString(fmt.Sprintf(`<div class="environment trmnl">
 	<div class="%s">
-		<div class="mashup mashup--%s">`, screenClasses, layout))
+		<div class="mashup mashup--%s"%s>`, screenClasses, layout, mashupStyle))
 
 	for _, slot := range slotConfig {
 		var slotContent string
@@ -776,7 +782,7 @@ func (p *MashupPlugin) fetchExternalPluginSlotHTML(childInfo ChildData, slotInfo
 	}
 	
 	// Create POST request
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonData)))
+	req, err := http.NewRequestWithContext(ctx.Context(), "POST", url, strings.NewReader(string(jsonData)))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
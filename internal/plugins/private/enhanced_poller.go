@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/rmitchellscott/stationmaster/internal/config"
 	"github.com/rmitchellscott/stationmaster/internal/database"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
 	"github.com/rmitchellscott/stationmaster/internal/rendering"
 )
 
@@ -22,7 +27,36 @@ type EnhancedPollingConfig struct {
 	Timeout     int                 `json:"timeout"`     // Request timeout in seconds
 	MaxSize     int                 `json:"max_size"`    // Maximum response size in bytes
 	UserAgent   string              `json:"user_agent"`  // Custom User-Agent header
-	RetryCount  int                 `json:"retry_count"` // Number of retries on failure
+	RetryCount  int                 `json:"retry_count"` // Number of retries on transient failure, defaults to POLLING_RETRY_COUNT
+}
+
+// pollHTTPError is returned when a polled URL answers with a non-2xx status
+type pollHTTPError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *pollHTTPError) Error() string {
+	return fmt.Sprintf("HTTP error: %d %s", e.StatusCode, e.Status)
+}
+
+// isTransientPollError reports whether a failed fetch is worth retrying: timeouts, network
+// errors, rate limiting and 5xx responses. Other HTTP errors and bad responses are not.
+func isTransientPollError(err error) bool {
+	var httpErr *pollHTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if urlErr.Timeout() {
+			return true
+		}
+		// Connection refused/reset and dropped connections, but not malformed URLs
+		var opErr *net.OpError
+		return errors.As(urlErr.Err, &opErr) || errors.Is(urlErr.Err, io.EOF) || errors.Is(urlErr.Err, io.ErrUnexpectedEOF)
+	}
+	return false
 }
 
 // EnhancedURLConfig represents configuration for a single URL to poll
@@ -50,9 +84,7 @@ type EnhancedDataPoller struct {
 // NewEnhancedDataPoller creates a new enhanced data poller
 func NewEnhancedDataPoller(renderer *rendering.UnifiedRenderer) *EnhancedDataPoller {
 	return &EnhancedDataPoller{
-		client: &http.Client{
-			Timeout: 30 * time.Second, // Default timeout, will be overridden per request
-		},
+		client:   utils.NewHTTPClient(30 * time.Second), // Default timeout, will be overridden per request
 		renderer: renderer,
 	}
 }
@@ -89,7 +121,7 @@ func (p *EnhancedDataPoller) PollData(ctx context.Context, plugin *database.Plug
 		config.MaxSize = 1024 * 1024 // 1MB default
 	}
 	if config.RetryCount == 0 {
-		config.RetryCount = 2
+		config.RetryCount = defaultPollingRetryCount()
 	}
 	if config.UserAgent == "" {
 		config.UserAgent = "TRMNL-Private-Plugin/1.0"
@@ -170,13 +202,25 @@ func (p *EnhancedDataPoller) pollSingleURL(ctx context.Context, urlConfig Enhanc
 	return results, nil
 }
 
-// fetchURLWithRetry fetches a URL with retry logic
+// defaultPollingRetryCount is the number of retries for plugins that don't set retry_count
+func defaultPollingRetryCount() int {
+	return max(config.GetInt("POLLING_RETRY_COUNT", 2), 0)
+}
+
+// pollingRetryBackoff is the wait before the first retry; it doubles on each further retry
+func pollingRetryBackoff() time.Duration {
+	return config.GetDuration("POLLING_RETRY_BACKOFF", 500*time.Millisecond)
+}
+
+// fetchURLWithRetry fetches a URL, retrying transient failures with exponential backoff
 func (p *EnhancedDataPoller) fetchURLWithRetry(ctx context.Context, urlConfig EnhancedURLConfig, config *EnhancedPollingConfig, templateData map[string]interface{}, renderedURL string) (interface{}, error) {
 	var lastErr error
+	maxRetries := max(config.RetryCount, 0)
+	backoff := pollingRetryBackoff()
 
-	for attempt := 0; attempt <= config.RetryCount; attempt++ {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
-			waitTime := time.Duration(attempt*attempt) * time.Second
+			waitTime := backoff << (attempt - 1)
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
@@ -189,18 +233,26 @@ func (p *EnhancedDataPoller) fetchURLWithRetry(ctx context.Context, urlConfig En
 
 		data, err := p.fetchURL(ctx, modifiedConfig, config, templateData)
 		if err == nil {
+			if attempt > 0 {
+				logging.Info("[ENHANCED_POLLER] URL fetch succeeded after retry", "url", renderedURL, "attempt", attempt+1)
+			}
 			return data, nil
 		}
 
 		lastErr = err
-		logging.Warn("[ENHANCED_POLLER] URL fetch failed, retrying",
-			"url", renderedURL,
-			"attempt", attempt+1,
-			"max_attempts", config.RetryCount+1,
-			"error", err)
+		if ctx.Err() != nil || !isTransientPollError(err) {
+			return nil, err
+		}
+		if attempt < maxRetries {
+			logging.Warn("[ENHANCED_POLLER] URL fetch failed, retrying",
+				"url", renderedURL,
+				"attempt", attempt+1,
+				"max_attempts", maxRetries+1,
+				"error", err)
+		}
 	}
 
-	return nil, fmt.Errorf("all attempts failed: %w", lastErr)
+	return nil, fmt.Errorf("all %d attempts failed: %w", maxRetries+1, lastErr)
 }
 
 // fetchURL fetches data from a single URL with template variable substitution
@@ -250,7 +302,7 @@ func (p *EnhancedDataPoller) fetchURL(ctx context.Context, urlConfig EnhancedURL
 
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("HTTP error: %d %s", resp.StatusCode, resp.Status)
+		return nil, &pollHTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
 	// Read response body with size limit
@@ -81,6 +81,11 @@ func (p *PrivatePlugin) Process(ctx plugins.PluginContext) (plugins.PluginRespon
 		return plugins.CreateErrorResponse("Device model information not available"),
 			fmt.Errorf("device model is required for private plugin processing")
 	}
+
+	// Image webhook plugins show the pushed image directly, without a template
+	if p.definition.DataStrategy != nil && *p.definition.DataStrategy == DataStrategyImage {
+		return p.processWebhookImage(ctx)
+	}
 	
 	// Get the user's template from the definition
 	if p.definition.MarkupFull == nil || *p.definition.MarkupFull == "" {
@@ -133,7 +138,7 @@ func (p *PrivatePlugin) Process(ctx plugins.PluginContext) (plugins.PluginRespon
 			// Poll fresh data and store it
 			unifiedRenderer := rendering.NewUnifiedRenderer()
 			poller := NewEnhancedDataPoller(unifiedRenderer)
-			pollingCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			pollingCtx, cancel := context.WithTimeout(ctx.Context(), 30*time.Second)
 			defer cancel()
 			
 			pollStartTime := time.Now().UTC()
@@ -279,7 +284,7 @@ func (p *PrivatePlugin) Process(ctx plugins.PluginContext) (plugins.PluginRespon
 	}
 
 	// Use Ruby server-side rendering (required)
-	html, err := htmlRenderer.RenderToServerSideHTML(context.Background(), renderOptions)
+	html, err := htmlRenderer.RenderToServerSideHTML(ctx.Context(), renderOptions)
 	if err != nil {
 		return plugins.CreateErrorResponse(fmt.Sprintf("Ruby template rendering failed: %v", err)),
 			fmt.Errorf("failed to render HTML template with Ruby: %w", err)
@@ -294,7 +299,7 @@ func (p *PrivatePlugin) Process(ctx plugins.PluginContext) (plugins.PluginRespon
 	defer browserRenderer.Close()
 
 	// Always render HTML to image using browserless
-	renderCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	renderCtx, cancel := context.WithTimeout(ctx.Context(), 30*time.Second)
 	defer cancel()
 
 	renderResult, err := browserRenderer.RenderHTMLWithResult(
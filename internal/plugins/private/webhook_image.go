@@ -0,0 +1,160 @@
+package private
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/imageprocessing"
+	"github.com/rmitchellscott/stationmaster/internal/plugins"
+	"github.com/rmitchellscott/stationmaster/internal/rendering"
+	"github.com/rmitchellscott/stationmaster/internal/storage"
+)
+
+// DataStrategyImage is the data strategy for private plugins whose webhook receives a ready-made image
+// that is shown as-is instead of rendering a template
+const DataStrategyImage = "image"
+
+const (
+	// webhookImageAreaFactor is how many times the largest device screen area a webhook image may cover
+	webhookImageAreaFactor = 4
+	// defaultLargestScreenArea is used when no device models are known (1872x1404, the largest TRMNL panel)
+	defaultLargestScreenArea = 1872 * 1404
+)
+
+// ErrWebhookImageTooLarge is returned for webhook images with more pixels than any device could need
+var ErrWebhookImageTooLarge = errors.New("image dimensions are too large")
+
+// maxWebhookImagePixels returns the largest pixel count accepted for a webhook image. Compressed images can
+// declare far more pixels than their byte size suggests, and decoding allocates for every one of them.
+func maxWebhookImagePixels() int {
+	var largestArea int
+	database.GetDB().Model(&database.DeviceModel{}).Select("COALESCE(MAX(screen_width * screen_height), 0)").Scan(&largestArea)
+	if largestArea <= 0 {
+		largestArea = defaultLargestScreenArea
+	}
+	return largestArea * webhookImageAreaFactor
+}
+
+// checkWebhookImageSize rejects images whose declared dimensions exceed maxWebhookImagePixels
+func checkWebhookImageSize(imgConfig image.Config) error {
+	if imgConfig.Width <= 0 || imgConfig.Height <= 0 {
+		return fmt.Errorf("invalid image dimensions %dx%d", imgConfig.Width, imgConfig.Height)
+	}
+	if maxPixels := maxWebhookImagePixels(); imgConfig.Width*imgConfig.Height > maxPixels {
+		return fmt.Errorf("%w: %dx%d is more than %d pixels", ErrWebhookImageTooLarge, imgConfig.Width, imgConfig.Height, maxPixels)
+	}
+	return nil
+}
+
+// webhookImageKey returns the storage key of the latest image pushed to a plugin instance webhook
+func webhookImageKey(instanceID uuid.UUID) string {
+	return fmt.Sprintf("webhook_images/%s", instanceID.String())
+}
+
+// StoreWebhookImage validates and stores an image pushed to a plugin instance webhook,
+// replacing any previous image. It returns the decoded image dimensions.
+func StoreWebhookImage(ctx context.Context, instanceID uuid.UUID, data []byte) (int, int, error) {
+	imgConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, fmt.Errorf("unsupported image data: %w", err)
+	}
+	if err := checkWebhookImageSize(imgConfig); err != nil {
+		return 0, 0, err
+	}
+
+	if err := storage.GetStorageBackend().Put(ctx, webhookImageKey(instanceID), bytes.NewReader(data)); err != nil {
+		return 0, 0, fmt.Errorf("failed to store webhook image: %w", err)
+	}
+
+	return imgConfig.Width, imgConfig.Height, nil
+}
+
+// DeleteWebhookImage removes the image stored for a plugin instance webhook, if any
+func DeleteWebhookImage(ctx context.Context, instanceID uuid.UUID) error {
+	return storage.GetStorageBackend().Delete(ctx, webhookImageKey(instanceID))
+}
+
+// loadWebhookImage reads and decodes the latest image pushed to a plugin instance webhook
+func loadWebhookImage(ctx context.Context, instanceID uuid.UUID) (image.Image, error) {
+	reader, err := storage.GetStorageBackend().Get(ctx, webhookImageKey(instanceID))
+	if err != nil {
+		return nil, fmt.Errorf("no image has been received by the webhook yet: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook image: %w", err)
+	}
+
+	// Images stored before dimensions were checked could still be too large to decode
+	imgConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode webhook image: %w", err)
+	}
+	if err := checkWebhookImageSize(imgConfig); err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode webhook image: %w", err)
+	}
+
+	return img, nil
+}
+
+// processWebhookImage fits the latest webhook image to the device instead of rendering a template.
+// The render worker quantizes the result to the device bit depth like any other private plugin output.
+func (p *PrivatePlugin) processWebhookImage(ctx plugins.PluginContext) (plugins.PluginResponse, error) {
+	if p.instance == nil || p.instance.ID == uuid.Nil {
+		return plugins.CreateErrorResponse("Image webhook plugins require a plugin instance"),
+			fmt.Errorf("no plugin instance for image webhook plugin %s", p.definition.ID)
+	}
+
+	img, err := loadWebhookImage(ctx.Context(), p.instance.ID)
+	if err != nil {
+		return plugins.CreateErrorResponse("No image has been received by the webhook yet"), err
+	}
+
+	renderWidth, renderHeight := rendering.RenderDimensions(
+		ctx.Device.DeviceModel.ScreenWidth,
+		ctx.Device.DeviceModel.ScreenHeight,
+		ctx.Device.ScreenOrientation,
+	)
+
+	// Dither photos here, the render worker only quantizes without dithering
+	resized := imageprocessing.ResizeToFill(img, renderWidth, renderHeight)
+	dithered := imageprocessing.DitherFloydSteinbergLevels(imageprocessing.ToGrayscale(resized), ctx.Device.DeviceModel.BitDepth, ctx.Device.DeviceModel.GrayscaleLevels)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dithered); err != nil {
+		return plugins.CreateErrorResponse(fmt.Sprintf("Failed to encode image: %v", err)),
+			fmt.Errorf("failed to encode webhook image: %w", err)
+	}
+	imageData := buf.Bytes()
+
+	if rotation := rendering.ImageRotation(ctx.Device.DeviceModel.ScreenWidth, ctx.Device.DeviceModel.ScreenHeight, ctx.Device.ScreenOrientation); rotation != "none" {
+		rotated, rotErr := imageprocessing.RotatePNGBytes(imageData, rotation)
+		if rotErr != nil {
+			return plugins.CreateErrorResponse(fmt.Sprintf("Failed to rotate image: %v", rotErr)),
+				fmt.Errorf("failed to rotate image: %w", rotErr)
+		}
+		imageData = rotated
+	}
+
+	filename := fmt.Sprintf("private_plugin_image_%s_%dx%d.png",
+		time.Now().UTC().Format("20060102_150405"),
+		ctx.Device.DeviceModel.ScreenWidth,
+		ctx.Device.DeviceModel.ScreenHeight)
+
+	return plugins.CreateImageDataResponse(imageData, filename), nil
+}
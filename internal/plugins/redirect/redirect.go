@@ -115,12 +115,15 @@ func (p *RedirectPlugin) Process(ctx plugins.PluginContext) (plugins.PluginRespo
 	}
 
 	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: time.Duration(timeoutSeconds) * time.Second,
-	}
+	client := utils.NewHTTPClient(time.Duration(timeoutSeconds) * time.Second)
 
 	// Fetch JSON from endpoint
-	resp, err := client.Get(endpointURL)
+	req, err := http.NewRequestWithContext(ctx.Context(), "GET", endpointURL, nil)
+	if err != nil {
+		return plugins.CreateErrorResponse(fmt.Sprintf("Failed to create request: %v", err)),
+			fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return plugins.CreateErrorResponse(fmt.Sprintf("Failed to fetch from endpoint: %v", err)),
 			fmt.Errorf("failed to fetch from endpoint: %w", err)
@@ -16,6 +16,12 @@ import (
 	"github.com/rmitchellscott/stationmaster/internal/utils"
 )
 
+const (
+	minViewportSize      = 320
+	maxViewportSize      = 3840
+	maxDeviceScaleFactor = 3
+)
+
 // ScreenshotPlugin implements a data plugin that captures screenshots of web pages
 type ScreenshotPlugin struct{}
 
@@ -79,6 +85,40 @@ func (p *ScreenshotPlugin) ConfigSchema() string {
 				"title": "HTTP Headers",
 				"description": "Custom HTTP headers in format: key1=value1&key2=value2 (use %3D for = in values)",
 				"examples": ["authorization=bearer token123", "authorization=bearer%20jwt%3D%3D&content-type=application/json"]
+			},
+			"viewport_width": {
+				"type": "integer",
+				"title": "Viewport Width",
+				"description": "Browser width in pixels used to lay out the page. Leave empty to use the device width; the capture is scaled to fit the device",
+				"minimum": 320,
+				"maximum": 3840
+			},
+			"viewport_height": {
+				"type": "integer",
+				"title": "Viewport Height",
+				"description": "Browser height in pixels used to lay out the page. Leave empty to use the device height",
+				"minimum": 320,
+				"maximum": 3840
+			},
+			"device_scale_factor": {
+				"type": "number",
+				"title": "Device Scale Factor",
+				"description": "Pixel density to emulate, e.g. 2 for a high-DPI phone. Higher values give sharper text after downscaling",
+				"minimum": 1,
+				"maximum": 3,
+				"default": 1
+			},
+			"emulate_mobile": {
+				"type": "boolean",
+				"title": "Emulate Mobile Device",
+				"description": "Render the page as a mobile browser so responsive sites use their mobile layout",
+				"default": false
+			},
+			"user_agent": {
+				"type": "string",
+				"title": "User Agent",
+				"description": "Custom User-Agent header sent by the browser. Leave empty for the browser default",
+				"examples": ["Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"]
 			}
 		},
 		"required": ["url"]
@@ -150,6 +190,30 @@ func (p *ScreenshotPlugin) Validate(settings map[string]interface{}) error {
 		}
 	}
 
+	// Validate viewport dimensions if provided
+	for _, key := range []string{"viewport_width", "viewport_height"} {
+		if value, exists := settings[key]; exists && value != nil && value != "" {
+			size, ok := value.(float64)
+			if !ok {
+				return fmt.Errorf("%s must be a number (pixels)", key)
+			}
+			if size < minViewportSize || size > maxViewportSize {
+				return fmt.Errorf("%s must be between %d and %d pixels", key, minViewportSize, maxViewportSize)
+			}
+		}
+	}
+
+	// Validate device scale factor if provided
+	if value, exists := settings["device_scale_factor"]; exists && value != nil && value != "" {
+		scale, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("device scale factor must be a number")
+		}
+		if scale < 1 || scale > maxDeviceScaleFactor {
+			return fmt.Errorf("device scale factor must be between 1 and %d", maxDeviceScaleFactor)
+		}
+	}
+
 	// Validate headers if provided
 	if headerStr, exists := settings["headers"]; exists {
 		if headerStrValue, ok := headerStr.(string); ok {
@@ -200,17 +264,23 @@ func (p *ScreenshotPlugin) Process(ctx plugins.PluginContext) (plugins.PluginRes
 	defer renderer.Close()
 
 	// Capture screenshot using browserless with device resolution
-	screenshotCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	screenshotCtx, cancel := context.WithTimeout(ctx.Context(), 60*time.Second)
 	defer cancel()
 
-	imageData, err := renderer.CaptureScreenshot(
-		screenshotCtx, 
-		url, 
-		ctx.Device.DeviceModel.ScreenWidth, 
-		ctx.Device.DeviceModel.ScreenHeight, 
-		waitTimeSeconds,
-		headers,
-	)
+	// The viewport defaults to the device size; a custom viewport is scaled to the device after capture
+	viewport := rendering.ScreenshotViewport{
+		Width:             ctx.GetIntSetting("viewport_width", ctx.Device.DeviceModel.ScreenWidth),
+		Height:            ctx.GetIntSetting("viewport_height", ctx.Device.DeviceModel.ScreenHeight),
+		DeviceScaleFactor: ctx.GetFloatSetting("device_scale_factor", 1),
+		IsMobile:          ctx.GetBoolSetting("emulate_mobile", false),
+	}
+
+	imageData, err := renderer.CaptureScreenshotWithOptions(screenshotCtx, url, rendering.ScreenshotOptions{
+		Viewport:        viewport,
+		UserAgent:       strings.TrimSpace(ctx.GetStringSetting("user_agent", "")),
+		WaitTimeSeconds: waitTimeSeconds,
+		Headers:         headers,
+	})
 	if err != nil {
 		return plugins.CreateErrorResponse(fmt.Sprintf("Failed to capture screenshot: %v", err)),
 			fmt.Errorf("failed to capture screenshot of %s: %w", url, err)
@@ -0,0 +1,114 @@
+package pollers
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/smtp"
+	"github.com/rmitchellscott/stationmaster/internal/trmnl"
+)
+
+// DeviceCleanupPoller unclaims devices that have not checked in for longer than a configured threshold
+type DeviceCleanupPoller struct {
+	*BasePoller
+	db        *gorm.DB
+	threshold time.Duration
+}
+
+// NewDeviceCleanupPoller creates a new device cleanup poller. It is disabled unless
+// DEVICE_INACTIVITY_UNCLAIM is set to true.
+func NewDeviceCleanupPoller(db *gorm.DB) *DeviceCleanupPoller {
+	interval := config.GetDuration("DEVICE_INACTIVITY_CHECK_INTERVAL", time.Hour)
+	threshold := config.GetDuration("DEVICE_INACTIVITY_THRESHOLD", 90*24*time.Hour)
+	enabled := config.GetBool("DEVICE_INACTIVITY_UNCLAIM", false)
+
+	if threshold <= 0 {
+		logging.WarnWithComponent(logging.ComponentDeviceCleanup, "Invalid inactivity threshold, disabling device cleanup", "threshold", threshold)
+		enabled = false
+	}
+
+	config := PollerConfig{
+		Name:       "device_cleanup",
+		Interval:   interval,
+		Enabled:    enabled,
+		MaxRetries: 1,
+		RetryDelay: time.Minute,
+		Timeout:    5 * time.Minute,
+	}
+
+	poller := &DeviceCleanupPoller{
+		db:        db,
+		threshold: threshold,
+	}
+
+	poller.BasePoller = NewBasePoller(config, poller.poll)
+	return poller
+}
+
+// poll unlinks every claimed device whose last check-in is older than the threshold
+func (p *DeviceCleanupPoller) poll(ctx context.Context) error {
+	cutoff := time.Now().Add(-p.threshold)
+
+	var devices []database.Device
+	if err := p.db.WithContext(ctx).Preload("User").
+		Where("is_claimed = ? AND last_seen IS NOT NULL AND last_seen < ?", true, cutoff).
+		Find(&devices).Error; err != nil {
+		return err
+	}
+
+	if len(devices) == 0 {
+		return nil
+	}
+
+	deviceService := database.NewDeviceService(p.db)
+	unlinked := 0
+
+	for _, device := range devices {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := deviceService.UnlinkDevice(device.ID); err != nil {
+			logging.ErrorWithComponent(logging.ComponentDeviceCleanup, "Failed to unclaim inactive device",
+				"device_id", device.ID, "error", err)
+			continue
+		}
+		trmnl.ClearLastRequestHeaders(device.ID)
+		unlinked++
+
+		logging.InfoWithComponent(logging.ComponentDeviceCleanup, "Unclaimed inactive device",
+			"device_id", device.ID,
+			"friendly_id", device.FriendlyID,
+			"user_id", device.UserID,
+			"last_seen", device.LastSeen,
+			"threshold", p.threshold)
+
+		p.notifyOwner(device)
+	}
+
+	logging.InfoWithComponent(logging.ComponentDeviceCleanup, "Inactive device cleanup complete",
+		"unclaimed", unlinked, "candidates", len(devices))
+	return nil
+}
+
+// notifyOwner emails the former owner of an unclaimed device when SMTP is available
+func (p *DeviceCleanupPoller) notifyOwner(device database.Device) {
+	if device.User == nil || device.User.Email == "" || !smtp.IsSMTPConfiguredForUser(device.User) {
+		return
+	}
+
+	name := device.Name
+	if name == "" {
+		name = device.FriendlyID
+	}
+
+	if err := smtp.SendDeviceUnclaimedEmail(device.User, name, *device.LastSeen, p.threshold); err != nil {
+		logging.WarnWithComponent(logging.ComponentDeviceCleanup, "Failed to notify owner of unclaimed device",
+			"device_id", device.ID, "user_id", device.User.ID, "error", err)
+	}
+}
@@ -19,15 +19,18 @@ import (
 	"github.com/rmitchellscott/stationmaster/internal/config"
 	"github.com/rmitchellscott/stationmaster/internal/database"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
 )
 
 const s3BaseURL = "https://trmnl-fw.s3.us-east-2.amazonaws.com"
 const defaultManifestURL = "https://trmnl.com/firmware/releases.json"
+const defaultReleaseNotesURL = "https://api.github.com/repos/usetrmnl/trmnl-firmware/releases"
 
 type FirmwarePoller struct {
 	*BasePoller
 	db           *gorm.DB
 	manifestURL  string
+	notesURL     string
 	s3BucketURL  string
 	storageDir   string
 	firmwareMode string
@@ -39,6 +42,13 @@ type FirmwareManifestEntry struct {
 	Versions   []string `json:"versions"`
 }
 
+// firmwareRelease is the part of an upstream release used for release notes
+type firmwareRelease struct {
+	TagName string `json:"tag_name"`
+	Body    string `json:"body"`
+	Draft   bool   `json:"draft"`
+}
+
 type s3ListResult struct {
 	XMLName  xml.Name  `xml:"ListBucketResult"`
 	Contents []s3Entry `xml:"Contents"`
@@ -51,15 +61,16 @@ type s3Entry struct {
 }
 
 type discoveredVersion struct {
-	Family      string
-	Version     string
-	RawVersion  string
-	DownloadURL string
-	ReleasedAt  time.Time
-	FileSize    int64
-	IsStable    bool
-	ChipFamily  string
-	Label       string
+	Family       string
+	Version      string
+	RawVersion   string
+	DownloadURL  string
+	ReleasedAt   time.Time
+	FileSize     int64
+	IsStable     bool
+	ChipFamily   string
+	Label        string
+	ReleaseNotes string
 }
 
 func NewFirmwarePoller(db *gorm.DB) *FirmwarePoller {
@@ -90,6 +101,7 @@ func NewFirmwarePoller(db *gorm.DB) *FirmwarePoller {
 	poller := &FirmwarePoller{
 		db:           db,
 		manifestURL:  manifestURL,
+		notesURL:     config.Get("TRMNL_FIRMWARE_RELEASE_NOTES_URL", defaultReleaseNotesURL),
 		s3BucketURL:  s3BaseURL,
 		storageDir:   storageDir,
 		firmwareMode: firmwareMode,
@@ -200,6 +212,12 @@ func (p *FirmwarePoller) discoverAllVersions(ctx context.Context) ([]discoveredV
 		manifest = nil
 	}
 
+	// Fetch release notes — optional, versions are still tracked without them
+	releaseNotes, err := p.fetchReleaseNotes(ctx)
+	if err != nil {
+		logging.Warn("[FIRMWARE POLLER] Failed to fetch release notes", "error", err)
+	}
+
 	// Build stable version set from manifest
 	stableSet := map[string]map[string]bool{}          // family -> version -> true
 	familyMeta := map[string]FirmwareManifestEntry{}    // family -> metadata
@@ -235,15 +253,16 @@ func (p *FirmwarePoller) discoverAllVersions(ctx context.Context) ([]discoveredV
 		meta := familyMeta[sv.Family]
 
 		results = append(results, discoveredVersion{
-			Family:      sv.Family,
-			Version:     sv.Version,
-			RawVersion:  sv.RawVersion,
-			DownloadURL: sv.DownloadURL,
-			ReleasedAt:  sv.ReleasedAt,
-			FileSize:    sv.FileSize,
-			IsStable:    stable,
-			ChipFamily:  meta.ChipFamily,
-			Label:       meta.Label,
+			Family:       sv.Family,
+			Version:      sv.Version,
+			RawVersion:   sv.RawVersion,
+			DownloadURL:  sv.DownloadURL,
+			ReleasedAt:   sv.ReleasedAt,
+			FileSize:     sv.FileSize,
+			IsStable:     stable,
+			ChipFamily:   meta.ChipFamily,
+			Label:        meta.Label,
+			ReleaseNotes: releaseNotes[sv.Version],
 		})
 	}
 
@@ -260,7 +279,7 @@ type s3Version struct {
 }
 
 func (p *FirmwarePoller) fetchS3Versions(ctx context.Context) ([]s3Version, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := utils.NewHTTPClient(30 * time.Second)
 	req, err := http.NewRequestWithContext(ctx, "GET", p.s3BucketURL, nil)
 	if err != nil {
 		return nil, err
@@ -322,7 +341,7 @@ func parseS3Key(key string) (string, string) {
 }
 
 func (p *FirmwarePoller) fetchManifest(ctx context.Context) (map[string]FirmwareManifestEntry, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := utils.NewHTTPClient(30 * time.Second)
 	req, err := http.NewRequestWithContext(ctx, "GET", p.manifestURL, nil)
 	if err != nil {
 		return nil, err
@@ -346,6 +365,45 @@ func (p *FirmwarePoller) fetchManifest(ctx context.Context) (map[string]Firmware
 	return manifest, nil
 }
 
+// fetchReleaseNotes returns upstream release notes keyed by cleaned version. An empty URL disables it.
+func (p *FirmwarePoller) fetchReleaseNotes(ctx context.Context) (map[string]string, error) {
+	if p.notesURL == "" {
+		return nil, nil
+	}
+
+	client := utils.NewHTTPClient(30 * time.Second)
+	req, err := http.NewRequestWithContext(ctx, "GET", p.notesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release notes returned status %d", resp.StatusCode)
+	}
+
+	var releases []firmwareRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode release notes: %w", err)
+	}
+
+	notes := make(map[string]string)
+	for _, release := range releases {
+		body := strings.TrimSpace(release.Body)
+		if release.Draft || body == "" {
+			continue
+		}
+		notes[cleanVersion(strings.TrimPrefix(release.TagName, "v"))] = body
+	}
+	return notes, nil
+}
+
 func cleanVersion(version string) string {
 	return strings.TrimPrefix(version, "FW")
 }
@@ -358,6 +416,7 @@ func (p *FirmwarePoller) upsertFirmwareVersion(ctx context.Context, v discovered
 		changed := false
 		if existing.IsStable != v.IsStable {
 			existing.IsStable = v.IsStable
+			existing.Channel = firmwareChannelFor(v.IsStable)
 			changed = true
 		}
 		if existing.ChipFamily != v.ChipFamily && v.ChipFamily != "" {
@@ -372,6 +431,10 @@ func (p *FirmwarePoller) upsertFirmwareVersion(ctx context.Context, v discovered
 			existing.FileSize = v.FileSize
 			changed = true
 		}
+		if existing.ReleaseNotes != v.ReleaseNotes && v.ReleaseNotes != "" {
+			existing.ReleaseNotes = v.ReleaseNotes
+			changed = true
+		}
 		if changed {
 			return p.db.Save(&existing).Error
 		}
@@ -387,10 +450,12 @@ func (p *FirmwarePoller) upsertFirmwareVersion(ctx context.Context, v discovered
 		ModelFamily:    v.Family,
 		ChipFamily:     v.ChipFamily,
 		FamilyLabel:    v.Label,
+		ReleaseNotes:   v.ReleaseNotes,
 		DownloadURL:    v.DownloadURL,
 		FileSize:       v.FileSize,
 		IsLatest:       false,
 		IsStable:       v.IsStable,
+		Channel:        firmwareChannelFor(v.IsStable),
 		IsDownloaded:   false,
 		DownloadStatus: "pending",
 		ReleasedAt:     v.ReleasedAt,
@@ -406,6 +471,14 @@ func (p *FirmwarePoller) upsertFirmwareVersion(ctx context.Context, v discovered
 	return p.updateLatestForFamily(v.Family)
 }
 
+// firmwareChannelFor maps the manifest's stability flag to a release channel
+func firmwareChannelFor(stable bool) string {
+	if stable {
+		return database.FirmwareChannelStable
+	}
+	return database.FirmwareChannelBeta
+}
+
 func (p *FirmwarePoller) updateLatestForFamily(family string) error {
 	tx := p.db.Begin()
 
@@ -466,7 +539,7 @@ func (p *FirmwarePoller) downloadFirmwareFile(ctx context.Context, firmware *dat
 
 	logging.Info("[FIRMWARE POLLER] Downloading firmware", "family", firmware.ModelFamily, "version", firmware.Version)
 
-	client := &http.Client{Timeout: 5 * time.Minute}
+	client := utils.NewHTTPClient(5 * time.Minute)
 	req, err := http.NewRequestWithContext(ctx, "GET", firmware.DownloadURL, nil)
 	if err != nil {
 		p.markDownloadFailed(firmware, fmt.Sprintf("Failed to create request: %v", err))
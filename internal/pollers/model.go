@@ -11,7 +11,9 @@ import (
 
 	"github.com/rmitchellscott/stationmaster/internal/config"
 	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/imageprocessing"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
 )
 
 // ModelPoller polls for device model updates
@@ -102,9 +104,7 @@ func (p *ModelPoller) poll(ctx context.Context) error {
 
 // fetchDeviceModels fetches device model information from the API
 func (p *ModelPoller) fetchDeviceModels(ctx context.Context) ([]DeviceModelInfo, error) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	client := utils.NewHTTPClient(30 * time.Second)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", p.apiURL, nil)
 	if err != nil {
@@ -216,6 +216,13 @@ func (p *ModelPoller) processDeviceModel(ctx context.Context, modelInfo DeviceMo
 		ApiLastSeenAt: &now,
 	}
 
+	// Keep an admin's grayscale level override across model versions while it still fits the bit depth
+	var previousModel database.DeviceModel
+	if err := p.db.Where("model_name = ? AND deleted_at IS NULL", modelName).Order("created_at DESC").First(&previousModel).Error; err == nil &&
+		imageprocessing.ValidGrayscaleLevels(deviceModel.BitDepth, previousModel.GrayscaleLevels) {
+		deviceModel.GrayscaleLevels = previousModel.GrayscaleLevels
+	}
+
 	if err := p.db.Create(&deviceModel).Error; err != nil {
 		return fmt.Errorf("failed to create device model: %w", err)
 	}
@@ -0,0 +1,165 @@
+package rendering
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// Render scheduling modes selected with RENDER_PRIORITY_MODE
+const (
+	RenderPriorityStatic   = "static"   // Order jobs by priority and scheduled time only
+	RenderPriorityActivity = "activity" // Boost jobs for instances shown on recently active devices
+)
+
+// activityCandidateFactor widens the pending job query in activity mode so jobs for active devices
+// further down the static order can still be picked
+const activityCandidateFactor = 5
+
+// activityBoosts are added to a job's priority by how recently the most active device showing the
+// instance checked in. They stay below the 999 used for user-triggered renders.
+var activityBoosts = []struct {
+	within time.Duration
+	boost  int
+}{
+	{15 * time.Minute, 90},
+	{time.Hour, 60},
+	{24 * time.Hour, 30},
+}
+
+// renderPriorityMode returns the configured scheduling mode, falling back to static
+func renderPriorityMode() string {
+	mode := strings.ToLower(strings.TrimSpace(config.Get("RENDER_PRIORITY_MODE", RenderPriorityStatic)))
+	if mode != RenderPriorityActivity {
+		return RenderPriorityStatic
+	}
+	return mode
+}
+
+// pendingJobCandidates returns how many per-instance jobs to load for a batch of the given size
+func pendingJobCandidates(batchSize int) int {
+	if renderPriorityMode() == RenderPriorityActivity {
+		return batchSize * activityCandidateFactor
+	}
+	return batchSize
+}
+
+// activityBoost returns the priority boost for a device last seen at lastSeen
+func activityBoost(lastSeen time.Time, now time.Time) int {
+	if lastSeen.IsZero() {
+		return 0
+	}
+	age := now.Sub(lastSeen)
+	for _, tier := range activityBoosts {
+		if age <= tier.within {
+			return tier.boost
+		}
+	}
+	return 0
+}
+
+// instanceLastSeen returns the latest check-in of any active device showing each instance, directly,
+// as a mashup child or as the empty playlist fallback
+func instanceLastSeen(ctx context.Context, db *gorm.DB, instanceIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	type row struct {
+		PluginInstanceID uuid.UUID
+		LastSeen         *time.Time
+	}
+	queries := []string{
+		`SELECT playlist_items.plugin_instance_id AS plugin_instance_id, devices.last_seen AS last_seen
+		FROM playlist_items
+		JOIN playlists ON playlists.id = playlist_items.playlist_id
+		JOIN devices ON devices.id = playlists.device_id
+		WHERE playlist_items.plugin_instance_id IN ? AND devices.is_active = true AND devices.last_seen IS NOT NULL`,
+		`SELECT mashup_children.child_instance_id AS plugin_instance_id, devices.last_seen AS last_seen
+		FROM mashup_children
+		JOIN playlist_items ON playlist_items.plugin_instance_id = mashup_children.mashup_instance_id
+		JOIN playlists ON playlists.id = playlist_items.playlist_id
+		JOIN devices ON devices.id = playlists.device_id
+		WHERE mashup_children.child_instance_id IN ? AND devices.is_active = true AND devices.last_seen IS NOT NULL`,
+		`SELECT empty_playlist_instance_id AS plugin_instance_id, last_seen
+		FROM devices
+		WHERE empty_playlist_instance_id IN ? AND empty_playlist_mode = ?
+		AND is_active = true AND last_seen IS NOT NULL`,
+	}
+
+	lastSeen := make(map[uuid.UUID]time.Time)
+	for i, query := range queries {
+		args := []interface{}{instanceIDs}
+		if i == len(queries)-1 {
+			args = append(args, database.EmptyPlaylistModePlugin)
+		}
+		var rows []row
+		if err := db.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			if r.LastSeen != nil && r.LastSeen.After(lastSeen[r.PluginInstanceID]) {
+				lastSeen[r.PluginInstanceID] = *r.LastSeen
+			}
+		}
+	}
+	return lastSeen, nil
+}
+
+// prioritizeJobs orders jobs for processing and trims them to limit. In static mode the database order
+// is kept; in activity mode jobs are ordered by priority plus the activity boost of their devices.
+// Preview jobs get the highest boost since someone is waiting on them.
+func prioritizeJobs(ctx context.Context, db *gorm.DB, jobs []database.RenderQueue, limit int) []database.RenderQueue {
+	if renderPriorityMode() != RenderPriorityActivity || len(jobs) == 0 {
+		return jobs
+	}
+
+	var instanceIDs []uuid.UUID
+	for _, job := range jobs {
+		if job.PluginInstanceID != nil {
+			instanceIDs = append(instanceIDs, *job.PluginInstanceID)
+		}
+	}
+	lastSeen := map[uuid.UUID]time.Time{}
+	if len(instanceIDs) > 0 {
+		var err error
+		lastSeen, err = instanceLastSeen(ctx, db, instanceIDs)
+		if err != nil {
+			logging.Warn("[RENDER_WORKER] Failed to load device activity, using static priority", "error", err)
+			return truncateJobs(jobs, limit)
+		}
+	}
+
+	now := time.Now().UTC()
+	effective := make(map[uuid.UUID]int, len(jobs))
+	for _, job := range jobs {
+		boost := 0
+		switch {
+		case job.IsPreview:
+			boost = activityBoosts[0].boost
+		case job.PluginInstanceID != nil:
+			boost = activityBoost(lastSeen[*job.PluginInstanceID], now)
+		}
+		effective[job.ID] = job.Priority + boost
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if effective[jobs[i].ID] != effective[jobs[j].ID] {
+			return effective[jobs[i].ID] > effective[jobs[j].ID]
+		}
+		return jobs[i].ScheduledFor.Before(jobs[j].ScheduledFor)
+	})
+	return truncateJobs(jobs, limit)
+}
+
+// truncateJobs keeps at most limit jobs
+func truncateJobs(jobs []database.RenderQueue, limit int) []database.RenderQueue {
+	if limit > 0 && len(jobs) > limit {
+		return jobs[:limit]
+	}
+	return jobs
+}
@@ -0,0 +1,193 @@
+package rendering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/sse"
+)
+
+// ErrBrowserlessUnavailable is returned instead of calling browserless while the circuit breaker is open
+var ErrBrowserlessUnavailable = errors.New("browserless is unavailable")
+
+// BrowserlessState describes the browserless circuit breaker for health reporting
+type BrowserlessState struct {
+	Available           bool       `json:"available"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// browserlessBreaker stops sending requests to browserless after repeated failures. Once the retry
+// interval has passed requests are let through again; the first success closes the breaker and
+// another failure reopens it for a further interval.
+type browserlessBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	open      bool
+	openedAt  time.Time
+	retryAt   time.Time
+	lastError string
+}
+
+var breaker = &browserlessBreaker{}
+
+// browserlessFailureThreshold is the number of consecutive failures that opens the breaker (0 disables it)
+func browserlessFailureThreshold() int {
+	return config.GetInt("BROWSERLESS_FAILURE_THRESHOLD", 5)
+}
+
+// browserlessRetryInterval is how long the breaker stays open before browserless is tried again
+func browserlessRetryInterval() time.Duration {
+	return config.GetDuration("BROWSERLESS_RETRY_INTERVAL", time.Minute)
+}
+
+// allow reports whether a request may be sent to browserless
+func (b *browserlessBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.open || !time.Now().Before(b.retryAt)
+}
+
+// recordSuccess closes the breaker, announcing the recovery if it was open
+func (b *browserlessBreaker) recordSuccess() {
+	b.mu.Lock()
+	wasOpen := b.open
+	downtime := time.Since(b.openedAt)
+	b.failures = 0
+	b.open = false
+	b.lastError = ""
+	b.mu.Unlock()
+
+	if wasOpen {
+		logging.Info("[BROWSERLESS] Browserless is reachable again, resuming renders", "downtime", downtime.Round(time.Second))
+		notifyAdmins(sse.Event{
+			Type: "browserless_recovered",
+			Data: map[string]interface{}{"downtime_seconds": int(downtime.Seconds())},
+		})
+	}
+}
+
+// recordFailure counts a failed request, opening the breaker once the threshold is reached
+func (b *browserlessBreaker) recordFailure(err error) {
+	threshold := browserlessFailureThreshold()
+	if threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	b.failures++
+	b.lastError = err.Error()
+	now := time.Now()
+	if b.open {
+		// A failed retry keeps the breaker open for another interval
+		b.retryAt = now.Add(browserlessRetryInterval())
+		b.mu.Unlock()
+		return
+	}
+	if b.failures < threshold {
+		b.mu.Unlock()
+		return
+	}
+	b.open = true
+	b.openedAt = now
+	b.retryAt = now.Add(browserlessRetryInterval())
+	failures, retryAt := b.failures, b.retryAt
+	b.mu.Unlock()
+
+	logging.Error("[BROWSERLESS] Browserless unavailable, pausing renders that need it",
+		"consecutive_failures", failures, "retry_at", retryAt, "error", err)
+	notifyAdmins(sse.Event{
+		Type: "browserless_unavailable",
+		Data: map[string]interface{}{
+			"consecutive_failures": failures,
+			"retry_at":             retryAt.UTC(),
+			"error":                err.Error(),
+		},
+	})
+}
+
+// state returns a snapshot of the breaker
+func (b *browserlessBreaker) state() BrowserlessState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := BrowserlessState{
+		Available:           !b.open,
+		ConsecutiveFailures: b.failures,
+		LastError:           b.lastError,
+	}
+	if b.open {
+		openedAt, retryAt := b.openedAt.UTC(), b.retryAt.UTC()
+		state.OpenedAt = &openedAt
+		state.RetryAt = &retryAt
+	}
+	return state
+}
+
+// GetBrowserlessState reports whether browserless is considered available
+func GetBrowserlessState() BrowserlessState {
+	return breaker.state()
+}
+
+// IsBrowserlessAvailable reports whether renders that need browserless should run now
+func IsBrowserlessAvailable() bool {
+	return breaker.allow()
+}
+
+// browserlessRetryAt returns when browserless will next be tried, or now when it's available
+func browserlessRetryAt() time.Time {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	if breaker.open && time.Now().Before(breaker.retryAt) {
+		return breaker.retryAt
+	}
+	return time.Now()
+}
+
+// doBrowserlessRequest sends a request to browserless through the circuit breaker. Transport
+// errors and 5xx responses count as failures; cancellations by the caller don't.
+func doBrowserlessRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	if !breaker.allow() {
+		return nil, ErrBrowserlessUnavailable
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			breaker.recordFailure(err)
+		}
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		breaker.recordFailure(fmt.Errorf("browserless returned status %d", resp.StatusCode))
+	} else {
+		breaker.recordSuccess()
+	}
+	return resp, nil
+}
+
+// notifyAdmins sends an event to the connected sessions of every admin
+func notifyAdmins(event sse.Event) {
+	if database.DB == nil {
+		return
+	}
+
+	var admins []database.User
+	if err := database.DB.Where("is_admin = ? AND is_active = ?", true, true).Find(&admins).Error; err != nil {
+		logging.Warn("[BROWSERLESS] Failed to load admins for notification", "error", err)
+		return
+	}
+	sseService := sse.GetSSEService()
+	for _, admin := range admins {
+		sseService.BroadcastToUser(admin.ID, event)
+	}
+}
@@ -48,13 +48,27 @@ type WaitForSelector struct {
 	Visible  bool   `json:"visible"`
 }
 
+// ScreenshotViewport represents the browserless viewport, including device emulation options
+type ScreenshotViewport struct {
+	Width             int     `json:"width"`
+	Height            int     `json:"height"`
+	DeviceScaleFactor float64 `json:"deviceScaleFactor,omitempty"`
+	IsMobile          bool    `json:"isMobile,omitempty"`
+}
+
+// ScreenshotOptions controls how a page is captured. The viewport is independent of the
+// output size; callers scale the captured image to the device afterwards.
+type ScreenshotOptions struct {
+	Viewport        ScreenshotViewport
+	UserAgent       string
+	WaitTimeSeconds int
+	Headers         map[string]string
+}
+
 // ScreenshotRequest represents the request payload for browserless screenshot API
 type ScreenshotRequest struct {
-	URL      string `json:"url"`
-	Viewport struct {
-		Width  int `json:"width"`
-		Height int `json:"height"`
-	} `json:"viewport"`
+	URL      string             `json:"url"`
+	Viewport ScreenshotViewport `json:"viewport"`
 	Options struct {
 		Type           string `json:"type"`
 		Quality        *int   `json:"quality,omitempty"`
@@ -66,21 +80,29 @@ type ScreenshotRequest struct {
 		Timeout   int    `json:"timeout"`
 	} `json:"gotoOptions"`
 	Headers         map[string]string `json:"headers,omitempty"`
+	UserAgent       string            `json:"userAgent,omitempty"`
 	WaitForSelector *WaitForSelector  `json:"waitForSelector,omitempty"`
 }
 
 // CaptureScreenshot captures a screenshot of the given URL using browserless
 func (r *BrowserlessRenderer) CaptureScreenshot(ctx context.Context, url string, width, height int, waitTimeSeconds int, headers map[string]string) ([]byte, error) {
+	return r.CaptureScreenshotWithOptions(ctx, url, ScreenshotOptions{
+		Viewport:        ScreenshotViewport{Width: width, Height: height},
+		WaitTimeSeconds: waitTimeSeconds,
+		Headers:         headers,
+	})
+}
+
+// CaptureScreenshotWithOptions captures a screenshot of the given URL with a custom viewport and user agent
+func (r *BrowserlessRenderer) CaptureScreenshotWithOptions(ctx context.Context, url string, opts ScreenshotOptions) ([]byte, error) {
+	waitTimeSeconds := opts.WaitTimeSeconds
+	headers := opts.Headers
+
 	// Prepare browserless request with proper wait time handling
 	req := ScreenshotRequest{
-		URL: url,
-		Viewport: struct {
-			Width  int `json:"width"`
-			Height int `json:"height"`
-		}{
-			Width:  width,
-			Height: height,
-		},
+		URL:       url,
+		Viewport:  opts.Viewport,
+		UserAgent: opts.UserAgent,
 	}
 	
 	req.Options.Type = "png"
@@ -111,7 +133,7 @@ func (r *BrowserlessRenderer) CaptureScreenshot(ctx context.Context, url string,
 	
 	httpReq.Header.Set("Content-Type", "application/json")
 	
-	resp, err := r.client.Do(httpReq)
+	resp, err := doBrowserlessRequest(r.client, httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request to browserless: %w", err)
 	}
@@ -199,7 +221,8 @@ func (r *BrowserlessRenderer) RenderHTML(ctx context.Context, html string, width
 
 // RenderHTMLWithResult renders HTML content and returns both image data and flags
 func (r *BrowserlessRenderer) RenderHTMLWithResult(ctx context.Context, html string, width, height int) (*RenderHTMLResult, error) {
-	
+	html = SignImageProxyURLs(html)
+
 	// Prepare browserless request for HTML content
 	req := HTMLScreenshotRequest{
 		HTML: html,
@@ -289,7 +312,7 @@ func (r *BrowserlessRenderer) RenderHTMLWithResult(ctx context.Context, html str
 	
 	httpReq.Header.Set("Content-Type", "application/json")
 	
-	resp, err := r.client.Do(httpReq)
+	resp, err := doBrowserlessRequest(r.client, httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request to browserless: %w", err)
 	}
@@ -471,7 +494,7 @@ func (r *BrowserlessRenderer) parseTRMNLFlagsFromDOM(ctx context.Context, html s
 	
 	httpReq.Header.Set("Content-Type", "application/json")
 	
-	resp, err := r.client.Do(httpReq)
+	resp, err := doBrowserlessRequest(r.client, httpReq)
 	if err != nil {
 		return flags, fmt.Errorf("failed to make content request to browserless: %w", err)
 	}
@@ -0,0 +1,75 @@
+package rendering
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"image"
+
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// calculateContentHash hashes rendered image bytes for change detection. When the plugin definition
+// declares regions to ignore, only the decoded pixels outside them are hashed, so a changing clock
+// doesn't count as new content.
+func (w *RenderWorker) calculateContentHash(imageBytes []byte, regions []database.HashIgnoreRegion) string {
+	if len(regions) == 0 {
+		return w.calculateImageHash(imageBytes)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		logging.Warn("[RENDER_WORKER] Failed to decode image for masked hash, hashing all bytes", "error", err)
+		return w.calculateImageHash(imageBytes)
+	}
+	return maskedImageHash(img, regions)
+}
+
+// maskedImageHash hashes the image size and every pixel outside the regions
+func maskedImageHash(img image.Image, regions []database.HashIgnoreRegion) string {
+	bounds := img.Bounds()
+	masks := make([]image.Rectangle, 0, len(regions))
+	for _, region := range regions {
+		rect := image.Rect(region.X, region.Y, region.X+region.Width, region.Y+region.Height).
+			Add(bounds.Min).
+			Intersect(bounds)
+		if !rect.Empty() {
+			masks = append(masks, rect)
+		}
+	}
+
+	hasher := sha256.New()
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[0:4], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(header[4:8], uint32(bounds.Dy()))
+	hasher.Write(header[:])
+
+	var pixel [8]byte
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if pointMasked(x, y, masks) {
+				continue
+			}
+			r, g, b, a := img.At(x, y).RGBA()
+			binary.BigEndian.PutUint16(pixel[0:2], uint16(r))
+			binary.BigEndian.PutUint16(pixel[2:4], uint16(g))
+			binary.BigEndian.PutUint16(pixel[4:6], uint16(b))
+			binary.BigEndian.PutUint16(pixel[6:8], uint16(a))
+			hasher.Write(pixel[:])
+		}
+	}
+	return fmt.Sprintf("%x", hasher.Sum(nil))
+}
+
+// pointMasked reports whether the pixel falls inside any of the masks
+func pointMasked(x, y int, masks []image.Rectangle) bool {
+	p := image.Pt(x, y)
+	for _, mask := range masks {
+		if p.In(mask) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package rendering
+
+import (
+	"bytes"
+	"image"
+	_ "image/png"
+	"os"
+	"strings"
+
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/imageprocessing"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// setDirtyRegion records on a new render the bounding box of the pixels that changed since the previous
+// render's image, so devices that support partial refresh can update only that area. It leaves the region
+// unset when the previous image can't be read or has a different size.
+func setDirtyRegion(content *database.RenderedContent, previousImagePath string, imageData []byte) {
+	if previousImagePath == "" || strings.HasPrefix(previousImagePath, "http://") || strings.HasPrefix(previousImagePath, "https://") {
+		return
+	}
+
+	previousData, err := os.ReadFile(previousImagePath)
+	if err != nil {
+		logging.Debug("[RENDER_WORKER] Previous render unavailable for dirty region", "path", previousImagePath, "error", err)
+		return
+	}
+	previous, _, err := image.Decode(bytes.NewReader(previousData))
+	if err != nil {
+		return
+	}
+	next, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return
+	}
+
+	bounds, ok := imageprocessing.ChangedBounds(previous, next)
+	if !ok {
+		return
+	}
+	x, y, width, height := bounds.Min.X, bounds.Min.Y, bounds.Dx(), bounds.Dy()
+	content.DirtyX = &x
+	content.DirtyY = &y
+	content.DirtyWidth = &width
+	content.DirtyHeight = &height
+}
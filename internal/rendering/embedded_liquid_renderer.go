@@ -79,6 +79,9 @@ func (r *EmbeddedLiquidRenderer) renderWithRetry(
 		"template": template,
 		"data":     data,
 	}
+	if formats := localeFormatsFor(data); formats != nil {
+		request["locale_formats"] = formats
+	}
 
 	requestJSON, err := json.Marshal(request)
 	if err != nil {
@@ -0,0 +1,74 @@
+package rendering
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/smtp"
+)
+
+// recordRenderSuccess resets the consecutive failure count after a successful render
+func (w *RenderWorker) recordRenderSuccess(ctx context.Context, pluginInstance database.PluginInstance) {
+	if pluginInstance.ConsecutiveFailures == 0 && pluginInstance.LastError == "" {
+		return
+	}
+
+	err := w.db.WithContext(ctx).Model(&database.PluginInstance{}).
+		Where("id = ?", pluginInstance.ID).
+		UpdateColumns(map[string]interface{}{
+			"consecutive_failures": 0,
+			"last_error":           "",
+		}).Error
+	if err != nil {
+		logging.Error("[RENDER_WORKER] Failed to reset render failure count", "plugin_instance_id", pluginInstance.ID, "error", err)
+	}
+}
+
+// recordRenderFailure increments the consecutive failure count and sends a single notification
+// email when it reaches the instance's threshold. Further failures stay silent until a success resets the count.
+func (w *RenderWorker) recordRenderFailure(ctx context.Context, pluginInstance database.PluginInstance, renderErr error) {
+	err := w.db.WithContext(ctx).Model(&database.PluginInstance{}).
+		Where("id = ?", pluginInstance.ID).
+		UpdateColumns(map[string]interface{}{
+			"consecutive_failures": gorm.Expr("consecutive_failures + 1"),
+			"last_error":           renderErr.Error(),
+		}).Error
+	if err != nil {
+		logging.Error("[RENDER_WORKER] Failed to record render failure", "plugin_instance_id", pluginInstance.ID, "error", err)
+		return
+	}
+
+	var failures int
+	if err := w.db.WithContext(ctx).Model(&database.PluginInstance{}).
+		Where("id = ?", pluginInstance.ID).
+		Pluck("consecutive_failures", &failures).Error; err != nil {
+		logging.Error("[RENDER_WORKER] Failed to load render failure count", "plugin_instance_id", pluginInstance.ID, "error", err)
+		return
+	}
+
+	threshold := pluginInstance.FailureNotifyThreshold
+	if threshold <= 0 || failures != threshold {
+		return
+	}
+
+	recipient := pluginInstance.FailureNotifyEmail
+	if recipient == "" {
+		recipient = pluginInstance.User.Email
+	}
+	smtpConfigured := smtp.IsSMTPConfiguredForUser(&pluginInstance.User)
+	if recipient == "" || !smtpConfigured {
+		logging.Warn("[RENDER_WORKER] Render failure threshold reached but no notification could be sent",
+			"plugin_instance_id", pluginInstance.ID, "failures", failures, "smtp_configured", smtpConfigured)
+		return
+	}
+
+	if err := smtp.SendRenderFailureEmail(&pluginInstance.User, recipient, pluginInstance.Name, failures, renderErr.Error()); err != nil {
+		logging.Error("[RENDER_WORKER] Failed to send render failure notification", "plugin_instance_id", pluginInstance.ID, "error", err)
+		return
+	}
+
+	logging.Info("[RENDER_WORKER] Sent render failure notification", "plugin_instance_id", pluginInstance.ID, "failures", failures)
+}
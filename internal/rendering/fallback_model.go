@@ -0,0 +1,56 @@
+package rendering
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// FallbackModelName is the model name given to the fallback model, so its renders can be told apart
+const FallbackModelName = "fallback"
+
+// warnInvalidFallbackModel logs a malformed RENDER_FALLBACK_MODEL once, since it is read for every render
+var warnInvalidFallbackModel sync.Once
+
+// FallbackDeviceModel returns the model used to render for devices that have no device model, from
+// RENDER_FALLBACK_MODEL in WIDTHxHEIGHTxBIT_DEPTH form (e.g. 800x480x1). The second return value is
+// false when no fallback is configured, in which case those devices are skipped.
+func FallbackDeviceModel() (*database.DeviceModel, bool) {
+	value := strings.TrimSpace(config.Get("RENDER_FALLBACK_MODEL", ""))
+	if value == "" {
+		return nil, false
+	}
+
+	var width, height, bitDepth int
+	_, err := fmt.Sscanf(strings.ToLower(value), "%dx%dx%d", &width, &height, &bitDepth)
+	if err != nil || width <= 0 || height <= 0 || !validFallbackBitDepth(bitDepth) {
+		warnInvalidFallbackModel.Do(func() {
+			logging.Warn("[RENDER_WORKER] Ignoring invalid RENDER_FALLBACK_MODEL, expected WIDTHxHEIGHTxBIT_DEPTH with a bit depth of 1, 2, 4 or 8", "value", value)
+		})
+		return nil, false
+	}
+
+	return &database.DeviceModel{
+		ModelName:    FallbackModelName,
+		DisplayName:  "Fallback",
+		ScreenWidth:  width,
+		ScreenHeight: height,
+		ColorDepth:   bitDepth,
+		BitDepth:     bitDepth,
+		ScaleFactor:  1.0,
+		MimeType:     "image/png",
+		IsActive:     true,
+	}, true
+}
+
+func validFallbackBitDepth(bitDepth int) bool {
+	switch bitDepth {
+	case 1, 2, 4, 8:
+		return true
+	}
+	return false
+}
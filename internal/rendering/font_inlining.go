@@ -0,0 +1,110 @@
+package rendering
+
+import (
+	"encoding/base64"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// inlinedStylesheets are the TRMNL stylesheets whose font references are inlined, relative to the asset root
+var inlinedStylesheets = []string{"fonts/inter.css", "css/plugins.css"}
+
+// fontMimeTypes maps font file extensions to the MIME type used in data URIs
+var fontMimeTypes = map[string]string{
+	".woff2": "font/woff2",
+	".woff":  "font/woff",
+	".ttf":   "font/ttf",
+	".otf":   "font/otf",
+}
+
+// cssURLPattern matches url(...) references in a stylesheet
+var cssURLPattern = regexp.MustCompile(`url\(\s*['"]?([^'")]+?)['"]?\s*\)`)
+
+var (
+	trmnlAssets fs.FS
+
+	inlinedCSSOnce sync.Once
+	inlinedCSS     string
+)
+
+// SetTRMNLAssets registers the embedded TRMNL asset tree (rooted at assets/trmnl) used to inline fonts
+func SetTRMNLAssets(assets fs.FS) {
+	trmnlAssets = assets
+}
+
+// fontInliningEnabled reports whether fonts should be embedded in generated HTML instead of
+// being fetched by browserless from the font endpoints on every render
+func fontInliningEnabled() bool {
+	return trmnlAssets != nil && config.GetBool("RENDER_INLINE_FONTS", false)
+}
+
+// getInlinedFontCSS returns the TRMNL stylesheets with font URLs replaced by base64 data URIs.
+// The result is built once since encoding the fonts is the expensive part.
+func getInlinedFontCSS() string {
+	inlinedCSSOnce.Do(func() {
+		var builder strings.Builder
+		originalSize := 0
+		for _, stylesheet := range inlinedStylesheets {
+			data, err := fs.ReadFile(trmnlAssets, stylesheet)
+			if err != nil {
+				logging.Warn("[FONT_INLINING] Stylesheet not found in embedded assets", "stylesheet", stylesheet, "error", err)
+				continue
+			}
+			originalSize += len(data)
+			builder.WriteString(inlineFontURLs(string(data), path.Dir(stylesheet)))
+			builder.WriteString("\n")
+		}
+		inlinedCSS = builder.String()
+
+		// Every rendered document carries this CSS, so log the cost once for tuning
+		logging.Info("[FONT_INLINING] Built inlined font stylesheet", "stylesheet_bytes", originalSize, "inlined_bytes", len(inlinedCSS))
+	})
+	return inlinedCSS
+}
+
+// inlineFontURLs rewrites font url(...) references in css to data URIs. Paths are resolved
+// against the stylesheet directory, /fonts/ and /assets/trmnl/; anything else is left untouched.
+func inlineFontURLs(css, stylesheetDir string) string {
+	return cssURLPattern.ReplaceAllStringFunc(css, func(match string) string {
+		ref := cssURLPattern.FindStringSubmatch(match)[1]
+		if strings.HasPrefix(ref, "data:") || strings.Contains(ref, "://") {
+			return match
+		}
+
+		// Drop query strings and fragments used for cache busting or format hints
+		cleanRef := ref
+		if idx := strings.IndexAny(cleanRef, "?#"); idx >= 0 {
+			cleanRef = cleanRef[:idx]
+		}
+
+		mimeType, ok := fontMimeTypes[strings.ToLower(path.Ext(cleanRef))]
+		if !ok {
+			return match
+		}
+
+		var assetPath string
+		switch {
+		case strings.HasPrefix(cleanRef, "/fonts/"):
+			assetPath = path.Join("fonts", strings.TrimPrefix(cleanRef, "/fonts/"))
+		case strings.HasPrefix(cleanRef, "/assets/trmnl/"):
+			assetPath = strings.TrimPrefix(cleanRef, "/assets/trmnl/")
+		case strings.HasPrefix(cleanRef, "/"):
+			return match
+		default:
+			assetPath = path.Join(stylesheetDir, cleanRef)
+		}
+
+		data, err := fs.ReadFile(trmnlAssets, assetPath)
+		if err != nil {
+			logging.Debug("[FONT_INLINING] Font not found in embedded assets", "ref", ref, "error", err)
+			return match
+		}
+		return "url(data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data) + ")"
+	})
+}
@@ -14,8 +14,20 @@ func NewHTMLAssetsManager() *HTMLAssetsManager {
 	return &HTMLAssetsManager{}
 }
 
-// GenerateTRNMLHeadScripts returns TRMNL scripts to be loaded in the document head
+// GenerateTRNMLHeadScripts returns TRMNL scripts to be loaded in the document head.
+// With RENDER_INLINE_FONTS the stylesheets are embedded with their fonts as data URIs.
 func (h *HTMLAssetsManager) GenerateTRNMLHeadScripts(assetBaseURL string) string {
+	if fontInliningEnabled() {
+		if css := getInlinedFontCSS(); css != "" {
+			return fmt.Sprintf(`<!-- TRMNL Framework v3 scripts (fonts inlined) -->
+    <style>%s</style>
+    <script src="%s/assets/trmnl/js/plugins.js"></script>
+    <script src="%s/assets/trmnl/plugin-render/dithering.js"></script>
+    <script src="%s/assets/trmnl/plugin-render/asset-deduplication.js"></script>`,
+				css, assetBaseURL, assetBaseURL, assetBaseURL)
+		}
+	}
+
 	return fmt.Sprintf(`<!-- TRMNL Framework v3 scripts -->
     <link rel="stylesheet" href="%s/assets/trmnl/fonts/inter.css">
     <link rel="stylesheet" href="%s/assets/trmnl/css/plugins.css">
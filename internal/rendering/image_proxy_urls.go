@@ -0,0 +1,30 @@
+package rendering
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+)
+
+// imageProxyURLPattern matches image proxy references in attributes and CSS, up to the closing quote
+var imageProxyURLPattern = regexp.MustCompile(`/api/image-proxy\?[^"'\s)<>]+`)
+
+// SignImageProxyURLs adds a signature to every /api/image-proxy reference in rendered HTML. The proxy only
+// fetches signed URLs, so templates can use it but nobody else can turn it into an open proxy.
+func SignImageProxyURLs(content string) string {
+	if !strings.Contains(content, "/api/image-proxy?") {
+		return content
+	}
+	return imageProxyURLPattern.ReplaceAllStringFunc(content, func(match string) string {
+		query, err := url.ParseQuery(html.UnescapeString(strings.TrimPrefix(match, "/api/image-proxy?")))
+		if err != nil || query.Get("url") == "" {
+			return match
+		}
+		// A bare & is read the same in attributes and in <style>, where &amp; wouldn't be decoded
+		imageURL := query.Get("url")
+		return "/api/image-proxy?url=" + url.QueryEscape(imageURL) + "&sig=" + utils.SignImageProxyURL(imageURL)
+	})
+}
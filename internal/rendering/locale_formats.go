@@ -0,0 +1,45 @@
+package rendering
+
+import (
+	"sync"
+
+	"github.com/rmitchellscott/stationmaster/internal/locales"
+)
+
+var (
+	localeManager   *locales.LocaleManager
+	localeManagerMu sync.RWMutex
+)
+
+// SetLocaleManager provides the locale data used by the localized_* template filters
+func SetLocaleManager(lm *locales.LocaleManager) {
+	localeManagerMu.Lock()
+	defer localeManagerMu.Unlock()
+	localeManager = lm
+}
+
+// localeFormatsFor returns the formats for the user locale in the template data (trmnl.user.locale).
+// It returns nil when no locale manager is configured, in which case the filters use English.
+func localeFormatsFor(data map[string]interface{}) *locales.Formats {
+	localeManagerMu.RLock()
+	lm := localeManager
+	localeManagerMu.RUnlock()
+	if lm == nil {
+		return nil
+	}
+
+	locale := "en"
+	if trmnlData, ok := data["trmnl"].(map[string]interface{}); ok {
+		if user, ok := trmnlData["user"].(map[string]interface{}); ok {
+			if userLocale, ok := user["locale"].(string); ok && userLocale != "" {
+				locale = userLocale
+			}
+		}
+	}
+
+	formats, ok := lm.GetFormats(locale)
+	if !ok {
+		return nil
+	}
+	return &formats
+}
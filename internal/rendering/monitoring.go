@@ -14,6 +14,7 @@ type HealthStatus struct {
 	WorkerPool      WorkerPoolHealth       `json:"worker_pool"`
 	Queue           QueueHealth            `json:"queue"`
 	Performance     PerformanceMetrics     `json:"performance"`
+	Browserless     BrowserlessState       `json:"browserless"`
 	LastUpdated     time.Time              `json:"last_updated"`
 	Recommendations []string               `json:"recommendations,omitempty"`
 }
@@ -144,14 +145,17 @@ func (m *MonitoringService) GetHealthStatus(ctx context.Context) (*HealthStatus,
 		JobsPerMinute: jobsPerMinute,
 	}
 	
+	browserless := GetBrowserlessState()
+
 	// Determine overall health status and recommendations
-	status, recommendations := m.determineHealthStatus(workerPoolHealth, queueHealth, performanceMetrics)
+	status, recommendations := m.determineHealthStatus(workerPoolHealth, queueHealth, performanceMetrics, browserless)
 	
 	healthStatus := &HealthStatus{
 		Status:          status,
 		WorkerPool:      workerPoolHealth,
 		Queue:           queueHealth,
 		Performance:     performanceMetrics,
+		Browserless:     browserless,
 		LastUpdated:     time.Now().UTC(),
 		Recommendations: recommendations,
 	}
@@ -164,6 +168,7 @@ func (m *MonitoringService) determineHealthStatus(
 	workerPool WorkerPoolHealth, 
 	queue QueueHealth, 
 	performance PerformanceMetrics,
+	browserless BrowserlessState,
 ) (string, []string) {
 	var recommendations []string
 	
@@ -193,6 +198,13 @@ func (m *MonitoringService) determineHealthStatus(
 				workerPool.WorkerUtilization))
 	}
 	
+	if !browserless.Available {
+		unhealthyConditions++
+		recommendations = append(recommendations,
+			fmt.Sprintf("Browserless unavailable after %d consecutive failures - renders are paused until it recovers",
+				browserless.ConsecutiveFailures))
+	}
+	
 	// Queue checks
 	if queue.PendingJobs > 50 {
 		degradedConditions++
@@ -0,0 +1,143 @@
+package rendering
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// warnInvalidModelDirs logs malformed RENDERED_MODEL_DIRS entries once, since the setting is read on every request
+var warnInvalidModelDirs sync.Once
+
+// ModelRenderedDirs returns the per-model render output directories configured in RENDERED_MODEL_DIRS,
+// a comma-separated list of model_name=directory pairs, keyed by device model name
+func ModelRenderedDirs() map[string]string {
+	dirs := make(map[string]string)
+	var invalid []string
+	for _, entry := range strings.Split(config.Get("RENDERED_MODEL_DIRS", ""), ",") {
+		modelName, dir, ok := strings.Cut(entry, "=")
+		modelName, dir = strings.TrimSpace(modelName), strings.TrimSpace(dir)
+		if !ok || modelName == "" || dir == "" {
+			if strings.TrimSpace(entry) != "" {
+				invalid = append(invalid, entry)
+			}
+			continue
+		}
+		dirs[modelName] = filepath.Clean(dir)
+	}
+	if len(invalid) > 0 {
+		warnInvalidModelDirs.Do(func() {
+			logging.Warn("[RENDER_WORKER] Ignoring invalid RENDERED_MODEL_DIRS entries", "entries", invalid)
+		})
+	}
+	return dirs
+}
+
+// RenderedDirs returns every directory rendered images may be written to: the default directory
+// followed by the per-model directories
+func RenderedDirs(defaultDir string) []string {
+	dirs := []string{filepath.Clean(defaultDir)}
+	var modelDirs []string
+	for _, dir := range ModelRenderedDirs() {
+		modelDirs = append(modelDirs, dir)
+	}
+	sort.Strings(modelDirs)
+	for _, dir := range modelDirs {
+		if !containsString(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// ResolveRenderedFile finds a rendered image by file name across the rendered directories. Names
+// containing a path are rejected.
+func ResolveRenderedFile(defaultDir, name string) (string, bool) {
+	if name == "" || filepath.Base(name) != name || name == "." || name == ".." {
+		return "", false
+	}
+	for _, dir := range RenderedDirs(defaultDir) {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// InModelRenderedDir reports whether a stored image path is inside one of the per-model directories
+func InModelRenderedDir(imagePath string) bool {
+	for _, dir := range ModelRenderedDirs() {
+		if pathInDir(dir, imagePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// outputDir returns the directory new renders for a device are written to, falling back to the
+// default directory when its model has none configured or it can't be created
+func (w *RenderWorker) outputDir(device database.Device) string {
+	if device.DeviceModel == nil {
+		return w.renderedDir
+	}
+	dir, ok := w.modelDirs[device.DeviceModel.ModelName]
+	if !ok {
+		return w.renderedDir
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logging.Warn("[RENDER_WORKER] Failed to create model rendered directory, using default", "model", device.DeviceModel.ModelName, "dir", dir, "error", err)
+		return w.renderedDir
+	}
+	return dir
+}
+
+// inRenderedDirs reports whether a file is inside one of the worker's rendered directories
+func (w *RenderWorker) inRenderedDirs(path string) bool {
+	for _, dir := range w.renderedDirs() {
+		if pathInDir(dir, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderedDirs lists the worker's default and per-model rendered directories
+func (w *RenderWorker) renderedDirs() []string {
+	dirs := []string{w.renderedDir}
+	for _, dir := range w.modelDirs {
+		if !containsString(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// pathInDir reports whether path is dir or below it
+func pathInDir(dir, path string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
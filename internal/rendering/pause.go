@@ -0,0 +1,26 @@
+package rendering
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/rmitchellscott/stationmaster/internal/database"
+)
+
+// renderPausedSetting is the system setting holding the global render pause switch
+const renderPausedSetting = "render_paused"
+
+// IsRenderingPaused reports whether an admin has paused all background rendering.
+// Pending jobs stay queued while paused and are picked up again once rendering resumes.
+func IsRenderingPaused() bool {
+	paused, err := database.GetSystemSetting(renderPausedSetting)
+	return err == nil && paused == "true"
+}
+
+// SetRenderingPaused pauses or resumes background rendering
+func SetRenderingPaused(paused bool, updatedBy *uuid.UUID) error {
+	value := "false"
+	if paused {
+		value = "true"
+	}
+	return database.SetSystemSetting(renderPausedSetting, value, updatedBy)
+}
@@ -83,11 +83,11 @@ func (qm *QueueManager) ScheduleImmediateRenderWithOptions(ctx context.Context,
 		
 		// Then submit directly to worker pool
 		if qm.workerPool.SubmitJob(job) {
-			logging.Info("[QUEUE_MANAGER] Submitted immediate render job directly to worker pool", 
+			logging.FromContext(ctx).Info("[QUEUE_MANAGER] Submitted immediate render job directly to worker pool", 
 				"plugin_id", pluginInstanceID, "job_id", job.ID)
 			return nil
 		} else {
-			logging.Warn("[QUEUE_MANAGER] Worker pool channel full, falling back to database queue", 
+			logging.FromContext(ctx).Warn("[QUEUE_MANAGER] Worker pool channel full, falling back to database queue", 
 				"plugin_id", pluginInstanceID)
 		}
 	}
@@ -133,7 +133,7 @@ func (qm *QueueManager) ScheduleInitialRenders(ctx context.Context) error {
 
 		// Check if plugin is used in any playlists
 		playlistService := database.NewPlaylistService(qm.db)
-		devicesUsingPlugin, err := playlistService.GetDevicesUsingPluginInstance(pluginInstance.ID)
+		devicesUsingPlugin, err := playlistService.GetRenderDevicesForPluginInstance(pluginInstance)
 		if err != nil {
 			logging.Error("[QUEUE_MANAGER] Failed to check devices using plugin", "plugin_id", pluginInstance.ID, "error", err)
 			continue
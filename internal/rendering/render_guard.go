@@ -0,0 +1,98 @@
+package rendering
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"strconv"
+	"time"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/plugins"
+)
+
+// maxRenderedImagePixels bounds the decoded size of a plugin image so a malformed or hostile
+// PNG cannot exhaust memory when it is decoded for quantization
+const maxRenderedImagePixels = 50_000_000
+
+// ErrRenderTimeout is returned when a plugin does not finish processing within RENDER_TIMEOUT
+var ErrRenderTimeout = errors.New("render timed out")
+
+// renderTimeout returns the wall-clock limit for processing one plugin render
+func renderTimeout() time.Duration {
+	return config.GetDuration("RENDER_TIMEOUT", 2*time.Minute)
+}
+
+// renderTimeoutForType returns the render timeout for a plugin type (system, private, external or
+// mashup). The render_timeout_<type>_seconds system setting overrides RENDER_TIMEOUT when positive.
+func renderTimeoutForType(pluginType string) time.Duration {
+	if pluginType != "" {
+		if value, err := database.GetSystemSetting(fmt.Sprintf("render_timeout_%s_seconds", pluginType)); err == nil {
+			if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return renderTimeout()
+}
+
+// processPluginWithTimeout runs plugin.Process under the render timeout for the instance's plugin type.
+// The plugin context carries a context that is cancelled when the timeout fires or ctx is done, so the
+// plugin's outbound HTTP and browserless calls abort instead of running on after the render is abandoned.
+func processPluginWithTimeout(ctx context.Context, plugin plugins.Plugin, pluginCtx plugins.PluginContext) (plugins.PluginResponse, error) {
+	timeout := renderTimeoutForType(pluginCtx.PluginInstance.PluginDefinition.PluginType)
+	if timeout <= 0 {
+		pluginCtx.Ctx = ctx
+		return plugin.Process(pluginCtx)
+	}
+
+	processCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	pluginCtx.Ctx = processCtx
+
+	type processResult struct {
+		response plugins.PluginResponse
+		err      error
+	}
+	done := make(chan processResult, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- processResult{err: fmt.Errorf("plugin panicked: %v", r)}
+			}
+		}()
+		response, err := plugin.Process(pluginCtx)
+		done <- processResult{response: response, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.response, result.err
+	case <-processCtx.Done():
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		logging.Warn("[RENDER_WORKER] Plugin exceeded render timeout, cancelling render",
+			"plugin_instance_id", pluginCtx.PluginInstance.ID,
+			"plugin_type", pluginCtx.PluginInstance.PluginDefinition.PluginType,
+			"timeout", timeout)
+		return nil, fmt.Errorf("%w after %s", ErrRenderTimeout, timeout)
+	}
+}
+
+// checkImageDimensions rejects images whose decoded size would exceed maxRenderedImagePixels
+func checkImageDimensions(imageData []byte) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(imageData))
+	if err != nil {
+		return fmt.Errorf("failed to read image header: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxRenderedImagePixels {
+		return fmt.Errorf("image dimensions %dx%d exceed the render limit", cfg.Width, cfg.Height)
+	}
+	return nil
+}
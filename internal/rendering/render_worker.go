@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 
 	"github.com/rmitchellscott/stationmaster/internal/database"
@@ -25,11 +26,15 @@ import (
 	"github.com/rmitchellscott/stationmaster/internal/sse"
 )
 
+// resolutionVariantMaxAge is how long a resolution reported by a device keeps being rendered after its last check-in
+const resolutionVariantMaxAge = 7 * 24 * time.Hour
+
 // RenderWorker handles background rendering of plugin content
 type RenderWorker struct {
 	db          *gorm.DB
 	staticDir   string
 	renderedDir string
+	modelDirs   map[string]string // Per-model output directories from RENDERED_MODEL_DIRS, keyed by model name
 	factory     *plugins.UnifiedPluginFactory
 }
 
@@ -56,12 +61,19 @@ func NewRenderWorker(db *gorm.DB, staticDir string) (*RenderWorker, error) {
 		db:          db,
 		staticDir:   staticDir,
 		renderedDir: renderedDir,
+		modelDirs:   ModelRenderedDirs(),
 		factory:     plugins.GetPluginFactory(),
 	}, nil
 }
 
 // ProcessRenderQueue processes pending render jobs
 func (w *RenderWorker) ProcessRenderQueue(ctx context.Context) error {
+	// Leave jobs pending while an admin has paused rendering
+	if IsRenderingPaused() {
+		logging.Debug("[RENDER_WORKER] Rendering paused, skipping queue processing")
+		return nil
+	}
+
 	// Get pending render jobs, ensuring only one job per plugin instance
 	// by selecting the earliest scheduled job for each plugin_instance_id
 	
@@ -86,8 +98,8 @@ func (w *RenderWorker) ProcessRenderQueue(ctx context.Context) error {
 			LIMIT 1
 		)
 		GROUP BY rq1.plugin_instance_id, rq1.id
-		LIMIT 10
-	`, "pending", time.Now().UTC(), "pending", time.Now().UTC()).Scan(&jobIDs).Error
+		LIMIT ?
+	`, "pending", time.Now().UTC(), "pending", time.Now().UTC(), pendingJobCandidates(10)).Scan(&jobIDs).Error
 
 	if err != nil {
 		return fmt.Errorf("failed to find job IDs: %w", err)
@@ -123,6 +135,7 @@ func (w *RenderWorker) ProcessRenderQueue(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to fetch render jobs: %w", err)
 	}
+	jobs = prioritizeJobs(ctx, w.db, jobs, 10+len(previewJobIDs))
 
 	logging.Info("[RENDER_WORKER] Processing render jobs", "job_count", len(jobs))
 
@@ -197,9 +210,15 @@ func (w *RenderWorker) processRenderJob(ctx context.Context, job database.Render
 		return nil
 	}
 
-	// Get only devices that have this plugin instance in their playlists
+	// Hold renders that need browserless while it's down so devices keep their last good content
+	if pluginInstance.PluginDefinition.RequiresProcessing && !IsBrowserlessAvailable() {
+		w.deferJob(ctx, job, browserlessRetryAt())
+		return nil
+	}
+
+	// Get only devices that have this plugin instance in their playlists and pass its render allowlist
 	playlistService := database.NewPlaylistService(w.db)
-	devices, err := playlistService.GetDevicesUsingPluginInstance(pluginInstance.ID)
+	devices, err := playlistService.GetRenderDevicesForPluginInstance(pluginInstance)
 	if err != nil {
 		w.markJobFailed(ctx, job, fmt.Sprintf("failed to load devices using plugin instance: %v", err))
 		return err
@@ -217,6 +236,10 @@ func (w *RenderWorker) processRenderJob(ctx context.Context, job database.Render
 
 	// Track if SKIP_DISPLAY flag was detected for any device
 	var skipDisplayDetected bool
+
+	// Track per-device outcomes for render failure notifications
+	renderedCount := 0
+	var lastRenderErr error
 	
 	// Process plugin and render for each individual device
 	for _, device := range devices {
@@ -224,10 +247,16 @@ func (w *RenderWorker) processRenderJob(ctx context.Context, job database.Render
 			break
 		}
 
-		// Skip devices without a device model
+		// Devices without a device model use the configured fallback model, or are skipped
 		if device.DeviceModel == nil {
-			logging.Warn("[RENDER_WORKER] Skipping device without device model", "device_id", device.ID, "friendly_id", device.FriendlyID)
-			continue
+			fallback, ok := FallbackDeviceModel()
+			if !ok {
+				logging.Warn("[RENDER_WORKER] Skipping device without device model", "device_id", device.ID, "friendly_id", device.FriendlyID)
+				continue
+			}
+			logging.Warn("[RENDER_WORKER] Device has no device model, rendering with fallback model", "device_id", device.ID, "friendly_id", device.FriendlyID,
+				"width", fallback.ScreenWidth, "height", fallback.ScreenHeight, "bit_depth", fallback.BitDepth)
+			device.DeviceModel = fallback
 		}
 
 		skipDisplay, err := w.renderForDevice(ctx, pluginInstance, device)
@@ -238,14 +267,27 @@ func (w *RenderWorker) processRenderJob(ctx context.Context, job database.Render
 				continue
 			}
 			logging.Error("[RENDER_WORKER] Failed to render for device", "device_id", device.ID, "friendly_id", device.FriendlyID, "error", err)
+			lastRenderErr = err
 			continue // Continue with other devices
 		}
+		renderedCount++
 		
 		if skipDisplay {
 			skipDisplayDetected = true
 		}
+
+		if w.renderResolutionVariants(ctx, pluginInstance, device) {
+			skipDisplayDetected = true
+		}
 	}
 	
+	// A render counts as failed only when no device rendered; skipped renders are neither
+	if renderedCount > 0 {
+		w.recordRenderSuccess(ctx, pluginInstance)
+	} else if lastRenderErr != nil {
+		w.recordRenderFailure(ctx, pluginInstance, lastRenderErr)
+	}
+
 	// Always update playlist items with current skip display status (true or false)
 	if err := w.updatePlaylistItemsSkipDisplay(ctx, pluginInstance.ID, skipDisplayDetected); err != nil {
 		logging.Error("[RENDER_WORKER] Failed to update playlist items with skip display flag", "plugin_instance_id", pluginInstance.ID, "skip_display", skipDisplayDetected, "error", err)
@@ -273,6 +315,51 @@ func (w *RenderWorker) processRenderJob(ctx context.Context, job database.Render
 	return nil
 }
 
+// renderResolutionVariants renders a plugin at every non-native resolution the device has recently
+// requested and returns whether SKIP_DISPLAY was detected for any of them
+func (w *RenderWorker) renderResolutionVariants(ctx context.Context, pluginInstance database.PluginInstance, device database.Device) bool {
+	since := time.Now().UTC().Add(-resolutionVariantMaxAge)
+	resolutions, err := database.NewDeviceService(w.db).GetRecentDeviceResolutions(device.ID, since)
+	if err != nil {
+		logging.Warn("[RENDER_WORKER] Failed to load resolution variants", "device_id", device.ID, "error", err)
+		return false
+	}
+
+	skipDisplayDetected := false
+	for _, resolution := range resolutions {
+		if ctx.Err() != nil {
+			break
+		}
+		if resolution.Width == device.DeviceModel.ScreenWidth && resolution.Height == device.DeviceModel.ScreenHeight &&
+			resolution.BitDepth == device.DeviceModel.BitDepth {
+			continue
+		}
+		if resolution.Width > database.MaxDeviceResolutionDimension || resolution.Height > database.MaxDeviceResolutionDimension {
+			// Recorded before reported sizes were bounded
+			continue
+		}
+
+		variantModel := *device.DeviceModel
+		variantModel.ScreenWidth = resolution.Width
+		variantModel.ScreenHeight = resolution.Height
+		variantModel.BitDepth = resolution.BitDepth
+		variantDevice := device
+		variantDevice.DeviceModel = &variantModel
+
+		skipDisplay, err := w.renderForDevice(ctx, pluginInstance, variantDevice)
+		if err != nil {
+			logging.Error("[RENDER_WORKER] Failed to render resolution variant", "device_id", device.ID, "friendly_id", device.FriendlyID,
+				"width", resolution.Width, "height", resolution.Height, "bit_depth", resolution.BitDepth, "error", err)
+			continue
+		}
+		if skipDisplay {
+			skipDisplayDetected = true
+		}
+	}
+
+	return skipDisplayDetected
+}
+
 // renderForDevice renders a plugin for a specific device and returns whether SKIP_DISPLAY was detected
 func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance database.PluginInstance, device database.Device) (bool, error) {
 	var plugin plugins.Plugin
@@ -326,8 +413,8 @@ func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance datab
 		return false, fmt.Errorf("failed to create plugin context: %w", err)
 	}
 
-	// Process plugin
-	response, err := plugin.Process(pluginCtx)
+	// Process plugin under the render timeout so a hung poll or browserless call cannot block the worker
+	response, err := processPluginWithTimeout(ctx, plugin, pluginCtx)
 	if err != nil {
 		return false, fmt.Errorf("plugin processing failed: %w", err)
 	}
@@ -345,6 +432,7 @@ func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance datab
 	var imagePath string
 	var fileSize int64
 	var contentHash *string
+	var savedImageData []byte // Image written for this render, for diffing against the previous one
 	var contentChanged bool = true // Default to true, set to false if content unchanged
 	var skipDisplay bool = false // Track if SKIP_DISPLAY flag was detected
 	
@@ -368,14 +456,23 @@ func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance datab
 			// For private plugins, external plugins, mashups, and system plugins that render via browserless, we need to process the image to correct bit depth
 			if pluginInstance.PluginDefinition.PluginType == "private" || pluginInstance.PluginDefinition.PluginType == "external" || 
 			   pluginInstance.PluginDefinition.PluginType == "mashup" || (pluginInstance.PluginDefinition.PluginType == "system" && plugin.RequiresProcessing()) {
+				if err := checkImageDimensions(imageData); err != nil {
+					return false, fmt.Errorf("rejected browserless plugin image: %w", err)
+				}
+
 				// Decode the raw PNG image from browserless
 				img, _, err := image.Decode(bytes.NewReader(imageData))
 				if err != nil {
 					return false, fmt.Errorf("failed to decode browserless plugin image: %w", err)
 				}
 
-				// Convert to grayscale and quantize to target bit depth (no dithering)
-				quantizedImg := imageprocessing.QuantizeToGrayscalePalette(img, device.DeviceModel.BitDepth)
+				// Shrink the content into the device's safe area when its bezel overlaps the screen edges
+				if device.SafeAreaTop > 0 || device.SafeAreaRight > 0 || device.SafeAreaBottom > 0 || device.SafeAreaLeft > 0 {
+					img = imageprocessing.InsetImage(img, device.SafeAreaTop, device.SafeAreaRight, device.SafeAreaBottom, device.SafeAreaLeft)
+				}
+
+				// Convert to grayscale and quantize to the model's gray levels (no dithering)
+				quantizedImg := imageprocessing.QuantizeToGrayscaleLevels(img, device.DeviceModel.BitDepth, device.DeviceModel.GrayscaleLevels)
 				if quantizedImg == nil {
 					return false, fmt.Errorf("failed to quantize browserless plugin image")
 				}
@@ -398,13 +495,14 @@ func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance datab
 			}
 
 			// Check if processed image content has changed by comparing with existing rendered content
-			newHash := w.calculateImageHash(processedImageData)
+			newHash := w.calculateContentHash(processedImageData, pluginInstance.PluginDefinition.HashIgnoreRegions())
 			contentHash = &newHash
 			
 			// Query for existing RenderedContent with same plugin_instance_id and device_id
 			var existingContent database.RenderedContent
 			err = w.db.WithContext(ctx).
-				Where("plugin_instance_id = ? AND device_id = ?", pluginInstance.ID, device.ID).
+				Where("plugin_instance_id = ? AND device_id = ? AND width = ? AND height = ? AND bit_depth = ?", pluginInstance.ID, device.ID,
+					device.DeviceModel.ScreenWidth, device.DeviceModel.ScreenHeight, device.DeviceModel.BitDepth).
 				Order("rendered_at DESC").
 				First(&existingContent).Error
 			
@@ -443,7 +541,7 @@ func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance datab
 				randomString := generateRandomString(10)
 				filename := fmt.Sprintf("%s_%s_%s.png",
 					pluginInstance.ID, device.ID, randomString)
-				imagePath = filepath.Join(w.renderedDir, filename)
+				imagePath = filepath.Join(w.outputDir(device), filename)
 
 				err = os.WriteFile(imagePath, processedImageData, 0644)
 				if err != nil {
@@ -464,6 +562,7 @@ func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance datab
 					fileSize = fileInfo.Size()
 				}
 
+				savedImageData = processedImageData
 				logging.Debug("[RENDER_WORKER] Successfully wrote image file", "path", imagePath, "size", fileSize)
 			}
 		} else {
@@ -487,7 +586,8 @@ func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance datab
 		var previousHash *string
 		var existingForPreviousHash database.RenderedContent
 		err = w.db.WithContext(ctx).
-			Where("plugin_instance_id = ? AND device_id = ?", pluginInstance.ID, device.ID).
+			Where("plugin_instance_id = ? AND device_id = ? AND width = ? AND height = ? AND bit_depth = ?", pluginInstance.ID, device.ID,
+				device.DeviceModel.ScreenWidth, device.DeviceModel.ScreenHeight, device.DeviceModel.BitDepth).
 			Order("rendered_at DESC").
 			First(&existingForPreviousHash).Error
 		if err == nil && existingForPreviousHash.ContentHash != nil {
@@ -509,6 +609,9 @@ func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance datab
 			PreviousHash:   previousHash,
 			RenderAttempts: 0, // Reset attempts on successful render
 		}
+		if previousHash != nil && savedImageData != nil {
+			setDirtyRegion(&renderedContent, existingForPreviousHash.ImagePath, savedImageData)
+		}
 
 		err = w.db.WithContext(ctx).Create(&renderedContent).Error
 		if err != nil {
@@ -546,6 +649,58 @@ func (w *RenderWorker) renderForDevice(ctx context.Context, pluginInstance datab
 	return skipDisplay, nil
 }
 
+// deviceRenders collapses concurrent immediate renders of the same plugin instance for the same device.
+// It is package-level because handlers create a RenderWorker per request.
+var deviceRenders singleflight.Group
+
+// deviceRenderGrace is added to the plugin render timeout to cover image processing and storage
+const deviceRenderGrace = 30 * time.Second
+
+// RenderForDevice renders a plugin instance for a single device immediately, outside the render queue,
+// and returns the rendered content stored for the device. The plugin instance must have its User and
+// PluginDefinition loaded and the device its DeviceModel. Concurrent calls for the same instance and
+// device share one render. If ctx expires first, ctx.Err() is returned and the render keeps running in
+// the background, bounded by the render timeout for the plugin type.
+func (w *RenderWorker) RenderForDevice(ctx context.Context, pluginInstance database.PluginInstance, device database.Device) (*database.RenderedContent, bool, error) {
+	key := fmt.Sprintf("%s:%s:%dx%d:%d", pluginInstance.ID, device.ID,
+		device.DeviceModel.ScreenWidth, device.DeviceModel.ScreenHeight, device.DeviceModel.BitDepth)
+	results := deviceRenders.DoChan(key, func() (interface{}, error) {
+		timeout := renderTimeoutForType(pluginInstance.PluginDefinition.PluginType)
+		if timeout <= 0 {
+			timeout = 2 * time.Minute
+		}
+		renderCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout+deviceRenderGrace)
+		defer cancel()
+		return w.renderForDevice(renderCtx, pluginInstance, device)
+	})
+
+	var skipDisplay bool
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return nil, false, result.Err
+		}
+		skipDisplay = result.Val.(bool)
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+
+	var content database.RenderedContent
+	err := w.db.WithContext(ctx).
+		Where("plugin_instance_id = ? AND device_id = ? AND width = ? AND height = ? AND bit_depth = ?", pluginInstance.ID, device.ID,
+			device.DeviceModel.ScreenWidth, device.DeviceModel.ScreenHeight, device.DeviceModel.BitDepth).
+		Order("rendered_at DESC").
+		First(&content).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, skipDisplay, fmt.Errorf("plugin did not produce an image")
+		}
+		return nil, skipDisplay, fmt.Errorf("failed to load rendered content: %w", err)
+	}
+
+	return &content, skipDisplay, nil
+}
+
 // scheduleNextRender schedules the next render for a plugin based on its refresh interval with timezone support
 func (w *RenderWorker) scheduleNextRender(ctx context.Context, pluginInstance database.PluginInstance) {
 	w.scheduleNextRenderWithOptions(ctx, pluginInstance, false)
@@ -725,6 +880,7 @@ type PreviewRenderData struct {
 	TemplateData      map[string]interface{} `json:"template_data"`
 	DeviceModelName   string                 `json:"device_model_name"`
 	BitDepth          int                    `json:"bit_depth"`
+	OutputBitDepth    int                    `json:"output_bit_depth,omitempty"` // Overrides BitDepth for quantization only
 	ScreenWidth       int                    `json:"screen_width"`
 	ScreenHeight      int                    `json:"screen_height"`
 	ScreenOrientation string                 `json:"screen_orientation"`
@@ -797,13 +953,18 @@ func (w *RenderWorker) processPreviewJob(ctx context.Context, job database.Rende
 		return err
 	}
 
-	quantized := imageprocessing.QuantizeToGrayscalePalette(img, preview.BitDepth)
+	outputBitDepth := preview.BitDepth
+	if preview.OutputBitDepth > 0 {
+		outputBitDepth = preview.OutputBitDepth
+	}
+
+	quantized := imageprocessing.QuantizeToGrayscalePalette(img, outputBitDepth)
 	if quantized == nil {
 		w.markJobFailed(ctx, job, "failed to quantize image")
 		return fmt.Errorf("quantization returned nil")
 	}
 
-	processedData, err := imageprocessing.EncodePalettedPNG(quantized, preview.BitDepth)
+	processedData, err := imageprocessing.EncodePalettedPNG(quantized, outputBitDepth)
 	if err != nil {
 		w.markJobFailed(ctx, job, fmt.Sprintf("failed to encode image: %v", err))
 		return err
@@ -860,8 +1021,56 @@ func (w *RenderWorker) markJobCancelled(ctx context.Context, job database.Render
 	}
 }
 
-// CleanupOldContent removes old rendered content and files
-func (w *RenderWorker) CleanupOldContent(ctx context.Context, maxAge time.Duration) error {
+// deferJob returns a job to the queue for a later attempt without counting it as a failure
+func (w *RenderWorker) deferJob(ctx context.Context, job database.RenderQueue, until time.Time) {
+	err := w.db.WithContext(ctx).Model(&job).Updates(map[string]interface{}{
+		"status":        "pending",
+		"scheduled_for": until.UTC(),
+		"attempts":      job.Attempts,
+	}).Error
+	if err != nil {
+		logging.Error("[RENDER_WORKER] Failed to defer job", "job_id", job.ID, "error", err)
+	} else {
+		logging.Debug("[RENDER_WORKER] Deferred job until browserless is available", "job_id", job.ID, "until", until.UTC())
+	}
+}
+
+// CleanupStats counts what a cleanup pass removed
+type CleanupStats struct {
+	RecordsRemoved int   `json:"records_removed"`
+	FilesRemoved   int   `json:"files_removed"`
+	BytesFreed     int64 `json:"bytes_freed"`
+}
+
+// Add accumulates another pass's counts
+func (s *CleanupStats) Add(other CleanupStats) {
+	s.RecordsRemoved += other.RecordsRemoved
+	s.FilesRemoved += other.FilesRemoved
+	s.BytesFreed += other.BytesFreed
+}
+
+// removeRenderedFile deletes a rendered image inside the rendered directories, counting it in stats
+func (w *RenderWorker) removeRenderedFile(fullPath string, stats *CleanupStats) {
+	if !w.inRenderedDirs(fullPath) {
+		return
+	}
+	var size int64
+	if info, err := os.Stat(fullPath); err == nil {
+		size = info.Size()
+	}
+	if err := os.Remove(fullPath); err != nil {
+		if !os.IsNotExist(err) {
+			logging.Error("[RENDER_WORKER] Failed to delete old image", "path", fullPath, "error", err)
+		}
+		return
+	}
+	stats.FilesRemoved++
+	stats.BytesFreed += size
+}
+
+// CleanupOldContent removes rendered content older than maxAge, along with its files
+func (w *RenderWorker) CleanupOldContent(ctx context.Context, maxAge time.Duration) (CleanupStats, error) {
+	var stats CleanupStats
 	cutoff := time.Now().UTC().Add(-maxAge)
 
 	// Find old rendered content
@@ -870,35 +1079,34 @@ func (w *RenderWorker) CleanupOldContent(ctx context.Context, maxAge time.Durati
 		Where("rendered_at < ?", cutoff).
 		Find(&oldContent).Error
 	if err != nil {
-		return fmt.Errorf("failed to find old content: %w", err)
+		return stats, fmt.Errorf("failed to find old content: %w", err)
 	}
 
 	for _, content := range oldContent {
-		// Delete file if it's a local file (not a URL)
-		if filepath.IsAbs(content.ImagePath) && filepath.HasPrefix(content.ImagePath, w.renderedDir) {
-			if err := os.Remove(content.ImagePath); err != nil && !os.IsNotExist(err) {
-				logging.Info("[RENDER_WORKER] Failed to delete file", "path", content.ImagePath, "error", err)
-			}
-		}
+		// URLs never resolve into the rendered directory, so only local files are removed
+		w.removeRenderedFile(w.contentFilePath(content.ImagePath), &stats)
 	}
 
 	// Delete database records
-	err = w.db.WithContext(ctx).
+	result := w.db.WithContext(ctx).
 		Where("rendered_at < ?", cutoff).
-		Delete(&database.RenderedContent{}).Error
-	if err != nil {
-		return fmt.Errorf("failed to delete old content records: %w", err)
+		Delete(&database.RenderedContent{})
+	if result.Error != nil {
+		return stats, fmt.Errorf("failed to delete old content records: %w", result.Error)
 	}
+	stats.RecordsRemoved = int(result.RowsAffected)
 
 	if len(oldContent) > 0 {
 		logging.Info("[RENDER_WORKER] Cleaned up old rendered content items", "count", len(oldContent))
 	}
 
-	return nil
+	return stats, nil
 }
 
 // CleanupOldContentSmart removes old rendered content based on plugin refresh intervals
-func (w *RenderWorker) CleanupOldContentSmart(ctx context.Context) error {
+func (w *RenderWorker) CleanupOldContentSmart(ctx context.Context) (CleanupStats, error) {
+	var stats CleanupStats
+
 	// Find all unique PluginInstance IDs that have rendered content
 	var pluginInstanceIDs []uuid.UUID
 	err := w.db.WithContext(ctx).
@@ -906,7 +1114,7 @@ func (w *RenderWorker) CleanupOldContentSmart(ctx context.Context) error {
 		Distinct("plugin_instance_id").
 		Pluck("plugin_instance_id", &pluginInstanceIDs).Error
 	if err != nil {
-		return fmt.Errorf("failed to get user plugin IDs: %w", err)
+		return stats, fmt.Errorf("failed to get user plugin IDs: %w", err)
 	}
 
 	totalCleaned := 0
@@ -935,6 +1143,7 @@ func (w *RenderWorker) CleanupOldContentSmart(ctx context.Context) error {
 				SELECT COUNT(*) FROM rendered_contents rc2
 				WHERE rc2.plugin_instance_id = rc1.plugin_instance_id
 				AND rc2.device_id = rc1.device_id
+				AND rc2.width = rc1.width AND rc2.height = rc1.height AND rc2.bit_depth = rc1.bit_depth
 				AND rc2.rendered_at > rc1.rendered_at
 			) >= 2
 		`, pluginInstanceID).Find(&oldContent).Error
@@ -948,23 +1157,8 @@ func (w *RenderWorker) CleanupOldContentSmart(ctx context.Context) error {
 		}
 
 		// Delete files for this plugin
-		filesDeleted := 0
 		for _, content := range oldContent {
-			var fullPath string
-			if filepath.IsAbs(content.ImagePath) {
-				fullPath = content.ImagePath
-			} else {
-				// Convert relative path to absolute
-				fullPath = filepath.Join(w.staticDir, content.ImagePath)
-			}
-			
-			if filepath.HasPrefix(fullPath, w.renderedDir) {
-				if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
-					logging.Error("[RENDER_WORKER] Failed to delete old image", "path", fullPath, "error", err)
-				} else if err == nil {
-					filesDeleted++
-				}
-			}
+			w.removeRenderedFile(w.contentFilePath(content.ImagePath), &stats)
 		}
 		
 		// Delete database records for this plugin using the same latest + 1 previous logic
@@ -975,6 +1169,7 @@ func (w *RenderWorker) CleanupOldContentSmart(ctx context.Context) error {
 				SELECT COUNT(*) FROM rendered_contents rc2
 				WHERE rc2.plugin_instance_id = rc1.plugin_instance_id
 				AND rc2.device_id = rc1.device_id
+				AND rc2.width = rc1.width AND rc2.height = rc1.height AND rc2.bit_depth = rc1.bit_depth
 				AND rc2.rendered_at > rc1.rendered_at
 			) >= 2
 		`, pluginInstanceID)
@@ -985,6 +1180,7 @@ func (w *RenderWorker) CleanupOldContentSmart(ctx context.Context) error {
 		}
 		
 		totalCleaned += len(oldContent)
+		stats.RecordsRemoved += int(result.RowsAffected)
 		if len(oldContent) > 0 {
 			logging.Info("[RENDER_WORKER] Plugin cleanup completed", "plugin_name", pluginInstance.Name, "refresh_interval", refreshInterval, "items_cleaned", len(oldContent), "retention_policy", "latest_plus_one_previous")
 		}
@@ -994,7 +1190,7 @@ func (w *RenderWorker) CleanupOldContentSmart(ctx context.Context) error {
 		logging.Info("[RENDER_WORKER] Smart cleanup completed", "total_items_removed", totalCleaned)
 	}
 
-	return nil
+	return stats, nil
 }
 
 // CleanupOldContentForPlugin removes old content for a specific plugin using latest + 1 previous retention
@@ -1009,6 +1205,7 @@ func (w *RenderWorker) CleanupOldContentForPlugin(ctx context.Context, pluginIns
 			SELECT COUNT(*) FROM rendered_contents rc2
 			WHERE rc2.plugin_instance_id = rc1.plugin_instance_id
 			AND rc2.device_id = rc1.device_id
+			AND rc2.width = rc1.width AND rc2.height = rc1.height AND rc2.bit_depth = rc1.bit_depth
 			AND rc2.rendered_at > rc1.rendered_at
 		) >= 2
 	`, pluginInstanceID).Find(&oldContent).Error
@@ -1030,7 +1227,7 @@ func (w *RenderWorker) CleanupOldContentForPlugin(ctx context.Context, pluginIns
 			fullPath = filepath.Join(w.staticDir, content.ImagePath)
 		}
 		
-		if filepath.HasPrefix(fullPath, w.renderedDir) {
+		if w.inRenderedDirs(fullPath) {
 			if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
 				logging.Error("[RENDER_WORKER] Failed to delete old image", "path", fullPath, "error", err)
 			} else if err == nil {
@@ -1047,6 +1244,7 @@ func (w *RenderWorker) CleanupOldContentForPlugin(ctx context.Context, pluginIns
 			SELECT COUNT(*) FROM rendered_contents rc2
 			WHERE rc2.plugin_instance_id = rc1.plugin_instance_id
 			AND rc2.device_id = rc1.device_id
+			AND rc2.width = rc1.width AND rc2.height = rc1.height AND rc2.bit_depth = rc1.bit_depth
 			AND rc2.rendered_at > rc1.rendered_at
 		) >= 2
 	`, pluginInstanceID)
@@ -1059,51 +1257,99 @@ func (w *RenderWorker) CleanupOldContentForPlugin(ctx context.Context, pluginIns
 	return nil
 }
 
-// CleanupOrphanedFiles removes image files that exist but have no corresponding database records
-func (w *RenderWorker) CleanupOrphanedFiles(ctx context.Context) error {
-	// Get all files in the rendered directory
-	files, err := filepath.Glob(filepath.Join(w.renderedDir, "*.png"))
-	if err != nil {
-		return fmt.Errorf("failed to list rendered files: %w", err)
+// OrphanedFile describes a rendered image on disk with no corresponding database record
+type OrphanedFile struct {
+	Path      string    `json:"path"`
+	SizeBytes int64     `json:"size_bytes"`
+	ModTime   time.Time `json:"modified_at"`
+}
+
+// FindOrphanedFiles lists image files in the rendered directories that have no corresponding database records
+func (w *RenderWorker) FindOrphanedFiles(ctx context.Context) ([]OrphanedFile, error) {
+	// Get all files in the rendered directories
+	var files []string
+	for _, dir := range w.renderedDirs() {
+		// WebP copies of previews are served next to their PNG and go with it
+		for _, pattern := range []string{"*.png", "*.webp"} {
+			dirFiles, err := filepath.Glob(filepath.Join(dir, pattern))
+			if err != nil {
+				return nil, fmt.Errorf("failed to list rendered files: %w", err)
+			}
+			files = append(files, dirFiles...)
+		}
 	}
 
 	if len(files) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Get all image paths from database
 	var dbPaths []string
-	err = w.db.WithContext(ctx).Model(&database.RenderedContent{}).
+	err := w.db.WithContext(ctx).Model(&database.RenderedContent{}).
 		Pluck("image_path", &dbPaths).Error
 	if err != nil {
-		return fmt.Errorf("failed to get database image paths: %w", err)
+		return nil, fmt.Errorf("failed to get database image paths: %w", err)
 	}
 
-	// Convert database paths to absolute paths for comparison
-	dbAbsPaths := make(map[string]bool)
+	// Database paths already include the static directory (e.g., "static/rendered/file.png"),
+	// so they compare directly against the globbed file paths
+	dbAbsPaths := make(map[string]bool, len(dbPaths))
 	for _, dbPath := range dbPaths {
-		var fullPath string
-		if filepath.IsAbs(dbPath) {
-			fullPath = dbPath
-		} else {
-			// Database paths already include the static directory (e.g., "static/rendered/file.png")
-			// so we don't need to add w.staticDir prefix
-			fullPath = dbPath
-		}
-		dbAbsPaths[fullPath] = true
+		dbAbsPaths[dbPath] = true
 	}
 
-	// Find orphaned files and delete them
-	orphanedCount := 0
+	var orphaned []OrphanedFile
 	for _, file := range files {
-		if !dbAbsPaths[file] {
-			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
-				logging.Error("[RENDER_WORKER] Failed to delete orphaned file", "path", file, "error", err)
-			} else if err == nil {
-				orphanedCount++
-				logging.Debug("[RENDER_WORKER] Deleted orphaned file", "path", file)
+		if dbAbsPaths[file] {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				logging.Warn("[RENDER_WORKER] Failed to stat orphaned file", "path", file, "error", err)
 			}
+			continue
 		}
+
+		orphaned = append(orphaned, OrphanedFile{
+			Path:      file,
+			SizeBytes: info.Size(),
+			ModTime:   info.ModTime().UTC(),
+		})
+	}
+
+	return orphaned, nil
+}
+
+// DeleteOrphanedFiles removes image files that have no corresponding database records.
+// It returns the number of files deleted and the bytes reclaimed.
+func (w *RenderWorker) DeleteOrphanedFiles(ctx context.Context) (int, int64, error) {
+	orphaned, err := w.FindOrphanedFiles(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	deletedCount := 0
+	var reclaimedBytes int64
+	for _, file := range orphaned {
+		if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+			logging.Error("[RENDER_WORKER] Failed to delete orphaned file", "path", file.Path, "error", err)
+		} else if err == nil {
+			deletedCount++
+			reclaimedBytes += file.SizeBytes
+			logging.Debug("[RENDER_WORKER] Deleted orphaned file", "path", file.Path)
+		}
+	}
+
+	return deletedCount, reclaimedBytes, nil
+}
+
+// CleanupOrphanedFiles removes image files that exist but have no corresponding database records
+func (w *RenderWorker) CleanupOrphanedFiles(ctx context.Context) error {
+	orphanedCount, _, err := w.DeleteOrphanedFiles(ctx)
+	if err != nil {
+		return err
 	}
 
 	if orphanedCount > 0 {
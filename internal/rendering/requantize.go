@@ -0,0 +1,146 @@
+package rendering
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/imageprocessing"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// RequantizeResult summarizes a requantize pass over a device's rendered content
+type RequantizeResult struct {
+	Requantized []uuid.UUID `json:"requantized"`  // Plugin instances whose latest image was converted in place
+	NeedsRender []uuid.UUID `json:"needs_render"` // Plugin instances that must be rendered again
+	Unchanged   int         `json:"unchanged"`    // Plugin instances whose content already matches the model
+}
+
+// RequantizeForDevice converts the latest rendered image of each plugin instance shown on a device to
+// the device model's current bit depth without running the plugins again. Content can only be converted
+// when the dimensions match and the new bit depth is not higher than the stored one; anything else
+// (resized screens, deeper bit depths, URL-only images, missing files) is reported in NeedsRender.
+// The device must have its DeviceModel loaded.
+func (w *RenderWorker) RequantizeForDevice(ctx context.Context, device database.Device) (*RequantizeResult, error) {
+	if device.DeviceModel == nil {
+		return nil, fmt.Errorf("device has no model")
+	}
+	model := device.DeviceModel
+
+	var contents []database.RenderedContent
+	err := w.db.WithContext(ctx).
+		Where("device_id = ?", device.ID).
+		Order("rendered_at DESC").
+		Find(&contents).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rendered content: %w", err)
+	}
+
+	result := &RequantizeResult{Requantized: []uuid.UUID{}, NeedsRender: []uuid.UUID{}}
+	seen := make(map[uuid.UUID]bool)
+	for _, content := range contents {
+		if seen[content.PluginInstanceID] {
+			continue
+		}
+		seen[content.PluginInstanceID] = true
+
+		if content.Width == model.ScreenWidth && content.Height == model.ScreenHeight && content.BitDepth == model.BitDepth {
+			result.Unchanged++
+			continue
+		}
+
+		if content.Width != model.ScreenWidth || content.Height != model.ScreenHeight || model.BitDepth > content.BitDepth {
+			result.NeedsRender = append(result.NeedsRender, content.PluginInstanceID)
+			continue
+		}
+
+		if err := w.requantizeContent(ctx, content, device); err != nil {
+			logging.Warn("[REQUANTIZE] Falling back to a fresh render",
+				"plugin_instance_id", content.PluginInstanceID, "device", device.FriendlyID, "error", err)
+			result.NeedsRender = append(result.NeedsRender, content.PluginInstanceID)
+			continue
+		}
+		result.Requantized = append(result.Requantized, content.PluginInstanceID)
+	}
+
+	logging.Info("[REQUANTIZE] Requantized rendered content for device",
+		"device", device.FriendlyID,
+		"bit_depth", model.BitDepth,
+		"requantized", len(result.Requantized),
+		"needs_render", len(result.NeedsRender),
+		"unchanged", result.Unchanged)
+
+	return result, nil
+}
+
+// requantizeContent re-encodes a stored image at the device model's bit depth and records it as new content
+func (w *RenderWorker) requantizeContent(ctx context.Context, content database.RenderedContent, device database.Device) error {
+	if strings.HasPrefix(content.ImagePath, "http://") || strings.HasPrefix(content.ImagePath, "https://") {
+		return fmt.Errorf("content is a remote image")
+	}
+
+	imageData, err := os.ReadFile(w.contentFilePath(content.ImagePath))
+	if err != nil {
+		return fmt.Errorf("failed to read rendered image: %w", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return fmt.Errorf("failed to decode rendered image: %w", err)
+	}
+
+	bitDepth := device.DeviceModel.BitDepth
+	quantizedImg := imageprocessing.QuantizeToGrayscaleLevels(img, bitDepth, device.DeviceModel.GrayscaleLevels)
+	if quantizedImg == nil {
+		return fmt.Errorf("failed to quantize rendered image")
+	}
+	processedImageData, err := imageprocessing.EncodePalettedPNG(quantizedImg, bitDepth)
+	if err != nil {
+		return fmt.Errorf("failed to encode rendered image: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s.png", content.PluginInstanceID, device.ID, generateRandomString(10))
+	imagePath := filepath.Join(w.outputDir(device), filename)
+	if err := os.WriteFile(imagePath, processedImageData, 0644); err != nil {
+		return fmt.Errorf("failed to save requantized image: %w", err)
+	}
+
+	// Hash the same way fresh renders do so the next render can still detect unchanged content
+	var regions []database.HashIgnoreRegion
+	var instance database.PluginInstance
+	if err := w.db.WithContext(ctx).Preload("PluginDefinition").First(&instance, "id = ?", content.PluginInstanceID).Error; err == nil {
+		regions = instance.PluginDefinition.HashIgnoreRegions()
+	}
+	contentHash := w.calculateContentHash(processedImageData, regions)
+	requantized := database.RenderedContent{
+		ID:               uuid.New(),
+		PluginInstanceID: content.PluginInstanceID,
+		DeviceID:         &device.ID,
+		Width:            content.Width,
+		Height:           content.Height,
+		BitDepth:         bitDepth,
+		ImagePath:        imagePath,
+		FileSize:         int64(len(processedImageData)),
+		ContentHash:      &contentHash,
+		RenderedAt:       time.Now().UTC(),
+		PreviousHash:     content.ContentHash,
+	}
+	if err := w.db.WithContext(ctx).Create(&requantized).Error; err != nil {
+		os.Remove(imagePath)
+		return fmt.Errorf("failed to store requantized content: %w", err)
+	}
+
+	if err := w.CleanupOldContentForPlugin(ctx, content.PluginInstanceID); err != nil {
+		logging.Warn("[REQUANTIZE] Failed to cleanup old content", "plugin_instance_id", content.PluginInstanceID, "error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package rendering
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// sizeLimitEvictionBatch is how many rendered content records are loaded per eviction pass
+const sizeLimitEvictionBatch = 100
+
+// renderedMaxSizeBytes returns the configured cap for the rendered directory, or 0 when unlimited
+func renderedMaxSizeBytes() int64 {
+	maxSizeMB := config.GetInt("RENDERED_MAX_SIZE_MB", 0)
+	if maxSizeMB <= 0 {
+		return 0
+	}
+	return int64(maxSizeMB) * 1024 * 1024
+}
+
+// renderedDirSize sums the size of all regular files in the rendered directories
+func (w *RenderWorker) renderedDirSize() (int64, error) {
+	var total int64
+	for _, dir := range w.renderedDirs() {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			total += info.Size()
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// contentFilePath resolves a stored image path to its location on disk
+func (w *RenderWorker) contentFilePath(imagePath string) string {
+	cleaned := filepath.Clean(imagePath)
+	if filepath.IsAbs(cleaned) || w.inRenderedDirs(cleaned) {
+		return cleaned
+	}
+	return filepath.Join(w.staticDir, cleaned)
+}
+
+// protectedContentIDs returns the latest rendered content of each variant of the instances in a visible
+// playlist item on an active device. Devices are showing it, so the size limit never evicts it.
+func (w *RenderWorker) protectedContentIDs(ctx context.Context) (map[uuid.UUID]bool, error) {
+	var instanceIDs []uuid.UUID
+	err := w.db.WithContext(ctx).Model(&database.PlaylistItem{}).
+		Joins("JOIN playlists ON playlists.id = playlist_items.playlist_id").
+		Joins("JOIN devices ON devices.id = playlists.device_id").
+		Where("playlist_items.is_visible = ? AND devices.is_active = ?", true, true).
+		Distinct("playlist_items.plugin_instance_id").
+		Pluck("playlist_items.plugin_instance_id", &instanceIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	protected := make(map[uuid.UUID]bool)
+	if len(instanceIDs) == 0 {
+		return protected, nil
+	}
+
+	var contents []database.RenderedContent
+	err = w.db.WithContext(ctx).
+		Select("id", "plugin_instance_id", "device_id", "width", "height", "bit_depth", "rendered_at").
+		Where("plugin_instance_id IN ?", instanceIDs).
+		Order("rendered_at DESC").
+		Find(&contents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, content := range contents {
+		deviceID := ""
+		if content.DeviceID != nil {
+			deviceID = content.DeviceID.String()
+		}
+		variant := fmt.Sprintf("%s:%s:%dx%d:%d", content.PluginInstanceID, deviceID, content.Width, content.Height, content.BitDepth)
+		if !seen[variant] {
+			seen[variant] = true
+			protected[content.ID] = true
+		}
+	}
+	return protected, nil
+}
+
+// EnforceRenderedSizeLimit brings the rendered directories within RENDERED_MAX_SIZE_MB. Orphaned files,
+// including previews, are deleted first; then the least recently rendered content is evicted. Unlike the
+// per-plugin retention policy this is a hard cap, so it may remove content that retention would otherwise
+// keep, but never the latest content of an instance in an active playlist. Records whose image lives
+// outside the rendered directories free no space and are left alone.
+func (w *RenderWorker) EnforceRenderedSizeLimit(ctx context.Context) error {
+	maxSize := renderedMaxSizeBytes()
+	if maxSize == 0 {
+		return nil
+	}
+
+	currentSize, err := w.renderedDirSize()
+	if err != nil {
+		return fmt.Errorf("failed to measure rendered directory: %w", err)
+	}
+	if currentSize <= maxSize {
+		return nil
+	}
+	startSize := currentSize
+
+	if _, _, err := w.DeleteOrphanedFiles(ctx); err != nil {
+		logging.Error("[RENDER_WORKER] Failed to delete orphaned files before eviction", "error", err)
+	}
+	currentSize, err = w.renderedDirSize()
+	if err != nil {
+		return fmt.Errorf("failed to measure rendered directory: %w", err)
+	}
+
+	protected, err := w.protectedContentIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find content in use: %w", err)
+	}
+
+	// Walk the content oldest first with a cursor, so records that are skipped aren't loaded again
+	var lastRenderedAt time.Time
+	var lastID uuid.UUID
+	evicted := 0
+	for currentSize > maxSize {
+		query := w.db.WithContext(ctx).
+			Order("rendered_at ASC, id ASC").
+			Limit(sizeLimitEvictionBatch)
+		if lastID != uuid.Nil {
+			query = query.Where("rendered_at > ? OR (rendered_at = ? AND id > ?)", lastRenderedAt, lastRenderedAt, lastID)
+		}
+		var oldest []database.RenderedContent
+		if err := query.Find(&oldest).Error; err != nil {
+			return fmt.Errorf("failed to find content to evict: %w", err)
+		}
+		if len(oldest) == 0 {
+			break
+		}
+
+		for _, content := range oldest {
+			if currentSize <= maxSize {
+				break
+			}
+			lastRenderedAt, lastID = content.RenderedAt, content.ID
+
+			if protected[content.ID] {
+				continue
+			}
+			fullPath := w.contentFilePath(content.ImagePath)
+			if !w.inRenderedDirs(fullPath) {
+				continue
+			}
+			info, err := os.Stat(fullPath)
+			if err != nil {
+				continue
+			}
+			if err := os.Remove(fullPath); err != nil {
+				logging.Error("[RENDER_WORKER] Failed to evict rendered image", "path", fullPath, "error", err)
+				continue
+			}
+			currentSize -= info.Size()
+
+			if err := w.db.WithContext(ctx).Delete(&database.RenderedContent{}, "id = ?", content.ID).Error; err != nil {
+				return fmt.Errorf("failed to delete evicted content record: %w", err)
+			}
+			evicted++
+		}
+	}
+
+	if currentSize < startSize {
+		logging.Info("[RENDER_WORKER] Reduced rendered directory to enforce size limit",
+			"items_evicted", evicted,
+			"size_before", startSize,
+			"size_after", currentSize,
+			"max_size", maxSize)
+	}
+	if currentSize > maxSize {
+		logging.Warn("[RENDER_WORKER] Rendered directory still exceeds size limit after eviction",
+			"size", currentSize, "max_size", maxSize)
+	}
+
+	return nil
+}
@@ -22,10 +22,11 @@ func NewTRNMLDataBuilder() *TRNMLDataBuilder {
 // This is the shared logic extracted from both private and mashup plugins
 func (b *TRNMLDataBuilder) BuildTRNMLData(ctx plugins.PluginContext, instance *database.PluginInstance, formFieldValues map[string]interface{}) map[string]interface{} {
 	trmnlData := map[string]interface{}{}
+	now := time.Now().UTC()
 
 	// Add system information - Unix timestamp
 	systemData := map[string]interface{}{
-		"timestamp_utc": time.Now().UTC().Unix(),
+		"timestamp_utc": now.Unix(),
 	}
 	trmnlData["system"] = systemData
 
@@ -58,7 +59,7 @@ func (b *TRNMLDataBuilder) BuildTRNMLData(ctx plugins.PluginContext, instance *d
 	}
 
 	if ctx.User != nil {
-		trmnlData["user"] = buildUserData(ctx.User)
+		trmnlData["user"] = buildUserData(ctx.User, now)
 	}
 
 	// Add plugin settings - this contains plugin metadata, not user form data
@@ -107,7 +108,8 @@ func (b *TRNMLDataBuilder) BuildTRNMLData(ctx plugins.PluginContext, instance *d
 	}
 	
 	// Add default plugin configuration
-	pluginSettings["dark_mode"] = "no"
+	enableDarkMode := instance.PluginDefinition.EnableDarkMode != nil && *instance.PluginDefinition.EnableDarkMode
+	pluginSettings["dark_mode"] = yesNo(enableDarkMode)
 	pluginSettings["no_screen_padding"] = "no"
 	
 	// Add custom_fields_values containing form field values (TRMNL compatibility)
@@ -118,16 +120,24 @@ func (b *TRNMLDataBuilder) BuildTRNMLData(ctx plugins.PluginContext, instance *d
 	return trmnlData
 }
 
-// BuildPreviewData builds TRMNL data for preview renders without a real device/instance
+// BuildPreviewData builds TRMNL data for preview renders without a real device/instance.
+// renderTime overrides the clock seen by templates; a zero value uses the current time.
 func (b *TRNMLDataBuilder) BuildPreviewData(
 	user *database.User,
 	deviceWidth, deviceHeight, bitDepth int,
 	modelName, pluginName, dataStrategy string,
+	enableDarkMode bool,
+	renderTime time.Time,
 	formFieldValues map[string]interface{},
 ) map[string]interface{} {
+	if renderTime.IsZero() {
+		renderTime = time.Now()
+	}
+	renderTime = renderTime.UTC()
+
 	trmnlData := map[string]interface{}{
 		"system": map[string]interface{}{
-			"timestamp_utc": time.Now().UTC().Unix(),
+			"timestamp_utc": renderTime.Unix(),
 		},
 		"device": map[string]interface{}{
 			"friendly_id":     "PREVIEW",
@@ -141,13 +151,13 @@ func (b *TRNMLDataBuilder) BuildPreviewData(
 	}
 
 	if user != nil {
-		trmnlData["user"] = buildUserData(user)
+		trmnlData["user"] = buildUserData(user, renderTime)
 	}
 
 	pluginSettings := map[string]interface{}{
 		"instance_name":       pluginName,
 		"strategy":            dataStrategy,
-		"dark_mode":           "no",
+		"dark_mode":           yesNo(enableDarkMode),
 		"no_screen_padding":   "no",
 		"custom_fields_values": formFieldValues,
 	}
@@ -156,7 +166,16 @@ func (b *TRNMLDataBuilder) BuildPreviewData(
 	return trmnlData
 }
 
-func buildUserData(user *database.User) map[string]interface{} {
+// yesNo formats a flag the way TRMNL exposes plugin settings to templates
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// buildUserData builds the trmnl.user block, resolving the UTC offset in effect at the given time
+func buildUserData(user *database.User, at time.Time) map[string]interface{} {
 	utcOffset := int64(0)
 	locale := "en"
 	timezone := "UTC"
@@ -167,7 +186,7 @@ func buildUserData(user *database.User) map[string]interface{} {
 		timezoneFriendly = utils.GetTimezoneFriendlyName(user.Timezone)
 		loc, err := time.LoadLocation(user.Timezone)
 		if err == nil {
-			_, offset := time.Now().UTC().In(loc).Zone()
+			_, offset := at.In(loc).Zone()
 			utcOffset = int64(offset)
 		}
 	}
@@ -247,8 +247,16 @@ func (p *RenderWorkerPool) feedJobs(ctx context.Context) {
 	}
 }
 
+// pendingJobBatchSize is how many plugin instance jobs loadPendingJobs submits per pass
+const pendingJobBatchSize = 20
+
 // loadPendingJobs loads pending render jobs from the database and submits them to workers
 func (p *RenderWorkerPool) loadPendingJobs(ctx context.Context) error {
+	// Leave jobs pending while an admin has paused rendering
+	if IsRenderingPaused() {
+		return nil
+	}
+
 	// Don't overload if channel is nearly full
 	if len(p.jobChan) > cap(p.jobChan)*8/10 {
 		return nil
@@ -272,8 +280,8 @@ func (p *RenderWorkerPool) loadPendingJobs(ctx context.Context) error {
 			LIMIT 1
 		)
 		GROUP BY plugin_instance_id, id
-		LIMIT 20
-	`, "pending", now, "pending", now).Scan(&jobIDs).Error
+		LIMIT ?
+	`, "pending", now, "pending", now, pendingJobCandidates(pendingJobBatchSize)).Scan(&jobIDs).Error
 
 	if err != nil {
 		return err
@@ -309,6 +317,7 @@ func (p *RenderWorkerPool) loadPendingJobs(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	dbJobs = prioritizeJobs(ctx, p.db, dbJobs, pendingJobBatchSize+len(previewIDs))
 	
 	submitted := 0
 	for _, dbJob := range dbJobs {
@@ -500,6 +509,11 @@ func (p *RenderWorkerPool) cleanupRoutine(ctx context.Context) {
 			if err := p.renderWorker.CleanupOrphanedFiles(ctx); err != nil {
 				logging.Error("[WORKER_POOL] Failed to cleanup orphaned files", "error", err)
 			}
+
+			// Enforce the hard cap on the rendered directory size
+			if err := p.renderWorker.EnforceRenderedSizeLimit(ctx); err != nil {
+				logging.Error("[WORKER_POOL] Failed to enforce rendered size limit", "error", err)
+			}
 		}
 	}
 }
@@ -533,8 +547,15 @@ func (w *Worker) processJob(job RenderJob) {
 	atomic.StoreInt32(&w.isProcessing, 1)
 	defer atomic.StoreInt32(&w.isProcessing, 0)
 	
-	atomic.AddInt64(&w.pool.metrics.TotalJobs, 1)
 	atomic.AddInt32(&w.pool.metrics.QueueLength, -1)
+
+	// Jobs already handed to workers before a pause stay pending and are reloaded on resume
+	if IsRenderingPaused() {
+		logging.Debug("[WORKER] Rendering paused, leaving job pending", "worker_id", w.id, "job_id", job.ID)
+		return
+	}
+
+	atomic.AddInt64(&w.pool.metrics.TotalJobs, 1)
 	
 	// Load plugin instance to get name and user context for better logging
 	var pluginInstance database.PluginInstance
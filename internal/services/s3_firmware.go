@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/rmitchellscott/stationmaster/internal/logging"
+
+	"github.com/rmitchellscott/stationmaster/internal/utils"
 )
 
 const (
@@ -40,9 +42,7 @@ var firmwareFilenameRegex = regexp.MustCompile(`^FW([0-9.]+)\.bin$`)
 func FetchS3FirmwareList(ctx context.Context) ([]FirmwareInfo, error) {
 	logging.Info("[S3 FIRMWARE] Fetching firmware list from S3 bucket")
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	client := utils.NewHTTPClient(30 * time.Second)
 
 	url := fmt.Sprintf("%s/?list-type=2", S3BucketURL)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
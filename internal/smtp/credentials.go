@@ -0,0 +1,89 @@
+package smtp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+)
+
+// ErrNoEncryptionKey is returned when per-user SMTP passwords cannot be stored because no key is configured
+var ErrNoEncryptionKey = errors.New("ENCRYPTION_KEY or JWT_SECRET must be set to store SMTP passwords")
+
+// credentialKey derives the AES-256 key used for stored SMTP passwords.
+// A stable key is required, so the randomly generated fallback JWT secret cannot be used.
+func credentialKey() ([]byte, error) {
+	secret := config.Get("ENCRYPTION_KEY", "")
+	if secret == "" {
+		secret = config.Get("JWT_SECRET", "")
+	}
+	if secret == "" {
+		return nil, ErrNoEncryptionKey
+	}
+	key := sha256.Sum256([]byte(secret))
+	return key[:], nil
+}
+
+// EncryptPassword encrypts an SMTP password for storage on a user record
+func EncryptPassword(password string) (string, error) {
+	key, err := credentialKey()
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(password), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptPassword decrypts an SMTP password stored with EncryptPassword
+func DecryptPassword(encrypted string) (string, error) {
+	key, err := credentialKey()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted password: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted password")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt password: %w", err)
+	}
+
+	return string(plaintext), nil
+}
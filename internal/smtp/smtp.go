@@ -2,18 +2,29 @@ package smtp
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"html"
 	"html/template"
+	"net"
 	"net/smtp"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rmitchellscott/stationmaster/internal/config"
 	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
+)
+
+const (
+	smtpDialTimeout = 10 * time.Second
+	// smtpSessionTimeout bounds a whole SMTP session so an unresponsive server can't hold a connection open
+	smtpSessionTimeout = 60 * time.Second
 )
 
 // SMTPConfig holds SMTP configuration
@@ -24,6 +35,8 @@ type SMTPConfig struct {
 	Password string
 	From     string
 	UseTLS   bool
+	// PublicOnly only connects to public addresses, for servers set by non-admin users
+	PublicOnly bool
 }
 
 // EmailData holds data for email templates
@@ -129,8 +142,18 @@ func sanitizeEmailData(data *EmailData) error {
 	return nil
 }
 
-// GetSMTPConfig reads SMTP configuration from environment variables
-func GetSMTPConfig() (*SMTPConfig, error) {
+// GetSMTPConfig returns the SMTP configuration for an email. When user is non-nil and has an SMTP
+// override, the user's server is used; otherwise configuration is read from environment variables.
+// Pass nil for system emails such as password resets, which always use the global server.
+func GetSMTPConfig(user *database.User) (*SMTPConfig, error) {
+	if user != nil && user.SMTPHost != "" {
+		cfg, err := userSMTPConfig(user)
+		if err == nil {
+			return cfg, nil
+		}
+		logging.Warn("[SMTP] Ignoring invalid user SMTP settings", "user_id", user.ID, "error", err)
+	}
+
 	host := config.Get("SMTP_HOST", "")
 	if host == "" {
 		return nil, fmt.Errorf("SMTP_HOST not configured")
@@ -168,15 +191,53 @@ func GetSMTPConfig() (*SMTPConfig, error) {
 	}, nil
 }
 
-// IsSMTPConfigured checks if SMTP is properly configured
+// userSMTPConfig builds the SMTP configuration from a user's override settings
+func userSMTPConfig(user *database.User) (*SMTPConfig, error) {
+	if user.SMTPFrom == "" {
+		return nil, fmt.Errorf("user SMTP from address not configured")
+	}
+
+	port := user.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+
+	password := ""
+	if user.SMTPPasswordEncrypted != "" {
+		decrypted, err := DecryptPassword(user.SMTPPasswordEncrypted)
+		if err != nil {
+			return nil, err
+		}
+		password = decrypted
+	}
+
+	return &SMTPConfig{
+		Host:     user.SMTPHost,
+		Port:     port,
+		Username: user.SMTPUsername,
+		Password: password,
+		From:     user.SMTPFrom,
+		UseTLS:   user.SMTPUseTLS,
+		// Non-admins can't reach servers on the internal network through their settings
+		PublicOnly: !user.IsAdmin,
+	}, nil
+}
+
+// IsSMTPConfigured checks if the global SMTP server is properly configured
 func IsSMTPConfigured() bool {
-	_, err := GetSMTPConfig()
+	_, err := GetSMTPConfig(nil)
+	return err == nil
+}
+
+// IsSMTPConfiguredForUser checks if email can be sent for a user, via their own server or the global one
+func IsSMTPConfiguredForUser(user *database.User) bool {
+	_, err := GetSMTPConfig(user)
 	return err == nil
 }
 
 // SendPasswordResetEmail sends a password reset email
 func SendPasswordResetEmail(email, username, resetToken string) error {
-	cfg, err := GetSMTPConfig()
+	cfg, err := GetSMTPConfig(nil)
 	if err != nil {
 		return fmt.Errorf("SMTP not configured: %w", err)
 	}
@@ -224,12 +285,12 @@ func SendPasswordResetEmail(email, username, resetToken string) error {
 	textBody := generatePasswordResetText(emailData)
 
 	// Send email
-	return sendEmail(cfg, email, subject, textBody, htmlBody)
+	return sendEmail(cfg, []string{email}, nil, subject, textBody, htmlBody)
 }
 
 // SendWelcomeEmail sends a welcome email to new users
 func SendWelcomeEmail(email, username string) error {
-	cfg, err := GetSMTPConfig()
+	cfg, err := GetSMTPConfig(nil)
 	if err != nil {
 		return fmt.Errorf("SMTP not configured: %w", err)
 	}
@@ -258,15 +319,96 @@ func SendWelcomeEmail(email, username string) error {
 
 	textBody := generateWelcomeText(emailData)
 
-	return sendEmail(cfg, email, subject, textBody, htmlBody)
+	return sendEmail(cfg, []string{email}, nil, subject, textBody, htmlBody)
+}
+
+// SendTestEmail sends a diagnostic test email to the given recipients and CC addresses
+func SendTestEmail(to, cc []string) error {
+	cfg, err := GetSMTPConfig(nil)
+	if err != nil {
+		return fmt.Errorf("SMTP not configured: %w", err)
+	}
+
+	if len(to) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+
+	tlsMode := "disabled"
+	if cfg.UseTLS {
+		tlsMode = "STARTTLS"
+	}
+	sentAt := time.Now().UTC().Format(time.RFC1123)
+
+	subject := "Stationmaster SMTP Test"
+	textBody := fmt.Sprintf(`This is a test email from Stationmaster.
+
+If you received this message, your SMTP configuration is working.
+
+Server: %s:%d
+TLS mode: %s
+From: %s
+Recipients: %s
+CC: %s
+Sent at: %s
+`, cfg.Host, cfg.Port, tlsMode, cfg.From, strings.Join(to, ", "), strings.Join(cc, ", "), sentAt)
+	htmlBody := "<html><body><pre>" + html.EscapeString(textBody) + "</pre></body></html>"
+
+	return sendEmail(cfg, to, cc, subject, textBody, htmlBody)
+}
+
+// SendDeviceUnclaimedEmail notifies a user that one of their devices was unclaimed due to inactivity
+func SendDeviceUnclaimedEmail(user *database.User, deviceName string, lastSeen time.Time, threshold time.Duration) error {
+	cfg, err := GetSMTPConfig(user)
+	if err != nil {
+		return fmt.Errorf("SMTP not configured: %w", err)
+	}
+
+	subject := "Stationmaster: device unclaimed due to inactivity"
+	textBody := fmt.Sprintf(`Hello %s,
+
+Your device "%s" has not checked in since %s and was automatically unclaimed
+after %s of inactivity. Its playlist has been removed.
+
+If you still use this device, power it on and claim it again from your Stationmaster dashboard.
+`, sanitizeUsername(user.Username), deviceName, lastSeen.UTC().Format(time.RFC1123), threshold)
+	htmlBody := "<html><body><pre>" + html.EscapeString(textBody) + "</pre></body></html>"
+
+	return sendEmail(cfg, []string{user.Email}, nil, subject, textBody, htmlBody)
 }
 
-// sendEmail sends an email using SMTP
-func sendEmail(config *SMTPConfig, to, subject, textBody, htmlBody string) error {
+// SendRenderFailureEmail notifies a user that a plugin instance has failed to render repeatedly.
+// The email goes to the given address, sent through the user's SMTP server when they have one.
+func SendRenderFailureEmail(user *database.User, email, instanceName string, failures int, lastError string) error {
+	cfg, err := GetSMTPConfig(user)
+	if err != nil {
+		return fmt.Errorf("SMTP not configured: %w", err)
+	}
+
+	subject := fmt.Sprintf("Stationmaster: %s is failing to render", instanceName)
+	textBody := fmt.Sprintf(`Hello %s,
+
+Your plugin "%s" has failed to render %d times in a row.
+
+Last error:
+%s
+
+Devices keep showing the last successful render until the plugin recovers.
+You will not be notified again until it renders successfully.
+`, sanitizeUsername(user.Username), instanceName, failures, lastError)
+	htmlBody := "<html><body><pre>" + html.EscapeString(textBody) + "</pre></body></html>"
+
+	return sendEmail(cfg, []string{email}, nil, subject, textBody, htmlBody)
+}
+
+// sendEmail sends an email using SMTP to all To and CC recipients
+func sendEmail(config *SMTPConfig, to, cc []string, subject, textBody, htmlBody string) error {
 	// Create message
 	headers := make(map[string]string)
 	headers["From"] = config.From
-	headers["To"] = to
+	headers["To"] = strings.Join(to, ", ")
+	if len(cc) > 0 {
+		headers["Cc"] = strings.Join(cc, ", ")
+	}
 	headers["Subject"] = subject
 	headers["MIME-Version"] = "1.0"
 	headers["Content-Type"] = "multipart/alternative; boundary=\"boundary123\""
@@ -297,9 +439,69 @@ func sendEmail(config *SMTPConfig, to, subject, textBody, htmlBody string) error
 	// Setup authentication
 	auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
 
-	// Send email
-	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
-	return smtp.SendMail(addr, auth, config.From, []string{to}, message.Bytes())
+	// Send email, following the same steps as smtp.SendMail over our own connection
+	client, err := dialSMTP(config)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: config.Host}); err != nil {
+			return err
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); !ok {
+		return fmt.Errorf("smtp: server doesn't support AUTH")
+	}
+	if err := client.Auth(auth); err != nil {
+		return err
+	}
+	if err := client.Mail(config.From); err != nil {
+		return err
+	}
+	for _, recipient := range append(append([]string{}, to...), cc...) {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message.Bytes()); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dialSMTP connects to the configured SMTP server. With PublicOnly set, the host is resolved and only
+// public addresses are dialed.
+func dialSMTP(config *SMTPConfig) (*smtp.Client, error) {
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	ctx, cancel := context.WithTimeout(context.Background(), smtpDialTimeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	dial := dialer.DialContext
+	if config.PublicOnly {
+		dial = utils.PublicDialContext(dialer, nil)
+	}
+	conn, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(smtpSessionTimeout))
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
 }
 
 // generatePasswordResetHTML generates HTML content for password reset email
@@ -683,19 +885,33 @@ This email was sent by %s (%s)
 `, data.SiteName, data.Username, data.SiteName, data.SiteName, data.SiteURL, data.SiteName, data.SiteURL)
 }
 
-// TestSMTPConnection tests the SMTP connection
+// TestSMTPConnection tests the global SMTP connection
 func TestSMTPConnection() error {
-	config, err := GetSMTPConfig()
+	config, err := GetSMTPConfig(nil)
 	if err != nil {
 		return err
 	}
 
+	return testConnection(config)
+}
+
+// TestUserSMTPConnection tests the connection to a user's own SMTP server
+func TestUserSMTPConnection(user *database.User) error {
+	config, err := userSMTPConfig(user)
+	if err != nil {
+		return err
+	}
+
+	return testConnection(config)
+}
+
+// testConnection dials the SMTP server and verifies TLS and authentication
+func testConnection(config *SMTPConfig) error {
 	// Setup authentication
 	auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
 
 	// Test connection
-	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
-	client, err := smtp.Dial(addr)
+	client, err := dialSMTP(config)
 	if err != nil {
 		return fmt.Errorf("failed to connect to SMTP server: %w", err)
 	}
@@ -25,6 +25,8 @@ type Client struct {
 	Writer   http.ResponseWriter
 	Flusher  http.Flusher
 	Done     chan bool
+
+	ConnectedAt time.Time
 }
 
 // Service manages SSE connections and broadcasts
@@ -63,6 +65,8 @@ func (s *Service) AddClient(deviceID, userID uuid.UUID, w http.ResponseWriter) *
 		Writer:   w,
 		Flusher:  flusher,
 		Done:     make(chan bool),
+
+		ConnectedAt: time.Now().UTC(),
 	}
 
 	s.mu.Lock()
@@ -174,6 +178,27 @@ func (s *Service) GetDeviceClientCount(deviceID uuid.UUID) int {
 	return count
 }
 
+// GetDeviceConnectionInfo returns the number of clients connected to a device and when the
+// longest-lived of them connected, or nil when there are none
+func (s *Service) GetDeviceConnectionInfo(deviceID uuid.UUID) (int, *time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	var connectedSince *time.Time
+	for _, client := range s.clients {
+		if client.DeviceID != deviceID {
+			continue
+		}
+		count++
+		if connectedSince == nil || client.ConnectedAt.Before(*connectedSince) {
+			connectedAt := client.ConnectedAt
+			connectedSince = &connectedAt
+		}
+	}
+	return count, connectedSince
+}
+
 // Global SSE service instance
 var globalSSEService *Service
 
@@ -0,0 +1,60 @@
+package trmnl
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rmitchellscott/stationmaster/internal/database"
+)
+
+// isInActiveHours reports whether playlist rotation may advance for a device. Devices without an
+// active hours schedule are always active.
+func isInActiveHours(device *database.Device, userTimezone string) bool {
+	if !device.ActiveHoursEnabled || device.ActiveHoursStartTime == "" || device.ActiveHoursEndTime == "" {
+		return true
+	}
+
+	loc, err := time.LoadLocation(userTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now().UTC().In(loc)
+
+	startTime, err := parseSleepTime(device.ActiveHoursStartTime, now)
+	if err != nil {
+		return true
+	}
+	endTime, err := parseSleepTime(device.ActiveHoursEndTime, now)
+	if err != nil {
+		return true
+	}
+
+	if startTime.After(endTime) {
+		// Active hours cross midnight (e.g., 18:00 to 02:00)
+		return !now.Before(startTime) || now.Before(endTime)
+	}
+	return !now.Before(startTime) && now.Before(endTime)
+}
+
+// activeHoursHoldIndex returns the index of the item to hold on outside the device's active hours:
+// its configured hold item, or the item it last showed. The second return value is false when
+// rotation should advance as usual.
+func activeHoursHoldIndex(device *database.Device, activeItems []database.PlaylistItem, userTimezone string) (int, bool) {
+	if isInActiveHours(device, userTimezone) {
+		return 0, false
+	}
+
+	// The hold item may be hidden by its own schedule, in which case the last shown item is held instead
+	for _, itemID := range []*uuid.UUID{device.InactiveHoldItemID, device.LastPlaylistItemID} {
+		if itemID == nil {
+			continue
+		}
+		for i := range activeItems {
+			if activeItems[i].ID == *itemID {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
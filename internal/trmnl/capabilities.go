@@ -0,0 +1,66 @@
+package trmnl
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// CapabilitiesHandler stores the capabilities a device firmware reports about itself.
+// Fields left out of the report fall back to the device model defaults.
+// POST /api/capabilities
+func CapabilitiesHandler(c *gin.Context) {
+	deviceID := c.GetHeader("ID")
+	accessToken := c.GetHeader("Access-Token")
+
+	if deviceID == "" || accessToken == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing device ID or access token"})
+		return
+	}
+
+	deviceService := database.NewDeviceService(database.GetDB())
+
+	device, err := deviceService.GetDeviceByAPIKey(accessToken)
+	if err != nil || device.MacAddress != deviceID {
+		logging.Debug("[/api/capabilities] Authentication failed", "device_id", deviceID, "error", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid device credentials"})
+		return
+	}
+
+	var report database.DeviceCapabilityReport
+	if err := c.ShouldBindJSON(&report); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid capability report"})
+		return
+	}
+	if err := report.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process capability report"})
+		return
+	}
+
+	now := time.Now().UTC()
+	if err := deviceService.UpdateDeviceFields(device.ID, map[string]interface{}{
+		"reported_capabilities":    string(reportJSON),
+		"capabilities_reported_at": now,
+	}); err != nil {
+		logging.Error("[/api/capabilities] Failed to store capabilities", "device_id", device.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store capabilities"})
+		return
+	}
+
+	device.ReportedCapabilities = string(reportJSON)
+	device.CapabilitiesReportedAt = &now
+
+	logging.Info("[/api/capabilities] Device reported capabilities", "mac_address", device.MacAddress, "capabilities", string(reportJSON))
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "capabilities": device.EffectiveCapabilities()})
+}
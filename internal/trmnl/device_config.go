@@ -0,0 +1,103 @@
+package trmnl
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// DeviceConfigHandler returns a device's effective configuration so firmware doesn't have to infer
+// it from display responses. The refresh rate reflects maintenance mode, unclaimed devices and the
+// sleep schedule the same way /api/display does, without any playlist item overrides.
+// GET /api/device-config
+func DeviceConfigHandler(c *gin.Context) {
+	deviceID := c.GetHeader("ID")
+	accessToken := c.GetHeader("Access-Token")
+
+	if deviceID == "" || accessToken == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing device ID or access token"})
+		return
+	}
+
+	db := database.GetDB()
+	device, err := database.NewDeviceService(db).GetDeviceByAPIKey(accessToken)
+	if err != nil || device.MacAddress != deviceID {
+		logging.Debug("[/api/device-config] Authentication failed", "device_id", deviceID, "error", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid device credentials"})
+		return
+	}
+
+	userTimezone := "UTC"
+	if device.UserID != nil {
+		if user, err := database.NewUserService(db).GetUserByID(*device.UserID); err == nil && user.Timezone != "" {
+			userTimezone = user.Timezone
+		}
+	}
+
+	maintenance := IsMaintenanceModeEnabled()
+	inSleepPeriod := isInSleepPeriod(device, userTimezone)
+
+	refreshRate := device.RefreshRate
+	switch {
+	case maintenance:
+		refreshRate = maintenanceRefreshRate()
+	case inSleepPeriod:
+		refreshRate = calculateSecondsUntilSleepEnd(device, userTimezone)
+	case !device.IsClaimed:
+		if rate, ok := unclaimedRefreshRate(); ok {
+			refreshRate = rate
+		}
+	}
+
+	config := gin.H{
+		"friendly_id":          device.FriendlyID,
+		"claimed":              device.IsClaimed,
+		"maintenance":          maintenance,
+		"refresh_rate":         refreshRate,
+		"default_refresh_rate": device.RefreshRate,
+		"sleep": gin.H{
+			"enabled":     device.SleepEnabled,
+			"start_time":  device.SleepStartTime,
+			"end_time":    device.SleepEndTime,
+			"show_screen": device.SleepShowScreen,
+			"timezone":    userTimezone,
+			"active":      inSleepPeriod,
+		},
+		"display": gin.H{
+			"screen_orientation":    device.ScreenOrientation,
+			"maximum_compatibility": device.MaximumCompatibility,
+			"temperature_profile":   device.TemperatureProfile,
+			"safe_area": gin.H{
+				"top":    device.SafeAreaTop,
+				"right":  device.SafeAreaRight,
+				"bottom": device.SafeAreaBottom,
+				"left":   device.SafeAreaLeft,
+			},
+		},
+		"buttons": gin.H{
+			"touchbar_mode": device.TouchbarMode,
+		},
+		"firmware": gin.H{
+			"allow_updates":     device.AllowFirmwareUpdates,
+			"target_version":    device.TargetFirmwareVersion,
+			"channel":           device.FirmwareChannel,
+			"update_start_time": device.FirmwareUpdateStartTime,
+			"update_end_time":   device.FirmwareUpdateEndTime,
+		},
+		"capabilities": device.EffectiveCapabilities(),
+	}
+
+	if device.DeviceModel != nil {
+		config["model"] = gin.H{
+			"name":          device.DeviceModel.ModelName,
+			"screen_width":  device.DeviceModel.ScreenWidth,
+			"screen_height": device.DeviceModel.ScreenHeight,
+			"bit_depth":     device.DeviceModel.BitDepth,
+			"buttons":       device.DeviceModel.HasButtons,
+		}
+	}
+
+	c.JSON(http.StatusOK, config)
+}
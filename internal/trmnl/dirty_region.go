@@ -0,0 +1,50 @@
+package trmnl
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"gorm.io/gorm"
+)
+
+// maxDirtyRegionCoverage is the largest share of the screen a dirty region may cover. Past it a full
+// refresh is as fast and clears e-ink ghosting, so no region is sent.
+const maxDirtyRegionCoverage = 0.5
+
+// dirtyRegionForDevice returns the dirty_region to send with rendered content, or nil when the device
+// should do a full refresh. The region is only valid against the render before it, so it is sent only when
+// the device last showed this plugin instance and already had that render at its last check-in.
+func dirtyRegionForDevice(db *gorm.DB, device *database.Device, pluginInstanceID uuid.UUID, content *database.RenderedContent) gin.H {
+	if content.DirtyX == nil || content.DirtyY == nil || content.DirtyWidth == nil || content.DirtyHeight == nil || content.PreviousHash == nil {
+		return nil
+	}
+	width, height := *content.DirtyWidth, *content.DirtyHeight
+	if width <= 0 || height <= 0 || float64(width*height) > maxDirtyRegionCoverage*float64(content.Width*content.Height) {
+		return nil
+	}
+	if device.LastSeen == nil || device.LastPlaylistItemID == nil || !content.RenderedAt.After(*device.LastSeen) {
+		// Devices that already fetched this render don't need a region for it
+		return nil
+	}
+
+	var lastItem database.PlaylistItem
+	if err := db.Select("plugin_instance_id").Where("id = ?", *device.LastPlaylistItemID).First(&lastItem).Error; err != nil || lastItem.PluginInstanceID != pluginInstanceID {
+		return nil
+	}
+
+	// The previous render must be one the device could have fetched, not one made since its last check-in
+	var previousCount int64
+	db.Model(&database.RenderedContent{}).
+		Where("plugin_instance_id = ? AND device_id = ? AND content_hash = ? AND rendered_at <= ?", pluginInstanceID, device.ID, *content.PreviousHash, *device.LastSeen).
+		Count(&previousCount)
+	if previousCount == 0 {
+		return nil
+	}
+
+	return gin.H{
+		"x": *content.DirtyX,
+		"y": *content.DirtyY,
+		"w": width,
+		"h": height,
+	}
+}
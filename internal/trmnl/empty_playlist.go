@@ -0,0 +1,65 @@
+package trmnl
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// processEmptyPlaylist builds the response for a device with no active playlist items,
+// according to the device's empty playlist mode. The default mode returns an error so
+// callers fall back to their usual placeholder screen.
+func (pp *PluginProcessor) processEmptyPlaylist(ctx context.Context, device *database.Device) (gin.H, error) {
+	switch device.EmptyPlaylistMode {
+	case database.EmptyPlaylistModeSetup:
+		return gin.H{
+			"image_url": getSetupImageURL(),
+			"filename":  "empty_state",
+		}, nil
+
+	case database.EmptyPlaylistModeImage:
+		imageURL := strings.TrimSpace(device.EmptyPlaylistImageURL)
+		if imageURL == "" {
+			return nil, fmt.Errorf("no active playlist items and no empty playlist image configured")
+		}
+
+		// Include a hash of the URL so devices fetch the new image when it changes
+		hash := fnv.New32a()
+		hash.Write([]byte(imageURL))
+		return gin.H{
+			"image_url": imageURL,
+			"filename":  fmt.Sprintf("empty_playlist_%08x", hash.Sum32()),
+		}, nil
+
+	case database.EmptyPlaylistModePlugin:
+		if device.EmptyPlaylistInstanceID == nil || device.UserID == nil {
+			return nil, fmt.Errorf("no active playlist items and no empty playlist plugin configured")
+		}
+
+		pluginInstance, err := pp.pluginService.GetPluginInstanceByID(*device.EmptyPlaylistInstanceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get empty playlist plugin instance: %w", err)
+		}
+		if pluginInstance.UserID != *device.UserID {
+			return nil, fmt.Errorf("empty playlist plugin instance does not belong to the device owner")
+		}
+
+		response, err := pp.processUnifiedPluginInstance(ctx, device, pluginInstance)
+		if err != nil {
+			return nil, fmt.Errorf("empty playlist plugin processing failed: %w", err)
+		}
+		if skipItem, ok := response["skip_item"].(bool); ok && skipItem {
+			return nil, fmt.Errorf("no_prerender_content: empty playlist plugin %s", pluginInstance.Name)
+		}
+
+		logging.FromContext(ctx).Info("[PLUGIN] Serving empty playlist plugin", "device", device.FriendlyID, "plugin_name", pluginInstance.Name)
+		return response, nil
+	}
+
+	return nil, fmt.Errorf("no active playlist items")
+}
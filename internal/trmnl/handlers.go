@@ -2,9 +2,13 @@ package trmnl
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
@@ -15,8 +19,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/rmitchellscott/stationmaster/internal/database"
 	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"github.com/rmitchellscott/stationmaster/internal/rendering"
 	"github.com/rmitchellscott/stationmaster/internal/sse"
 	"github.com/rmitchellscott/stationmaster/internal/utils"
+	"gorm.io/gorm"
 )
 
 // SetupHandler handles device setup requests from TRMNL devices
@@ -55,6 +61,7 @@ func SetupHandler(c *gin.Context) {
 			"image_url":   getSetupImageURL(),
 			"filename":    "empty_state",
 		}
+		applyUnclaimedRefreshRate(response, device)
 
 		if logging.IsDebugEnabled() {
 			responseBytes, _ := json.Marshal(response)
@@ -67,6 +74,15 @@ func SetupHandler(c *gin.Context) {
 
 	// Device doesn't exist, auto-register it as unclaimed
 	device, err = deviceService.CreateUnclaimedDevice(macAddress, modelHeader)
+	if errors.Is(err, database.ErrUnknownDeviceModel) {
+		logging.Warn("[/api/setup] Rejected device reporting unknown model", "mac_address", macAddress, "model", modelHeader)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"status":  http.StatusUnprocessableEntity,
+			"error":   fmt.Sprintf("Device model %q is not supported by this server", modelHeader),
+			"message": "Ask an administrator to add this model under Device Models or change how unknown models are handled in the system settings",
+		})
+		return
+	}
 	if err != nil {
 		logging.Error("[/api/setup] Error creating device", "mac_address", macAddress, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register device"})
@@ -75,6 +91,11 @@ func SetupHandler(c *gin.Context) {
 
 	logging.Debug("[/api/setup] Created new device", "mac_address", macAddress, "friendly_id", device.FriendlyID)
 
+	// Devices pre-registered by an admin go straight to their designated user
+	if _, err := database.NewPreregistrationService(db).ClaimPreregisteredDevice(device); err != nil {
+		logging.Error("[/api/setup] Failed to claim pre-registered device", "mac_address", macAddress, "error", err)
+	}
+
 	// Return the new device information
 	response := gin.H{
 		"status":      200,
@@ -83,6 +104,7 @@ func SetupHandler(c *gin.Context) {
 		"image_url":   getSetupImageURL(),
 		"filename":    "empty_state",
 	}
+	applyUnclaimedRefreshRate(response, device)
 
 	if logging.IsDebugEnabled() {
 		responseBytes, _ := json.Marshal(response)
@@ -96,8 +118,10 @@ func SetupHandler(c *gin.Context) {
 // GET /api/display with headers for device authentication and status
 func DisplayHandler(c *gin.Context) {
 	startTime := time.Now().UTC()
+	requestCtx := c.Request.Context()
+	simulation, simulated := displaySimulationFromContext(c)
 
-	logging.DebugWithComponent(logging.ComponentAPIDisplay, "Request received", "client_ip", c.ClientIP(), "method", c.Request.Method, "path", c.Request.URL.Path)
+	logging.DebugWithComponent(logging.ComponentAPIDisplay, "Request received", "client_ip", c.ClientIP(), "method", c.Request.Method, "path", c.Request.URL.Path, "request_id", logging.RequestIDFromContext(requestCtx))
 	
 	// Variables to capture for background operations (must be declared early for defer)
 	var backgroundData struct {
@@ -113,8 +137,17 @@ func DisplayHandler(c *gin.Context) {
 	
 	// Defer background operations to ensure they run even if API fails
 	defer func() {
+		if simulated {
+			// Simulated requests leave the device untouched unless asked to advance its playlist
+			if simulation.Advance && backgroundData.shouldUpdatePlaylist && backgroundData.currentItem != nil {
+				advanceSimulatedPlaylist(requestCtx, backgroundData.device, *backgroundData.currentItem, backgroundData.activeItems, backgroundData.sleepScreenServed)
+			}
+			return
+		}
 		if backgroundData.accessToken != "" {
 			go func() {
+				logging.FromContext(requestCtx).Debug("[BACKGROUND] Running deferred display operations", "device_id", backgroundData.deviceID)
+
 				// Update device status in database
 				deviceService := database.NewDeviceService(database.GetDB())
 				if statusValues, ok := backgroundData.statusValues.(struct {
@@ -128,19 +161,19 @@ func DisplayHandler(c *gin.Context) {
 				}); ok {
 					err := deviceService.UpdateDeviceStatus(statusValues.macAddress, statusValues.firmwareVersion, statusValues.batteryVoltage, statusValues.batteryPercent, statusValues.rssi, statusValues.modelHeader)
 					if err != nil {
-						logging.Error("[BACKGROUND] Failed to update device status", "mac_address", statusValues.macAddress, "error", err)
+						logging.FromContext(requestCtx).Error("[BACKGROUND] Failed to update device status", "mac_address", statusValues.macAddress, "error", err)
 					}
 				}
 				
 				// Update playlist item ID if needed
 				if backgroundData.shouldUpdatePlaylist && backgroundData.currentItem != nil {
 					if err := deviceService.UpdateLastPlaylistItemID(backgroundData.deviceID, backgroundData.currentItem.ID); err != nil {
-						logging.Error("[BACKGROUND] Failed to update last playlist item ID", "device_id", backgroundData.deviceID, "item_id", backgroundData.currentItem.ID, "error", err)
+						logging.FromContext(requestCtx).Error("[BACKGROUND] Failed to update last playlist item ID", "device_id", backgroundData.deviceID, "item_id", backgroundData.currentItem.ID, "error", err)
 					} else {
 						// Broadcast playlist change via SSE
 						processor := GetPluginProcessor()
 						if processor != nil {
-							processor.broadcastPlaylistChange(backgroundData.device, *backgroundData.currentItem, backgroundData.activeItems, backgroundData.sleepScreenServed)
+							processor.broadcastPlaylistChange(requestCtx, backgroundData.device, *backgroundData.currentItem, backgroundData.activeItems, backgroundData.sleepScreenServed)
 						}
 					}
 				}
@@ -148,7 +181,7 @@ func DisplayHandler(c *gin.Context) {
 				// Refresh device data for SSE broadcast
 				refreshedDevice, err := deviceService.GetDeviceByAPIKey(backgroundData.accessToken)
 				if err != nil {
-					logging.Error("[BACKGROUND] Failed to refresh device data", "device_id", backgroundData.deviceID, "error", err)
+					logging.FromContext(requestCtx).Error("[BACKGROUND] Failed to refresh device data", "device_id", backgroundData.deviceID, "error", err)
 				} else {
 					// Broadcast device status update to connected SSE clients
 					sseService := sse.GetSSEService()
@@ -229,6 +262,9 @@ func DisplayHandler(c *gin.Context) {
 	}
 
 	logging.Debug("[/api/display] Authentication successful", "mac_address", device.MacAddress, "friendly_id", device.FriendlyID)
+	if !simulated {
+		recordRequestHeaders(device.ID, c.ClientIP(), c.Request.Header)
+	}
 
 	// Get user timezone for sleep mode calculations
 	userTimezone := "UTC" // Default fallback
@@ -297,13 +333,7 @@ func DisplayHandler(c *gin.Context) {
 
 	// If device has no DeviceModel, use Width/Height headers as fallback
 	if device.DeviceModel == nil {
-		var reportedWidth, reportedHeight int
-		if w, err := strconv.Atoi(widthStr); err == nil && w > 0 {
-			reportedWidth = w
-		}
-		if h, err := strconv.Atoi(heightStr); err == nil && h > 0 {
-			reportedHeight = h
-		}
+		reportedWidth, reportedHeight := parseReportedDimensions(widthStr, heightStr)
 		if reportedWidth > 0 && reportedHeight > 0 {
 			logging.Warn("[/api/display] Device has no model in DB, using reported dimensions as fallback",
 				"mac_address", device.MacAddress, "width", reportedWidth, "height", reportedHeight, "model_header", modelHeader)
@@ -316,7 +346,27 @@ func DisplayHandler(c *gin.Context) {
 				ColorDepth:  1,
 				IsActive:    true,
 			}
+		} else if fallback, ok := rendering.FallbackDeviceModel(); ok {
+			// Match the content the render worker produced with the fallback model
+			device.DeviceModel = fallback
 		}
+	} else if reportedWidth, reportedHeight := parseReportedDimensions(widthStr, heightStr); reportedWidth > 0 && reportedHeight > 0 &&
+		(reportedWidth != device.DeviceModel.ScreenWidth || reportedHeight != device.DeviceModel.ScreenHeight) &&
+		matchesModelAspectRatio(reportedWidth, reportedHeight, device.DeviceModel) {
+		// Firmware is requesting a size other than the model's native one - remember the variant so the
+		// render worker produces content for it, and match pre-rendered content against the reported size
+		// Simulated requests don't start rendering a variant just because an admin tried the size
+		if !simulated {
+			if err := deviceService.RecordDeviceResolution(device.ID, reportedWidth, reportedHeight, device.DeviceModel.BitDepth); err != nil {
+				logging.Warn("[/api/display] Failed to record reported resolution", "mac_address", device.MacAddress, "error", err)
+			}
+		}
+		variantModel := *device.DeviceModel
+		variantModel.ScreenWidth = reportedWidth
+		variantModel.ScreenHeight = reportedHeight
+		device.DeviceModel = &variantModel
+		logging.Debug("[/api/display] Using reported resolution variant",
+			"mac_address", device.MacAddress, "width", reportedWidth, "height", reportedHeight)
 	}
 
 	// Get current playlist items for this device
@@ -369,6 +419,7 @@ func DisplayHandler(c *gin.Context) {
 			"touchbar_mode":         device.TouchbarMode,
 			"temperature_profile":   device.TemperatureProfile,
 		}
+		applyMinRefreshRate(response, device)
 
 		if logging.IsDebugEnabled() {
 			responseBytes, _ := json.Marshal(response)
@@ -380,6 +431,15 @@ func DisplayHandler(c *gin.Context) {
 		return
 	}
 
+	// Maintenance mode - every device shows the maintenance screen regardless of playlist
+	if response, ok := maintenanceResponse(device, baseURL); ok {
+		logging.Debug("[/api/display] Maintenance mode enabled, returning maintenance screen", "mac_address", device.MacAddress)
+		logging.Debug("[/api/display] Request processing time", "duration", time.Since(startTime))
+
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
 	// Check for firmware update AFTER device status is updated
 	firmwareUpdate := checkFirmwareUpdate(c, device, userTimezone)
 
@@ -419,7 +479,7 @@ func DisplayHandler(c *gin.Context) {
 	go func() {
 		var res pluginResult
 		if processor != nil {
-			res.response, res.currentItem, res.pluginErr = processor.processActivePlugins(device, activeItems)
+			res.response, res.currentItem, res.pluginErr = processor.processActivePlugins(requestCtx, device, activeItems, userTimezone)
 		} else {
 			// No processor available - return error
 			res.pluginErr = fmt.Errorf("unified plugin processor not available")
@@ -461,10 +521,17 @@ func DisplayHandler(c *gin.Context) {
 			}
 		}
 		
-		response = gin.H{
-			"image_url":  statusImageURL("timeout_error.png", device),
-			"filename":   statusFilename("timeout_error", device),
-			"refresh_rate": fmt.Sprintf("%d", timeoutRefreshRate),
+		// Optionally keep the last rendered content on screen instead of the error image
+		response = nil
+		if displayTimeoutFallback() == DisplayTimeoutFallbackLastContent {
+			response = lastContentTimeoutResponse(requestCtx, processor, device, timeoutRefreshRate)
+		}
+		if response == nil {
+			response = gin.H{
+				"image_url":  statusImageURL("timeout_error.png", device),
+				"filename":   statusFilename("timeout_error", device),
+				"refresh_rate": fmt.Sprintf("%d", timeoutRefreshRate),
+			}
 		}
 		pluginErr = fmt.Errorf("plugin processing timeout")
 	}
@@ -520,6 +587,12 @@ func DisplayHandler(c *gin.Context) {
 		// If no playlist override but plugin provided refresh_rate, keep plugin rate
 	}
 
+	// Error and timeout responses are clamped too, so a failing plugin doesn't cause rapid retries
+	applyMinRefreshRate(response, device)
+
+	// Unclaimed devices have no content yet, so poll at the slower unclaimed rate
+	applyUnclaimedRefreshRate(response, device)
+
 	// Handle sleep mode - override refresh rate and image if in sleep period
 	inSleepPeriod := isInSleepPeriod(device, userTimezone)
 	
@@ -674,6 +747,32 @@ func statusImageURL(filename string, device *database.Device) string {
 	}
 	return "/images/" + filename
 }
+
+// systemImageNames lists the built-in status screens that can be served to devices
+var systemImageNames = map[string]bool{
+	"low_battery":   true,
+	"sleep":         true,
+	"timeout_error": true,
+	"generic_error": true,
+	"empty_state":   true,
+}
+
+// SystemImageURL returns the URL of a built-in status screen for the given device.
+// The device may be nil, in which case the standard-resolution variant is returned.
+// The second return value is false if the name is not a known system screen.
+func SystemImageURL(name string, device *database.Device) (string, bool) {
+	if !systemImageNames[name] {
+		return "", false
+	}
+	if name == "empty_state" {
+		return getSetupImageURL(), true
+	}
+	if device == nil {
+		device = &database.Device{}
+	}
+	return statusImageURL(name+".png", device), true
+}
+
 // getSetupImageURL returns the setup/empty-state image URL appropriate for a device model.
 // The TRMNL X (1872x1404) and original TRMNL (800x480) use the same external setup image
 // since the firmware handles display scaling. Override via SETUP_IMAGE_URL env var.
@@ -712,93 +811,144 @@ func DeviceImageHandler(c *gin.Context) {
 		return
 	}
 
-	// Get current playlist items
-	playlistService := database.NewPlaylistService(db)
-	activeItems, err := playlistService.GetActivePlaylistItemsForTime(device.ID, time.Now().UTC())
+	// Serve the item the device is currently showing, as reported by its last display request
+	renderedContent, _, err := CurrentRenderedContent(db, device)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get playlist items"})
+		// No active playlist items or no rendered content available - return placeholder
+		logging.Debug("[DEVICE_IMAGE] No rendered content for current item, serving placeholder", "device", device.FriendlyID)
+		c.Header("Cache-Control", "no-cache")
+		c.Data(http.StatusOK, "image/png", placeholderPNG)
 		return
 	}
 
-	if len(activeItems) == 0 {
-		// No active playlist items - return placeholder
-		c.Header("Content-Type", "image/png")
-		c.Header("Cache-Control", "no-cache")
-		
-		placeholder := []byte{
-			0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A,
-			0x00, 0x00, 0x00, 0x0D, 0x49, 0x48, 0x44, 0x52,
-			0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
-			0x08, 0x06, 0x00, 0x00, 0x00, 0x1F, 0x15, 0xC4,
-			0x89, 0x00, 0x00, 0x00, 0x0A, 0x49, 0x44, 0x41,
-			0x54, 0x78, 0x9C, 0x63, 0x00, 0x01, 0x00, 0x00,
-			0x05, 0x00, 0x01, 0x0D, 0x0A, 0x2D, 0xB4, 0x00,
-			0x00, 0x00, 0x00, 0x49, 0x45, 0x4E, 0x44, 0xAE,
-			0x42, 0x60, 0x82,
-		}
-		c.Data(http.StatusOK, "image/png", placeholder)
+	etag, err := renderedContentETag(renderedContent)
+	if err != nil {
+		logging.Warn("[DEVICE_IMAGE] Rendered image file unavailable", "device", device.FriendlyID, "content_id", renderedContent.ID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rendered image not found"})
 		return
 	}
 
-	// Get the current playlist item (typically the first active item)
-	currentItem := activeItems[0]
-
-	// Look for device-specific rendered content first, fallback to device-model content
-	var renderedContent *database.RenderedContent
-	
-	// Try device-specific content first
-	err = db.Where("plugin_instance_id = ? AND device_id = ?", 
-		currentItem.PluginInstanceID, device.ID).
-		Order("rendered_at DESC").
-		First(&renderedContent).Error
-	
-	if err != nil {
-		// Fallback to device-model based content (backward compatibility)
-		if device.DeviceModel != nil {
-			err = db.Where("plugin_instance_id = ? AND device_id IS NULL AND width = ? AND height = ? AND bit_depth = ?",
-				currentItem.PluginInstanceID, 
-				device.DeviceModel.ScreenWidth, 
-				device.DeviceModel.ScreenHeight, 
-				device.DeviceModel.BitDepth).
-				Order("rendered_at DESC").
-				First(&renderedContent).Error
-		}
-		
-		if err != nil {
-			// No rendered content available - return placeholder
-			logging.Warn("[DEVICE_IMAGE] No rendered content found", "device", device.FriendlyID, "plugin_instance", currentItem.PluginInstanceID)
-			c.Header("Content-Type", "image/png")
-			c.Header("Cache-Control", "no-cache")
-			
-			placeholder := []byte{
-				0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A,
-				0x00, 0x00, 0x00, 0x0D, 0x49, 0x48, 0x44, 0x52,
-				0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
-				0x08, 0x06, 0x00, 0x00, 0x00, 0x1F, 0x15, 0xC4,
-				0x89, 0x00, 0x00, 0x00, 0x0A, 0x49, 0x44, 0x41,
-				0x54, 0x78, 0x9C, 0x63, 0x00, 0x01, 0x00, 0x00,
-				0x05, 0x00, 0x01, 0x0D, 0x0A, 0x2D, 0xB4, 0x00,
-				0x00, 0x00, 0x00, 0x49, 0x45, 0x4E, 0x44, 0xAE,
-				0x42, 0x60, 0x82,
-			}
-			c.Data(http.StatusOK, "image/png", placeholder)
-			return
-		}
+	// Devices revalidate on every request, and only download the image again when its content changed
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		logging.Debug("[DEVICE_IMAGE] Content unchanged, returning not modified", "device", device.FriendlyID, "content_id", renderedContent.ID)
+		c.Status(http.StatusNotModified)
+		return
 	}
 
-	// Serve the rendered image file
-	c.Header("Content-Type", "image/png")
-	c.Header("Cache-Control", "max-age=300") // Cache for 5 minutes
-	
 	if renderedContent.DeviceID != nil {
 		logging.Debug("[DEVICE_IMAGE] Serving device-specific content", "device", device.FriendlyID, "content_id", renderedContent.ID)
 	} else {
 		logging.Debug("[DEVICE_IMAGE] Serving device-model content", "device", device.FriendlyID, "content_id", renderedContent.ID)
 	}
-	
+
+	c.Header("Content-Type", "image/png")
 	c.File(renderedContent.ImagePath)
 }
 
+// placeholderPNG is a transparent 1x1 image served when a device has nothing to show
+var placeholderPNG = []byte{
+	0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A,
+	0x00, 0x00, 0x00, 0x0D, 0x49, 0x48, 0x44, 0x52,
+	0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+	0x08, 0x06, 0x00, 0x00, 0x00, 0x1F, 0x15, 0xC4,
+	0x89, 0x00, 0x00, 0x00, 0x0A, 0x49, 0x44, 0x41,
+	0x54, 0x78, 0x9C, 0x63, 0x00, 0x01, 0x00, 0x00,
+	0x05, 0x00, 0x01, 0x0D, 0x0A, 0x2D, 0xB4, 0x00,
+	0x00, 0x00, 0x00, 0x49, 0x45, 0x4E, 0x44, 0xAE,
+	0x42, 0x60, 0x82,
+}
+
+// renderedContentETag returns a strong ETag for a rendered image. The stored content hash is combined with
+// the image format so a re-render at another resolution or bit depth isn't mistaken for the cached copy.
+// Content rendered before hashes were stored is hashed from the file.
+func renderedContentETag(renderedContent *database.RenderedContent) (string, error) {
+	hash := ""
+	if renderedContent.ContentHash != nil {
+		hash = *renderedContent.ContentHash
+	}
+	if hash == "" {
+		data, err := os.ReadFile(renderedContent.ImagePath)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(data)
+		hash = hex.EncodeToString(sum[:])
+	}
+	return fmt.Sprintf(`"%s-%dx%dx%d"`, hash, renderedContent.Width, renderedContent.Height, renderedContent.BitDepth), nil
+}
+
+// etagMatches reports whether an If-None-Match header matches a strong ETag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+
+// latestRenderedContentForDevice returns the newest rendered content of a plugin instance for a device,
+// falling back to content rendered for the device's model
+func latestRenderedContentForDevice(db *gorm.DB, device *database.Device, pluginInstanceID uuid.UUID) (*database.RenderedContent, error) {
+	var renderedContent database.RenderedContent
+
+	// Try device-specific content first
+	err := db.Where("plugin_instance_id = ? AND device_id = ?", pluginInstanceID, device.ID).
+		Order("rendered_at DESC").
+		First(&renderedContent).Error
+	if err == nil {
+		return &renderedContent, nil
+	}
+
+	// Fallback to device-model based content (backward compatibility)
+	if device.DeviceModel == nil {
+		return nil, err
+	}
+	err = db.Where("plugin_instance_id = ? AND device_id IS NULL AND width = ? AND height = ? AND bit_depth = ?",
+		pluginInstanceID,
+		device.DeviceModel.ScreenWidth,
+		device.DeviceModel.ScreenHeight,
+		device.DeviceModel.BitDepth).
+		Order("rendered_at DESC").
+		First(&renderedContent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &renderedContent, nil
+}
+
+// aspectRatioTolerance is how far a reported size's aspect ratio may stray from the model's, as a fraction
+const aspectRatioTolerance = 0.02
+
+// parseReportedDimensions parses the Width/Height headers sent by the device, returning 0 for missing or invalid
+// values. Sizes past database.MaxDeviceResolutionDimension are invalid, since each variant is rendered for every
+// instance.
+func parseReportedDimensions(widthStr, heightStr string) (int, int) {
+	var width, height int
+	if w, err := strconv.Atoi(widthStr); err == nil && w > 0 && w <= database.MaxDeviceResolutionDimension {
+		width = w
+	}
+	if h, err := strconv.Atoi(heightStr); err == nil && h > 0 && h <= database.MaxDeviceResolutionDimension {
+		height = h
+	}
+	return width, height
+}
+
+// matchesModelAspectRatio reports whether a reported size has the model's aspect ratio in either orientation.
+// Firmware scales the model's screen, so any other shape is a bogus header rather than a real variant.
+func matchesModelAspectRatio(width, height int, model *database.DeviceModel) bool {
+	if model.ScreenWidth <= 0 || model.ScreenHeight <= 0 {
+		return false
+	}
+	ratio := float64(width) / float64(height)
+	modelRatio := float64(model.ScreenWidth) / float64(model.ScreenHeight)
+	return math.Abs(ratio-modelRatio) <= modelRatio*aspectRatioTolerance ||
+		math.Abs(ratio-1/modelRatio) <= aspectRatioTolerance/modelRatio
+}
 
 // parsePluginSettings parses plugin settings from JSON string
 func parsePluginSettings(settingsJSON string) (map[string]interface{}, error) {
@@ -818,81 +968,84 @@ type FirmwareUpdateResponse struct {
 	ResetFirmware  bool   `json:"reset_firmware"`
 }
 
-// checkFirmwareUpdate checks if device needs a firmware update and can receive one
-func checkFirmwareUpdate(c *gin.Context, device *database.Device, userTimezone string) FirmwareUpdateResponse {
-	// Default response - no firmware update
-	defaultResponse := FirmwareUpdateResponse{
-		UpdateFirmware: false,
-		FirmwareURL:    "",
-		ResetFirmware:  false,
-	}
+// FirmwareUpdateEligibility is the outcome of the firmware update checks for a device,
+// excluding the device's update schedule window
+type FirmwareUpdateEligibility struct {
+	Eligible       bool
+	Reason         string // Why the device is excluded, empty when eligible
+	Family         string
+	TargetVersion  string
+	TargetFirmware *database.FirmwareVersion
+}
 
+// EvaluateFirmwareUpdate runs the firmware update eligibility checks for a device except the schedule window.
+// When latestVersion is set it is used in place of the latest known version for devices tracking the latest firmware;
+// otherwise those devices target the newest release on their firmware channel.
+func EvaluateFirmwareUpdate(device *database.Device, latestVersion string) FirmwareUpdateEligibility {
 	// 0. Never update firmware for unclaimed devices
 	if !device.IsClaimed {
-		return defaultResponse
+		return FirmwareUpdateEligibility{Reason: "device is not claimed"}
 	}
 
 	// 1. Check if updates are allowed for this device
 	if !device.AllowFirmwareUpdates {
-		return defaultResponse
+		return FirmwareUpdateEligibility{Reason: "firmware updates are disabled for this device"}
 	}
 
-	// 2. Check if we're in the firmware update schedule window
-	if !isInFirmwareUpdatePeriod(device, userTimezone) {
-		return defaultResponse
-	}
-
-	// 3. Determine firmware family from device model
+	// 2. Determine firmware family from device model
 	var firmwareFamily string
 	if device.DeviceModel != nil {
 		family, updatable := database.GetFirmwareFamily(device.DeviceModel.ModelName)
 		if !updatable {
-			logging.Debug("[FIRMWARE UPDATE] Device model not firmware-updatable", "model", device.DeviceModel.ModelName)
-			return defaultResponse
+			return FirmwareUpdateEligibility{Reason: fmt.Sprintf("device model %s is not firmware-updatable", device.DeviceModel.ModelName)}
 		}
 		firmwareFamily = family
 	} else {
 		firmwareFamily = "trmnl"
 	}
+	result := FirmwareUpdateEligibility{Family: firmwareFamily}
 
 	db := database.GetDB()
 	firmwareService := database.NewFirmwareService(db)
 
-	// 4. Determine target firmware version
-	var targetVersion string
-	if device.TargetFirmwareVersion != "" && device.TargetFirmwareVersion != "latest" {
-		targetVersion = device.TargetFirmwareVersion
-	} else {
-		latestFirmware, err := firmwareService.GetLatestFirmwareVersionForFamily(firmwareFamily)
-		if err != nil {
-			return defaultResponse
-		}
-		targetVersion = latestFirmware.Version
-	}
-
-	// 5. Compare with device's current version
-	if device.FirmwareVersion == targetVersion {
-		return defaultResponse
-	}
-
-	// 6. Get the target firmware to check availability
+	// 3. Determine the target firmware
 	var targetFirmware *database.FirmwareVersion
 	var err error
-
 	if device.TargetFirmwareVersion != "" && device.TargetFirmwareVersion != "latest" {
-		targetFirmware, err = firmwareService.GetFirmwareVersionByVersion(targetVersion)
+		result.TargetVersion = device.TargetFirmwareVersion
+		targetFirmware, err = firmwareService.GetFirmwareVersionByVersion(device.TargetFirmwareVersion)
 		if err != nil {
-			logging.Warn("[FIRMWARE UPDATE] Target firmware version not found", "target_version", targetVersion, "mac_address", device.MacAddress)
-			return defaultResponse
+			result.Reason = fmt.Sprintf("pinned target firmware %s not found", device.TargetFirmwareVersion)
+			return result
+		}
+	} else if latestVersion != "" {
+		result.TargetVersion = latestVersion
+		targetFirmware, err = firmwareService.GetFirmwareVersionForFamily(latestVersion, firmwareFamily)
+		if err != nil {
+			result.Reason = fmt.Sprintf("firmware %s not found for family %s", latestVersion, firmwareFamily)
+			return result
 		}
 	} else {
-		targetFirmware, err = firmwareService.GetLatestFirmwareVersionForFamily(firmwareFamily)
+		channel := device.FirmwareChannel
+		if channel == "" {
+			channel = database.FirmwareChannelStable
+		}
+		targetFirmware, err = firmwareService.GetLatestFirmwareVersionForChannel(firmwareFamily, channel)
 		if err != nil {
-			return defaultResponse
+			result.Reason = fmt.Sprintf("no %s firmware known for family %s", channel, firmwareFamily)
+			return result
 		}
+		result.TargetVersion = targetFirmware.Version
+	}
+	result.TargetFirmware = targetFirmware
+
+	// 4. Compare with device's current version
+	if device.FirmwareVersion == result.TargetVersion {
+		result.Reason = "device already runs the target firmware"
+		return result
 	}
 
-	// 7. Check if firmware is available based on current mode
+	// 5. Check if firmware is available based on current mode
 	firmwareMode := os.Getenv("FIRMWARE_MODE")
 	if firmwareMode == "" {
 		firmwareMode = "proxy"
@@ -900,28 +1053,60 @@ func checkFirmwareUpdate(c *gin.Context, device *database.Device, userTimezone s
 
 	if firmwareMode == "proxy" {
 		if targetFirmware.DownloadURL == "" {
-			return defaultResponse
+			result.Reason = "target firmware has no download URL"
+			return result
 		}
 	} else {
 		if !targetFirmware.IsDownloaded || targetFirmware.FilePath == "" {
-			return defaultResponse
+			result.Reason = "target firmware has not been downloaded"
+			return result
 		}
 	}
 
-	// 8. Generate firmware URL
+	result.Eligible = true
+	return result
+}
+
+// checkFirmwareUpdate checks if device needs a firmware update and can receive one
+func checkFirmwareUpdate(c *gin.Context, device *database.Device, userTimezone string) FirmwareUpdateResponse {
+	// Default response - no firmware update
+	defaultResponse := FirmwareUpdateResponse{
+		UpdateFirmware: false,
+		FirmwareURL:    "",
+		ResetFirmware:  false,
+	}
+
+	// Unclaimed devices and devices with updates disabled are never updated, check them before the schedule
+	if !device.IsClaimed || !device.AllowFirmwareUpdates {
+		return defaultResponse
+	}
+
+	// Check if we're in the firmware update schedule window
+	if !isInFirmwareUpdatePeriod(device, userTimezone) {
+		return defaultResponse
+	}
+
+	eligibility := EvaluateFirmwareUpdate(device, "")
+	if !eligibility.Eligible {
+		logging.Debug("[FIRMWARE UPDATE] Device not eligible for update", "mac_address", device.MacAddress, "reason", eligibility.Reason)
+		return defaultResponse
+	}
+	targetFirmware := eligibility.TargetFirmware
+
+	// Generate firmware URL
 	baseURL := utils.BaseURLFromRequest(c.Request)
-	firmwareURL := fmt.Sprintf("%s/files/firmware/%s/firmware_%s.bin", baseURL, firmwareFamily, targetFirmware.Version)
+	firmwareURL := fmt.Sprintf("%s/files/firmware/%s/firmware_%s.bin", baseURL, eligibility.Family, targetFirmware.Version)
 
 	updateType := "upgrade"
-	if device.FirmwareVersion > targetVersion {
+	if device.FirmwareVersion > eligibility.TargetVersion {
 		updateType = "downgrade"
 	}
 
 	logging.Info("[FIRMWARE UPDATE] Device will be updated",
 		"mac_address", device.MacAddress,
-		"family", firmwareFamily,
+		"family", eligibility.Family,
 		"current_version", device.FirmwareVersion,
-		"target_version", targetVersion,
+		"target_version", eligibility.TargetVersion,
 		"update_type", updateType)
 
 	return FirmwareUpdateResponse{
@@ -984,9 +1169,7 @@ func FirmwareDownloadHandler(c *gin.Context) {
 		logging.Info("[FIRMWARE PROXY] Device requesting firmware, proxying", "mac_address", device.MacAddress, "version", firmwareVersion, "url", fwVersion.DownloadURL)
 
 		// Create HTTP client for proxying
-		client := &http.Client{
-			Timeout: 5 * time.Minute, // Allow time for large firmware downloads
-		}
+		client := utils.NewHTTPClient(5 * time.Minute) // Allow time for large firmware downloads
 
 		// Create request to TRMNL API
 		req, err := http.NewRequest("GET", fwVersion.DownloadURL, nil)
@@ -1167,7 +1350,7 @@ func CurrentScreenHandler(c *gin.Context) {
 	var pluginErr error
 	
 	if processor != nil {
-		response, pluginErr = processor.processCurrentPlugin(device, activeItems)
+		response, pluginErr = processor.processCurrentPlugin(c.Request.Context(), device, activeItems)
 	} else {
 		// No processor available - return error
 		pluginErr = fmt.Errorf("unified plugin processor not available")
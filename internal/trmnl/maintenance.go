@@ -0,0 +1,69 @@
+package trmnl
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+)
+
+// defaultMaintenanceRefreshRate is used when the maintenance_refresh_rate setting is missing or invalid
+const defaultMaintenanceRefreshRate = 3600
+
+// IsMaintenanceModeEnabled reports whether the admin has put all devices into maintenance mode
+func IsMaintenanceModeEnabled() bool {
+	enabled, err := database.GetSystemSetting("maintenance_mode_enabled")
+	return err == nil && enabled == "true"
+}
+
+// maintenanceRefreshRate returns how often devices poll while maintenance mode is on
+func maintenanceRefreshRate() int {
+	if rateStr, err := database.GetSystemSetting("maintenance_refresh_rate"); err == nil {
+		if rate, err := strconv.Atoi(rateStr); err == nil && rate > 0 {
+			return rate
+		}
+	}
+	return defaultMaintenanceRefreshRate
+}
+
+// maintenanceResponse builds the display response served to every device while maintenance mode is on.
+// It returns false when maintenance mode is off.
+func maintenanceResponse(device *database.Device, baseURL string) (gin.H, bool) {
+	if !IsMaintenanceModeEnabled() {
+		return nil, false
+	}
+
+	refreshRate := maintenanceRefreshRate()
+
+	// Fall back to the built-in sleep screen when no maintenance image is configured
+	imageURL := baseURL + statusImageURL("sleep.png", device)
+	filename := statusFilename("maintenance", device)
+	if customURL, err := database.GetSystemSetting("maintenance_image_url"); err == nil && strings.TrimSpace(customURL) != "" {
+		customURL = strings.TrimSpace(customURL)
+		if strings.HasPrefix(customURL, "/") {
+			customURL = baseURL + customURL
+		}
+		imageURL = customURL
+
+		// Include a hash of the URL so devices fetch the new image when it changes
+		hash := fnv.New32a()
+		hash.Write([]byte(customURL))
+		filename = fmt.Sprintf("maintenance_%08x", hash.Sum32())
+	}
+
+	return gin.H{
+		"status":                0,
+		"image_url":             imageURL,
+		"filename":              filename,
+		"refresh_rate":          fmt.Sprintf("%d", refreshRate),
+		"update_firmware":       false,
+		"firmware_url":          "",
+		"reset_firmware":        false,
+		"maximum_compatibility": device.MaximumCompatibility,
+		"touchbar_mode":         device.TouchbarMode,
+		"temperature_profile":   device.TemperatureProfile,
+	}, true
+}
@@ -54,6 +54,12 @@ func findItemByID(activeItems []database.PlaylistItem, itemID *uuid.UUID) *datab
 	return nil
 }
 
+// needsMoreDisplays reports whether the last shown item has been served fewer times than its minimum display count
+func needsMoreDisplays(device *database.Device, activeItems []database.PlaylistItem) bool {
+	currentItem := findItemByID(activeItems, device.LastPlaylistItemID)
+	return currentItem != nil && device.LastPlaylistItemDisplayCount < currentItem.MinDisplayCount
+}
+
 // findStartingIndex finds the starting index for playlist processing based on last shown item
 func findStartingIndex(lastItemID *uuid.UUID, activeItems []database.PlaylistItem) int {
 	if lastItemID == nil {
@@ -129,7 +135,7 @@ func (pp *PluginProcessor) Close() error {
 }
 
 // processUnifiedPluginInstance processes a unified plugin instance
-func (pp *PluginProcessor) processUnifiedPluginInstance(device *database.Device, pluginInstance *database.PluginInstance) (gin.H, error) {
+func (pp *PluginProcessor) processUnifiedPluginInstance(ctx context.Context, device *database.Device, pluginInstance *database.PluginInstance) (gin.H, error) {
 	// Get the plugin definition
 	definition, err := pp.pluginService.GetPluginDefinitionByID(pluginInstance.PluginDefinitionID)
 	if err != nil {
@@ -146,9 +152,9 @@ func (pp *PluginProcessor) processUnifiedPluginInstance(device *database.Device,
 	var renderedContent *database.RenderedContent
 	if plugin.RequiresProcessing() {
 		// First, try to get pre-rendered content
-		renderedContent, err = pp.getPreRenderedContentForInstance(pluginInstance.ID, device)
+		renderedContent, err = pp.getPreRenderedContentForInstance(ctx, pluginInstance.ID, device)
 		if err != nil {
-			logging.Error("[PLUGIN] Failed to check for pre-rendered content", "error", err)
+			logging.FromContext(ctx).Error("[PLUGIN] Failed to check for pre-rendered content", "error", err)
 		}
 	}
 	
@@ -157,38 +163,23 @@ func (pp *PluginProcessor) processUnifiedPluginInstance(device *database.Device,
 	
 	if renderedContent != nil {
 		// Use pre-rendered content
-		var imageURL string
-		if strings.HasPrefix(renderedContent.ImagePath, "/static/rendered/") {
-			// Already a properly formatted URL
-			imageURL = renderedContent.ImagePath
-		} else if filepath.IsAbs(renderedContent.ImagePath) {
-			// Local file path - convert to URL
-			relPath, err := filepath.Rel(pp.imageStorage.GetBasePath(), renderedContent.ImagePath)
-			if err != nil {
-				logging.Error("[PLUGIN] Failed to compute relative path", "path", renderedContent.ImagePath, "error", err)
-				imageURL = renderedContent.ImagePath // Fallback to original path
-			} else {
-				imageURL = "/static/rendered/" + relPath
-			}
-		} else {
-			// URL reference
-			imageURL = renderedContent.ImagePath
-		}
-		
 		response = gin.H{
-			"image_url": imageURL,
+			"image_url": pp.renderedContentURL(ctx, renderedContent),
 			"filename":  filepath.Base(renderedContent.ImagePath),
 		}
+		if region := dirtyRegionForDevice(database.GetDB(), device, pluginInstance.ID, renderedContent); region != nil {
+			response["dirty_region"] = region
+		}
 		
-		logging.Info("[PLUGIN] Using pre-rendered content", 
+		logging.FromContext(ctx).Info("[PLUGIN] Using pre-rendered content", 
 			"plugin_type", plugin.Type(), 
 			"plugin_name", pluginInstance.Name)
 	} else {
 		// No pre-rendered content available - skip this playlist item instead of blocking
 		if plugin.RequiresProcessing() {
-			logging.Info("[PLUGIN] No pre-rendered content available, skipping playlist item", "plugin_type", plugin.Type(), "plugin_name", pluginInstance.Name)
+			logging.FromContext(ctx).Info("[PLUGIN] No pre-rendered content available, skipping playlist item", "plugin_type", plugin.Type(), "plugin_name", pluginInstance.Name)
 			// Schedule an immediate render job so it's ready next time
-			pp.scheduleImmediateRenderForInstance(pluginInstance.ID)
+			pp.scheduleImmediateRenderForInstance(ctx, pluginInstance.ID)
 			
 			// Return a special response indicating this item should be skipped
 			return gin.H{
@@ -201,15 +192,16 @@ func (pp *PluginProcessor) processUnifiedPluginInstance(device *database.Device,
 		
 		// For plugins that don't require processing, we can still process them on-demand
 		// Create unified plugin context
-		ctx, err := pp.createUnifiedPluginContext(device, pluginInstance)
+		pluginCtx, err := pp.createUnifiedPluginContext(device, pluginInstance)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create plugin context: %w", err)
 		}
+		pluginCtx.Ctx = ctx
 		
 		// Process the plugin (only for non-processing plugins)
-		response, pluginErr = plugin.Process(ctx)
+		response, pluginErr = plugin.Process(pluginCtx)
 		if pluginErr != nil {
-			logging.Error("[PLUGIN] Plugin processing failed", "plugin_type", plugin.Type(), "error", pluginErr)
+			logging.FromContext(ctx).Error("[PLUGIN] Plugin processing failed", "plugin_type", plugin.Type(), "error", pluginErr)
 			// Return error response but don't fail the whole request
 			response = gin.H{
 				"image_url": getImageURLForDevice(device),
@@ -217,13 +209,36 @@ func (pp *PluginProcessor) processUnifiedPluginInstance(device *database.Device,
 			}
 		} else {
 			// Since plugin doesn't require processing, we can use the response directly
-			logging.Debug("[PLUGIN] Plugin processed successfully", "plugin_type", plugin.Type())
+			logging.FromContext(ctx).Debug("[PLUGIN] Plugin processed successfully", "plugin_type", plugin.Type())
 		}
 	}
 	
 	return response, pluginErr
 }
 
+// renderedContentURL returns the URL a device fetches rendered content from
+func (pp *PluginProcessor) renderedContentURL(ctx context.Context, renderedContent *database.RenderedContent) string {
+	if strings.HasPrefix(renderedContent.ImagePath, "/static/rendered/") {
+		// Already a properly formatted URL
+		return renderedContent.ImagePath
+	}
+	if rendering.InModelRenderedDir(renderedContent.ImagePath) {
+		// Per-model output directories are served by file name from the rendered route
+		return "/static/rendered/" + filepath.Base(renderedContent.ImagePath)
+	}
+	if filepath.IsAbs(renderedContent.ImagePath) {
+		// Local file path - convert to URL
+		relPath, err := filepath.Rel(pp.imageStorage.GetBasePath(), renderedContent.ImagePath)
+		if err != nil {
+			logging.FromContext(ctx).Error("[PLUGIN] Failed to compute relative path", "path", renderedContent.ImagePath, "error", err)
+			return renderedContent.ImagePath // Fallback to original path
+		}
+		return "/static/rendered/" + relPath
+	}
+	// URL reference
+	return renderedContent.ImagePath
+}
+
 // renderHTMLToImage converts HTML content to an image using browserless
 func (pp *PluginProcessor) renderHTMLToImage(htmlContent string, device *database.Device) ([]byte, error) {
 	if device.DeviceModel == nil {
@@ -278,7 +293,7 @@ func (pp *PluginProcessor) renderHTMLToImage(htmlContent string, device *databas
 }
 
 // getPreRenderedContentForInstance attempts to get pre-rendered content for a plugin instance
-func (pp *PluginProcessor) getPreRenderedContentForInstance(pluginInstanceID uuid.UUID, device *database.Device) (*database.RenderedContent, error) {
+func (pp *PluginProcessor) getPreRenderedContentForInstance(ctx context.Context, pluginInstanceID uuid.UUID, device *database.Device) (*database.RenderedContent, error) {
 	var renderedContent database.RenderedContent
 	
 	// Get device specifications from device model
@@ -292,6 +307,16 @@ func (pp *PluginProcessor) getPreRenderedContentForInstance(pluginInstanceID uui
 		Order("rendered_at DESC").
 		First(&renderedContent).Error
 	
+	if err == gorm.ErrRecordNotFound {
+		// A newly reported resolution may not be rendered yet - serve this device's latest content meanwhile
+		err = pp.db.Where("plugin_instance_id = ? AND device_id = ?", pluginInstanceID, device.ID).
+			Order("rendered_at DESC").
+			First(&renderedContent).Error
+		if err == nil && !isSimulatedRequest(ctx) && pp.needsFallbackRender(ctx, pluginInstanceID) {
+			pp.scheduleImmediateRenderForInstance(ctx, pluginInstanceID)
+		}
+	}
+	
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil // No pre-rendered content available
@@ -302,6 +327,25 @@ func (pp *PluginProcessor) getPreRenderedContentForInstance(pluginInstanceID uui
 	return &renderedContent, nil
 }
 
+// fallbackRenderCooldown is how long after an immediate render was queued for an instance before a device
+// served fallback content queues another, so renders that fail or never produce the variant don't loop
+const fallbackRenderCooldown = 5 * time.Minute
+
+// needsFallbackRender reports whether a device served fallback content should queue a render for the
+// instance: not while one is pending or processing, or if an immediate render was queued recently
+func (pp *PluginProcessor) needsFallbackRender(ctx context.Context, pluginInstanceID uuid.UUID) bool {
+	var count int64
+	err := pp.db.WithContext(ctx).Model(&database.RenderQueue{}).
+		Where("plugin_instance_id = ? AND (status IN ? OR (priority >= ? AND created_at > ?))",
+			pluginInstanceID, []string{"pending", "processing"}, 100, time.Now().UTC().Add(-fallbackRenderCooldown)).
+		Count(&count).Error
+	if err != nil {
+		logging.FromContext(ctx).Warn("[PLUGIN_PROCESSOR] Failed to check for queued renders", "plugin_id", pluginInstanceID, "error", err)
+		return false
+	}
+	return count == 0
+}
+
 // scheduleRenderIfNeededForInstance schedules a render job for a plugin instance if no recent content exists
 func (pp *PluginProcessor) scheduleRenderIfNeededForInstance(pluginInstanceID uuid.UUID) {
 	// TODO: Update QueueManager to work with plugin instances
@@ -310,19 +354,19 @@ func (pp *PluginProcessor) scheduleRenderIfNeededForInstance(pluginInstanceID uu
 }
 
 // scheduleImmediateRenderForInstance schedules an immediate high-priority render job for a plugin instance
-func (pp *PluginProcessor) scheduleImmediateRenderForInstance(pluginInstanceID uuid.UUID) {
+func (pp *PluginProcessor) scheduleImmediateRenderForInstance(ctx context.Context, pluginInstanceID uuid.UUID) {
 	if pp.queueManager != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		scheduleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
 		defer cancel()
 		
-		err := pp.queueManager.ScheduleImmediateRender(ctx, pluginInstanceID)
+		err := pp.queueManager.ScheduleImmediateRender(scheduleCtx, pluginInstanceID)
 		if err != nil {
-			logging.Error("[PLUGIN_PROCESSOR] Failed to schedule immediate render", "plugin_id", pluginInstanceID, "error", err)
+			logging.FromContext(ctx).Error("[PLUGIN_PROCESSOR] Failed to schedule immediate render", "plugin_id", pluginInstanceID, "error", err)
 		} else {
-			logging.Info("[PLUGIN_PROCESSOR] Scheduled immediate render", "plugin_id", pluginInstanceID)
+			logging.FromContext(ctx).Info("[PLUGIN_PROCESSOR] Scheduled immediate render", "plugin_id", pluginInstanceID)
 		}
 	} else {
-		logging.Warn("[PLUGIN_PROCESSOR] Queue manager not available for immediate render", "plugin_id", pluginInstanceID)
+		logging.FromContext(ctx).Warn("[PLUGIN_PROCESSOR] Queue manager not available for immediate render", "plugin_id", pluginInstanceID)
 	}
 }
 
@@ -390,7 +434,7 @@ func (pp *PluginProcessor) scheduleRenderIfNeeded(userPluginID uuid.UUID) {
 }
 
 // tryProcessPlaylistItem attempts to process a single playlist item
-func (pp *PluginProcessor) tryProcessPlaylistItem(device *database.Device, item *database.PlaylistItem, attempt int) (gin.H, error) {
+func (pp *PluginProcessor) tryProcessPlaylistItem(ctx context.Context, device *database.Device, item *database.PlaylistItem, attempt int) (gin.H, error) {
 	// Check if plugin instance ID is valid
 	if item.PluginInstanceID == uuid.Nil {
 		return nil, fmt.Errorf("invalid_item: playlist item has no plugin instance configured")
@@ -403,7 +447,7 @@ func (pp *PluginProcessor) tryProcessPlaylistItem(device *database.Device, item
 	}
 
 	// Process using unified system
-	response, err := pp.processUnifiedPluginInstance(device, pluginInstance)
+	response, err := pp.processUnifiedPluginInstance(ctx, device, pluginInstance)
 	if err != nil {
 		return nil, fmt.Errorf("processing_error: plugin processing failed: %w", err)
 	}
@@ -411,7 +455,7 @@ func (pp *PluginProcessor) tryProcessPlaylistItem(device *database.Device, item
 	// Check if the plugin requested to skip this item
 	if skipItem, ok := response["skip_item"].(bool); ok && skipItem {
 		// Schedule an immediate render job so it's ready next time
-		pp.scheduleImmediateRenderForInstance(pluginInstance.ID)
+		pp.scheduleImmediateRenderForInstance(ctx, pluginInstance.ID)
 		return nil, fmt.Errorf("no_prerender_content: plugin type %v name %v", response["plugin_type"], response["plugin_name"])
 	}
 
@@ -421,22 +465,35 @@ func (pp *PluginProcessor) tryProcessPlaylistItem(device *database.Device, item
 	}
 	
 	// Success!
-	logging.Info("[PLUGIN] Successfully processed playlist item", 
+	logging.FromContext(ctx).Info("[PLUGIN] Successfully processed playlist item", 
 		"plugin_type", response["plugin_type"], "plugin_name", pluginInstance.Name, 
 		"item_id", item.ID, "attempt", attempt)
 	return response, nil
 }
 
 // processActivePlugins processes plugins using iterative approach to avoid recursion complexity
-func (pp *PluginProcessor) processActivePlugins(device *database.Device, activeItems []database.PlaylistItem) (gin.H, *database.PlaylistItem, error) {
+func (pp *PluginProcessor) processActivePlugins(ctx context.Context, device *database.Device, activeItems []database.PlaylistItem, userTimezone string) (gin.H, *database.PlaylistItem, error) {
 	if len(activeItems) == 0 {
-		return nil, nil, fmt.Errorf("no active playlist items")
+		response, err := pp.processEmptyPlaylist(ctx, device)
+		return response, nil, err
 	}
 
 	// Find starting position (where we left off)
 	startIndex := findStartingIndex(device.LastPlaylistItemID, activeItems)
+	if holdIndex, hold := activeHoursHoldIndex(device, activeItems, userTimezone); hold {
+		// Outside active hours rotation is frozen on the hold item
+		startIndex = holdIndex
+	} else if device.HoldCurrentItem || needsMoreDisplays(device, activeItems) {
+		// Current item was pinned via the API or hasn't reached its minimum display count - serve it again rather than advancing
+		for i, item := range activeItems {
+			if device.LastPlaylistItemID != nil && item.ID == *device.LastPlaylistItemID {
+				startIndex = i
+				break
+			}
+		}
+	}
 	
-	logging.Info("[PLUGIN] Starting playlist processing", "device", device.FriendlyID, 
+	logging.FromContext(ctx).Info("[PLUGIN] Starting playlist processing", "device", device.FriendlyID, 
 		"active_items_count", len(activeItems), "start_index", startIndex)
 	
 	// Try each item in sequence, starting from the next position
@@ -444,24 +501,24 @@ func (pp *PluginProcessor) processActivePlugins(device *database.Device, activeI
 		currentIndex := (startIndex + attempt) % len(activeItems)
 		item := &activeItems[currentIndex]
 		
-		logging.Info("[PLUGIN] Trying playlist item", "attempt", attempt, "index", currentIndex, 
+		logging.FromContext(ctx).Info("[PLUGIN] Trying playlist item", "attempt", attempt, "index", currentIndex, 
 			"item_id", item.ID, "plugin_instance_id", item.PluginInstanceID)
 		
-		result, err := pp.tryProcessPlaylistItem(device, item, attempt)
+		result, err := pp.tryProcessPlaylistItem(ctx, device, item, attempt)
 		if err == nil {
 			// Success! Return this item
-			logging.Info("[PLUGIN] Playlist processing successful", "selected_item", item.ID, 
+			logging.FromContext(ctx).Info("[PLUGIN] Playlist processing successful", "selected_item", item.ID, 
 				"total_attempts", attempt+1)
 			return result, item, nil
 		}
 		
 		// Log the skip/failure and continue to next item
-		logging.Info("[PLUGIN] Skipping playlist item", "reason", err.Error(), 
+		logging.FromContext(ctx).Info("[PLUGIN] Skipping playlist item", "reason", err.Error(), 
 			"item_id", item.ID, "attempt", attempt)
 	}
 	
 	// All items failed
-	logging.Warn("[PLUGIN] All playlist items unavailable", "items_tried", len(activeItems))
+	logging.FromContext(ctx).Warn("[PLUGIN] All playlist items unavailable", "items_tried", len(activeItems))
 	return gin.H{
 		"image_url": getImageURLForDevice(device),
 		"filename":  fmt.Sprintf("all_failed_%s", time.Now().UTC().Format("20060102150405")),
@@ -474,9 +531,9 @@ func (pp *PluginProcessor) renderDataPlugin(response plugins.PluginResponse, dev
 }
 
 // processCurrentPlugin processes the current plugin without advancing the index (unified system only)
-func (pp *PluginProcessor) processCurrentPlugin(device *database.Device, activeItems []database.PlaylistItem) (gin.H, error) {
+func (pp *PluginProcessor) processCurrentPlugin(ctx context.Context, device *database.Device, activeItems []database.PlaylistItem) (gin.H, error) {
 	if len(activeItems) == 0 {
-		return nil, fmt.Errorf("no active playlist items")
+		return pp.processEmptyPlaylist(ctx, device)
 	}
 
 	// Get the current item based on UUID
@@ -495,7 +552,7 @@ func (pp *PluginProcessor) processCurrentPlugin(device *database.Device, activeI
 	// Check if plugin instance ID is valid
 	if item.PluginInstanceID == uuid.Nil {
 		errorMsg := "Current playlist item has no plugin instance configured"
-		logging.Warn("[PLUGIN] Skipping current playlist item", "error", errorMsg, "item_id", item.ID)
+		logging.FromContext(ctx).Warn("[PLUGIN] Skipping current playlist item", "error", errorMsg, "item_id", item.ID)
 		
 		return gin.H{
 			"image_url": getImageURLForDevice(device),
@@ -510,9 +567,9 @@ func (pp *PluginProcessor) processCurrentPlugin(device *database.Device, activeI
 	}
 
 	// Process using unified system
-	response, err := pp.processUnifiedPluginInstance(device, pluginInstance)
+	response, err := pp.processUnifiedPluginInstance(ctx, device, pluginInstance)
 	if err != nil {
-		logging.Error("[PLUGIN] Unified plugin processing failed (current)", "plugin_instance_id", pluginInstance.ID, "error", err)
+		logging.FromContext(ctx).Error("[PLUGIN] Unified plugin processing failed (current)", "plugin_instance_id", pluginInstance.ID, "error", err)
 		// Return error response
 		response = gin.H{
 			"image_url": getImageURLForDevice(device),
@@ -523,7 +580,7 @@ func (pp *PluginProcessor) processCurrentPlugin(device *database.Device, activeI
 
 	// Check if the plugin requested to skip this item
 	if skipItem, ok := response["skip_item"].(bool); ok && skipItem {
-		logging.Info("[PLUGIN] Current playlist item needs to be skipped, returning error image", "plugin_type", response["plugin_type"], "plugin_name", response["plugin_name"])
+		logging.FromContext(ctx).Info("[PLUGIN] Current playlist item needs to be skipped, returning error image", "plugin_type", response["plugin_type"], "plugin_name", response["plugin_name"])
 		
 		// For the current item request, we can't easily skip to the next item since this function
 		// doesn't manage playlist state. Return an error image instead.
@@ -542,7 +599,7 @@ func (pp *PluginProcessor) processCurrentPlugin(device *database.Device, activeI
 }
 
 // broadcastPlaylistChange broadcasts playlist changes via SSE
-func (pp *PluginProcessor) broadcastPlaylistChange(device *database.Device, currentItem database.PlaylistItem, activeItems []database.PlaylistItem, sleepScreenServed bool) {
+func (pp *PluginProcessor) broadcastPlaylistChange(ctx context.Context, device *database.Device, currentItem database.PlaylistItem, activeItems []database.PlaylistItem, sleepScreenServed bool) {
 	// Get user timezone for sleep calculations
 	userTimezone := "UTC" // Default fallback
 	if device.UserID != nil {
@@ -567,6 +624,7 @@ func (pp *PluginProcessor) broadcastPlaylistChange(device *database.Device, curr
 	}
 
 	// Broadcast playlist change to connected SSE clients
+	logging.FromContext(ctx).Debug("[SSE] Broadcasting playlist change", "device_id", device.ID, "item_id", currentItem.ID)
 	sseService := sse.GetSSEService()
 	sseService.BroadcastToDevice(device.ID, sse.Event{
 		Type: "playlist_index_changed",
@@ -0,0 +1,40 @@
+package trmnl
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// applyMinRefreshRate raises the response refresh rate to the device's minimum, so fast-refreshing
+// plugins can't drain its battery. Sleep mode overrides are applied afterwards and aren't clamped.
+func applyMinRefreshRate(response gin.H, device *database.Device) {
+	if device.MinRefreshRate <= 0 {
+		return
+	}
+
+	var rate int
+	switch v := response["refresh_rate"].(type) {
+	case string:
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return
+		}
+		rate = parsed
+	case int:
+		rate = v
+	case float64:
+		rate = int(v)
+	default:
+		return
+	}
+
+	if rate >= device.MinRefreshRate {
+		return
+	}
+	logging.Debug("[/api/display] Raising refresh rate to device minimum", "mac_address", device.MacAddress, "refresh_rate", rate, "min_refresh_rate", device.MinRefreshRate)
+	response["refresh_rate"] = fmt.Sprintf("%d", device.MinRefreshRate)
+}
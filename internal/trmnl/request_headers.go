@@ -0,0 +1,61 @@
+package trmnl
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// redactedHeaders are replaced before captured headers are stored, since they grant device access
+var redactedHeaders = []string{"Access-Token"}
+
+// CapturedRequest holds the headers of a device's most recent /api/display request
+type CapturedRequest struct {
+	ReceivedAt time.Time           `json:"received_at"`
+	ClientIP   string              `json:"client_ip"`
+	Headers    map[string][]string `json:"headers"`
+}
+
+// lastRequestHeaders is kept in memory only and is lost on restart
+var (
+	lastRequestHeaders   = make(map[uuid.UUID]CapturedRequest)
+	lastRequestHeadersMu sync.RWMutex
+)
+
+// recordRequestHeaders stores the headers of the latest display request for a device
+func recordRequestHeaders(deviceID uuid.UUID, clientIP string, header http.Header) {
+	headers := make(map[string][]string, len(header))
+	for name, values := range header {
+		headers[name] = append([]string(nil), values...)
+	}
+	for _, name := range redactedHeaders {
+		if _, ok := headers[name]; ok {
+			headers[name] = []string{"[REDACTED]"}
+		}
+	}
+
+	lastRequestHeadersMu.Lock()
+	defer lastRequestHeadersMu.Unlock()
+	lastRequestHeaders[deviceID] = CapturedRequest{
+		ReceivedAt: time.Now().UTC(),
+		ClientIP:   clientIP,
+		Headers:    headers,
+	}
+}
+
+// GetLastRequestHeaders returns the headers captured on the device's most recent display request
+func GetLastRequestHeaders(deviceID uuid.UUID) (CapturedRequest, bool) {
+	lastRequestHeadersMu.RLock()
+	defer lastRequestHeadersMu.RUnlock()
+	captured, ok := lastRequestHeaders[deviceID]
+	return captured, ok
+}
+
+// ClearLastRequestHeaders forgets the captured headers for a device
+func ClearLastRequestHeaders(deviceID uuid.UUID) {
+	lastRequestHeadersMu.Lock()
+	defer lastRequestHeadersMu.Unlock()
+	delete(lastRequestHeaders, deviceID)
+}
@@ -0,0 +1,77 @@
+package trmnl
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+	"gorm.io/gorm"
+)
+
+// ErrNoCurrentScreen is returned when a device has no rendered content for the item it is showing
+var ErrNoCurrentScreen = errors.New("no screen is currently available")
+
+// CurrentRenderedContent returns the rendered content of the item a device last displayed, falling back to
+// its first active item. It never advances the playlist.
+func CurrentRenderedContent(db *gorm.DB, device *database.Device) (*database.RenderedContent, *database.PlaylistItem, error) {
+	activeItems, err := database.NewPlaylistService(db).GetActivePlaylistItemsForTime(device.ID, time.Now().UTC())
+	if err != nil || len(activeItems) == 0 {
+		return nil, nil, ErrNoCurrentScreen
+	}
+
+	currentItem := findItemByID(activeItems, device.LastPlaylistItemID)
+	if currentItem == nil {
+		currentItem = &activeItems[0]
+	}
+
+	renderedContent, err := latestRenderedContentForDevice(db, device, currentItem.PluginInstanceID)
+	if err != nil {
+		return nil, nil, ErrNoCurrentScreen
+	}
+	return renderedContent, currentItem, nil
+}
+
+// SharedScreenHandler serves the screen a device is currently showing to holders of a share link.
+// It reads the item the device last displayed and never advances the playlist.
+// GET /api/public/devices/:token/current.png
+func SharedScreenHandler(c *gin.Context) {
+	db := database.GetDB()
+	shareService := database.NewDeviceShareService(db)
+
+	link, err := shareService.GetShareLinkByToken(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found"})
+		return
+	}
+
+	device, err := database.NewDeviceService(db).GetDeviceByID(link.DeviceID)
+	if err != nil || !device.IsClaimed || device.UserID == nil || *device.UserID != link.UserID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found"})
+		return
+	}
+
+	renderedContent, _, err := CurrentRenderedContent(db, device)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No screen is currently available"})
+		return
+	}
+
+	if err := shareService.TouchShareLink(link.ID); err != nil {
+		logging.Warn("[SHARE] Failed to record share link access", "share_link_id", link.ID, "error", err)
+	}
+
+	// The current screen changes as the playlist advances, so embeds must revalidate
+	c.Header("Cache-Control", "no-cache")
+
+	if strings.HasPrefix(renderedContent.ImagePath, "http://") || strings.HasPrefix(renderedContent.ImagePath, "https://") {
+		c.Redirect(http.StatusFound, renderedContent.ImagePath)
+		return
+	}
+
+	c.Header("Content-Type", "image/png")
+	c.File(renderedContent.ImagePath)
+}
@@ -0,0 +1,147 @@
+package trmnl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// displaySimulationKey marks a display request as simulated in the gin context
+const displaySimulationKey = "display_simulation"
+
+// DisplaySimulationOptions overrides what a simulated device reports. Unset fields use the values the
+// device last reported.
+type DisplaySimulationOptions struct {
+	Advance         bool     `json:"advance"` // Record the served item as the device's current item
+	FirmwareVersion string   `json:"firmware_version"`
+	BatteryVoltage  *float64 `json:"battery_voltage"`
+	BatteryPercent  *int     `json:"battery_percent"`
+	RSSI            *int     `json:"rssi"`
+	Width           int      `json:"width"`
+	Height          int      `json:"height"`
+}
+
+// DisplaySimulationResult is the response DisplayHandler produced for a simulated request
+type DisplaySimulationResult struct {
+	StatusCode int                    `json:"status_code"`
+	Response   map[string]interface{} `json:"response"`
+	DurationMS int64                  `json:"duration_ms"`
+	Advanced   bool                   `json:"advanced"`
+}
+
+// simulatedRequestKey marks the request context of a simulated display request, for code that only
+// has the request context rather than the gin context
+type simulatedRequestKey struct{}
+
+// isSimulatedRequest reports whether ctx belongs to a simulated display request
+func isSimulatedRequest(ctx context.Context) bool {
+	simulated, _ := ctx.Value(simulatedRequestKey{}).(bool)
+	return simulated
+}
+
+// displaySimulationFromContext returns the simulation options when the request is simulated
+func displaySimulationFromContext(c *gin.Context) (DisplaySimulationOptions, bool) {
+	value, ok := c.Get(displaySimulationKey)
+	if !ok {
+		return DisplaySimulationOptions{}, false
+	}
+	opts, ok := value.(DisplaySimulationOptions)
+	return opts, ok
+}
+
+// SimulateDisplay runs DisplayHandler for a device as if it had just checked in and returns the JSON
+// it would have sent. The device's status, request headers and resolution variants are left alone, and
+// the playlist only advances when opts.Advance is set. The original request supplies the host used
+// for absolute image URLs.
+func SimulateDisplay(original *http.Request, device *database.Device, opts DisplaySimulationOptions) (*DisplaySimulationResult, error) {
+	req := httptest.NewRequest(http.MethodGet, "/api/display", nil).
+		WithContext(context.WithValue(original.Context(), simulatedRequestKey{}, true))
+	req.Host = original.Host
+	req.TLS = original.TLS
+	for _, name := range []string{"X-Forwarded-Proto", "X-Forwarded-Host", "X-Forwarded-Port"} {
+		if value := original.Header.Get(name); value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+
+	req.Header.Set("ID", device.MacAddress)
+	req.Header.Set("Access-Token", device.APIKey)
+	req.Header.Set("Refresh-Rate", strconv.Itoa(device.RefreshRate))
+	req.Header.Set("User-Agent", "Stationmaster-Simulator")
+
+	firmwareVersion := device.FirmwareVersion
+	if opts.FirmwareVersion != "" {
+		firmwareVersion = opts.FirmwareVersion
+	}
+	if firmwareVersion != "" {
+		req.Header.Set("Fw-Version", firmwareVersion)
+	}
+
+	batteryVoltage := device.BatteryVoltage
+	if opts.BatteryVoltage != nil {
+		batteryVoltage = *opts.BatteryVoltage
+	}
+	if batteryVoltage > 0 {
+		req.Header.Set("Battery-Voltage", strconv.FormatFloat(batteryVoltage, 'f', 2, 64))
+	}
+	batteryPercent := device.BatteryPercent
+	if opts.BatteryPercent != nil {
+		batteryPercent = *opts.BatteryPercent
+	}
+	req.Header.Set("Percent-Charged", strconv.Itoa(batteryPercent))
+	rssi := device.RSSI
+	if opts.RSSI != nil {
+		rssi = *opts.RSSI
+	}
+	req.Header.Set("Rssi", strconv.Itoa(rssi))
+
+	if device.ReportedModelName != nil {
+		req.Header.Set("Model", *device.ReportedModelName)
+	}
+	if opts.Width > 0 && opts.Height > 0 {
+		req.Header.Set("Width", strconv.Itoa(opts.Width))
+		req.Header.Set("Height", strconv.Itoa(opts.Height))
+	}
+
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = req
+	c.Set(displaySimulationKey, opts)
+
+	start := time.Now()
+	DisplayHandler(c)
+	duration := time.Since(start)
+
+	result := &DisplaySimulationResult{
+		StatusCode: recorder.Code,
+		DurationMS: duration.Milliseconds(),
+		Advanced:   opts.Advance,
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result.Response); err != nil {
+		return nil, fmt.Errorf("failed to decode display response: %w", err)
+	}
+
+	logging.Info("[DISPLAY SIMULATION] Simulated display request", "device", device.FriendlyID, "status_code", result.StatusCode, "duration", duration, "advance", opts.Advance)
+	return result, nil
+}
+
+// advanceSimulatedPlaylist records the item served to a simulated request as the device's current item
+func advanceSimulatedPlaylist(ctx context.Context, device *database.Device, currentItem database.PlaylistItem, activeItems []database.PlaylistItem, sleepScreenServed bool) {
+	deviceService := database.NewDeviceService(database.GetDB())
+	if err := deviceService.UpdateLastPlaylistItemID(device.ID, currentItem.ID); err != nil {
+		logging.Error("[DISPLAY SIMULATION] Failed to advance playlist", "device_id", device.ID, "item_id", currentItem.ID, "error", err)
+		return
+	}
+	if processor := GetPluginProcessor(); processor != nil {
+		processor.broadcastPlaylistChange(ctx, device, currentItem, activeItems, sleepScreenServed)
+	}
+}
@@ -0,0 +1,62 @@
+package trmnl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/rmitchellscott/stationmaster/internal/database"
+	"github.com/rmitchellscott/stationmaster/internal/logging"
+)
+
+// What a device is shown when plugin processing times out, selected with the display_timeout_fallback setting
+const (
+	DisplayTimeoutFallbackErrorImage  = "error_image"  // Show the timeout error screen
+	DisplayTimeoutFallbackLastContent = "last_content" // Keep showing the last rendered content, retrying soon
+)
+
+// lastContentRetryRefreshRate is the refresh rate, in seconds, served with last content after a timeout
+// so the device retries soon
+const lastContentRetryRefreshRate = 60
+
+// displayTimeoutFallback returns the configured timeout fallback, defaulting to the error image
+func displayTimeoutFallback() string {
+	fallback, err := database.GetSystemSetting("display_timeout_fallback")
+	if err != nil || fallback != DisplayTimeoutFallbackLastContent {
+		return DisplayTimeoutFallbackErrorImage
+	}
+	return fallback
+}
+
+// lastContentTimeoutResponse builds a response serving the rendered content of the item the device last
+// showed, with a shortened refresh rate. It returns nil when there is no such content.
+func lastContentTimeoutResponse(ctx context.Context, processor *PluginProcessor, device *database.Device, refreshRate int) gin.H {
+	if processor == nil || device.LastPlaylistItemID == nil {
+		return nil
+	}
+
+	db := database.GetDB()
+	var lastItem database.PlaylistItem
+	if err := db.WithContext(ctx).Select("id", "plugin_instance_id").First(&lastItem, "id = ?", *device.LastPlaylistItemID).Error; err != nil {
+		return nil
+	}
+
+	renderedContent, err := latestRenderedContentForDevice(db.WithContext(ctx), device, lastItem.PluginInstanceID)
+	if err != nil {
+		return nil
+	}
+
+	if refreshRate <= 0 || refreshRate > lastContentRetryRefreshRate {
+		refreshRate = lastContentRetryRefreshRate
+	}
+
+	logging.Info("[/api/display] Serving last rendered content after plugin processing timeout",
+		"mac_address", device.MacAddress, "plugin_instance_id", lastItem.PluginInstanceID, "refresh_rate", refreshRate)
+	return gin.H{
+		"image_url":    processor.renderedContentURL(ctx, renderedContent),
+		"filename":     filepath.Base(renderedContent.ImagePath),
+		"refresh_rate": fmt.Sprintf("%d", refreshRate),
+	}
+}
@@ -0,0 +1,33 @@
+package trmnl
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rmitchellscott/stationmaster/internal/database"
+)
+
+// unclaimedRefreshRate returns the refresh rate configured for unclaimed devices, or false when
+// unclaimed devices should keep using their own refresh rate
+func unclaimedRefreshRate() (int, bool) {
+	rateStr, err := database.GetSystemSetting("unclaimed_refresh_rate")
+	if err != nil {
+		return 0, false
+	}
+	rate, err := strconv.Atoi(rateStr)
+	if err != nil || rate <= 0 {
+		return 0, false
+	}
+	return rate, true
+}
+
+// applyUnclaimedRefreshRate slows down polling for devices that have not been claimed yet
+func applyUnclaimedRefreshRate(response gin.H, device *database.Device) {
+	if device.IsClaimed {
+		return
+	}
+	if rate, ok := unclaimedRefreshRate(); ok {
+		response["refresh_rate"] = fmt.Sprintf("%d", rate)
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+)
+
+// NewHTTPClient creates an HTTP client for outbound requests to external services.
+// Requests are routed through OUTBOUND_PROXY when set, otherwise through the standard
+// HTTP_PROXY/HTTPS_PROXY variables. NO_PROXY is honoured in both cases.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = OutboundProxy()
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// OutboundProxy returns the proxy selection function used for outbound requests
+func OutboundProxy() func(*http.Request) (*url.URL, error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if proxy := config.Get("OUTBOUND_PROXY", ""); proxy != "" {
+		proxyConfig.HTTPProxy = proxy
+		proxyConfig.HTTPSProxy = proxy
+	}
+
+	proxyFunc := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
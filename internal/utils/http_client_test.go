@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestOutboundProxy(t *testing.T) {
+	tests := []struct {
+		name          string
+		outboundProxy string
+		httpsProxy    string
+		noProxy       string
+		url           string
+		expected      string
+	}{
+		{
+			name:     "no proxy configured",
+			url:      "https://example.com/data.json",
+			expected: "",
+		},
+		{
+			name:          "outbound proxy for https",
+			outboundProxy: "http://proxy.corp:3128",
+			url:           "https://example.com/data.json",
+			expected:      "http://proxy.corp:3128",
+		},
+		{
+			name:          "outbound proxy for http",
+			outboundProxy: "http://proxy.corp:3128",
+			url:           "http://example.com/data.json",
+			expected:      "http://proxy.corp:3128",
+		},
+		{
+			name:          "outbound proxy overrides standard variable",
+			outboundProxy: "http://proxy.corp:3128",
+			httpsProxy:    "http://other.corp:8080",
+			url:           "https://example.com/data.json",
+			expected:      "http://proxy.corp:3128",
+		},
+		{
+			name:       "standard variable used as fallback",
+			httpsProxy: "http://other.corp:8080",
+			url:        "https://example.com/data.json",
+			expected:   "http://other.corp:8080",
+		},
+		{
+			name:          "no proxy bypass",
+			outboundProxy: "http://proxy.corp:3128",
+			noProxy:       "internal.corp",
+			url:           "https://internal.corp/data.json",
+			expected:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OUTBOUND_PROXY", tt.outboundProxy)
+			t.Setenv("HTTP_PROXY", "")
+			t.Setenv("HTTPS_PROXY", tt.httpsProxy)
+			t.Setenv("NO_PROXY", tt.noProxy)
+
+			req, err := http.NewRequest("GET", tt.url, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			proxyURL, err := OutboundProxy()(req)
+			if err != nil {
+				t.Fatalf("OutboundProxy() error = %v", err)
+			}
+
+			got := ""
+			if proxyURL != nil {
+				got = proxyURL.String()
+			}
+			if got != tt.expected {
+				t.Errorf("OutboundProxy() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+)
+
+// DefaultAllowedImageMIMETypes is the raster-only set of image types accepted from remote hosts
+const DefaultAllowedImageMIMETypes = "image/png,image/jpeg,image/gif,image/webp"
+
+// imageFormatMIMETypes maps image.Decode format names to their MIME types
+var imageFormatMIMETypes = map[string]string{
+	"png":  "image/png",
+	"jpeg": "image/jpeg",
+	"gif":  "image/gif",
+	"webp": "image/webp",
+}
+
+// AllowedImageMIMETypes returns the image types accepted when fetching remote images, from
+// IMAGE_ALLOWED_MIME_TYPES
+func AllowedImageMIMETypes() []string {
+	var allowed []string
+	for _, mimeType := range strings.Split(config.Get("IMAGE_ALLOWED_MIME_TYPES", DefaultAllowedImageMIMETypes), ",") {
+		mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+		if mimeType != "" {
+			allowed = append(allowed, mimeType)
+		}
+	}
+	return allowed
+}
+
+// ValidateImageMIMEType checks a Content-Type header value against the allowed image types
+func ValidateImageMIMEType(contentType string) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type %q", contentType)
+	}
+	for _, allowed := range AllowedImageMIMETypes() {
+		if mediaType == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("image type %s is not allowed", mediaType)
+}
+
+// ValidateImageFormat checks a decoded image format, as returned by image.Decode, against the allowed image types
+func ValidateImageFormat(format string) error {
+	mimeType, ok := imageFormatMIMETypes[format]
+	if !ok {
+		return fmt.Errorf("image format %s is not allowed", format)
+	}
+	return ValidateImageMIMEType(mimeType)
+}
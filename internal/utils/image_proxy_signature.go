@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+)
+
+var imageProxyKey []byte
+
+func init() {
+	// Signed URLs only have to outlive a render, so a per-process key is fine unless several
+	// instances share renders
+	if secret := config.Get("IMAGE_PROXY_SECRET", ""); secret != "" {
+		imageProxyKey = []byte(secret)
+	} else {
+		imageProxyKey = make([]byte, 32)
+		rand.Read(imageProxyKey)
+	}
+}
+
+// SignImageProxyURL returns the signature that authorizes the image proxy to fetch a URL
+func SignImageProxyURL(imageURL string) string {
+	mac := hmac.New(sha256.New, imageProxyKey)
+	mac.Write([]byte(imageURL))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyImageProxySignature reports whether a signature was made by SignImageProxyURL for the URL
+func VerifyImageProxySignature(imageURL, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, imageProxyKey)
+	mac.Write([]byte(imageURL))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/rmitchellscott/stationmaster/internal/config"
+)
+
+var nonPublicIPRanges = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "This" network
+	mustParseCIDR("100.64.0.0/10"), // RFC 6598 - Carrier-grade NAT
+	mustParseCIDR("192.0.0.0/24"),  // RFC 6890 - IETF protocol assignments
+	mustParseCIDR("198.18.0.0/15"), // RFC 2544 - Benchmarking
+	mustParseCIDR("240.0.0.0/4"),   // Reserved, including broadcast
+}
+
+// IsPublicIP reports whether an IP address is routable on the public internet, rejecting loopback,
+// private, link-local and other special-purpose ranges
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || isPrivateIP(ip) {
+		return false
+	}
+	for _, r := range nonPublicIPRanges {
+		if r.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolvePublicIPs resolves a host and returns its addresses, failing if any of them isn't public
+func ResolvePublicIPs(ctx context.Context, host string) ([]net.IP, error) {
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+	for _, ip := range ips {
+		if !IsPublicIP(ip) {
+			return nil, fmt.Errorf("address %s of %s is not a public address", ip, host)
+		}
+	}
+	return ips, nil
+}
+
+// PublicDialContext returns a dial function that only connects to public addresses. Hosts are resolved
+// here and the checked addresses dialed directly, so DNS can't change between the check and the connect.
+// Connections to the addresses in allowed, such as a configured outbound proxy, skip the check.
+func PublicDialContext(dialer *net.Dialer, allowed map[string]bool) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if allowed[address] {
+			return dialer.DialContext(ctx, network, address)
+		}
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		ips, err := ResolvePublicIPs(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var lastErr error
+		for _, ip := range ips {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// NewPublicHTTPClient creates an outbound HTTP client like NewHTTPClient that refuses to connect to
+// loopback, private and link-local addresses, whatever BLOCK_PRIVATE_IPS is set to. Use it for fetches
+// of URLs supplied by users. When a proxy is configured, the proxy itself may be on a private network.
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	client := NewHTTPClient(timeout)
+	transport := client.Transport.(*http.Transport)
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport.DialContext = PublicDialContext(dialer, outboundProxyAddresses())
+	return client
+}
+
+// outboundProxyAddresses returns the host:port of each configured outbound proxy
+func outboundProxyAddresses() map[string]bool {
+	proxyConfig := httpproxy.FromEnvironment()
+	proxies := []string{proxyConfig.HTTPProxy, proxyConfig.HTTPSProxy, config.Get("OUTBOUND_PROXY", "")}
+
+	addresses := make(map[string]bool)
+	for _, proxy := range proxies {
+		if proxy == "" {
+			continue
+		}
+		u, err := url.Parse(proxy)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		port := u.Port()
+		if port == "" {
+			port = "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+		}
+		addresses[net.JoinHostPort(u.Hostname(), port)] = true
+	}
+	return addresses
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -116,3 +117,96 @@ func NormalizeTimezone(timezone string) string {
 
 	return "UTC"
 }
+
+// regionTimezones maps regions with a single predominant timezone to it, for inferring a timezone from a
+// locale. Regions spanning several timezones, like the US or Australia, are left out.
+var regionTimezones = map[string]string{
+	"GB": "Europe/London",
+	"IE": "Europe/Dublin",
+	"FR": "Europe/Paris",
+	"DE": "Europe/Berlin",
+	"IT": "Europe/Rome",
+	"ES": "Europe/Madrid",
+	"NL": "Europe/Amsterdam",
+	"BE": "Europe/Brussels",
+	"LU": "Europe/Luxembourg",
+	"CH": "Europe/Zurich",
+	"AT": "Europe/Vienna",
+	"SE": "Europe/Stockholm",
+	"NO": "Europe/Oslo",
+	"DK": "Europe/Copenhagen",
+	"FI": "Europe/Helsinki",
+	"PL": "Europe/Warsaw",
+	"CZ": "Europe/Prague",
+	"SK": "Europe/Bratislava",
+	"HU": "Europe/Budapest",
+	"RO": "Europe/Bucharest",
+	"BG": "Europe/Sofia",
+	"GR": "Europe/Athens",
+	"TR": "Europe/Istanbul",
+	"UA": "Europe/Kyiv",
+	"HR": "Europe/Zagreb",
+	"SI": "Europe/Ljubljana",
+	"RS": "Europe/Belgrade",
+	"EE": "Europe/Tallinn",
+	"LV": "Europe/Riga",
+	"LT": "Europe/Vilnius",
+	"IS": "Atlantic/Reykjavik",
+	"JP": "Asia/Tokyo",
+	"KR": "Asia/Seoul",
+	"CN": "Asia/Shanghai",
+	"HK": "Asia/Hong_Kong",
+	"TW": "Asia/Taipei",
+	"SG": "Asia/Singapore",
+	"MY": "Asia/Kuala_Lumpur",
+	"TH": "Asia/Bangkok",
+	"VN": "Asia/Ho_Chi_Minh",
+	"PH": "Asia/Manila",
+	"IN": "Asia/Kolkata",
+	"PK": "Asia/Karachi",
+	"AE": "Asia/Dubai",
+	"SA": "Asia/Riyadh",
+	"IL": "Asia/Jerusalem",
+	"IR": "Asia/Tehran",
+	"NZ": "Pacific/Auckland",
+	"ZA": "Africa/Johannesburg",
+	"EG": "Africa/Cairo",
+	"NG": "Africa/Lagos",
+	"KE": "Africa/Nairobi",
+	"AR": "America/Argentina/Buenos_Aires",
+	"CO": "America/Bogota",
+	"PE": "America/Lima",
+	"VE": "America/Caracas",
+}
+
+// localeRegion returns the upper-cased region subtag of a locale such as "de-DE", "en_GB" or "zh-Hant-TW"
+func localeRegion(locale string) string {
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			return strings.ToUpper(parts[i])
+		}
+	}
+	return ""
+}
+
+// TimezoneFromLocale returns the timezone of a locale's region. It returns "" when the locale has no
+// region or the region spans several timezones.
+func TimezoneFromLocale(locale string) string {
+	if timezone, ok := regionTimezones[localeRegion(locale)]; ok && IsValidTimezone(timezone) {
+		return timezone
+	}
+	return ""
+}
+
+// TimezoneFromAcceptLanguage infers a timezone from the first locale with a region in an Accept-Language
+// header. Later locales are ignored so a secondary language doesn't override where the user is.
+func TimezoneFromAcceptLanguage(header string) string {
+	for _, entry := range strings.Split(header, ",") {
+		locale, _, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		if localeRegion(locale) != "" {
+			return TimezoneFromLocale(locale)
+		}
+	}
+	return ""
+}
@@ -16,7 +16,6 @@ import (
 	"time"
 
 	// third-party
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -38,6 +37,7 @@ import (
 
 	"github.com/rmitchellscott/stationmaster/internal/sse"
 	"github.com/rmitchellscott/stationmaster/internal/trmnl"
+	"github.com/rmitchellscott/stationmaster/internal/utils"
 
 	"github.com/rmitchellscott/stationmaster/internal/version"
 
@@ -45,6 +45,7 @@ import (
 	_ "github.com/rmitchellscott/stationmaster/internal/plugins/alias"
 	_ "github.com/rmitchellscott/stationmaster/internal/plugins/core_proxy"
 	_ "github.com/rmitchellscott/stationmaster/internal/plugins/external"  // Register external plugin factory
+	_ "github.com/rmitchellscott/stationmaster/internal/plugins/health_check"
 	_ "github.com/rmitchellscott/stationmaster/internal/plugins/image_display"
 	_ "github.com/rmitchellscott/stationmaster/internal/plugins/redirect"
 	_ "github.com/rmitchellscott/stationmaster/internal/plugins/screenshot"
@@ -169,6 +170,7 @@ func main() {
 	}()
 	firmwarePoller := pollers.NewFirmwarePoller(db)
 	modelPoller := pollers.NewModelPoller(db)
+	deviceCleanupPoller := pollers.NewDeviceCleanupPoller(db)
 
 	// Discover firmware versions from manifest on startup
 	logging.Info("[STARTUP] Discovering firmware versions from manifest")
@@ -197,6 +199,7 @@ func main() {
 
 	pollerManager.Register(firmwarePoller)
 	pollerManager.Register(modelPoller)
+	pollerManager.Register(deviceCleanupPoller)
 	pollerManager.Register(renderPoller)
 
 	// Start pollers and SSE keep-alive
@@ -236,31 +239,17 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Let the renderer inline TRMNL fonts from the embedded assets when RENDER_INLINE_FONTS is set
+	if trmnlAssets, err := fs.Sub(embeddedTRNMLAssets, "assets/trmnl"); err == nil {
+		rendering.SetTRMNLAssets(trmnlAssets)
+	}
+
 	router := gin.New()
-	router.Use(gin.Logger(), gin.Recovery())
-
-	// Configure CORS for browser-based device simulators
-	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowMethods = []string{"GET", "POST", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{
-		"Origin",
-		"Content-Type",
-		"Accept",
-		"Authorization",
-		// TRMNL device headers
-		"ID",
-		"Access-Token",
-		"Refresh-Rate",
-		"Battery-Voltage",
-		"Fw-Version",
-		"Rssi",
-		"Model",
-		"Width",
-		"Height",
-		"User-Agent",
-	}
-	router.Use(cors.New(corsConfig))
+	router.Use(gin.Logger(), gin.Recovery(), middleware.RequestID())
+
+	// Device endpoints accept any origin for browser-based device simulators, the rest of the API only
+	// the origins in CORS_ALLOWED_ORIGINS
+	router.Use(middleware.CORS(strings.Split(config.Get("CORS_ALLOWED_ORIGINS", ""), ",")))
 
 	// Initialize locale manager for TRMNL i18n compatibility
 	localeManager, err := locales.NewLocaleManager()
@@ -270,6 +259,7 @@ func main() {
 	}
 	logging.InfoWithComponent(logging.ComponentStartup, "Locale manager initialized", 
 		"locales", len(localeManager.GetAvailableLocales()))
+	rendering.SetLocaleManager(localeManager)
 
 	// Register public locale API routes (needed by browserless for template rendering)
 	handlers.RegisterLocaleRoutes(router, localeManager)
@@ -293,7 +283,11 @@ func main() {
 	router.GET("/api/current_screen", trmnl.CurrentScreenHandler)
 	router.POST("/api/logs", trmnl.LogsHandler)
 	router.POST("/api/log", trmnl.LogsHandler)
+	router.POST("/api/capabilities", trmnl.CapabilitiesHandler)
+	router.GET("/api/device-config", trmnl.DeviceConfigHandler)
 	router.GET("/api/trmnl/devices/:deviceId/image", trmnl.DeviceImageHandler)
+	router.GET("/api/public/devices/:token/current.png", trmnl.SharedScreenHandler)
+	router.GET("/api/image-proxy", handlers.ImageProxyHandler) // Remote image fetch/cache for plugin templates (signed URLs instead of auth, browserless fetches it)
 	router.GET("/api/trmnl/firmware/:version/download", trmnl.FirmwareDownloadHandler)
 	router.POST("/api/trmnl/firmware/update-complete", trmnl.FirmwareUpdateCompleteHandler)
 
@@ -304,6 +298,7 @@ func main() {
 		rateLimiter.RateLimit(),
 		handlers.WebhookHandler,
 	)
+	router.GET("/api/webhooks/instance/:id/renders/:jobId", handlers.GetWebhookRenderStatusHandler) // Status of a render a webhook push triggered, for ?wait=true callers that timed out
 
 	// Public firmware downloads (no authentication required)
 	// Custom handler to serve firmware files - supports both proxy and download modes
@@ -355,7 +350,7 @@ func main() {
 
 			logging.Info("[FIRMWARE PROXY] Proxying firmware", "family", family, "version", version, "url", fwVersion.DownloadURL)
 
-			client := &http.Client{Timeout: 5 * time.Minute}
+			client := utils.NewHTTPClient(5 * time.Minute)
 			req, err := http.NewRequest("GET", fwVersion.DownloadURL, nil)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to proxy firmware request"})
@@ -439,6 +434,10 @@ func main() {
 		profile.POST("/password", auth.UpdatePasswordHandler)  // POST /api/profile/password - update password
 		profile.GET("/stats", auth.GetCurrentUserStatsHandler) // GET /api/profile/stats - get current user stats
 		profile.DELETE("", auth.DeleteCurrentUserHandler)      // DELETE /api/profile - delete current user account
+		profile.GET("/smtp", auth.GetUserSMTPHandler)          // GET /api/profile/smtp - get SMTP override for the user's notifications
+		profile.PUT("/smtp", auth.UpdateUserSMTPHandler)       // PUT /api/profile/smtp - set SMTP override
+		profile.DELETE("/smtp", auth.DeleteUserSMTPHandler)    // DELETE /api/profile/smtp - remove SMTP override
+		profile.POST("/smtp/test", auth.TestUserSMTPHandler)   // POST /api/profile/smtp/test - test SMTP override connection
 	}
 
 	// OAuth endpoints for external service integration
@@ -494,7 +493,25 @@ func main() {
 		admin.GET("/devices", handlers.GetAllDevicesHandler)              // GET /api/admin/devices - list all devices
 		admin.GET("/devices/stats", handlers.GetDeviceStatsHandler)       // GET /api/admin/devices/stats - get device statistics
 		admin.DELETE("/devices/:id/unlink", handlers.UnlinkDeviceHandler)
+		admin.POST("/devices/:id/reassign", handlers.ReassignDeviceHandler) // POST /api/admin/devices/:id/reassign - transfer device to another user
+		admin.POST("/devices/:id/simulate-display", handlers.SimulateDeviceDisplayHandler) // POST /api/admin/devices/:id/simulate-display - preview the display response a device would get
+		admin.GET("/devices/:id/verify-image", handlers.VerifyDeviceImageHandler)           // GET /api/admin/devices/:id/verify-image - check the served image matches its rendered content
 		admin.DELETE("/devices/:id", handlers.AdminDeleteDeviceHandler)
+		admin.GET("/device-claims", handlers.GetPendingClaimRequestsHandler)              // GET /api/admin/device-claims - list pending device claim requests
+		admin.POST("/device-claims/:id/approve", handlers.ApproveClaimRequestHandler)     // POST /api/admin/device-claims/:id/approve - approve a claim request
+		admin.POST("/device-claims/:id/deny", handlers.DenyClaimRequestHandler)           // POST /api/admin/device-claims/:id/deny - deny a claim request
+		admin.GET("/device-preregistrations", handlers.GetPreregistrationsHandler)          // GET /api/admin/device-preregistrations - list MACs pre-registered to users
+		admin.POST("/device-preregistrations", handlers.CreatePreregistrationsHandler)      // POST /api/admin/device-preregistrations - pre-register MACs so devices are claimed on first setup
+		admin.DELETE("/device-preregistrations/:id", handlers.DeletePreregistrationHandler) // DELETE /api/admin/device-preregistrations/:id - remove a pre-registration
+
+		// Render reporting
+		admin.GET("/render/report.csv", handlers.GetRenderReportCSVHandler) // GET /api/admin/render/report.csv - per-day render activity as CSV
+		admin.GET("/render/pause", handlers.GetRenderPauseHandler)          // GET /api/admin/render/pause - get render pause state and pending job count
+		admin.PUT("/render/pause", handlers.UpdateRenderPauseHandler)       // PUT /api/admin/render/pause - pause or resume background rendering
+		admin.GET("/render/orphaned-files", handlers.GetOrphanedRenderedFilesHandler)     // GET /api/admin/render/orphaned-files - list rendered files with no database record
+		admin.POST("/render/orphaned-files", handlers.DeleteOrphanedRenderedFilesHandler) // POST /api/admin/render/orphaned-files - delete orphaned rendered files
+		admin.POST("/render/cleanup", handlers.RunRenderCleanupHandler)                  // POST /api/admin/render/cleanup - run retention, old-content and orphan cleanup now
+		admin.GET("/render/queue-dump", handlers.GetRenderQueueDumpHandler)              // GET /api/admin/render/queue-dump - list queued render jobs in processing order
 
 
 		// Firmware management endpoints
@@ -503,11 +520,16 @@ func main() {
 		admin.GET("/firmware/stats", handlers.GetFirmwareStatsHandler)                    // GET /api/admin/firmware/stats - get firmware statistics
 		admin.GET("/firmware/status", handlers.GetFirmwareStatusHandler)                  // GET /api/admin/firmware/status - get real-time download status
 		admin.GET("/firmware/mode", handlers.GetFirmwareModeHandler)                      // GET /api/admin/firmware/mode - get current firmware mode
+		admin.GET("/firmware/update-candidates", handlers.GetFirmwareUpdateCandidatesHandler) // GET /api/admin/firmware/update-candidates - preview which devices would be updated
 		admin.POST("/firmware/versions/:id/retry", handlers.RetryFirmwareDownloadHandler) // POST /api/admin/firmware/versions/:id/retry - retry firmware download
 		admin.DELETE("/firmware/versions/:id", handlers.DeleteFirmwareVersionHandler)     // DELETE /api/admin/firmware/versions/:id - delete firmware version
+		admin.PUT("/firmware/versions/:id/channel", handlers.UpdateFirmwareVersionChannelHandler) // PUT /api/admin/firmware/versions/:id/channel - move firmware version to a release channel
+		admin.GET("/firmware/versions/:id/release-notes", handlers.GetFirmwareReleaseNotesHandler) // GET /api/admin/firmware/versions/:id/release-notes - preview firmware release notes
 
 		// Device model management endpoints
 		admin.GET("/device-models", handlers.GetDeviceModelsHandler) // GET /api/admin/device-models - list device models
+		admin.PUT("/device-models/:id/review", handlers.ReviewDeviceModelHandler) // PUT /api/admin/device-models/:id/review - approve a provisional device model
+		admin.PUT("/device-models/:id/grayscale-levels", handlers.UpdateDeviceModelGrayscaleLevelsHandler) // PUT /api/admin/device-models/:id/grayscale-levels - override quantization gray levels
 
 		// Manual polling endpoints
 		admin.POST("/firmware/poll", handlers.TriggerFirmwarePollHandler) // POST /api/admin/firmware/poll - trigger manual firmware poll
@@ -524,16 +546,29 @@ func main() {
 		devices.GET("", handlers.GetDevicesHandler)                         // GET /api/devices - list user's devices
 		devices.GET("/models", handlers.GetDeviceModelOptionsHandler)       // GET /api/devices/models - list available device models
 		devices.POST("/claim", handlers.ClaimDeviceHandler)                 // POST /api/devices/claim - claim unclaimed device
+		devices.GET("/claim-requests", handlers.GetMyClaimRequestsHandler)  // GET /api/devices/claim-requests - list the user's device claim requests
 		devices.POST("/import", handlers.ImportDeviceHandler)
 		devices.GET("/:id", handlers.GetDeviceHandler)                      // GET /api/devices/:id - get specific device
 		devices.PUT("/:id", handlers.UpdateDeviceHandler)                   // PUT /api/devices/:id - update device
 		devices.DELETE("/:id", handlers.UnclaimDeviceHandler)
 		devices.GET("/:id/logs", handlers.GetDeviceLogsHandler)             // GET /api/devices/:id/logs - get device logs
 		devices.GET("/:id/events", handlers.DeviceEventsHandler)            // GET /api/devices/:id/events - SSE for device events
+		devices.GET("/:id/online", handlers.GetDeviceOnlineStatusHandler)   // GET /api/devices/:id/online - connection state and time since last check-in
 		devices.GET("/:id/active-items", handlers.DeviceActiveItemsHandler) // GET /api/devices/:id/active-items - get schedule-filtered active items
+		devices.GET("/:id/last-request-headers", handlers.GetDeviceLastRequestHeadersHandler) // GET /api/devices/:id/last-request-headers - headers from the latest display request
+		devices.GET("/:id/rendered-archive", handlers.GetDeviceRenderedArchiveHandler)        // GET /api/devices/:id/rendered-archive - zip of recently rendered images
+		devices.POST("/:id/requantize", handlers.RequantizeDeviceContentHandler)              // POST /api/devices/:id/requantize - convert rendered content to the device's current model
+		devices.POST("/:id/set-current", handlers.SetCurrentPlaylistItemHandler) // POST /api/devices/:id/set-current - pin a playlist item as the current screen
 		devices.POST("/:id/mirror", handlers.MirrorDeviceHandler)           // POST /api/devices/:id/mirror - mirror another device
 		devices.POST("/:id/sync-mirror", handlers.SyncMirrorHandler)        // POST /api/devices/:id/sync-mirror - sync from mirrored device
 		devices.DELETE("/:id/unmirror", handlers.UnmirrorDeviceHandler)     // DELETE /api/devices/:id/unmirror - stop mirroring
+		devices.GET("/:id/share", handlers.GetDeviceShareLinksHandler)      // GET /api/devices/:id/share - list public share links
+		devices.POST("/:id/share", handlers.CreateDeviceShareLinkHandler)   // POST /api/devices/:id/share - create a public share link for the current screen
+		devices.DELETE("/:id/share/:shareId", handlers.RevokeDeviceShareLinkHandler) // DELETE /api/devices/:id/share/:shareId - revoke a share link
+		devices.POST("/:id/snapshot", handlers.CreateDeviceSnapshotHandler)  // POST /api/devices/:id/snapshot - save the current screen as a snapshot
+		devices.GET("/:id/snapshots", handlers.GetDeviceSnapshotsHandler)    // GET /api/devices/:id/snapshots - list saved snapshots
+		devices.GET("/:id/snapshots/:snapshotId/image", handlers.GetDeviceSnapshotImageHandler) // GET /api/devices/:id/snapshots/:snapshotId/image - get snapshot image
+		devices.DELETE("/:id/snapshots/:snapshotId", handlers.DeleteDeviceSnapshotHandler)      // DELETE /api/devices/:id/snapshots/:snapshotId - delete snapshot
 	}
 
 
@@ -546,12 +581,18 @@ func main() {
 		pluginDefs.PUT("/:id", handlers.UpdatePluginDefinitionHandler) // PUT /api/plugin-definitions/:id - update plugin definition
 		pluginDefs.DELETE("/:id", handlers.DeletePluginDefinitionHandler) // DELETE /api/plugin-definitions/:id - delete plugin definition
 		pluginDefs.POST("/validate", handlers.ValidatePluginDefinitionHandler) // POST /api/plugin-definitions/validate - validate plugin templates
-		pluginDefs.POST("/test", handlers.TestPluginDefinitionHandler) // POST /api/plugin-definitions/test - queue preview render
-		pluginDefs.GET("/preview/:jobId", handlers.GetPreviewResultHandler) // GET /api/plugin-definitions/preview/:jobId - poll preview result
+		pluginDefs.POST("/test", handlers.TestPluginDefinitionHandler) // POST /api/plugin-definitions/test - queue preview render, or return rendered HTML with render_mode=html
+		pluginDefs.POST("/variables", handlers.GetTemplateVariablesHandler) // POST /api/plugin-definitions/variables - list template variable paths for autocomplete
+		pluginDefs.GET("/preview/:jobId", handlers.GetPreviewResultHandler) // GET /api/plugin-definitions/preview/:jobId - poll preview result, as WebP with format=webp
 		pluginDefs.GET("/refresh-rate-options", handlers.GetRefreshRateOptionsHandler) // GET /api/plugin-definitions/refresh-rate-options - get available refresh rates
 		pluginDefs.POST("/validate-settings", handlers.ValidatePluginSettingsHandler) // POST /api/plugin-definitions/validate-settings - validate plugin settings
 		pluginDefs.POST("/import", handlers.ImportPluginDefinitionHandler) // POST /api/plugin-definitions/import - import TRMNL-compatible ZIP file
+		pluginDefs.POST("/import/validate", handlers.ValidatePluginImportHandler) // POST /api/plugin-definitions/import/validate - check a TRMNL-compatible ZIP file without importing it
+		pluginDefs.POST("/import-account", handlers.ImportTRMNLAccountExportHandler) // POST /api/plugin-definitions/import-account - import all plugins from a TRMNL account export
+		pluginDefs.POST("/:id/force-refresh-instances", handlers.ForceRefreshDefinitionInstancesHandler) // POST /api/plugin-definitions/:id/force-refresh-instances - force refresh all of the user's instances of a definition
 		pluginDefs.GET("/:id/export", handlers.ExportPluginDefinitionHandler) // GET /api/plugin-definitions/:id/export - export plugin as TRMNL-compatible ZIP file
+		pluginDefs.POST("/:id/publish", handlers.PublishPluginDefinitionHandler) // POST /api/plugin-definitions/:id/publish - publish private plugin to the shared catalog
+		pluginDefs.DELETE("/:id/publish", handlers.UnpublishPluginDefinitionHandler) // DELETE /api/plugin-definitions/:id/publish - remove plugin from the shared catalog
 		pluginDefs.GET("/types", handlers.GetAvailablePluginTypesHandler) // GET /api/plugin-definitions/types - get available plugin types
 		pluginDefs.POST("/debug/validate-yaml", handlers.ValidateTRMNLYAMLHandler) // POST /api/plugin-definitions/debug/validate-yaml - validate TRMNL YAML format
 		pluginDefs.POST("/debug/test-conversion", handlers.TestTRMNLConversionHandler) // POST /api/plugin-definitions/debug/test-conversion - test bidirectional TRMNL conversion
@@ -563,6 +604,11 @@ func main() {
 		pluginDefs.GET("/mashup/layouts/:layout/slots", handlers.GetMashupSlotsHandler) // GET /api/plugin-definitions/mashup/layouts/:layout/slots - get slots for layout
 	}
 
+	// Shared plugin catalog
+	protected.GET("/plugin-catalog", handlers.GetPluginCatalogHandler) // GET /api/plugin-catalog - list published plugins
+	protected.GET("/plugin-catalog/:id", handlers.GetCatalogPluginHandler) // GET /api/plugin-catalog/:id - get published plugin details
+	protected.POST("/plugin-catalog/:id/install", handlers.InstallCatalogPluginHandler) // POST /api/plugin-catalog/:id/install - copy published plugin into user's account
+
 	protected.GET("/plugin-instances", handlers.GetPluginInstancesHandler) // GET /api/plugin-instances - list user's plugin instances
 	protected.POST("/plugin-instances", handlers.CreatePluginInstanceFromDefinitionHandler) // POST /api/plugin-instances - create plugin instance from definition
 	
@@ -571,13 +617,24 @@ func main() {
 	protected.POST("/plugins/:plugin_identifier/options/:field_name", handlers.GetPluginDynamicOptionsHandler) // POST /api/plugins/:plugin_identifier/options/:field_name - get dynamic field options
 	
 	// Static routes must come before parameterized routes
+	protected.GET("/plugin-instances/poll-status", handlers.GetPollStatusHandler) // GET /api/plugin-instances/poll-status - freshness of polled data across polling instances
 	protected.GET("/plugin-instances/private", handlers.GetUserPrivatePluginInstancesHandler) // GET /api/plugin-instances/private - get user's private plugin instances for mashup children
 	
 	// Parameterized routes (all using :id parameter)
 	protected.PUT("/plugin-instances/:id", handlers.UpdatePluginInstanceHandler) // PUT /api/plugin-instances/:id - update plugin instance
 	protected.DELETE("/plugin-instances/:id", handlers.DeletePluginInstanceHandler) // DELETE /api/plugin-instances/:id - delete plugin instance
 	protected.POST("/plugin-instances/:id/force-refresh", handlers.ForceRefreshPluginInstanceHandler) // POST /api/plugin-instances/:id/force-refresh - force refresh plugin instance
+	protected.POST("/plugin-instances/:id/render-sync", handlers.RenderPluginInstanceSyncHandler) // POST /api/plugin-instances/:id/render-sync - render for a device and return the PNG
 	protected.GET("/plugin-instances/:id/schema-diff", handlers.GetPluginInstanceSchemaDiffHandler) // GET /api/plugin-instances/:id/schema-diff - get schema differences for instance
+	protected.GET("/plugin-instances/:id/webhook-info", handlers.GetWebhookInfoHandler) // GET /api/plugin-instances/:id/webhook-info - get webhook URL and QR code
+	protected.GET("/plugin-instances/:id/preflight", handlers.GetPluginInstancePreflightHandler) // GET /api/plugin-instances/:id/preflight - check required settings are filled
+	protected.GET("/plugin-instances/:id/devices", handlers.GetPluginInstanceDevicesHandler) // GET /api/plugin-instances/:id/devices - list devices showing this instance
+	protected.GET("/plugin-instances/:id/timeline", handlers.GetPluginInstanceTimelineHandler) // GET /api/plugin-instances/:id/timeline - chronological activity feed for an instance
+	protected.POST("/plugin-instances/:id/add-to-playlists", handlers.AddPluginInstanceToPlaylistsHandler) // POST /api/plugin-instances/:id/add-to-playlists - add instance to several playlists at once
+	protected.GET("/plugin-instances/:id/profiles", handlers.GetPluginInstanceProfilesHandler) // GET /api/plugin-instances/:id/profiles - list settings profiles
+	protected.PUT("/plugin-instances/:id/profiles/:name", handlers.SavePluginInstanceProfileHandler) // PUT /api/plugin-instances/:id/profiles/:name - create or replace a settings profile
+	protected.DELETE("/plugin-instances/:id/profiles/:name", handlers.DeletePluginInstanceProfileHandler) // DELETE /api/plugin-instances/:id/profiles/:name - delete a settings profile
+	protected.POST("/plugin-instances/:id/profiles/:name/activate", handlers.ActivatePluginInstanceProfileHandler) // POST /api/plugin-instances/:id/profiles/:name/activate - switch to a settings profile
 	
 	// Mashup instance endpoints (using consistent :id parameter)
 	protected.POST("/plugin-instances/:id/mashup/children", handlers.AssignMashupChildrenHandler) // POST /api/plugin-instances/:id/mashup/children - assign children to mashup slots
@@ -590,12 +647,15 @@ func main() {
 	{
 		playlists.GET("", handlers.GetPlaylistsHandler)                                // GET /api/playlists - list user's playlists
 		playlists.POST("", handlers.CreatePlaylistHandler)                             // POST /api/playlists - create playlist
+		playlists.POST("/import", handlers.ImportPlaylistHandler)                      // POST /api/playlists/import - create a playlist from an export
 		playlists.GET("/:id", handlers.GetPlaylistHandler)                             // GET /api/playlists/:id - get playlist with items
+		playlists.GET("/:id/export", handlers.ExportPlaylistHandler)                   // GET /api/playlists/:id/export - export playlist with items and schedules
 		playlists.PUT("/:id", handlers.UpdatePlaylistHandler)                          // PUT /api/playlists/:id - update playlist
 		playlists.DELETE("/:id", handlers.DeletePlaylistHandler)                       // DELETE /api/playlists/:id - delete playlist
 		playlists.POST("/:id/items", handlers.AddPlaylistItemHandler)                  // POST /api/playlists/:id/items - add item to playlist
 		playlists.PUT("/:id/reorder", handlers.ReorderPlaylistItemsHandler)            // PUT /api/playlists/:id/reorder - reorder items (legacy)
 		playlists.PUT("/:id/reorder-array", handlers.ReorderPlaylistItemsArrayHandler) // PUT /api/playlists/:id/reorder-array - reorder items by array
+		playlists.PUT("/:id/visibility", handlers.UpdatePlaylistVisibilityHandler)     // PUT /api/playlists/:id/visibility - show/hide items in bulk
 		playlists.PUT("/items/:itemId", handlers.UpdatePlaylistItemHandler)            // PUT /api/playlists/items/:itemId - update playlist item
 		playlists.DELETE("/items/:itemId", handlers.DeletePlaylistItemHandler)         // DELETE /api/playlists/items/:itemId - delete playlist item
 		playlists.POST("/items/:itemId/schedules", handlers.AddScheduleHandler)        // POST /api/playlists/items/:itemId/schedules - add schedule
@@ -603,6 +663,9 @@ func main() {
 		playlists.DELETE("/schedules/:scheduleId", handlers.DeleteScheduleHandler)     // DELETE /api/playlists/schedules/:scheduleId - delete schedule
 	}
 
+	// System screen previews
+	protected.GET("/system-images/:name", handlers.GetSystemImageHandler) // GET /api/system-images/:name - preview a built-in system screen
+
 	// Dashboard endpoint (simple placeholder for now)
 	protected.GET("/dashboard", handlers.DashboardHandler)
 
@@ -710,6 +773,11 @@ func main() {
 		if strings.HasPrefix(filepath, "/") {
 			filepath = filepath[1:]
 		}
+		// Renders for models with their own output directory are served under the same URL
+		if path, ok := rendering.ResolveRenderedFile("./static/rendered", filepath); ok {
+			c.File(path)
+			return
+		}
 		c.File("./static/rendered/" + filepath)
 	})
 
@@ -1440,7 +1440,7 @@ export function AdminPage() {
 
   const deleteExternalPlugin = async (pluginId: string) => {
     try {
-      const response = await fetch(`/api/admin/external-plugins/${pluginId}`, {
+      const response = await fetch(`/api/admin/external-plugins/${pluginId}?force=true`, {
         method: "DELETE",
         credentials: "include",
       });
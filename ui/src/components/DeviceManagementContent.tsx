@@ -107,10 +107,12 @@ interface Device {
   is_claimed: boolean;
   firmware_version?: string;
   target_firmware_version?: string;
+  firmware_channel?: string;
   battery_voltage?: number;
   battery_percent?: number;
   rssi?: number;
   refresh_rate: number;
+  min_refresh_rate?: number;
   allow_firmware_updates?: boolean;
   last_seen?: string;
   is_active: boolean;
@@ -235,6 +237,7 @@ export function DeviceManagementContent({ onUpdate }: DeviceManagementContentPro
   const deviceSettingsDefaults = {
     name: "",
     refresh_rate: "1800",
+    min_refresh_rate: "60",
     allow_firmware_updates: true,
     is_shareable: false,
     sleep_enabled: false,
@@ -244,6 +247,7 @@ export function DeviceManagementContent({ onUpdate }: DeviceManagementContentPro
     firmware_update_start_time: "00:00",
     firmware_update_end_time: "23:59",
     target_firmware_version: "latest",
+    firmware_channel: "stable",
     maximum_compatibility: false,
     touchbar_mode: "tap",
     temperature_profile: "default",
@@ -584,6 +588,12 @@ export function DeviceManagementContent({ onUpdate }: DeviceManagementContentPro
       return;
     }
 
+    const minRefreshRate = parseInt(editSettings.min_refresh_rate);
+    if (isNaN(minRefreshRate) || minRefreshRate < 0 || minRefreshRate > 86400) {
+      setEditDialogError("Minimum refresh rate must be between 0 and 86400 seconds");
+      return;
+    }
+
     try {
       setEditDialogError(null);
 
@@ -591,6 +601,7 @@ export function DeviceManagementContent({ onUpdate }: DeviceManagementContentPro
         ...editSettings,
         name: editSettings.name.trim(),
         refresh_rate: refreshRate,
+        min_refresh_rate: minRefreshRate,
       };
 
 
@@ -699,6 +710,7 @@ export function DeviceManagementContent({ onUpdate }: DeviceManagementContentPro
       const settings: DeviceSettingsState = {
         name: device.name || "",
         refresh_rate: device.refresh_rate.toString(),
+        min_refresh_rate: (device.min_refresh_rate ?? 60).toString(),
         allow_firmware_updates: device.allow_firmware_updates ?? true,
         is_shareable: device.is_shareable ?? false,
         sleep_enabled: device.sleep_enabled ?? false,
@@ -708,6 +720,7 @@ export function DeviceManagementContent({ onUpdate }: DeviceManagementContentPro
         firmware_update_start_time: device.firmware_update_start_time || "00:00",
         firmware_update_end_time: device.firmware_update_end_time || "23:59",
         target_firmware_version: device.target_firmware_version || "latest",
+        firmware_channel: device.firmware_channel || "stable",
         maximum_compatibility: device.maximum_compatibility ?? false,
         touchbar_mode: device.touchbar_mode || "tap",
         temperature_profile: device.temperature_profile || "default",
@@ -1613,6 +1626,21 @@ export function DeviceManagementContent({ onUpdate }: DeviceManagementContentPro
                   How often the device should check for new content (60-86400 seconds)
                 </p>
               </div>
+              <div>
+                <Label htmlFor="edit-min-refresh-rate">Minimum Refresh Rate (seconds)</Label>
+                <Input
+                  id="edit-min-refresh-rate"
+                  type="number"
+                  min="0"
+                  max="86400"
+                  value={editSettings.min_refresh_rate}
+                  onChange={(e) => updateSetting("min_refresh_rate", e.target.value)}
+                  className="mt-2"
+                />
+                <p className="text-sm text-muted-foreground mt-1">
+                  Plugins and playlist items can't make the device refresh faster than this, to save battery (0 disables)
+                </p>
+              </div>
               <div>
                 <div className="flex items-center space-x-2">
                   <Switch
@@ -1665,10 +1693,10 @@ export function DeviceManagementContent({ onUpdate }: DeviceManagementContentPro
                         onValueChange={(v) => updateSetting("target_firmware_version", v)}
                       >
                         <SelectTrigger id="edit-target-firmware" className="mt-1">
-                          <SelectValue placeholder="Stable (Auto)" />
+                          <SelectValue placeholder="Latest (Auto)" />
                         </SelectTrigger>
                         <SelectContent>
-                          <SelectItem value="latest">Stable (Auto)</SelectItem>
+                          <SelectItem value="latest">Latest on channel (Auto)</SelectItem>
                           {filteredFirmwareVersions.map((fw) => (
                             <SelectItem key={fw.id} value={fw.version}>
                               {fw.version} {fw.is_latest && "(Stable)"}
@@ -1687,7 +1715,25 @@ export function DeviceManagementContent({ onUpdate }: DeviceManagementContentPro
                         </Label>
                       </div>
                       <p className="text-sm text-muted-foreground mt-1">
-                        Pin device to a specific firmware version or keep it on stable
+                        Pin device to a specific firmware version or keep it on the latest release of its channel
+                      </p>
+                    </div>
+                    <div>
+                      <Label htmlFor="edit-firmware-channel" className="text-sm">Firmware Channel</Label>
+                      <Select
+                        value={editSettings.firmware_channel}
+                        onValueChange={(v) => updateSetting("firmware_channel", v)}
+                      >
+                        <SelectTrigger id="edit-firmware-channel" className="mt-1">
+                          <SelectValue />
+                        </SelectTrigger>
+                        <SelectContent>
+                          <SelectItem value="stable">Stable</SelectItem>
+                          <SelectItem value="beta">Beta</SelectItem>
+                        </SelectContent>
+                      </Select>
+                      <p className="text-sm text-muted-foreground mt-1">
+                        Beta devices also receive pre-release firmware
                       </p>
                     </div>
                   </div>
@@ -234,6 +234,7 @@ export function PluginManagement({ selectedDeviceId, onUpdate }: PluginManagemen
   const [mashupAssignments, setMashupAssignments] = useState<Record<string, string>>({});
   const [mashupDescription, setMashupDescription] = useState("");
   const [mashupBackdrop, setMashupBackdrop] = useState(false);
+  const [mashupSlotGutter, setMashupSlotGutter] = useState(0);
 
   // Get active subtab from URL query parameters
   const activeTab = (searchParams.get('subtab') as 'instances' | 'private') || 'instances';
@@ -368,6 +369,7 @@ export function PluginManagement({ selectedDeviceId, onUpdate }: PluginManagemen
         description: mashupDescription.trim() || undefined,
         layout: selectedMashupLayout.id,
         backdrop: mashupBackdrop,
+        slot_gutter: mashupSlotGutter,
       });
 
       if (!mashupResponse || !mashupResponse.mashup || !mashupResponse.mashup.id) {
@@ -499,6 +501,7 @@ export function PluginManagement({ selectedDeviceId, onUpdate }: PluginManagemen
     setMashupAssignments({});
     setMashupDescription("");
     setMashupBackdrop(false);
+    setMashupSlotGutter(0);
   };
 
   const hasPluginInstanceChanges = () => {
@@ -1934,6 +1937,25 @@ export function PluginManagement({ selectedDeviceId, onUpdate }: PluginManagemen
                     />
                   </div>
 
+                  {/* Slot gutter */}
+                  <div className="flex items-center justify-between">
+                    <div className="space-y-0.5">
+                      <Label htmlFor="mashup-slot-gutter" className="text-sm">Slot Spacing (px)</Label>
+                      <p className="text-xs text-muted-foreground">
+                        Space between mashup panels
+                      </p>
+                    </div>
+                    <Input
+                      id="mashup-slot-gutter"
+                      type="number"
+                      min="0"
+                      max="64"
+                      value={mashupSlotGutter}
+                      onChange={(e) => setMashupSlotGutter(Math.min(64, Math.max(0, parseInt(e.target.value) || 0)))}
+                      className="w-24"
+                    />
+                  </div>
+
                   {/* Slot assignments */}
                   {mashupSlots.length > 0 && (
                     <div>
@@ -121,7 +121,7 @@ export function PrivatePluginList({
   const deletePrivatePlugin = async (pluginId: string) => {
     try {
       setError(null);
-      const response = await fetch(`/api/plugin-definitions/${pluginId}`, {
+      const response = await fetch(`/api/plugin-definitions/${pluginId}?force=true`, {
         method: "DELETE",
         credentials: "include",
       });
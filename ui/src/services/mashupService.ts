@@ -40,12 +40,14 @@ export interface CreateMashupRequest {
   description?: string;
   layout: string;
   backdrop?: boolean;
+  slot_gutter?: number;
 }
 
 export interface UpdateMashupRequest {
   name: string;
   description?: string;
   backdrop?: boolean;
+  slot_gutter?: number;
 }
 
 export interface CreateMashupResponse {